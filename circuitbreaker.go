@@ -0,0 +1,155 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus-community/windows_exporter/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var circuitStateDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(collector.Namespace, "exporter", "collector_circuit_state"),
+	"windows_exporter: State of the collector circuit breaker (0 = closed, 1 = open, 2 = half-open).",
+	[]string{"collector"},
+	nil,
+)
+
+type circuitState int
+
+const (
+	// Closed circuits run their collector on every scrape.
+	circuitClosed circuitState = iota
+	// Open circuits skip their collector until the backoff has elapsed.
+	circuitOpen
+	// Half-open circuits have a single trial run in progress.
+	circuitHalfOpen
+)
+
+type circuit struct {
+	state    circuitState
+	failures int
+	backoff  time.Duration
+	retryAt  time.Time
+}
+
+// circuitBreaker stops running collectors which keep failing. A collector's
+// circuit opens after threshold consecutive failures, after which the collector
+// is skipped until the backoff has elapsed. The next run is a trial: if it fails,
+// the circuit opens again with twice the backoff, up to maxBackoff.
+type circuitBreaker struct {
+	threshold  int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// newCircuitBreaker returns a circuitBreaker, or nil if threshold disables it.
+func newCircuitBreaker(threshold int, backoff, maxBackoff time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	if maxBackoff < backoff {
+		maxBackoff = backoff
+	}
+	return &circuitBreaker{
+		threshold:  threshold,
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+		circuits:   make(map[string]*circuit),
+	}
+}
+
+func (b *circuitBreaker) circuit(name string) *circuit {
+	c, ok := b.circuits[name]
+	if !ok {
+		c = &circuit{}
+		b.circuits[name] = c
+	}
+	return c
+}
+
+// allow reports whether the named collector may run now. An open circuit whose
+// backoff has elapsed turns half-open and allows a single trial run.
+func (b *circuitBreaker) allow(name string, now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(name)
+	switch c.state {
+	case circuitOpen:
+		if now.Before(c.retryAt) {
+			return false
+		}
+		c.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// record updates the circuit of the named collector with the outcome of a run.
+func (b *circuitBreaker) record(name string, outcome collectorOutcome, now time.Time) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(name)
+	if outcome == success {
+		if c.state != circuitClosed {
+			log.Infof("collector %s succeeded, closing its circuit", name)
+		}
+		*c = circuit{}
+		return
+	}
+
+	c.failures++
+	switch {
+	case c.state == circuitHalfOpen:
+		c.backoff *= 2
+		if c.backoff > b.maxBackoff {
+			c.backoff = b.maxBackoff
+		}
+	case c.state == circuitClosed && c.failures >= b.threshold:
+		c.backoff = b.backoff
+	default:
+		return
+	}
+	c.state = circuitOpen
+	c.retryAt = now.Add(c.backoff)
+	log.Warnf("collector %s failed %d times in a row, skipping it for %s", name, c.failures, c.backoff)
+}
+
+func (b *circuitBreaker) state(name string) circuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.circuit(name).state
+}
+
+// collectStates sends the circuit state of the named collectors to prometheus.
+func (b *circuitBreaker) collectStates(names []string, ch chan<- prometheus.Metric) {
+	if b == nil {
+		return
+	}
+	for _, name := range names {
+		ch <- prometheus.MustNewConstMetric(
+			circuitStateDesc,
+			prometheus.GaugeValue,
+			float64(b.state(name)),
+			name,
+		)
+	}
+}
@@ -8,11 +8,17 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("ad", NewADCollector)
+	registerCollectorInfo("ad", CollectorInfo{
+		Description: "Active Directory Domain Services",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "Active Directory Domain Services role",
+		Expensive:   true,
+	})
+	registerApplicabilityCheck("ad", serviceInstalled("NTDS"))
 }
 
 // A ADCollector is a Prometheus collector for WMI Win32_PerfRawData_DirectoryServices_DirectoryServices metrics
@@ -84,367 +90,367 @@ type ADCollector struct {
 func NewADCollector() (Collector, error) {
 	const subsystem = "ad"
 	return &ADCollector{
-		AddressBookOperationsTotal: prometheus.NewDesc(
+		AddressBookOperationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "address_book_operations_total"),
 			"",
 			[]string{"operation"},
 			nil,
 		),
-		AddressBookClientSessions: prometheus.NewDesc(
+		AddressBookClientSessions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "address_book_client_sessions"),
 			"",
 			nil,
 			nil,
 		),
-		ApproximateHighestDistinguishedNameTag: prometheus.NewDesc(
+		ApproximateHighestDistinguishedNameTag: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "approximate_highest_distinguished_name_tag"),
 			"",
 			nil,
 			nil,
 		),
-		AtqEstimatedDelaySeconds: prometheus.NewDesc(
+		AtqEstimatedDelaySeconds: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "atq_estimated_delay_seconds"),
 			"",
 			nil,
 			nil,
 		),
-		AtqOutstandingRequests: prometheus.NewDesc(
+		AtqOutstandingRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "atq_outstanding_requests"),
 			"",
 			nil,
 			nil,
 		),
-		AtqAverageRequestLatency: prometheus.NewDesc(
+		AtqAverageRequestLatency: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "atq_average_request_latency"),
 			"",
 			nil,
 			nil,
 		),
-		AtqCurrentThreads: prometheus.NewDesc(
+		AtqCurrentThreads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "atq_current_threads"),
 			"",
 			[]string{"service"},
 			nil,
 		),
-		SearchesTotal: prometheus.NewDesc(
+		SearchesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "searches_total"),
 			"",
 			[]string{"scope"},
 			nil,
 		),
-		DatabaseOperationsTotal: prometheus.NewDesc(
+		DatabaseOperationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "database_operations_total"),
 			"",
 			[]string{"operation"},
 			nil,
 		),
-		BindsTotal: prometheus.NewDesc(
+		BindsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "binds_total"),
 			"",
 			[]string{"bind_method"},
 			nil,
 		),
-		ReplicationHighestUsn: prometheus.NewDesc(
+		ReplicationHighestUsn: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_highest_usn"),
 			"",
 			[]string{"state"},
 			nil,
 		),
-		IntrasiteReplicationDataBytesTotal: prometheus.NewDesc(
+		IntrasiteReplicationDataBytesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_data_intrasite_bytes_total"),
 			"",
 			[]string{"direction"},
 			nil,
 		),
-		IntersiteReplicationDataBytesTotal: prometheus.NewDesc(
+		IntersiteReplicationDataBytesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_data_intersite_bytes_total"),
 			"",
 			[]string{"direction"},
 			nil,
 		),
-		ReplicationInboundSyncObjectsRemaining: prometheus.NewDesc(
+		ReplicationInboundSyncObjectsRemaining: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_inbound_sync_objects_remaining"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationInboundLinkValueUpdatesRemaining: prometheus.NewDesc(
+		ReplicationInboundLinkValueUpdatesRemaining: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_inbound_link_value_updates_remaining"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationInboundObjectsUpdatedTotal: prometheus.NewDesc(
+		ReplicationInboundObjectsUpdatedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_inbound_objects_updated_total"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationInboundObjectsFilteredTotal: prometheus.NewDesc(
+		ReplicationInboundObjectsFilteredTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_inbound_objects_filtered_total"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationInboundPropertiesUpdatedTotal: prometheus.NewDesc(
+		ReplicationInboundPropertiesUpdatedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_inbound_properties_updated_total"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationInboundPropertiesFilteredTotal: prometheus.NewDesc(
+		ReplicationInboundPropertiesFilteredTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_inbound_properties_filtered_total"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationPendingOperations: prometheus.NewDesc(
+		ReplicationPendingOperations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_pending_operations"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationPendingSynchronizations: prometheus.NewDesc(
+		ReplicationPendingSynchronizations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_pending_synchronizations"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationSyncRequestsTotal: prometheus.NewDesc(
+		ReplicationSyncRequestsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_sync_requests_total"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationSyncRequestsSuccessTotal: prometheus.NewDesc(
+		ReplicationSyncRequestsSuccessTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_sync_requests_success_total"),
 			"",
 			nil,
 			nil,
 		),
-		ReplicationSyncRequestsSchemaMismatchFailureTotal: prometheus.NewDesc(
+		ReplicationSyncRequestsSchemaMismatchFailureTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "replication_sync_requests_schema_mismatch_failure_total"),
 			"",
 			nil,
 			nil,
 		),
-		NameTranslationsTotal: prometheus.NewDesc(
+		NameTranslationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "name_translations_total"),
 			"",
 			[]string{"target_name"},
 			nil,
 		),
-		ChangeMonitorsRegistered: prometheus.NewDesc(
+		ChangeMonitorsRegistered: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "change_monitors_registered"),
 			"",
 			nil,
 			nil,
 		),
-		ChangeMonitorUpdatesPending: prometheus.NewDesc(
+		ChangeMonitorUpdatesPending: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "change_monitor_updates_pending"),
 			"",
 			nil,
 			nil,
 		),
-		NameCacheHitsTotal: prometheus.NewDesc(
+		NameCacheHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "name_cache_hits_total"),
 			"",
 			nil,
 			nil,
 		),
-		NameCacheLookupsTotal: prometheus.NewDesc(
+		NameCacheLookupsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "name_cache_lookups_total"),
 			"",
 			nil,
 			nil,
 		),
-		DirectoryOperationsTotal: prometheus.NewDesc(
+		DirectoryOperationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "directory_operations_total"),
 			"",
 			[]string{"operation", "origin"},
 			nil,
 		),
-		DirectorySearchSuboperationsTotal: prometheus.NewDesc(
+		DirectorySearchSuboperationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "directory_search_suboperations_total"),
 			"",
 			nil,
 			nil,
 		),
-		SecurityDescriptorPropagationEventsTotal: prometheus.NewDesc(
+		SecurityDescriptorPropagationEventsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "security_descriptor_propagation_events_total"),
 			"",
 			nil,
 			nil,
 		),
-		SecurityDescriptorPropagationEventsQueued: prometheus.NewDesc(
+		SecurityDescriptorPropagationEventsQueued: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "security_descriptor_propagation_events_queued"),
 			"",
 			nil,
 			nil,
 		),
-		SecurityDescriptorPropagationAccessWaitTotalSeconds: prometheus.NewDesc(
+		SecurityDescriptorPropagationAccessWaitTotalSeconds: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "security_descriptor_propagation_access_wait_total_seconds"),
 			"",
 			nil,
 			nil,
 		),
-		SecurityDescriptorPropagationItemsQueuedTotal: prometheus.NewDesc(
+		SecurityDescriptorPropagationItemsQueuedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "security_descriptor_propagation_items_queued_total"),
 			"",
 			nil,
 			nil,
 		),
-		DirectoryServiceThreads: prometheus.NewDesc(
+		DirectoryServiceThreads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "directory_service_threads"),
 			"",
 			nil,
 			nil,
 		),
-		LdapClosedConnectionsTotal: prometheus.NewDesc(
+		LdapClosedConnectionsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ldap_closed_connections_total"),
 			"",
 			nil,
 			nil,
 		),
-		LdapOpenedConnectionsTotal: prometheus.NewDesc(
+		LdapOpenedConnectionsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ldap_opened_connections_total"),
 			"",
 			[]string{"type"},
 			nil,
 		),
-		LdapActiveThreads: prometheus.NewDesc(
+		LdapActiveThreads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ldap_active_threads"),
 			"",
 			nil,
 			nil,
 		),
-		LdapLastBindTimeSeconds: prometheus.NewDesc(
+		LdapLastBindTimeSeconds: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ldap_last_bind_time_seconds"),
 			"",
 			nil,
 			nil,
 		),
-		LdapSearchesTotal: prometheus.NewDesc(
+		LdapSearchesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ldap_searches_total"),
 			"",
 			nil,
 			nil,
 		),
-		LdapUdpOperationsTotal: prometheus.NewDesc(
+		LdapUdpOperationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ldap_udp_operations_total"),
 			"",
 			nil,
 			nil,
 		),
-		LdapWritesTotal: prometheus.NewDesc(
+		LdapWritesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ldap_writes_total"),
 			"",
 			nil,
 			nil,
 		),
-		LinkValuesCleanedTotal: prometheus.NewDesc(
+		LinkValuesCleanedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "link_values_cleaned_total"),
 			"",
 			nil,
 			nil,
 		),
-		PhantomObjectsCleanedTotal: prometheus.NewDesc(
+		PhantomObjectsCleanedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "phantom_objects_cleaned_total"),
 			"",
 			nil,
 			nil,
 		),
-		PhantomObjectsVisitedTotal: prometheus.NewDesc(
+		PhantomObjectsVisitedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "phantom_objects_visited_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamGroupMembershipEvaluationsTotal: prometheus.NewDesc(
+		SamGroupMembershipEvaluationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_group_membership_evaluations_total"),
 			"",
 			[]string{"group_type"},
 			nil,
 		),
-		SamGroupMembershipGlobalCatalogEvaluationsTotal: prometheus.NewDesc(
+		SamGroupMembershipGlobalCatalogEvaluationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_group_membership_global_catalog_evaluations_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamGroupMembershipEvaluationsNontransitiveTotal: prometheus.NewDesc(
+		SamGroupMembershipEvaluationsNontransitiveTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_group_membership_evaluations_nontransitive_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamGroupMembershipEvaluationsTransitiveTotal: prometheus.NewDesc(
+		SamGroupMembershipEvaluationsTransitiveTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_group_membership_evaluations_transitive_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamGroupEvaluationLatency: prometheus.NewDesc(
+		SamGroupEvaluationLatency: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_group_evaluation_latency"),
 			"The mean latency of the last 100 group evaluations performed for authentication",
 			[]string{"evaluation_type"},
 			nil,
 		),
-		SamComputerCreationRequestsTotal: prometheus.NewDesc(
+		SamComputerCreationRequestsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_computer_creation_requests_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamComputerCreationSuccessfulRequestsTotal: prometheus.NewDesc(
+		SamComputerCreationSuccessfulRequestsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_computer_creation_successful_requests_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamUserCreationRequestsTotal: prometheus.NewDesc(
+		SamUserCreationRequestsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_user_creation_requests_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamUserCreationSuccessfulRequestsTotal: prometheus.NewDesc(
+		SamUserCreationSuccessfulRequestsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_user_creation_successful_requests_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamQueryDisplayRequestsTotal: prometheus.NewDesc(
+		SamQueryDisplayRequestsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_query_display_requests_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamEnumerationsTotal: prometheus.NewDesc(
+		SamEnumerationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_enumerations_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamMembershipChangesTotal: prometheus.NewDesc(
+		SamMembershipChangesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_membership_changes_total"),
 			"",
 			nil,
 			nil,
 		),
-		SamPasswordChangesTotal: prometheus.NewDesc(
+		SamPasswordChangesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sam_password_changes_total"),
 			"",
 			nil,
 			nil,
 		),
-		TombstonedObjectsCollectedTotal: prometheus.NewDesc(
+		TombstonedObjectsCollectedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "tombstoned_objects_collected_total"),
 			"",
 			nil,
 			nil,
 		),
-		TombstonedObjectsVisitedTotal: prometheus.NewDesc(
+		TombstonedObjectsVisitedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "tombstoned_objects_visited_total"),
 			"",
 			nil,
@@ -453,10 +459,75 @@ func NewADCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *ADCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.AddressBookOperationsTotal
+	ch <- c.AddressBookClientSessions
+	ch <- c.ApproximateHighestDistinguishedNameTag
+	ch <- c.AtqEstimatedDelaySeconds
+	ch <- c.AtqOutstandingRequests
+	ch <- c.AtqAverageRequestLatency
+	ch <- c.AtqCurrentThreads
+	ch <- c.SearchesTotal
+	ch <- c.DatabaseOperationsTotal
+	ch <- c.BindsTotal
+	ch <- c.ReplicationHighestUsn
+	ch <- c.IntersiteReplicationDataBytesTotal
+	ch <- c.IntrasiteReplicationDataBytesTotal
+	ch <- c.ReplicationInboundSyncObjectsRemaining
+	ch <- c.ReplicationInboundLinkValueUpdatesRemaining
+	ch <- c.ReplicationInboundObjectsUpdatedTotal
+	ch <- c.ReplicationInboundObjectsFilteredTotal
+	ch <- c.ReplicationInboundPropertiesUpdatedTotal
+	ch <- c.ReplicationInboundPropertiesFilteredTotal
+	ch <- c.ReplicationPendingOperations
+	ch <- c.ReplicationPendingSynchronizations
+	ch <- c.ReplicationSyncRequestsTotal
+	ch <- c.ReplicationSyncRequestsSuccessTotal
+	ch <- c.ReplicationSyncRequestsSchemaMismatchFailureTotal
+	ch <- c.DirectoryOperationsTotal
+	ch <- c.NameTranslationsTotal
+	ch <- c.ChangeMonitorsRegistered
+	ch <- c.ChangeMonitorUpdatesPending
+	ch <- c.NameCacheHitsTotal
+	ch <- c.NameCacheLookupsTotal
+	ch <- c.DirectorySearchSuboperationsTotal
+	ch <- c.SecurityDescriptorPropagationEventsTotal
+	ch <- c.SecurityDescriptorPropagationEventsQueued
+	ch <- c.SecurityDescriptorPropagationAccessWaitTotalSeconds
+	ch <- c.SecurityDescriptorPropagationItemsQueuedTotal
+	ch <- c.DirectoryServiceThreads
+	ch <- c.LdapClosedConnectionsTotal
+	ch <- c.LdapOpenedConnectionsTotal
+	ch <- c.LdapActiveThreads
+	ch <- c.LdapLastBindTimeSeconds
+	ch <- c.LdapSearchesTotal
+	ch <- c.LdapUdpOperationsTotal
+	ch <- c.LdapWritesTotal
+	ch <- c.LinkValuesCleanedTotal
+	ch <- c.PhantomObjectsCleanedTotal
+	ch <- c.PhantomObjectsVisitedTotal
+	ch <- c.SamGroupMembershipEvaluationsTotal
+	ch <- c.SamGroupMembershipGlobalCatalogEvaluationsTotal
+	ch <- c.SamGroupMembershipEvaluationsNontransitiveTotal
+	ch <- c.SamGroupMembershipEvaluationsTransitiveTotal
+	ch <- c.SamGroupEvaluationLatency
+	ch <- c.SamComputerCreationRequestsTotal
+	ch <- c.SamComputerCreationSuccessfulRequestsTotal
+	ch <- c.SamUserCreationRequestsTotal
+	ch <- c.SamUserCreationSuccessfulRequestsTotal
+	ch <- c.SamQueryDisplayRequestsTotal
+	ch <- c.SamEnumerationsTotal
+	ch <- c.SamMembershipChangesTotal
+	ch <- c.SamPasswordChangesTotal
+	ch <- c.TombstonedObjectsCollectedTotal
+	ch <- c.TombstonedObjectsVisitedTotal
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *ADCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting ad metrics:", desc, err)
 		return err
 	}
@@ -615,10 +686,10 @@ type Win32_PerfRawData_DirectoryServices_DirectoryServices struct {
 	TransitivesuboperationsPersec                                    uint32
 }
 
-func (c *ADCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *ADCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_DirectoryServices_DirectoryServices
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -12,6 +12,12 @@ import (
 
 func init() {
 	registerCollector("adcs", adcsCollectorMethod, "Certification Authority")
+	registerCollectorInfo("adcs", CollectorInfo{
+		Description: "Active Directory Certificate Services",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "Active Directory Certificate Services role",
+	})
+	registerApplicabilityCheck("adcs", perflibObjectsPresent("Certification Authority"))
 }
 
 type adcsCollector struct {
@@ -34,79 +40,79 @@ type adcsCollector struct {
 func adcsCollectorMethod() (Collector, error) {
 	const subsystem = "adcs"
 	return &adcsCollector{
-		RequestsPerSecond: prometheus.NewDesc(
+		RequestsPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "requests_total"),
 			"Total certificate requests processed",
 			[]string{"cert_template"},
 			nil,
 		),
-		RequestProcessingTime: prometheus.NewDesc(
+		RequestProcessingTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "request_processing_time_seconds"),
 			"Last time elapsed for certificate requests",
 			[]string{"cert_template"},
 			nil,
 		),
-		RetrievalsPerSecond: prometheus.NewDesc(
+		RetrievalsPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "retrievals_total"),
 			"Total certificate retrieval requests processed",
 			[]string{"cert_template"},
 			nil,
 		),
-		RetrievalProcessingTime: prometheus.NewDesc(
+		RetrievalProcessingTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "retrievals_processing_time_seconds"),
 			"Last time elapsed for certificate retrieval request",
 			[]string{"cert_template"},
 			nil,
 		),
-		FailedRequestsPerSecond: prometheus.NewDesc(
+		FailedRequestsPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failed_requests_total"),
 			"Total failed certificate requests processed",
 			[]string{"cert_template"},
 			nil,
 		),
-		IssuedRequestsPerSecond: prometheus.NewDesc(
+		IssuedRequestsPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "issued_requests_total"),
 			"Total issued certificate requests processed",
 			[]string{"cert_template"},
 			nil,
 		),
-		PendingRequestsPerSecond: prometheus.NewDesc(
+		PendingRequestsPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pending_requests_total"),
 			"Total pending certificate requests processed",
 			[]string{"cert_template"},
 			nil,
 		),
-		RequestCryptographicSigningTime: prometheus.NewDesc(
+		RequestCryptographicSigningTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "request_cryptographic_signing_time_seconds"),
 			"Last time elapsed for signing operation request",
 			[]string{"cert_template"},
 			nil,
 		),
-		RequestPolicyModuleProcessingTime: prometheus.NewDesc(
+		RequestPolicyModuleProcessingTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "request_policy_module_processing_time_seconds"),
 			"Last time elapsed for policy module processing request",
 			[]string{"cert_template"},
 			nil,
 		),
-		ChallengeResponsesPerSecond: prometheus.NewDesc(
+		ChallengeResponsesPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "challenge_responses_total"),
 			"Total certificate challenge responses processed",
 			[]string{"cert_template"},
 			nil,
 		),
-		ChallengeResponseProcessingTime: prometheus.NewDesc(
+		ChallengeResponseProcessingTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "challenge_response_processing_time_seconds"),
 			"Last time elapsed for challenge response",
 			[]string{"cert_template"},
 			nil,
 		),
-		SignedCertificateTimestampListsPerSecond: prometheus.NewDesc(
+		SignedCertificateTimestampListsPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "signed_certificate_timestamp_lists_total"),
 			"Total Signed Certificate Timestamp Lists processed",
 			[]string{"cert_template"},
 			nil,
 		),
-		SignedCertificateTimestampListProcessingTime: prometheus.NewDesc(
+		SignedCertificateTimestampListProcessingTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "signed_certificate_timestamp_list_processing_time_seconds"),
 			"Last time elapsed for Signed Certificate Timestamp List",
 			[]string{"cert_template"},
@@ -115,6 +121,23 @@ func adcsCollectorMethod() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *adcsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.RequestsPerSecond
+	ch <- c.RequestProcessingTime
+	ch <- c.RetrievalsPerSecond
+	ch <- c.RetrievalProcessingTime
+	ch <- c.FailedRequestsPerSecond
+	ch <- c.IssuedRequestsPerSecond
+	ch <- c.PendingRequestsPerSecond
+	ch <- c.RequestCryptographicSigningTime
+	ch <- c.RequestPolicyModuleProcessingTime
+	ch <- c.ChallengeResponsesPerSecond
+	ch <- c.ChallengeResponseProcessingTime
+	ch <- c.SignedCertificateTimestampListsPerSecond
+	ch <- c.SignedCertificateTimestampListProcessingTime
+}
+
 func (c *adcsCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	if desc, err := c.collectADCSCounters(ctx, ch); err != nil {
 		log.Error("Failed collecting ADCS Metrics:", desc, err)
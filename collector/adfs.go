@@ -10,6 +10,12 @@ import (
 
 func init() {
 	registerCollector("adfs", newADFSCollector, "AD FS")
+	registerCollectorInfo("adfs", CollectorInfo{
+		Description: "Active Directory Federation Services",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "Active Directory Federation Services role",
+	})
+	registerApplicabilityCheck("adfs", perflibObjectsPresent("AD FS"))
 }
 
 type adfsCollector struct {
@@ -63,259 +69,259 @@ func newADFSCollector() (Collector, error) {
 	const subsystem = "adfs"
 
 	return &adfsCollector{
-		adLoginConnectionFailures: prometheus.NewDesc(
+		adLoginConnectionFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ad_login_connection_failures_total"),
 			"Total number of connection failures to an Active Directory domain controller",
 			nil,
 			nil,
 		),
-		certificateAuthentications: prometheus.NewDesc(
+		certificateAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "certificate_authentications_total"),
 			"Total number of User Certificate authentications",
 			nil,
 			nil,
 		),
-		deviceAuthentications: prometheus.NewDesc(
+		deviceAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "device_authentications_total"),
 			"Total number of Device authentications",
 			nil,
 			nil,
 		),
-		extranetAccountLockouts: prometheus.NewDesc(
+		extranetAccountLockouts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "extranet_account_lockouts_total"),
 			"Total number of Extranet Account Lockouts",
 			nil,
 			nil,
 		),
-		federatedAuthentications: prometheus.NewDesc(
+		federatedAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "federated_authentications_total"),
 			"Total number of authentications from a federated source",
 			nil,
 			nil,
 		),
-		passportAuthentications: prometheus.NewDesc(
+		passportAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "passport_authentications_total"),
 			"Total number of Microsoft Passport SSO authentications",
 			nil,
 			nil,
 		),
-		passiveRequests: prometheus.NewDesc(
+		passiveRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "passive_requests_total"),
 			"Total number of passive (browser-based) requests",
 			nil,
 			nil,
 		),
-		passwordChangeFailed: prometheus.NewDesc(
+		passwordChangeFailed: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "password_change_failed_total"),
 			"Total number of failed password changes",
 			nil,
 			nil,
 		),
-		passwordChangeSucceeded: prometheus.NewDesc(
+		passwordChangeSucceeded: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "password_change_succeeded_total"),
 			"Total number of successful password changes",
 			nil,
 			nil,
 		),
-		tokenRequests: prometheus.NewDesc(
+		tokenRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "token_requests_total"),
 			"Total number of token requests",
 			nil,
 			nil,
 		),
-		windowsIntegratedAuthentications: prometheus.NewDesc(
+		windowsIntegratedAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "windows_integrated_authentications_total"),
 			"Total number of Windows integrated authentications (Kerberos/NTLM)",
 			nil,
 			nil,
 		),
-		oAuthAuthZRequests: prometheus.NewDesc(
+		oAuthAuthZRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_authorization_requests_total"),
 			"Total number of incoming requests to the OAuth Authorization endpoint",
 			nil,
 			nil,
 		),
-		oAuthClientAuthentications: prometheus.NewDesc(
+		oAuthClientAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_authentication_success_total"),
 			"Total number of successful OAuth client Authentications",
 			nil,
 			nil,
 		),
-		oAuthClientAuthenticationsFailures: prometheus.NewDesc(
+		oAuthClientAuthenticationsFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_authentication_failure_total"),
 			"Total number of failed OAuth client Authentications",
 			nil,
 			nil,
 		),
-		oAuthClientCredentialsRequestFailures: prometheus.NewDesc(
+		oAuthClientCredentialsRequestFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_credentials_failure_total"),
 			"Total number of failed OAuth Client Credentials Requests",
 			nil,
 			nil,
 		),
-		oAuthClientCredentialsRequests: prometheus.NewDesc(
+		oAuthClientCredentialsRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_credentials_success_total"),
 			"Total number of successful RP tokens issued for OAuth Client Credentials Requests",
 			nil,
 			nil,
 		),
-		oAuthClientPrivateKeyJwtAuthenticationFailures: prometheus.NewDesc(
+		oAuthClientPrivateKeyJwtAuthenticationFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_privkey_jtw_authentication_failure_total"),
 			"Total number of failed OAuth Client Private Key Jwt Authentications",
 			nil,
 			nil,
 		),
-		oAuthClientPrivateKeyJwtAuthentications: prometheus.NewDesc(
+		oAuthClientPrivateKeyJwtAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_privkey_jwt_authentications_success_total"),
 			"Total number of successful OAuth Client Private Key Jwt Authentications",
 			nil,
 			nil,
 		),
-		oAuthClientSecretBasicAuthenticationFailures: prometheus.NewDesc(
+		oAuthClientSecretBasicAuthenticationFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_secret_basic_authentications_failure_total"),
 			"Total number of failed OAuth Client Secret Basic Authentications",
 			nil,
 			nil,
 		),
-		oAuthClientSecretBasicAuthentications: prometheus.NewDesc(
+		oAuthClientSecretBasicAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_secret_basic_authentications_success_total"),
 			"Total number of successful OAuth Client Secret Basic Authentications",
 			nil,
 			nil,
 		),
-		oAuthClientSecretPostAuthenticationFailures: prometheus.NewDesc(
+		oAuthClientSecretPostAuthenticationFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_secret_post_authentications_failure_total"),
 			"Total number of failed OAuth Client Secret Post Authentications",
 			nil,
 			nil,
 		),
-		oAuthClientSecretPostAuthentications: prometheus.NewDesc(
+		oAuthClientSecretPostAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_secret_post_authentications_success_total"),
 			"Total number of successful OAuth Client Secret Post Authentications",
 			nil,
 			nil,
 		),
-		oAuthClientWindowsIntegratedAuthenticationFailures: prometheus.NewDesc(
+		oAuthClientWindowsIntegratedAuthenticationFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_windows_authentications_failure_total"),
 			"Total number of failed OAuth Client Windows Integrated Authentications",
 			nil,
 			nil,
 		),
-		oAuthClientWindowsIntegratedAuthentications: prometheus.NewDesc(
+		oAuthClientWindowsIntegratedAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_client_windows_authentications_success_total"),
 			"Total number of successful OAuth Client Windows Integrated Authentications",
 			nil,
 			nil,
 		),
-		oAuthLogonCertificateRequestFailures: prometheus.NewDesc(
+		oAuthLogonCertificateRequestFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_logon_certificate_requests_failure_total"),
 			"Total number of failed OAuth Logon Certificate Requests",
 			nil,
 			nil,
 		),
-		oAuthLogonCertificateTokenRequests: prometheus.NewDesc(
+		oAuthLogonCertificateTokenRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_logon_certificate_token_requests_success_total"),
 			"Total number of successful RP tokens issued for OAuth Logon Certificate Requests",
 			nil,
 			nil,
 		),
-		oAuthPasswordGrantRequestFailures: prometheus.NewDesc(
+		oAuthPasswordGrantRequestFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_password_grant_requests_failure_total"),
 			"Total number of failed OAuth Password Grant Requests",
 			nil,
 			nil,
 		),
-		oAuthPasswordGrantRequests: prometheus.NewDesc(
+		oAuthPasswordGrantRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_password_grant_requests_success_total"),
 			"Total number of successful OAuth Password Grant Requests",
 			nil,
 			nil,
 		),
-		oAuthTokenRequests: prometheus.NewDesc(
+		oAuthTokenRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "oauth_token_requests_success_total"),
 			"Total number of successful RP tokens issued over OAuth protocol",
 			nil,
 			nil,
 		),
-		samlPTokenRequests: prometheus.NewDesc(
+		samlPTokenRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "samlp_token_requests_success_total"),
 			"Total number of successful RP tokens issued over SAML-P protocol",
 			nil,
 			nil,
 		),
-		ssoAuthenticationFailures: prometheus.NewDesc(
+		ssoAuthenticationFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sso_authentications_failure_total"),
 			"Total number of failed SSO authentications",
 			nil,
 			nil,
 		),
-		ssoAuthentications: prometheus.NewDesc(
+		ssoAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sso_authentications_success_total"),
 			"Total number of successful SSO authentications",
 			nil,
 			nil,
 		),
-		wsfedTokenRequests: prometheus.NewDesc(
+		wsfedTokenRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "wsfed_token_requests_success_total"),
 			"Total number of successful RP tokens issued over WS-Fed protocol",
 			nil,
 			nil,
 		),
-		wstrustTokenRequests: prometheus.NewDesc(
+		wstrustTokenRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "wstrust_token_requests_success_total"),
 			"Total number of successful RP tokens issued over WS-Trust protocol",
 			nil,
 			nil,
 		),
-		upAuthenticationFailures: prometheus.NewDesc(
+		upAuthenticationFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "userpassword_authentications_failure_total"),
 			"Total number of failed AD U/P authentications",
 			nil,
 			nil,
 		),
-		upAuthentications: prometheus.NewDesc(
+		upAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "userpassword_authentications_success_total"),
 			"Total number of successful AD U/P authentications",
 			nil,
 			nil,
 		),
-		externalAuthenticationFailures: prometheus.NewDesc(
+		externalAuthenticationFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "external_authentications_failure_total"),
 			"Total number of failed authentications from external MFA providers",
 			nil,
 			nil,
 		),
-		externalAuthentications: prometheus.NewDesc(
+		externalAuthentications: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "external_authentications_success_total"),
 			"Total number of successful authentications from external MFA providers",
 			nil,
 			nil,
 		),
-		artifactDBFailures: prometheus.NewDesc(
+		artifactDBFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "db_artifact_failure_total"),
 			"Total number of failures connecting to the artifact database",
 			nil,
 			nil,
 		),
-		avgArtifactDBQueryTime: prometheus.NewDesc(
+		avgArtifactDBQueryTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "db_artifact_query_time_seconds_total"),
 			"Accumulator of time taken for an artifact database query",
 			nil,
 			nil,
 		),
-		configDBFailures: prometheus.NewDesc(
+		configDBFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "db_config_failure_total"),
 			"Total number of failures connecting to the configuration database",
 			nil,
 			nil,
 		),
-		avgConfigDBQueryTime: prometheus.NewDesc(
+		avgConfigDBQueryTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "db_config_query_time_seconds_total"),
 			"Accumulator of time taken for a configuration database query",
 			nil,
 			nil,
 		),
-		federationMetadataRequests: prometheus.NewDesc(
+		federationMetadataRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "federation_metadata_requests_total"),
 			"Total number of Federation Metadata requests",
 			nil,
@@ -370,6 +376,53 @@ type perflibADFS struct {
 	FederationMetadataRequests           float64 `perflib:"Federation Metadata Requests"`
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *adfsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.adLoginConnectionFailures
+	ch <- c.certificateAuthentications
+	ch <- c.deviceAuthentications
+	ch <- c.extranetAccountLockouts
+	ch <- c.federatedAuthentications
+	ch <- c.passportAuthentications
+	ch <- c.passiveRequests
+	ch <- c.passwordChangeFailed
+	ch <- c.passwordChangeSucceeded
+	ch <- c.tokenRequests
+	ch <- c.windowsIntegratedAuthentications
+	ch <- c.oAuthAuthZRequests
+	ch <- c.oAuthClientAuthentications
+	ch <- c.oAuthClientAuthenticationsFailures
+	ch <- c.oAuthClientCredentialsRequestFailures
+	ch <- c.oAuthClientCredentialsRequests
+	ch <- c.oAuthClientPrivateKeyJwtAuthenticationFailures
+	ch <- c.oAuthClientPrivateKeyJwtAuthentications
+	ch <- c.oAuthClientSecretBasicAuthenticationFailures
+	ch <- c.oAuthClientSecretBasicAuthentications
+	ch <- c.oAuthClientSecretPostAuthenticationFailures
+	ch <- c.oAuthClientSecretPostAuthentications
+	ch <- c.oAuthClientWindowsIntegratedAuthenticationFailures
+	ch <- c.oAuthClientWindowsIntegratedAuthentications
+	ch <- c.oAuthLogonCertificateRequestFailures
+	ch <- c.oAuthLogonCertificateTokenRequests
+	ch <- c.oAuthPasswordGrantRequestFailures
+	ch <- c.oAuthPasswordGrantRequests
+	ch <- c.oAuthTokenRequests
+	ch <- c.samlPTokenRequests
+	ch <- c.ssoAuthenticationFailures
+	ch <- c.ssoAuthentications
+	ch <- c.wsfedTokenRequests
+	ch <- c.wstrustTokenRequests
+	ch <- c.upAuthenticationFailures
+	ch <- c.upAuthentications
+	ch <- c.externalAuthenticationFailures
+	ch <- c.externalAuthentications
+	ch <- c.artifactDBFailures
+	ch <- c.avgArtifactDBQueryTime
+	ch <- c.configDBFailures
+	ch <- c.avgConfigDBQueryTime
+	ch <- c.federationMetadataRequests
+}
+
 func (c *adfsCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	var adfsData []perflibADFS
 	err := unmarshalObject(ctx.perfObjects["AD FS"], &adfsData)
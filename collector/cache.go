@@ -10,6 +10,10 @@ import (
 
 func init() {
 	registerCollector("cache", newCacheCollector, "Cache")
+	registerCollectorInfo("cache", CollectorInfo{
+		Description: "Cache metrics",
+		Sources:     []DataSource{SourcePerflib},
+	})
 }
 
 // A CacheCollector is a Prometheus collector for Perflib Cache metrics
@@ -49,175 +53,175 @@ type CacheCollector struct {
 func newCacheCollector() (Collector, error) {
 	const subsystem = "cache"
 	return &CacheCollector{
-		AsyncCopyReadsTotal: prometheus.NewDesc(
+		AsyncCopyReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "async_copy_reads_total"),
 			"(AsyncCopyReadsTotal)",
 			nil,
 			nil,
 		),
-		AsyncDataMapsTotal: prometheus.NewDesc(
+		AsyncDataMapsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "async_data_maps_total"),
 			"(AsyncDataMapsTotal)",
 			nil,
 			nil,
 		),
-		AsyncFastReadsTotal: prometheus.NewDesc(
+		AsyncFastReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "async_fast_reads_total"),
 			"(AsyncFastReadsTotal)",
 			nil,
 			nil,
 		),
-		AsyncMDLReadsTotal: prometheus.NewDesc(
+		AsyncMDLReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "async_mdl_reads_total"),
 			"(AsyncMDLReadsTotal)",
 			nil,
 			nil,
 		),
-		AsyncPinReadsTotal: prometheus.NewDesc(
+		AsyncPinReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "async_pin_reads_total"),
 			"(AsyncPinReadsTotal)",
 			nil,
 			nil,
 		),
-		CopyReadHitsTotal: prometheus.NewDesc(
+		CopyReadHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "copy_read_hits_total"),
 			"(CopyReadHitsTotal)",
 			nil,
 			nil,
 		),
-		CopyReadsTotal: prometheus.NewDesc(
+		CopyReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "copy_reads_total"),
 			"(CopyReadsTotal)",
 			nil,
 			nil,
 		),
-		DataFlushesTotal: prometheus.NewDesc(
+		DataFlushesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "data_flushes_total"),
 			"(DataFlushesTotal)",
 			nil,
 			nil,
 		),
-		DataFlushPagesTotal: prometheus.NewDesc(
+		DataFlushPagesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "data_flush_pages_total"),
 			"(DataFlushPagesTotal)",
 			nil,
 			nil,
 		),
-		DataMapHitsPercent: prometheus.NewDesc(
+		DataMapHitsPercent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "data_map_hits_percent"),
 			"(DataMapHitsPercent)",
 			nil,
 			nil,
 		),
-		DataMapPinsTotal: prometheus.NewDesc(
+		DataMapPinsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "data_map_pins_total"),
 			"(DataMapPinsTotal)",
 			nil,
 			nil,
 		),
-		DataMapsTotal: prometheus.NewDesc(
+		DataMapsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "data_maps_total"),
 			"(DataMapsTotal)",
 			nil,
 			nil,
 		),
-		DirtyPages: prometheus.NewDesc(
+		DirtyPages: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dirty_pages"),
 			"(DirtyPages)",
 			nil,
 			nil,
 		),
-		DirtyPageThreshold: prometheus.NewDesc(
+		DirtyPageThreshold: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dirty_page_threshold"),
 			"(DirtyPageThreshold)",
 			nil,
 			nil,
 		),
-		FastReadNotPossiblesTotal: prometheus.NewDesc(
+		FastReadNotPossiblesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "fast_read_not_possibles_total"),
 			"(FastReadNotPossiblesTotal)",
 			nil,
 			nil,
 		),
-		FastReadResourceMissesTotal: prometheus.NewDesc(
+		FastReadResourceMissesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "fast_read_resource_misses_total"),
 			"(FastReadResourceMissesTotal)",
 			nil,
 			nil,
 		),
-		FastReadsTotal: prometheus.NewDesc(
+		FastReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "fast_reads_total"),
 			"(FastReadsTotal)",
 			nil,
 			nil,
 		),
-		LazyWriteFlushesTotal: prometheus.NewDesc(
+		LazyWriteFlushesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "lazy_write_flushes_total"),
 			"(LazyWriteFlushesTotal)",
 			nil,
 			nil,
 		),
-		LazyWritePagesTotal: prometheus.NewDesc(
+		LazyWritePagesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "lazy_write_pages_total"),
 			"(LazyWritePagesTotal)",
 			nil,
 			nil,
 		),
-		MDLReadHitsTotal: prometheus.NewDesc(
+		MDLReadHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mdl_read_hits_total"),
 			"(MDLReadHitsTotal)",
 			nil,
 			nil,
 		),
-		MDLReadsTotal: prometheus.NewDesc(
+		MDLReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mdl_reads_total"),
 			"(MDLReadsTotal)",
 			nil,
 			nil,
 		),
-		PinReadHitsTotal: prometheus.NewDesc(
+		PinReadHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pin_read_hits_total"),
 			"(PinReadHitsTotal)",
 			nil,
 			nil,
 		),
-		PinReadsTotal: prometheus.NewDesc(
+		PinReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pin_reads_total"),
 			"(PinReadsTotal)",
 			nil,
 			nil,
 		),
-		ReadAheadsTotal: prometheus.NewDesc(
+		ReadAheadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "read_aheads_total"),
 			"(ReadAheadsTotal)",
 			nil,
 			nil,
 		),
-		SyncCopyReadsTotal: prometheus.NewDesc(
+		SyncCopyReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sync_copy_reads_total"),
 			"(SyncCopyReadsTotal)",
 			nil,
 			nil,
 		),
-		SyncDataMapsTotal: prometheus.NewDesc(
+		SyncDataMapsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sync_data_maps_total"),
 			"(SyncDataMapsTotal)",
 			nil,
 			nil,
 		),
-		SyncFastReadsTotal: prometheus.NewDesc(
+		SyncFastReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sync_fast_reads_total"),
 			"(SyncFastReadsTotal)",
 			nil,
 			nil,
 		),
-		SyncMDLReadsTotal: prometheus.NewDesc(
+		SyncMDLReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sync_mdl_reads_total"),
 			"(SyncMDLReadsTotal)",
 			nil,
 			nil,
 		),
-		SyncPinReadsTotal: prometheus.NewDesc(
+		SyncPinReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sync_pin_reads_total"),
 			"(SyncPinReadsTotal)",
 			nil,
@@ -226,6 +230,39 @@ func newCacheCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *CacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.AsyncCopyReadsTotal
+	ch <- c.AsyncDataMapsTotal
+	ch <- c.AsyncFastReadsTotal
+	ch <- c.AsyncMDLReadsTotal
+	ch <- c.AsyncPinReadsTotal
+	ch <- c.CopyReadHitsTotal
+	ch <- c.CopyReadsTotal
+	ch <- c.DataFlushesTotal
+	ch <- c.DataFlushPagesTotal
+	ch <- c.DataMapHitsPercent
+	ch <- c.DataMapPinsTotal
+	ch <- c.DataMapsTotal
+	ch <- c.DirtyPages
+	ch <- c.DirtyPageThreshold
+	ch <- c.FastReadNotPossiblesTotal
+	ch <- c.FastReadResourceMissesTotal
+	ch <- c.FastReadsTotal
+	ch <- c.LazyWriteFlushesTotal
+	ch <- c.LazyWritePagesTotal
+	ch <- c.MDLReadHitsTotal
+	ch <- c.MDLReadsTotal
+	ch <- c.PinReadHitsTotal
+	ch <- c.PinReadsTotal
+	ch <- c.ReadAheadsTotal
+	ch <- c.SyncCopyReadsTotal
+	ch <- c.SyncDataMapsTotal
+	ch <- c.SyncFastReadsTotal
+	ch <- c.SyncMDLReadsTotal
+	ch <- c.SyncPinReadsTotal
+}
+
 // Collect implements the Collector interface
 func (c *CacheCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	if desc, err := c.collect(ctx, ch); err != nil {
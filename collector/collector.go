@@ -1,14 +1,21 @@
 package collector
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/leoluk/perflib_exporter/perflib"
+	"github.com/prometheus-community/windows_exporter/config"
+	"github.com/prometheus-community/windows_exporter/headers/sysinfoapi"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/registry"
 )
 
@@ -50,14 +57,122 @@ func getWindowsVersion() float64 {
 	return currentv_flt
 }
 
+var (
+	bootTimeOnce  sync.Once
+	bootTimeValue time.Time
+)
+
+// bootTime returns the time the system was started, to the second. Counters
+// which reset when the system restarts use it as their created timestamp. It
+// is computed once, so that it does not jitter between scrapes.
+func bootTime() time.Time {
+	bootTimeOnce.Do(func() {
+		uptime := time.Duration(sysinfoapi.GetTickCount64()) * time.Millisecond
+		bootTimeValue = time.Now().Add(-uptime).Round(time.Second)
+	})
+	return bootTimeValue
+}
+
 type collectorBuilder func() (Collector, error)
 
+// settingsBuilder builds a collector which has settings. It reads them from
+// its section of settings, which are parsed anew on every configuration
+// reload, rather than from the variables of its flags.
+type settingsBuilder func(settings *config.Config) (Collector, error)
+
 var (
-	builders                = make(map[string]collectorBuilder)
+	builders                = make(map[string]settingsBuilder)
 	perfCounterDependencies = make(map[string]string)
+	perfCounterObjects      = make(map[string][]string)
+	applicabilityChecks     = make(map[string]applicabilityCheck)
+	collectorInfos          = make(map[string]CollectorInfo)
 )
 
+// ErrorClass is the kind of failure of a collector.
+type ErrorClass string
+
+const (
+	ErrorClassWMI     ErrorClass = "wmi"
+	ErrorClassPerflib ErrorClass = "perflib"
+	ErrorClassTimeout ErrorClass = "timeout"
+	ErrorClassParse   ErrorClass = "parse"
+	ErrorClassOther   ErrorClass = "other"
+)
+
+// ErrorClasses lists every ErrorClass.
+var ErrorClasses = []ErrorClass{ErrorClassWMI, ErrorClassPerflib, ErrorClassTimeout, ErrorClassParse, ErrorClassOther}
+
+// classifiedError tags an error with the class of the failure which caused it.
+type classifiedError struct {
+	class ErrorClass
+	err   error
+}
+
+func (e classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e classifiedError) Unwrap() error {
+	return e.err
+}
+
+// ClassifyError returns the class of an error returned by a collector.
+func ClassifyError(err error) ErrorClass {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return ErrorClassTimeout
+	}
+	var classified classifiedError
+	if errors.As(err, &classified) {
+		return classified.class
+	}
+	var numErr *strconv.NumError
+	var timeErr *time.ParseError
+	if errors.As(err, &numErr) || errors.As(err, &timeErr) {
+		return ErrorClassParse
+	}
+	return ErrorClassOther
+}
+
+// DataSource is an API a collector reads its metrics from.
+type DataSource string
+
+// Data sources used by collectors.
+const (
+	SourcePerflib DataSource = "perflib"
+	SourceWMI     DataSource = "wmi"
+	SourceCOM     DataSource = "com"
+	SourceHCS     DataSource = "hcs"
+	SourceWin32   DataSource = "win32"
+	SourceFile    DataSource = "file"
+)
+
+// CollectorInfo describes what a collector does and what it needs to work.
+type CollectorInfo struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Sources     []DataSource `json:"sources"`
+	// Requires names the OS version, server role or software the collector
+	// needs, if any.
+	Requires string `json:"requires,omitempty"`
+	// Expensive collectors take long or noticeably load the host on each run.
+	Expensive bool `json:"expensive"`
+	// Metrics lists the metric families the collector emits. It is only known
+	// for collectors which have been built and implement Describer, see
+	// MetricFamilies.
+	Metrics []string `json:"metrics,omitempty"`
+}
+
+// applicabilityCheck reports whether a collector applies to this host, e.g.
+// whether the server role it collects metrics for is installed.
+type applicabilityCheck func() (bool, error)
+
 func registerCollector(name string, builder collectorBuilder, perfCounterNames ...string) {
+	registerCollectorWithSettings(name, func(*config.Config) (Collector, error) {
+		return builder()
+	}, perfCounterNames...)
+}
+
+func registerCollectorWithSettings(name string, builder settingsBuilder, perfCounterNames ...string) {
 	builders[name] = builder
 	addPerfCounterDependencies(name, perfCounterNames)
 }
@@ -68,6 +183,161 @@ func addPerfCounterDependencies(name string, perfCounterNames []string) {
 		perfIndicies = append(perfIndicies, MapCounterToIndex(cn))
 	}
 	perfCounterDependencies[name] = strings.Join(perfIndicies, " ")
+	perfCounterObjects[name] = append(perfCounterObjects[name], perfCounterNames...)
+}
+
+// PerflibObjects returns the names of the perflib objects the named collector
+// reads.
+func PerflibObjects(name string) []string {
+	return perfCounterObjects[name]
+}
+
+func registerCollectorInfo(name string, info CollectorInfo) {
+	info.Name = name
+	collectorInfos[name] = info
+}
+
+// Info returns the description of the named collector.
+func Info(name string) (CollectorInfo, bool) {
+	info, ok := collectorInfos[name]
+	return info, ok
+}
+
+// descNames holds the fully-qualified metric name of the descriptors built by
+// newDesc, which prometheus.Desc does not otherwise expose.
+var descNames = struct {
+	sync.RWMutex
+	names map[*prometheus.Desc]string
+}{names: make(map[*prometheus.Desc]string)}
+
+// newDesc is prometheus.NewDesc, recording the name of the descriptor for
+// DescName. Collectors build their descriptors with it when they are built,
+// not on every scrape.
+func newDesc(fqName, help string, variableLabels []string, constLabels prometheus.Labels) *prometheus.Desc {
+	desc := prometheus.NewDesc(fqName, help, variableLabels, constLabels)
+	descNames.Lock()
+	descNames.names[desc] = fqName
+	descNames.Unlock()
+	return desc
+}
+
+// DescName returns the fully-qualified metric name of a descriptor built by a
+// collector, or "" for other descriptors.
+func DescName(desc *prometheus.Desc) string {
+	descNames.RLock()
+	defer descNames.RUnlock()
+	return descNames.names[desc]
+}
+
+// namedMetric is a metric whose descriptor is built at scrape time, and thus
+// not known to DescName, such as those of the textfile collector.
+type namedMetric struct {
+	prometheus.Metric
+	name string
+}
+
+// MetricName returns the name of the metric family m belongs to, or "" if it
+// was not sent by a collector.
+func MetricName(m prometheus.Metric) string {
+	if named, ok := m.(namedMetric); ok {
+		return named.name
+	}
+	return DescName(m.Desc())
+}
+
+// Describer is implemented by collectors whose metric families are known
+// before they run. Collectors creating their descriptors at scrape time, such
+// as textfile, only describe the metrics they emit about themselves.
+type Describer interface {
+	// Describe sends the descriptors of the metrics the collector emits.
+	Describe(ch chan<- *prometheus.Desc)
+}
+
+// MetricFamilies returns the sorted names of the metric families described by
+// a built collector, or nil if it does not implement Describer.
+func MetricFamilies(c Collector) []string {
+	d, ok := c.(Describer)
+	if !ok {
+		return nil
+	}
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		d.Describe(ch)
+		close(ch)
+	}()
+
+	var families []string
+	for desc := range ch {
+		if desc == nil {
+			continue
+		}
+		if name := DescName(desc); name != "" {
+			families = append(families, name)
+		}
+	}
+	sort.Strings(families)
+	return families
+}
+
+// registerApplicabilityCheck makes a role-specific collector take part in
+// auto-detection.
+func registerApplicabilityCheck(name string, check applicabilityCheck) {
+	applicabilityChecks[name] = check
+}
+
+// Applicable reports whether the named collector applies to this host. ok is
+// false for collectors without an applicability check.
+func Applicable(name string) (applies bool, ok bool, err error) {
+	check, ok := applicabilityChecks[name]
+	if !ok {
+		return false, false, nil
+	}
+	applies, err = check()
+	return applies, true, err
+}
+
+// Detect returns the sorted names of the role-specific collectors which apply
+// to this host. Collectors without an applicability check are never returned.
+func Detect() []string {
+	detected := make([]string, 0, len(applicabilityChecks))
+	for name := range applicabilityChecks {
+		applies, _, err := Applicable(name)
+		if err != nil {
+			log.Warnf("Couldn't detect whether collector %s applies: %v", name, err)
+			continue
+		}
+		if applies {
+			detected = append(detected, name)
+		}
+	}
+	sort.Strings(detected)
+	return detected
+}
+
+// serviceInstalled returns an applicabilityCheck reporting whether the named
+// Windows service exists.
+func serviceInstalled(name string) applicabilityCheck {
+	return func() (bool, error) {
+		m, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_CONNECT)
+		if err != nil {
+			return false, err
+		}
+		defer windows.CloseServiceHandle(m) //nolint:errcheck
+
+		serviceName, err := windows.UTF16PtrFromString(name)
+		if err != nil {
+			return false, err
+		}
+		s, err := windows.OpenService(m, serviceName, windows.SERVICE_QUERY_STATUS)
+		if err == windows.ERROR_SERVICE_DOES_NOT_EXIST {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		windows.CloseServiceHandle(s) //nolint:errcheck
+		return true, nil
+	}
 }
 
 func Available() []string {
@@ -77,12 +347,14 @@ func Available() []string {
 	}
 	return cs
 }
-func Build(collector string) (Collector, error) {
+
+// Build builds the named collector with settings.
+func Build(collector string, settings *config.Config) (Collector, error) {
 	builder, exists := builders[collector]
 	if !exists {
 		return nil, fmt.Errorf("Unknown collector %q", collector)
 	}
-	return builder()
+	return builder(settings)
 }
 func getPerfQuery(collectors []string) string {
 	parts := make([]string, 0, len(collectors))
@@ -101,18 +373,73 @@ type Collector interface {
 }
 
 type ScrapeContext struct {
+	ctx         context.Context
 	perfObjects map[string]*perflib.PerfObject
+	// queries records the WMI queries run through the ScrapeContext, if set.
+	queries *queryLog
+	// collector is the collector the queries run through the ScrapeContext
+	// are counted under by OrphanedQueries.
+	collector string
 }
 
-// PrepareScrapeContext creates a ScrapeContext to be used during a single scrape
-func PrepareScrapeContext(collectors []string) (*ScrapeContext, error) {
+type queryLog struct {
+	sync.Mutex
+	queries []string
+}
+
+// PrepareScrapeContext creates a ScrapeContext to be used during a single scrape.
+// The given context bounds the scrape: once it is done, pending perflib and WMI
+// queries made through the ScrapeContext return early with the context error.
+func PrepareScrapeContext(ctx context.Context, collectors []string) (*ScrapeContext, error) {
 	q := getPerfQuery(collectors) // TODO: Memoize
-	objs, err := getPerflibSnapshot(q)
+	objs, err := getPerflibSnapshot(ctx, q)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ScrapeContext{objs}, nil
+	return &ScrapeContext{ctx: ctx, perfObjects: objs}, nil
+}
+
+// Context returns the context bounding the scrape.
+func (s *ScrapeContext) Context() context.Context {
+	return s.ctx
+}
+
+// WithQueryLog returns a copy of s which records the WMI queries run through it,
+// and a function returning the queries recorded so far.
+func (s *ScrapeContext) WithQueryLog() (*ScrapeContext, func() []string) {
+	l := &queryLog{}
+	queries := func() []string {
+		l.Lock()
+		defer l.Unlock()
+		return append([]string(nil), l.queries...)
+	}
+	if s == nil {
+		return nil, queries
+	}
+	c := *s
+	c.queries = l
+	return &c, queries
+}
+
+// ForCollector returns a copy of s whose queries are counted under the named
+// collector.
+func (s *ScrapeContext) ForCollector(name string) *ScrapeContext {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.collector = name
+	return &c
+}
+
+func (s *ScrapeContext) recordQuery(query string) {
+	if s.queries == nil {
+		return
+	}
+	s.queries.Lock()
+	s.queries.queries = append(s.queries.queries, query)
+	s.queries.Unlock()
 }
 func boolToFloat(b bool) float64 {
 	if b {
@@ -1,9 +1,16 @@
 package collector
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -35,10 +42,29 @@ func TestExpandChildCollectors(t *testing.T) {
 	}
 }
 
+func TestClassifyError(t *testing.T) {
+	_, numErr := strconv.ParseFloat("x", 64)
+	cases := []struct {
+		err      error
+		expected ErrorClass
+	}{
+		{classifiedError{ErrorClassWMI, errors.New("access denied")}, ErrorClassWMI},
+		{fmt.Errorf("wrapped: %w", classifiedError{ErrorClassPerflib, errors.New("counter not found")}), ErrorClassPerflib},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), ErrorClassTimeout},
+		{numErr, ErrorClassParse},
+		{errors.New("unknown"), ErrorClassOther},
+	}
+	for _, c := range cases {
+		if class := ClassifyError(c.err); class != c.expected {
+			t.Errorf("expected %q to be classified as %s, got %s", c.err, c.expected, class)
+		}
+	}
+}
+
 func benchmarkCollector(b *testing.B, name string, collectFunc func() (Collector, error)) {
 	// Create perflib scrape context. Some perflib collectors required a correct context,
 	// or will fail during benchmark.
-	scrapeContext, err := PrepareScrapeContext([]string{name})
+	scrapeContext, err := PrepareScrapeContext(context.Background(), []string{name})
 	if err != nil {
 		b.Error(err)
 	}
@@ -58,3 +84,78 @@ func benchmarkCollector(b *testing.B, name string, collectFunc func() (Collector
 		c.Collect(scrapeContext, metrics) //nolint:errcheck
 	}
 }
+
+// withSettings returns a function building a collector from the defaults of
+// the flags, overridden by args.
+func withSettings(tb testing.TB, builder settingsBuilder, args ...string) func() (Collector, error) {
+	return func() (Collector, error) {
+		settings, err := config.Parse(kingpin.CommandLine, config.SaveDefaults(kingpin.CommandLine), nil, args)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		return builder(settings)
+	}
+}
+
+func TestFlagsHaveSettings(t *testing.T) {
+	settings := make(map[string]bool)
+	for _, flag := range config.Flags() {
+		settings[flag] = true
+	}
+	for _, f := range kingpin.CommandLine.Model().Flags {
+		if strings.HasPrefix(f.Name, "help") || strings.HasPrefix(f.Name, "completion-") {
+			continue
+		}
+		if !settings[f.Name] {
+			t.Errorf("flag --%s has no setting in config.Config", f.Name)
+		}
+	}
+}
+
+type describedCollector struct {
+	requests *prometheus.Desc
+	Errors   *prometheus.Desc
+}
+
+func (c *describedCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.requests
+	ch <- c.Errors
+}
+
+func (c *describedCollector) Collect(_ *ScrapeContext, _ chan<- prometheus.Metric) error {
+	return nil
+}
+
+type undescribedCollector struct{}
+
+func (undescribedCollector) Collect(_ *ScrapeContext, _ chan<- prometheus.Metric) error {
+	return nil
+}
+
+func TestMetricFamilies(t *testing.T) {
+	c := &describedCollector{
+		requests: newDesc("windows_test_requests_total", "Requests.", nil, nil),
+		Errors:   newDesc("windows_test_errors_total", "Errors.", []string{"code"}, nil),
+	}
+	expected := []string{"windows_test_errors_total", "windows_test_requests_total"}
+	if families := MetricFamilies(c); !reflect.DeepEqual(families, expected) {
+		t.Errorf("expected %v, got %v", expected, families)
+	}
+	if families := MetricFamilies(undescribedCollector{}); families != nil {
+		t.Errorf("expected no families for a collector without Describe, got %v", families)
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	built := prometheus.MustNewConstMetric(newDesc("windows_test_requests_total", "Requests.", nil, nil), prometheus.CounterValue, 1)
+	if name := MetricName(built); name != "windows_test_requests_total" {
+		t.Errorf("expected the name of a built descriptor, got %q", name)
+	}
+	other := prometheus.MustNewConstMetric(prometheus.NewDesc("test_metric", "Test.", nil, nil), prometheus.GaugeValue, 1)
+	if name := MetricName(other); name != "" {
+		t.Errorf("expected no name for a descriptor not built by a collector, got %q", name)
+	}
+	if name := MetricName(namedMetric{Metric: other, name: "test_metric"}); name != "test_metric" {
+		t.Errorf("expected the name of a named metric, got %q", name)
+	}
+}
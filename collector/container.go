@@ -11,6 +11,11 @@ import (
 
 func init() {
 	registerCollector("container", NewContainerMetricsCollector)
+	registerCollectorInfo("container", CollectorInfo{
+		Description: "Container metrics",
+		Sources:     []DataSource{SourceHCS},
+		Requires:    "Containers feature",
+	})
 }
 
 // A ContainerMetricsCollector is a Prometheus collector for containers metrics
@@ -49,109 +54,109 @@ type ContainerMetricsCollector struct {
 func NewContainerMetricsCollector() (Collector, error) {
 	const subsystem = "container"
 	return &ContainerMetricsCollector{
-		ContainerAvailable: prometheus.NewDesc(
+		ContainerAvailable: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "available"),
 			"Available",
 			[]string{"container_id"},
 			nil,
 		),
-		ContainersCount: prometheus.NewDesc(
+		ContainersCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "count"),
 			"Number of containers",
 			nil,
 			nil,
 		),
-		UsageCommitBytes: prometheus.NewDesc(
+		UsageCommitBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memory_usage_commit_bytes"),
 			"Memory Usage Commit Bytes",
 			[]string{"container_id"},
 			nil,
 		),
-		UsageCommitPeakBytes: prometheus.NewDesc(
+		UsageCommitPeakBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memory_usage_commit_peak_bytes"),
 			"Memory Usage Commit Peak Bytes",
 			[]string{"container_id"},
 			nil,
 		),
-		UsagePrivateWorkingSetBytes: prometheus.NewDesc(
+		UsagePrivateWorkingSetBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memory_usage_private_working_set_bytes"),
 			"Memory Usage Private Working Set Bytes",
 			[]string{"container_id"},
 			nil,
 		),
-		RuntimeTotal: prometheus.NewDesc(
+		RuntimeTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cpu_usage_seconds_total"),
 			"Total Run time in Seconds",
 			[]string{"container_id"},
 			nil,
 		),
-		RuntimeUser: prometheus.NewDesc(
+		RuntimeUser: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cpu_usage_seconds_usermode"),
 			"Run Time in User mode in Seconds",
 			[]string{"container_id"},
 			nil,
 		),
-		RuntimeKernel: prometheus.NewDesc(
+		RuntimeKernel: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cpu_usage_seconds_kernelmode"),
 			"Run time in Kernel mode in Seconds",
 			[]string{"container_id"},
 			nil,
 		),
-		BytesReceived: prometheus.NewDesc(
+		BytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "network_receive_bytes_total"),
 			"Bytes Received on Interface",
 			[]string{"container_id", "interface"},
 			nil,
 		),
-		BytesSent: prometheus.NewDesc(
+		BytesSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "network_transmit_bytes_total"),
 			"Bytes Sent on Interface",
 			[]string{"container_id", "interface"},
 			nil,
 		),
-		PacketsReceived: prometheus.NewDesc(
+		PacketsReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "network_receive_packets_total"),
 			"Packets Received on Interface",
 			[]string{"container_id", "interface"},
 			nil,
 		),
-		PacketsSent: prometheus.NewDesc(
+		PacketsSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "network_transmit_packets_total"),
 			"Packets Sent on Interface",
 			[]string{"container_id", "interface"},
 			nil,
 		),
-		DroppedPacketsIncoming: prometheus.NewDesc(
+		DroppedPacketsIncoming: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "network_receive_packets_dropped_total"),
 			"Dropped Incoming Packets on Interface",
 			[]string{"container_id", "interface"},
 			nil,
 		),
-		DroppedPacketsOutgoing: prometheus.NewDesc(
+		DroppedPacketsOutgoing: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "network_transmit_packets_dropped_total"),
 			"Dropped Outgoing Packets on Interface",
 			[]string{"container_id", "interface"},
 			nil,
 		),
-		ReadCountNormalized: prometheus.NewDesc(
+		ReadCountNormalized: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "storage_read_count_normalized_total"),
 			"Read Count Normalized",
 			[]string{"container_id"},
 			nil,
 		),
-		ReadSizeBytes: prometheus.NewDesc(
+		ReadSizeBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "storage_read_size_bytes_total"),
 			"Read Size Bytes",
 			[]string{"container_id"},
 			nil,
 		),
-		WriteCountNormalized: prometheus.NewDesc(
+		WriteCountNormalized: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "storage_write_count_normalized_total"),
 			"Write Count Normalized",
 			[]string{"container_id"},
 			nil,
 		),
-		WriteSizeBytes: prometheus.NewDesc(
+		WriteSizeBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "storage_write_size_bytes_total"),
 			"Write Size Bytes",
 			[]string{"container_id"},
@@ -160,6 +165,28 @@ func NewContainerMetricsCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *ContainerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ContainerAvailable
+	ch <- c.ContainersCount
+	ch <- c.UsageCommitBytes
+	ch <- c.UsageCommitPeakBytes
+	ch <- c.UsagePrivateWorkingSetBytes
+	ch <- c.RuntimeTotal
+	ch <- c.RuntimeUser
+	ch <- c.RuntimeKernel
+	ch <- c.BytesReceived
+	ch <- c.BytesSent
+	ch <- c.PacketsReceived
+	ch <- c.PacketsSent
+	ch <- c.DroppedPacketsIncoming
+	ch <- c.DroppedPacketsOutgoing
+	ch <- c.ReadCountNormalized
+	ch <- c.ReadSizeBytes
+	ch <- c.WriteCountNormalized
+	ch <- c.WriteSizeBytes
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *ContainerMetricsCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
@@ -18,6 +18,10 @@ func init() {
 		deps = "Processor"
 	}
 	registerCollector("cpu", newCPUCollector, deps)
+	registerCollectorInfo("cpu", CollectorInfo{
+		Description: "CPU usage",
+		Sources:     []DataSource{SourcePerflib},
+	})
 }
 
 type cpuCollectorBasic struct {
@@ -56,25 +60,25 @@ func newCPUCollector() (Collector, error) {
 	// Value 6.05 was selected to split between Windows versions.
 	if version < 6.05 {
 		return &cpuCollectorBasic{
-			CStateSecondsTotal: prometheus.NewDesc(
+			CStateSecondsTotal: newDesc(
 				prometheus.BuildFQName(Namespace, subsystem, "cstate_seconds_total"),
 				"Time spent in low-power idle state",
 				[]string{"core", "state"},
 				nil,
 			),
-			TimeTotal: prometheus.NewDesc(
+			TimeTotal: newDesc(
 				prometheus.BuildFQName(Namespace, subsystem, "time_total"),
 				"Time that processor spent in different modes (dpc, idle, interrupt, privileged, user)",
 				[]string{"core", "mode"},
 				nil,
 			),
-			InterruptsTotal: prometheus.NewDesc(
+			InterruptsTotal: newDesc(
 				prometheus.BuildFQName(Namespace, subsystem, "interrupts_total"),
 				"Total number of received and serviced hardware interrupts",
 				[]string{"core"},
 				nil,
 			),
-			DPCsTotal: prometheus.NewDesc(
+			DPCsTotal: newDesc(
 				prometheus.BuildFQName(Namespace, subsystem, "dpcs_total"),
 				"Total number of received and serviced deferred procedure calls (DPCs)",
 				[]string{"core"},
@@ -84,79 +88,79 @@ func newCPUCollector() (Collector, error) {
 	}
 
 	return &cpuCollectorFull{
-		CStateSecondsTotal: prometheus.NewDesc(
+		CStateSecondsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cstate_seconds_total"),
 			"Time spent in low-power idle state",
 			[]string{"core", "state"},
 			nil,
 		),
-		TimeTotal: prometheus.NewDesc(
+		TimeTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "time_total"),
 			"Time that processor spent in different modes (dpc, idle, interrupt, privileged, user)",
 			[]string{"core", "mode"},
 			nil,
 		),
-		InterruptsTotal: prometheus.NewDesc(
+		InterruptsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "interrupts_total"),
 			"Total number of received and serviced hardware interrupts",
 			[]string{"core"},
 			nil,
 		),
-		DPCsTotal: prometheus.NewDesc(
+		DPCsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dpcs_total"),
 			"Total number of received and serviced deferred procedure calls (DPCs)",
 			[]string{"core"},
 			nil,
 		),
-		ClockInterruptsTotal: prometheus.NewDesc(
+		ClockInterruptsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clock_interrupts_total"),
 			"Total number of received and serviced clock tick interrupts",
 			[]string{"core"},
 			nil,
 		),
-		IdleBreakEventsTotal: prometheus.NewDesc(
+		IdleBreakEventsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "idle_break_events_total"),
 			"Total number of time processor was woken from idle",
 			[]string{"core"},
 			nil,
 		),
-		ParkingStatus: prometheus.NewDesc(
+		ParkingStatus: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "parking_status"),
 			"Parking Status represents whether a processor is parked or not",
 			[]string{"core"},
 			nil,
 		),
-		ProcessorFrequencyMHz: prometheus.NewDesc(
+		ProcessorFrequencyMHz: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "core_frequency_mhz"),
 			"Core frequency in megahertz",
 			[]string{"core"},
 			nil,
 		),
-		ProcessorPerformance: prometheus.NewDesc(
+		ProcessorPerformance: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "processor_performance_total"),
 			"Processor Performance is the average performance of the processor while it is executing instructions, as a percentage of the nominal performance of the processor. On some processors, Processor Performance may exceed 100%",
 			[]string{"core"},
 			nil,
 		),
-		ProcessorMPerf: prometheus.NewDesc(
+		ProcessorMPerf: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "processor_mperf_total"),
 			"Processor MPerf is the number of TSC ticks incremented while executing instructions",
 			[]string{"core"},
 			nil,
 		),
-		ProcessorRTC: prometheus.NewDesc(
+		ProcessorRTC: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "processor_rtc_total"),
 			"Processor RTC represents the number of RTC ticks made since the system booted. It should consistently be 64e6, and can be used to properly derive Processor Utility Rate",
 			[]string{"core"},
 			nil,
 		),
-		ProcessorUtility: prometheus.NewDesc(
+		ProcessorUtility: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "processor_utility_total"),
 			"Processor Utility represents is the amount of time the core spends executing instructions",
 			[]string{"core"},
 			nil,
 		),
-		ProcessorPrivUtility: prometheus.NewDesc(
+		ProcessorPrivUtility: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "processor_privileged_utility_total"),
 			"Processor Privilieged Utility represents is the amount of time the core has spent executing instructions inside the kernel",
 			[]string{"core"},
@@ -184,12 +188,22 @@ type perflibProcessor struct {
 	PercentUserTime       float64 `perflib:"% User Time"`
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *cpuCollectorBasic) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.CStateSecondsTotal
+	ch <- c.TimeTotal
+	ch <- c.InterruptsTotal
+	ch <- c.DPCsTotal
+}
+
 func (c *cpuCollectorBasic) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	data := make([]perflibProcessor, 0)
 	err := unmarshalObject(ctx.perfObjects["Processor"], &data)
 	if err != nil {
 		return err
 	}
+	// Processor counters reset when the system restarts.
+	created := bootTime()
 
 	for _, cpu := range data {
 		if strings.Contains(strings.ToLower(cpu.Name), "_total") {
@@ -197,66 +211,76 @@ func (c *cpuCollectorBasic) Collect(ctx *ScrapeContext, ch chan<- prometheus.Met
 		}
 		core := cpu.Name
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.CStateSecondsTotal,
 			prometheus.CounterValue,
 			cpu.PercentC1Time,
+			created,
 			core, "c1",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.CStateSecondsTotal,
 			prometheus.CounterValue,
 			cpu.PercentC2Time,
+			created,
 			core, "c2",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.CStateSecondsTotal,
 			prometheus.CounterValue,
 			cpu.PercentC3Time,
+			created,
 			core, "c3",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.PercentIdleTime,
+			created,
 			core, "idle",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.PercentInterruptTime,
+			created,
 			core, "interrupt",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.PercentDPCTime,
+			created,
 			core, "dpc",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.PercentPrivilegedTime,
+			created,
 			core, "privileged",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.PercentUserTime,
+			created,
 			core, "user",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.InterruptsTotal,
 			prometheus.CounterValue,
 			cpu.Interrupts,
+			created,
 			core,
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.DPCsTotal,
 			prometheus.CounterValue,
 			cpu.DPCsQueued,
+			created,
 			core,
 		)
 	}
@@ -293,12 +317,32 @@ type perflibProcessorInformation struct {
 	UserTimeSeconds          float64 `perflib:"% User Time"`
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *cpuCollectorFull) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.CStateSecondsTotal
+	ch <- c.TimeTotal
+	ch <- c.InterruptsTotal
+	ch <- c.DPCsTotal
+	ch <- c.ClockInterruptsTotal
+	ch <- c.IdleBreakEventsTotal
+	ch <- c.ParkingStatus
+	ch <- c.ProcessorFrequencyMHz
+	ch <- c.ProcessorMaxFrequencyMHz
+	ch <- c.ProcessorPerformance
+	ch <- c.ProcessorMPerf
+	ch <- c.ProcessorRTC
+	ch <- c.ProcessorUtility
+	ch <- c.ProcessorPrivUtility
+}
+
 func (c *cpuCollectorFull) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	data := make([]perflibProcessorInformation, 0)
 	err := unmarshalObject(ctx.perfObjects["Processor Information"], &data)
 	if err != nil {
 		return err
 	}
+	// Processor counters reset when the system restarts.
+	created := bootTime()
 
 	for _, cpu := range data {
 		if strings.Contains(strings.ToLower(cpu.Name), "_total") {
@@ -306,78 +350,90 @@ func (c *cpuCollectorFull) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metr
 		}
 		core := cpu.Name
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.CStateSecondsTotal,
 			prometheus.CounterValue,
 			cpu.C1TimeSeconds,
+			created,
 			core, "c1",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.CStateSecondsTotal,
 			prometheus.CounterValue,
 			cpu.C2TimeSeconds,
+			created,
 			core, "c2",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.CStateSecondsTotal,
 			prometheus.CounterValue,
 			cpu.C3TimeSeconds,
+			created,
 			core, "c3",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.IdleTimeSeconds,
+			created,
 			core, "idle",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.InterruptTimeSeconds,
+			created,
 			core, "interrupt",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.DPCTimeSeconds,
+			created,
 			core, "dpc",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.PrivilegedTimeSeconds,
+			created,
 			core, "privileged",
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.TimeTotal,
 			prometheus.CounterValue,
 			cpu.UserTimeSeconds,
+			created,
 			core, "user",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.InterruptsTotal,
 			prometheus.CounterValue,
 			cpu.InterruptsTotal,
+			created,
 			core,
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.DPCsTotal,
 			prometheus.CounterValue,
 			cpu.DPCsQueuedTotal,
+			created,
 			core,
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.ClockInterruptsTotal,
 			prometheus.CounterValue,
 			cpu.ClockInterruptsTotal,
+			created,
 			core,
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.IdleBreakEventsTotal,
 			prometheus.CounterValue,
 			cpu.IdleBreakEventsTotal,
+			created,
 			core,
 		)
 
@@ -394,34 +450,39 @@ func (c *cpuCollectorFull) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metr
 			cpu.ProcessorFrequencyMHz,
 			core,
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.ProcessorPerformance,
 			prometheus.CounterValue,
 			cpu.ProcessorPerformance,
+			created,
 			core,
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.ProcessorMPerf,
 			prometheus.CounterValue,
 			cpu.ProcessorMPerf,
+			created,
 			core,
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.ProcessorRTC,
 			prometheus.CounterValue,
 			cpu.ProcessorRTC,
+			created,
 			core,
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.ProcessorUtility,
 			prometheus.CounterValue,
 			cpu.ProcessorUtilityRate,
+			created,
 			core,
 		)
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.ProcessorPrivUtility,
 			prometheus.CounterValue,
 			cpu.PrivilegedUtilitySeconds,
+			created,
 			core,
 		)
 	}
@@ -10,11 +10,14 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("cpu_info", newCpuInfoCollector)
+	registerCollectorInfo("cpu_info", CollectorInfo{
+		Description: "CPU Information",
+		Sources:     []DataSource{SourceWMI},
+	})
 }
 
 // If you are adding additional labels to the metric, make sure that they get added in here as well. See below for explanation.
@@ -29,7 +32,7 @@ type CpuInfoCollector struct {
 
 func newCpuInfoCollector() (Collector, error) {
 	return &CpuInfoCollector{
-		CpuInfo: prometheus.NewDesc(
+		CpuInfo: newDesc(
 			prometheus.BuildFQName(Namespace, "", "cpu_info"),
 			"Labeled CPU information as provided provided by Win32_Processor",
 			[]string{
@@ -55,22 +58,27 @@ type win32_Processor struct {
 	Name         string
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *CpuInfoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.CpuInfo
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *CpuInfoCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting cpu_info metrics:", desc, err)
 		return err
 	}
 	return nil
 }
 
-func (c *CpuInfoCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *CpuInfoCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_Processor
 	// We use a static query here because the provided methods in wmi.go all issue a SELECT *;
 	// This results in the time consuming LoadPercentage field being read which seems to measure each CPU
 	// serially over a 1 second interval, so the scrape time is at least 1s * num_sockets
-	if err := wmi.Query(win32ProcessorQuery, &dst); err != nil {
+	if err := wmiQuery(ctx, win32ProcessorQuery, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -12,6 +12,10 @@ import (
 
 func init() {
 	registerCollector("cs", NewCSCollector)
+	registerCollectorInfo("cs", CollectorInfo{
+		Description: `"Computer System" metrics (system properties, num cpus/total memory)`,
+		Sources:     []DataSource{SourceWin32},
+	})
 }
 
 // A CSCollector is a Prometheus collector for WMI metrics
@@ -26,19 +30,19 @@ func NewCSCollector() (Collector, error) {
 	const subsystem = "cs"
 
 	return &CSCollector{
-		LogicalProcessors: prometheus.NewDesc(
+		LogicalProcessors: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "logical_processors"),
 			"ComputerSystem.NumberOfLogicalProcessors",
 			nil,
 			nil,
 		),
-		PhysicalMemoryBytes: prometheus.NewDesc(
+		PhysicalMemoryBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "physical_memory_bytes"),
 			"ComputerSystem.TotalPhysicalMemory",
 			nil,
 			nil,
 		),
-		Hostname: prometheus.NewDesc(
+		Hostname: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "hostname"),
 			"Labeled system hostname information as provided by ComputerSystem.DNSHostName and ComputerSystem.Domain",
 			[]string{
@@ -50,6 +54,13 @@ func NewCSCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *CSCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.PhysicalMemoryBytes
+	ch <- c.LogicalProcessors
+	ch <- c.Hostname
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *CSCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
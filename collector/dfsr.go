@@ -4,21 +4,35 @@
 package collector
 
 import (
+	"strings"
+
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-var dfsrEnabledCollectors = kingpin.Flag("collectors.dfsr.sources-enabled", "Comma-seperated list of DFSR Perflib sources to use.").Default("connection,folder,volume").String()
+const (
+	dfsrEnabledCollectorsFlag = "collectors.dfsr.sources-enabled"
+	dfsrDefaultSources        = "connection,folder,volume"
+)
 
 func init() {
 	// Perflib sources are dynamic, depending on the enabled child collectors
 	var perflibDependencies []string
-	for _, source := range expandEnabledChildCollectors(*dfsrEnabledCollectors) {
+	for _, source := range expandEnabledChildCollectors(dfsrDefaultSources) {
 		perflibDependencies = append(perflibDependencies, dfsrGetPerfObjectName(source))
 	}
 
-	registerCollector("dfsr", NewDFSRCollector, perflibDependencies...)
+	registerCollectorWithSettings("dfsr", NewDFSRCollector, perflibDependencies...)
+	registerCollectorInfo("dfsr", CollectorInfo{
+		Description: "DFSR metrics",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "DFS Replication role service",
+	})
+	registerApplicabilityCheck("dfsr", serviceInstalled("DFSR"))
+
+	kingpin.Flag(dfsrEnabledCollectorsFlag, "Comma-seperated list of DFSR Perflib sources to use.").Default(dfsrDefaultSources).String()
 }
 
 // DFSRCollector contains the metric and state data of the DFSR collectors.
@@ -93,11 +107,11 @@ func dfsrGetPerfObjectName(collector string) string {
 }
 
 // NewDFSRCollector is registered
-func NewDFSRCollector() (Collector, error) {
+func NewDFSRCollector(settings *config.Config) (Collector, error) {
 	log.Info("dfsr collector is in an experimental state! Metrics for this collector have not been tested.")
 	const subsystem = "dfsr"
 
-	enabled := expandEnabledChildCollectors(*dfsrEnabledCollectors)
+	enabled := expandEnabledChildCollectors(strings.Join(settings.Collectors.DFSR.SourcesEnabled, ","))
 	perfCounters := make([]string, 0, len(enabled))
 	for _, c := range enabled {
 		perfCounters = append(perfCounters, dfsrGetPerfObjectName(c))
@@ -106,63 +120,63 @@ func NewDFSRCollector() (Collector, error) {
 
 	dfsrCollector := DFSRCollector{
 		// Connection
-		ConnectionBandwidthSavingsUsingDFSReplicationTotal: prometheus.NewDesc(
+		ConnectionBandwidthSavingsUsingDFSReplicationTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_bandwidth_savings_using_dfs_replication_bytes_total"),
 			"Total bytes of bandwidth saved using DFS Replication for this connection",
 			[]string{"name"},
 			nil,
 		),
 
-		ConnectionBytesReceivedTotal: prometheus.NewDesc(
+		ConnectionBytesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_bytes_received_total"),
 			"Total bytes received for connection",
 			[]string{"name"},
 			nil,
 		),
 
-		ConnectionCompressedSizeOfFilesReceivedTotal: prometheus.NewDesc(
+		ConnectionCompressedSizeOfFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_compressed_size_of_files_received_bytes_total"),
 			"Total compressed size of files received on the connection, in bytes",
 			[]string{"name"},
 			nil,
 		),
 
-		ConnectionFilesReceivedTotal: prometheus.NewDesc(
+		ConnectionFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_received_files_total"),
 			"Total number of files received for connection",
 			[]string{"name"},
 			nil,
 		),
 
-		ConnectionRDCBytesReceivedTotal: prometheus.NewDesc(
+		ConnectionRDCBytesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_rdc_received_bytes_total"),
 			"Total bytes received on the connection while replicating files using Remote Differential Compression",
 			[]string{"name"},
 			nil,
 		),
 
-		ConnectionRDCCompressedSizeOfFilesReceivedTotal: prometheus.NewDesc(
+		ConnectionRDCCompressedSizeOfFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_rdc_compressed_size_of_received_files_bytes_total"),
 			"Total uncompressed size of files received with Remote Differential Compression for connection",
 			[]string{"name"},
 			nil,
 		),
 
-		ConnectionRDCNumberofFilesReceivedTotal: prometheus.NewDesc(
+		ConnectionRDCNumberofFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_rdc_received_files_total"),
 			"Total number of files received using remote differential compression",
 			[]string{"name"},
 			nil,
 		),
 
-		ConnectionRDCSizeOfFilesReceivedTotal: prometheus.NewDesc(
+		ConnectionRDCSizeOfFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_rdc_size_of_received_files_bytes_total"),
 			"Total size of received Remote Differential Compression files, in bytes.",
 			[]string{"name"},
 			nil,
 		),
 
-		ConnectionSizeOfFilesReceivedTotal: prometheus.NewDesc(
+		ConnectionSizeOfFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_files_received_bytes_total"),
 			"Total size of files received, in bytes",
 			[]string{"name"},
@@ -170,189 +184,189 @@ func NewDFSRCollector() (Collector, error) {
 		),
 
 		// Folder
-		FolderBandwidthSavingsUsingDFSReplicationTotal: prometheus.NewDesc(
+		FolderBandwidthSavingsUsingDFSReplicationTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_bandwidth_savings_using_dfs_replication_bytes_total"),
 			"Total bytes of bandwidth saved using DFS Replication for this folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderCompressedSizeOfFilesReceivedTotal: prometheus.NewDesc(
+		FolderCompressedSizeOfFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_compressed_size_of_received_files_bytes_total"),
 			"Total compressed size of files received on the folder, in bytes",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderConflictBytesCleanedupTotal: prometheus.NewDesc(
+		FolderConflictBytesCleanedupTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_conflict_cleaned_up_bytes_total"),
 			"Total size of conflict loser files and folders deleted from the Conflict and Deleted folder, in bytes",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderConflictBytesGeneratedTotal: prometheus.NewDesc(
+		FolderConflictBytesGeneratedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_conflict_generated_bytes_total"),
 			"Total size of conflict loser files and folders moved to the Conflict and Deleted folder, in bytes",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderConflictFilesCleanedUpTotal: prometheus.NewDesc(
+		FolderConflictFilesCleanedUpTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_conflict_cleaned_up_files_total"),
 			"Number of conflict loser files deleted from the Conflict and Deleted folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderConflictFilesGeneratedTotal: prometheus.NewDesc(
+		FolderConflictFilesGeneratedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_conflict_generated_files_total"),
 			"Number of files and folders moved to the Conflict and Deleted folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderConflictFolderCleanupsCompletedTotal: prometheus.NewDesc(
+		FolderConflictFolderCleanupsCompletedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_conflict_folder_cleanups_total"),
 			"Number of deletions of conflict loser files and folders in the Conflict and Deleted",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderConflictSpaceInUse: prometheus.NewDesc(
+		FolderConflictSpaceInUse: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_conflict_space_in_use_bytes"),
 			"Total size of the conflict loser files and folders currently in the Conflict and Deleted folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderDeletedSpaceInUse: prometheus.NewDesc(
+		FolderDeletedSpaceInUse: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_deleted_space_in_use_bytes"),
 			"Total size (in bytes) of the deleted files and folders currently in the Conflict and Deleted folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderDeletedBytesCleanedUpTotal: prometheus.NewDesc(
+		FolderDeletedBytesCleanedUpTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_deleted_cleaned_up_bytes_total"),
 			"Total size (in bytes) of replicating deleted files and folders that were cleaned up from the Conflict and Deleted folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderDeletedBytesGeneratedTotal: prometheus.NewDesc(
+		FolderDeletedBytesGeneratedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_deleted_generated_bytes_total"),
 			"Total size (in bytes) of replicated deleted files and folders that were moved to the Conflict and Deleted folder after they were deleted from a replicated folder on a sending member",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderDeletedFilesCleanedUpTotal: prometheus.NewDesc(
+		FolderDeletedFilesCleanedUpTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_deleted_cleaned_up_files_total"),
 			"Number of files and folders that were cleaned up from the Conflict and Deleted folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderDeletedFilesGeneratedTotal: prometheus.NewDesc(
+		FolderDeletedFilesGeneratedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_deleted_generated_files_total"),
 			"Number of deleted files and folders that were moved to the Conflict and Deleted folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderFileInstallsRetriedTotal: prometheus.NewDesc(
+		FolderFileInstallsRetriedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_file_installs_retried_total"),
 			"Total number of file installs that are being retried due to sharing violations or other errors encountered when installing the files",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderFileInstallsSucceededTotal: prometheus.NewDesc(
+		FolderFileInstallsSucceededTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_file_installs_succeeded_total"),
 			"Total number of files that were successfully received from sending members and installed locally on this server",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderFilesReceivedTotal: prometheus.NewDesc(
+		FolderFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_received_files_total"),
 			"Total number of files received",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderRDCBytesReceivedTotal: prometheus.NewDesc(
+		FolderRDCBytesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_rdc_received_bytes_total"),
 			"Total number of bytes received in replicating files using Remote Differential Compression",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderRDCCompressedSizeOfFilesReceivedTotal: prometheus.NewDesc(
+		FolderRDCCompressedSizeOfFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_rdc_compressed_size_of_received_files_bytes_total"),
 			"Total compressed size (in bytes) of the files received with Remote Differential Compression",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderRDCNumberofFilesReceivedTotal: prometheus.NewDesc(
+		FolderRDCNumberofFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_rdc_received_files_total"),
 			"Total number of files received with Remote Differential Compression",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderRDCSizeOfFilesReceivedTotal: prometheus.NewDesc(
+		FolderRDCSizeOfFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_rdc_files_received_bytes_total"),
 			"Total uncompressed size (in bytes) of the files received with Remote Differential Compression",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderSizeOfFilesReceivedTotal: prometheus.NewDesc(
+		FolderSizeOfFilesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_files_received_bytes_total"),
 			"Total uncompressed size (in bytes) of the files received",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderStagingSpaceInUse: prometheus.NewDesc(
+		FolderStagingSpaceInUse: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_staging_space_in_use_bytes"),
 			"Total size of files and folders currently in the staging folder.",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderStagingBytesCleanedUpTotal: prometheus.NewDesc(
+		FolderStagingBytesCleanedUpTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_staging_cleaned_up_bytes_total"),
 			"Total size (in bytes) of the files and folders that have been cleaned up from the staging folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderStagingBytesGeneratedTotal: prometheus.NewDesc(
+		FolderStagingBytesGeneratedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_staging_generated_bytes_total"),
 			"Total size (in bytes) of replicated files and folders in the staging folder created by the DFS Replication service since last restart",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderStagingFilesCleanedUpTotal: prometheus.NewDesc(
+		FolderStagingFilesCleanedUpTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_staging_cleaned_up_files_total"),
 			"Total number of files and folders that have been cleaned up from the staging folder",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderStagingFilesGeneratedTotal: prometheus.NewDesc(
+		FolderStagingFilesGeneratedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_staging_generated_files_total"),
 			"Total number of times replicated files and folders have been staged by the DFS Replication service",
 			[]string{"name"},
 			nil,
 		),
 
-		FolderUpdatesDroppedTotal: prometheus.NewDesc(
+		FolderUpdatesDroppedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "folder_dropped_updates_total"),
 			"Total number of redundant file replication update records that have been ignored by the DFS Replication service because they did not change the replicated file or folder",
 			[]string{"name"},
@@ -360,35 +374,35 @@ func NewDFSRCollector() (Collector, error) {
 		),
 
 		// Volume
-		VolumeDatabaseCommitsTotal: prometheus.NewDesc(
+		VolumeDatabaseCommitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "volume_database_commits_total"),
 			"Total number of DFSR Volume database commits",
 			[]string{"name"},
 			nil,
 		),
 
-		VolumeDatabaseLookupsTotal: prometheus.NewDesc(
+		VolumeDatabaseLookupsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "volume_database_lookups_total"),
 			"Total number of DFSR Volume database lookups",
 			[]string{"name"},
 			nil,
 		),
 
-		VolumeUSNJournalUnreadPercentage: prometheus.NewDesc(
+		VolumeUSNJournalUnreadPercentage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "volume_usn_journal_unread_percentage"),
 			"Percentage of DFSR Volume USN journal records that are unread",
 			[]string{"name"},
 			nil,
 		),
 
-		VolumeUSNJournalRecordsAcceptedTotal: prometheus.NewDesc(
+		VolumeUSNJournalRecordsAcceptedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "volume_usn_journal_accepted_records_total"),
 			"Total number of USN journal records accepted",
 			[]string{"name"},
 			nil,
 		),
 
-		VolumeUSNJournalRecordsReadTotal: prometheus.NewDesc(
+		VolumeUSNJournalRecordsReadTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "volume_usn_journal_read_records_total"),
 			"Total number of DFSR Volume USN journal records read",
 			[]string{"name"},
@@ -419,6 +433,51 @@ func (c *DFSRCollector) getDFSRChildCollectors(enabledCollectors []string) []dfs
 	return dfsrCollectors
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *DFSRCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ConnectionBandwidthSavingsUsingDFSReplicationTotal
+	ch <- c.ConnectionBytesReceivedTotal
+	ch <- c.ConnectionCompressedSizeOfFilesReceivedTotal
+	ch <- c.ConnectionFilesReceivedTotal
+	ch <- c.ConnectionRDCBytesReceivedTotal
+	ch <- c.ConnectionRDCCompressedSizeOfFilesReceivedTotal
+	ch <- c.ConnectionRDCSizeOfFilesReceivedTotal
+	ch <- c.ConnectionRDCNumberofFilesReceivedTotal
+	ch <- c.ConnectionSizeOfFilesReceivedTotal
+	ch <- c.FolderBandwidthSavingsUsingDFSReplicationTotal
+	ch <- c.FolderCompressedSizeOfFilesReceivedTotal
+	ch <- c.FolderConflictBytesCleanedupTotal
+	ch <- c.FolderConflictBytesGeneratedTotal
+	ch <- c.FolderConflictFilesCleanedUpTotal
+	ch <- c.FolderConflictFilesGeneratedTotal
+	ch <- c.FolderConflictFolderCleanupsCompletedTotal
+	ch <- c.FolderConflictSpaceInUse
+	ch <- c.FolderDeletedSpaceInUse
+	ch <- c.FolderDeletedBytesCleanedUpTotal
+	ch <- c.FolderDeletedBytesGeneratedTotal
+	ch <- c.FolderDeletedFilesCleanedUpTotal
+	ch <- c.FolderDeletedFilesGeneratedTotal
+	ch <- c.FolderFileInstallsRetriedTotal
+	ch <- c.FolderFileInstallsSucceededTotal
+	ch <- c.FolderFilesReceivedTotal
+	ch <- c.FolderRDCBytesReceivedTotal
+	ch <- c.FolderRDCCompressedSizeOfFilesReceivedTotal
+	ch <- c.FolderRDCNumberofFilesReceivedTotal
+	ch <- c.FolderRDCSizeOfFilesReceivedTotal
+	ch <- c.FolderSizeOfFilesReceivedTotal
+	ch <- c.FolderStagingSpaceInUse
+	ch <- c.FolderStagingBytesCleanedUpTotal
+	ch <- c.FolderStagingBytesGeneratedTotal
+	ch <- c.FolderStagingFilesCleanedUpTotal
+	ch <- c.FolderStagingFilesGeneratedTotal
+	ch <- c.FolderUpdatesDroppedTotal
+	ch <- c.VolumeDatabaseLookupsTotal
+	ch <- c.VolumeDatabaseCommitsTotal
+	ch <- c.VolumeUSNJournalUnreadPercentage
+	ch <- c.VolumeUSNJournalRecordsAcceptedTotal
+	ch <- c.VolumeUSNJournalRecordsReadTotal
+}
+
 // Collect implements the Collector interface.
 // Sends metric values for each metric to the provided prometheus Metric channel.
 func (c *DFSRCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
@@ -9,6 +9,12 @@ import (
 
 func init() {
 	registerCollector("dhcp", NewDhcpCollector, "DHCP Server")
+	registerCollectorInfo("dhcp", CollectorInfo{
+		Description: "DHCP Server",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "DHCP Server role",
+	})
+	registerApplicabilityCheck("dhcp", perflibObjectsPresent("DHCP Server"))
 }
 
 // A DhcpCollector is a Prometheus collector perflib DHCP metrics
@@ -44,151 +50,151 @@ func NewDhcpCollector() (Collector, error) {
 	const subsystem = "dhcp"
 
 	return &DhcpCollector{
-		PacketsReceivedTotal: prometheus.NewDesc(
+		PacketsReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_received_total"),
 			"Total number of packets received by the DHCP server (PacketsReceivedTotal)",
 			nil,
 			nil,
 		),
-		DuplicatesDroppedTotal: prometheus.NewDesc(
+		DuplicatesDroppedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "duplicates_dropped_total"),
 			"Total number of duplicate packets received by the DHCP server (DuplicatesDroppedTotal)",
 			nil,
 			nil,
 		),
-		PacketsExpiredTotal: prometheus.NewDesc(
+		PacketsExpiredTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_expired_total"),
 			"Total number of packets expired in the DHCP server message queue (PacketsExpiredTotal)",
 			nil,
 			nil,
 		),
-		ActiveQueueLength: prometheus.NewDesc(
+		ActiveQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "active_queue_length"),
 			"Number of packets in the processing queue of the DHCP server (ActiveQueueLength)",
 			nil,
 			nil,
 		),
-		ConflictCheckQueueLength: prometheus.NewDesc(
+		ConflictCheckQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "conflict_check_queue_length"),
 			"Number of packets in the DHCP server queue waiting on conflict detection (ping). (ConflictCheckQueueLength)",
 			nil,
 			nil,
 		),
-		DiscoversTotal: prometheus.NewDesc(
+		DiscoversTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "discovers_total"),
 			"Total DHCP Discovers received by the DHCP server (DiscoversTotal)",
 			nil,
 			nil,
 		),
-		OffersTotal: prometheus.NewDesc(
+		OffersTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "offers_total"),
 			"Total DHCP Offers sent by the DHCP server (OffersTotal)",
 			nil,
 			nil,
 		),
-		RequestsTotal: prometheus.NewDesc(
+		RequestsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "requests_total"),
 			"Total DHCP Requests received by the DHCP server (RequestsTotal)",
 			nil,
 			nil,
 		),
-		InformsTotal: prometheus.NewDesc(
+		InformsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "informs_total"),
 			"Total DHCP Informs received by the DHCP server (InformsTotal)",
 			nil,
 			nil,
 		),
-		AcksTotal: prometheus.NewDesc(
+		AcksTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "acks_total"),
 			"Total DHCP Acks sent by the DHCP server (AcksTotal)",
 			nil,
 			nil,
 		),
-		NacksTotal: prometheus.NewDesc(
+		NacksTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "nacks_total"),
 			"Total DHCP Nacks sent by the DHCP server (NacksTotal)",
 			nil,
 			nil,
 		),
-		DeclinesTotal: prometheus.NewDesc(
+		DeclinesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "declines_total"),
 			"Total DHCP Declines received by the DHCP server (DeclinesTotal)",
 			nil,
 			nil,
 		),
-		ReleasesTotal: prometheus.NewDesc(
+		ReleasesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "releases_total"),
 			"Total DHCP Releases received by the DHCP server (ReleasesTotal)",
 			nil,
 			nil,
 		),
-		OfferQueueLength: prometheus.NewDesc(
+		OfferQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "offer_queue_length"),
 			"Number of packets in the offer queue of the DHCP server (OfferQueueLength)",
 			nil,
 			nil,
 		),
-		DeniedDueToMatch: prometheus.NewDesc(
+		DeniedDueToMatch: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "denied_due_to_match_total"),
 			"Total number of DHCP requests denied, based on matches from the Deny list (DeniedDueToMatch)",
 			nil,
 			nil,
 		),
-		DeniedDueToNonMatch: prometheus.NewDesc(
+		DeniedDueToNonMatch: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "denied_due_to_nonmatch_total"),
 			"Total number of DHCP requests denied, based on non-matches from the Allow list (DeniedDueToNonMatch)",
 			nil,
 			nil,
 		),
-		FailoverBndupdSentTotal: prometheus.NewDesc(
+		FailoverBndupdSentTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_bndupd_sent_total"),
 			"Number of DHCP failover Binding Update messages sent (FailoverBndupdSentTotal)",
 			nil,
 			nil,
 		),
-		FailoverBndupdReceivedTotal: prometheus.NewDesc(
+		FailoverBndupdReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_bndupd_received_total"),
 			"Number of DHCP failover Binding Update messages received (FailoverBndupdReceivedTotal)",
 			nil,
 			nil,
 		),
-		FailoverBndackSentTotal: prometheus.NewDesc(
+		FailoverBndackSentTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_bndack_sent_total"),
 			"Number of DHCP failover Binding Ack messages sent (FailoverBndackSentTotal)",
 			nil,
 			nil,
 		),
-		FailoverBndackReceivedTotal: prometheus.NewDesc(
+		FailoverBndackReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_bndack_received_total"),
 			"Number of DHCP failover Binding Ack messages received (FailoverBndackReceivedTotal)",
 			nil,
 			nil,
 		),
-		FailoverBndupdPendingOutboundQueue: prometheus.NewDesc(
+		FailoverBndupdPendingOutboundQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_bndupd_pending_in_outbound_queue"),
 			"Number of pending outbound DHCP failover Binding Update messages (FailoverBndupdPendingOutboundQueue)",
 			nil,
 			nil,
 		),
-		FailoverTransitionsCommunicationinterruptedState: prometheus.NewDesc(
+		FailoverTransitionsCommunicationinterruptedState: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_transitions_communicationinterrupted_state_total"),
 			"Total number of transitions into COMMUNICATION INTERRUPTED state (FailoverTransitionsCommunicationinterruptedState)",
 			nil,
 			nil,
 		),
-		FailoverTransitionsPartnerdownState: prometheus.NewDesc(
+		FailoverTransitionsPartnerdownState: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_transitions_partnerdown_state_total"),
 			"Total number of transitions into PARTNER DOWN state (FailoverTransitionsPartnerdownState)",
 			nil,
 			nil,
 		),
-		FailoverTransitionsRecoverState: prometheus.NewDesc(
+		FailoverTransitionsRecoverState: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_transitions_recover_total"),
 			"Total number of transitions into RECOVER state (FailoverTransitionsRecoverState)",
 			nil,
 			nil,
 		),
-		FailoverBndupdDropped: prometheus.NewDesc(
+		FailoverBndupdDropped: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_bndupd_dropped_total"),
 			"Total number of DHCP faileover Binding Updates dropped (FailoverBndupdDropped)",
 			nil,
@@ -228,6 +234,35 @@ type dhcpPerf struct {
 	FailoverBndupdDropped                            float64 `perflib:"Failover: BndUpd Dropped."`
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *DhcpCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.PacketsReceivedTotal
+	ch <- c.DuplicatesDroppedTotal
+	ch <- c.PacketsExpiredTotal
+	ch <- c.ActiveQueueLength
+	ch <- c.ConflictCheckQueueLength
+	ch <- c.DiscoversTotal
+	ch <- c.OffersTotal
+	ch <- c.RequestsTotal
+	ch <- c.InformsTotal
+	ch <- c.AcksTotal
+	ch <- c.NacksTotal
+	ch <- c.DeclinesTotal
+	ch <- c.ReleasesTotal
+	ch <- c.OfferQueueLength
+	ch <- c.DeniedDueToMatch
+	ch <- c.DeniedDueToNonMatch
+	ch <- c.FailoverBndupdSentTotal
+	ch <- c.FailoverBndupdReceivedTotal
+	ch <- c.FailoverBndackSentTotal
+	ch <- c.FailoverBndackReceivedTotal
+	ch <- c.FailoverBndupdPendingOutboundQueue
+	ch <- c.FailoverTransitionsCommunicationinterruptedState
+	ch <- c.FailoverTransitionsPartnerdownState
+	ch <- c.FailoverTransitionsRecoverState
+	ch <- c.FailoverBndupdDropped
+}
+
 func (c *DhcpCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	var perflib []dhcpPerf
 	if err := unmarshalObject(ctx.perfObjects["DHCP Server"], &perflib); err != nil {
@@ -9,11 +9,14 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("disk_drive", newDiskDriveInfoCollector)
+	registerCollectorInfo("disk_drive", CollectorInfo{
+		Description: "Disk drive information and status",
+		Sources:     []DataSource{SourceWMI},
+	})
 }
 
 const (
@@ -33,7 +36,7 @@ func newDiskDriveInfoCollector() (Collector, error) {
 	const subsystem = "disk_drive"
 
 	return &DiskDriveInfoCollector{
-		DiskInfo: prometheus.NewDesc(
+		DiskInfo: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "info"),
 			"General drive information",
 			[]string{
@@ -45,7 +48,7 @@ func newDiskDriveInfoCollector() (Collector, error) {
 			nil,
 		),
 
-		Status: prometheus.NewDesc(
+		Status: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "status"),
 			"Status of the drive",
 			[]string{
@@ -53,21 +56,21 @@ func newDiskDriveInfoCollector() (Collector, error) {
 			nil,
 		),
 
-		Size: prometheus.NewDesc(
+		Size: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "size"),
 			"Size of the disk drive. It is calculated by multiplying the total number of cylinders, tracks in each cylinder, sectors in each track, and bytes in each sector.",
 			[]string{"name"},
 			nil,
 		),
 
-		Partitions: prometheus.NewDesc(
+		Partitions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "partitions"),
 			"Number of partitions",
 			[]string{"name"},
 			nil,
 		),
 
-		Availability: prometheus.NewDesc(
+		Availability: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availability"),
 			"Availability Status",
 			[]string{
@@ -130,19 +133,28 @@ var (
 	}
 )
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *DiskDriveInfoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.DiskInfo
+	ch <- c.Status
+	ch <- c.Size
+	ch <- c.Partitions
+	ch <- c.Availability
+}
+
 // Collect sends the metric values for each metric to the provided prometheus Metric channel.
 func (c *DiskDriveInfoCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting disk_drive_info metrics:", desc, err)
 		return err
 	}
 	return nil
 }
 
-func (c *DiskDriveInfoCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *DiskDriveInfoCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_DiskDrive
 
-	if err := wmi.Query(win32DiskQuery, &dst); err != nil {
+	if err := wmiQuery(ctx, win32DiskQuery, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -8,11 +8,16 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("dns", NewDNSCollector)
+	registerCollectorInfo("dns", CollectorInfo{
+		Description: "DNS Server",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "DNS Server role",
+	})
+	registerApplicabilityCheck("dns", serviceInstalled("DNS"))
 }
 
 // A DNSCollector is a Prometheus collector for WMI Win32_PerfRawData_DNS_DNS metrics
@@ -45,133 +50,133 @@ type DNSCollector struct {
 func NewDNSCollector() (Collector, error) {
 	const subsystem = "dns"
 	return &DNSCollector{
-		ZoneTransferRequestsReceived: prometheus.NewDesc(
+		ZoneTransferRequestsReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "zone_transfer_requests_received_total"),
 			"Number of zone transfer requests (AXFR/IXFR) received by the master DNS server",
 			[]string{"qtype"},
 			nil,
 		),
-		ZoneTransferRequestsSent: prometheus.NewDesc(
+		ZoneTransferRequestsSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "zone_transfer_requests_sent_total"),
 			"Number of zone transfer requests (AXFR/IXFR) sent by the secondary DNS server",
 			[]string{"qtype"},
 			nil,
 		),
-		ZoneTransferResponsesReceived: prometheus.NewDesc(
+		ZoneTransferResponsesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "zone_transfer_response_received_total"),
 			"Number of zone transfer responses (AXFR/IXFR) received by the secondary DNS server",
 			[]string{"qtype"},
 			nil,
 		),
-		ZoneTransferSuccessReceived: prometheus.NewDesc(
+		ZoneTransferSuccessReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "zone_transfer_success_received_total"),
 			"Number of successful zone transfers (AXFR/IXFR) received by the secondary DNS server",
 			[]string{"qtype", "protocol"},
 			nil,
 		),
-		ZoneTransferSuccessSent: prometheus.NewDesc(
+		ZoneTransferSuccessSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "zone_transfer_success_sent_total"),
 			"Number of successful zone transfers (AXFR/IXFR) of the master DNS server",
 			[]string{"qtype"},
 			nil,
 		),
-		ZoneTransferFailures: prometheus.NewDesc(
+		ZoneTransferFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "zone_transfer_failures_total"),
 			"Number of failed zone transfers of the master DNS server",
 			nil,
 			nil,
 		),
-		MemoryUsedBytes: prometheus.NewDesc(
+		MemoryUsedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memory_used_bytes"),
 			"Current memory used by DNS server",
 			[]string{"area"},
 			nil,
 		),
-		DynamicUpdatesQueued: prometheus.NewDesc(
+		DynamicUpdatesQueued: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dynamic_updates_queued"),
 			"Number of dynamic updates queued by the DNS server",
 			nil,
 			nil,
 		),
-		DynamicUpdatesReceived: prometheus.NewDesc(
+		DynamicUpdatesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dynamic_updates_received_total"),
 			"Number of secure update requests received by the DNS server",
 			[]string{"operation"},
 			nil,
 		),
-		DynamicUpdatesFailures: prometheus.NewDesc(
+		DynamicUpdatesFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dynamic_updates_failures_total"),
 			"Number of dynamic updates which timed out or were rejected by the DNS server",
 			[]string{"reason"},
 			nil,
 		),
-		NotifyReceived: prometheus.NewDesc(
+		NotifyReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "notify_received_total"),
 			"Number of notifies received by the secondary DNS server",
 			nil,
 			nil,
 		),
-		NotifySent: prometheus.NewDesc(
+		NotifySent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "notify_sent_total"),
 			"Number of notifies sent by the master DNS server",
 			nil,
 			nil,
 		),
-		SecureUpdateFailures: prometheus.NewDesc(
+		SecureUpdateFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "secure_update_failures_total"),
 			"Number of secure updates that failed on the DNS server",
 			nil,
 			nil,
 		),
-		SecureUpdateReceived: prometheus.NewDesc(
+		SecureUpdateReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "secure_update_received_total"),
 			"Number of secure update requests received by the DNS server",
 			nil,
 			nil,
 		),
-		Queries: prometheus.NewDesc(
+		Queries: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "queries_total"),
 			"Number of queries received by DNS server",
 			[]string{"protocol"},
 			nil,
 		),
-		Responses: prometheus.NewDesc(
+		Responses: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "responses_total"),
 			"Number of responses sent by DNS server",
 			[]string{"protocol"},
 			nil,
 		),
-		RecursiveQueries: prometheus.NewDesc(
+		RecursiveQueries: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "recursive_queries_total"),
 			"Number of recursive queries received by DNS server",
 			nil,
 			nil,
 		),
-		RecursiveQueryFailures: prometheus.NewDesc(
+		RecursiveQueryFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "recursive_query_failures_total"),
 			"Number of recursive query failures",
 			nil,
 			nil,
 		),
-		RecursiveQuerySendTimeouts: prometheus.NewDesc(
+		RecursiveQuerySendTimeouts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "recursive_query_send_timeouts_total"),
 			"Number of recursive query sending timeouts",
 			nil,
 			nil,
 		),
-		WinsQueries: prometheus.NewDesc(
+		WinsQueries: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "wins_queries_total"),
 			"Number of WINS lookup requests received by the server",
 			[]string{"direction"},
 			nil,
 		),
-		WinsResponses: prometheus.NewDesc(
+		WinsResponses: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "wins_responses_total"),
 			"Number of WINS lookup responses sent by the server",
 			[]string{"direction"},
 			nil,
 		),
-		UnmatchedResponsesReceived: prometheus.NewDesc(
+		UnmatchedResponsesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "unmatched_responses_total"),
 			"Number of response packets received by the DNS server that do not match any outstanding remote query",
 			nil,
@@ -180,10 +185,36 @@ func NewDNSCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *DNSCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ZoneTransferRequestsReceived
+	ch <- c.ZoneTransferRequestsSent
+	ch <- c.ZoneTransferResponsesReceived
+	ch <- c.ZoneTransferSuccessReceived
+	ch <- c.ZoneTransferSuccessSent
+	ch <- c.ZoneTransferFailures
+	ch <- c.MemoryUsedBytes
+	ch <- c.DynamicUpdatesQueued
+	ch <- c.DynamicUpdatesReceived
+	ch <- c.DynamicUpdatesFailures
+	ch <- c.NotifyReceived
+	ch <- c.NotifySent
+	ch <- c.SecureUpdateFailures
+	ch <- c.SecureUpdateReceived
+	ch <- c.Queries
+	ch <- c.Responses
+	ch <- c.RecursiveQueries
+	ch <- c.RecursiveQueryFailures
+	ch <- c.RecursiveQuerySendTimeouts
+	ch <- c.WinsQueries
+	ch <- c.WinsResponses
+	ch <- c.UnmatchedResponsesReceived
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *DNSCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting dns metrics:", desc, err)
 		return err
 	}
@@ -236,10 +267,10 @@ type Win32_PerfRawData_DNS_DNS struct {
 	ZoneTransferSOARequestSent     uint32
 }
 
-func (c *DNSCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *DNSCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_DNS_DNS
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
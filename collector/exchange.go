@@ -9,12 +9,18 @@ import (
 	"strings"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+const (
+	exchangeListAllCollectorsFlag = "collectors.exchange.list"
+	exchangeCollectorsEnabledFlag = "collectors.exchange.enabled"
+)
+
 func init() {
-	registerCollector("exchange", newExchangeCollector,
+	registerCollectorWithSettings("exchange", newExchangeCollector,
 		"MSExchange ADAccess Processes",
 		"MSExchangeTransport Queues",
 		"MSExchange HttpProxy",
@@ -25,6 +31,21 @@ func init() {
 		"MSExchange WorkloadManagement Workloads",
 		"MSExchange RpcClientAccess",
 	)
+	registerCollectorInfo("exchange", CollectorInfo{
+		Description: "Exchange metrics",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "Microsoft Exchange Server",
+	})
+	registerApplicabilityCheck("exchange", perflibObjectsPresent("MSExchange ADAccess Processes"))
+
+	kingpin.Flag(
+		exchangeListAllCollectorsFlag,
+		"List the collectors along with their perflib object name/ids",
+	).Bool()
+	kingpin.Flag(
+		exchangeCollectorsEnabledFlag,
+		"Comma-separated list of collectors to use. Defaults to all, if not specified.",
+	).Default("").String()
 }
 
 type exchangeCollector struct {
@@ -82,24 +103,14 @@ var (
 		"WorkloadManagement",
 		"RpcClientAccess",
 	}
-
-	argExchangeListAllCollectors = kingpin.Flag(
-		"collectors.exchange.list",
-		"List the collectors along with their perflib object name/ids",
-	).Bool()
-
-	argExchangeCollectorsEnabled = kingpin.Flag(
-		"collectors.exchange.enabled",
-		"Comma-separated list of collectors to use. Defaults to all, if not specified.",
-	).Default("").String()
 )
 
 // newExchangeCollector returns a new Collector
-func newExchangeCollector() (Collector, error) {
+func newExchangeCollector(settings *config.Config) (Collector, error) {
 
 	// desc creates a new prometheus description
 	desc := func(metricName string, description string, labels ...string) *prometheus.Desc {
-		return prometheus.NewDesc(
+		return newDesc(
 			prometheus.BuildFQName(Namespace, "exchange", metricName),
 			description,
 			labels,
@@ -161,7 +172,7 @@ func newExchangeCollector() (Collector, error) {
 		"RpcClientAccess":     "[29336] MSExchange RpcClientAccess",
 	}
 
-	if *argExchangeListAllCollectors {
+	if settings.Collectors.Exchange.List {
 		fmt.Printf("%-32s %-32s\n", "Collector Name", "[PerfID] Perflib Object")
 		for _, cname := range exchangeAllCollectorNames {
 			fmt.Printf("%-32s %-32s\n", cname, collectorDesc[cname])
@@ -169,12 +180,13 @@ func newExchangeCollector() (Collector, error) {
 		os.Exit(0)
 	}
 
-	if *argExchangeCollectorsEnabled == "" {
+	enabled := settings.Collectors.Exchange.Enabled
+	if len(enabled) == 0 {
 		for _, collectorName := range exchangeAllCollectorNames {
 			c.enabledCollectors = append(c.enabledCollectors, collectorName)
 		}
 	} else {
-		for _, collectorName := range strings.Split(*argExchangeCollectorsEnabled, ",") {
+		for _, collectorName := range enabled {
 			if find(exchangeAllCollectorNames, collectorName) {
 				c.enabledCollectors = append(c.enabledCollectors, collectorName)
 			} else {
@@ -186,6 +198,47 @@ func newExchangeCollector() (Collector, error) {
 	return &c, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *exchangeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.LDAPReadTime
+	ch <- c.LDAPSearchTime
+	ch <- c.LDAPWriteTime
+	ch <- c.LDAPTimeoutErrorsPerSec
+	ch <- c.LongRunningLDAPOperationsPerMin
+	ch <- c.ExternalActiveRemoteDeliveryQueueLength
+	ch <- c.InternalActiveRemoteDeliveryQueueLength
+	ch <- c.ActiveMailboxDeliveryQueueLength
+	ch <- c.RetryMailboxDeliveryQueueLength
+	ch <- c.UnreachableQueueLength
+	ch <- c.ExternalLargestDeliveryQueueLength
+	ch <- c.InternalLargestDeliveryQueueLength
+	ch <- c.PoisonQueueLength
+	ch <- c.MailboxServerLocatorAverageLatency
+	ch <- c.AverageAuthenticationLatency
+	ch <- c.AverageCASProcessingLatency
+	ch <- c.MailboxServerProxyFailureRate
+	ch <- c.OutstandingProxyRequests
+	ch <- c.ProxyRequestsPerSec
+	ch <- c.ActiveSyncRequestsPerSec
+	ch <- c.PingCommandsPending
+	ch <- c.SyncCommandsPerSec
+	ch <- c.AvailabilityRequestsSec
+	ch <- c.CurrentUniqueUsers
+	ch <- c.OWARequestsPerSec
+	ch <- c.AutodiscoverRequestsPerSec
+	ch <- c.ActiveTasks
+	ch <- c.CompletedTasks
+	ch <- c.QueuedTasks
+	ch <- c.YieldedTasks
+	ch <- c.IsActive
+	ch <- c.RPCAveragedLatency
+	ch <- c.RPCRequests
+	ch <- c.ActiveUserCount
+	ch <- c.ConnectionCount
+	ch <- c.RPCOperationsPerSec
+	ch <- c.UserCount
+}
+
 // Collect collects exchange metrics and sends them to prometheus
 func (c *exchangeCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 
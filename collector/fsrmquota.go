@@ -3,11 +3,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("fsrmquota", newFSRMQuotaCollector)
+	registerCollectorInfo("fsrmquota", CollectorInfo{
+		Description: "Microsoft File Server Resource Manager (FSRM) Quotas collector",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "File Server Resource Manager role service",
+	})
+	registerApplicabilityCheck("fsrmquota", serviceInstalled("SrmSvc"))
 }
 
 type FSRMQuotaCollector struct {
@@ -27,55 +32,55 @@ type FSRMQuotaCollector struct {
 func newFSRMQuotaCollector() (Collector, error) {
 	const subsystem = "fsrmquota"
 	return &FSRMQuotaCollector{
-		QuotasCount: prometheus.NewDesc(
+		QuotasCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "count"),
 			"Number of Quotas",
 			nil,
 			nil,
 		),
-		PeakUsage: prometheus.NewDesc(
+		PeakUsage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "peak_usage_bytes"),
 			"The highest amount of disk space usage charged to this quota. (PeakUsage)",
 			[]string{"path", "template"},
 			nil,
 		),
-		Size: prometheus.NewDesc(
+		Size: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "size_bytes"),
 			"The size of the quota. (Size)",
 			[]string{"path", "template"},
 			nil,
 		),
-		Usage: prometheus.NewDesc(
+		Usage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "usage_bytes"),
 			"The current amount of disk space usage charged to this quota. (Usage)",
 			[]string{"path", "template"},
 			nil,
 		),
-		Description: prometheus.NewDesc(
+		Description: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "description"),
 			"Description of the quota (Description)",
 			[]string{"path", "template", "description"},
 			nil,
 		),
-		Disabled: prometheus.NewDesc(
+		Disabled: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "disabled"),
 			"If 1, the quota is disabled. The default value is 0. (Disabled)",
 			[]string{"path", "template"},
 			nil,
 		),
-		SoftLimit: prometheus.NewDesc(
+		SoftLimit: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "softlimit"),
 			"If 1, the quota is a soft limit. If 0, the quota is a hard limit. The default value is 0. Optional (SoftLimit)",
 			[]string{"path", "template"},
 			nil,
 		),
-		Template: prometheus.NewDesc(
+		Template: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "template"),
 			"Quota template name. (Template)",
 			[]string{"path", "template"},
 			nil,
 		),
-		MatchesTemplate: prometheus.NewDesc(
+		MatchesTemplate: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "matchestemplate"),
 			"If 1, the property values of this quota match those values of the template from which it was derived. (MatchesTemplate)",
 			[]string{"path", "template"},
@@ -84,10 +89,24 @@ func newFSRMQuotaCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *FSRMQuotaCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.QuotasCount
+	ch <- c.Path
+	ch <- c.PeakUsage
+	ch <- c.Size
+	ch <- c.Usage
+	ch <- c.Description
+	ch <- c.Disabled
+	ch <- c.MatchesTemplate
+	ch <- c.SoftLimit
+	ch <- c.Template
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *FSRMQuotaCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting fsrmquota metrics:", desc, err)
 		return err
 	}
@@ -111,13 +130,13 @@ type MSFT_FSRMQuota struct {
 	SoftLimit       bool
 }
 
-func (c *FSRMQuotaCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *FSRMQuotaCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []MSFT_FSRMQuota
 	q := queryAll(&dst)
 
 	var count int
 
-	if err := wmi.QueryNamespace(q, &dst, "root/microsoft/windows/fsrm"); err != nil {
+	if err := wmiQueryNamespace(ctx, q, &dst, "root/microsoft/windows/fsrm"); err != nil {
 		return nil, err
 	}
 
@@ -8,11 +8,17 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("hyperv", NewHyperVCollector)
+	registerCollectorInfo("hyperv", CollectorInfo{
+		Description: "Hyper-V hosts",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "Hyper-V role",
+		Expensive:   true,
+	})
+	registerApplicabilityCheck("hyperv", serviceInstalled("vmms"))
 }
 
 // HyperVCollector is a Prometheus collector for hyper-v
@@ -134,13 +140,13 @@ type HyperVCollector struct {
 func NewHyperVCollector() (Collector, error) {
 	buildSubsystemName := func(component string) string { return "hyperv_" + component }
 	return &HyperVCollector{
-		HealthCritical: prometheus.NewDesc(
+		HealthCritical: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("health"), "critical"),
 			"This counter represents the number of virtual machines with critical health",
 			nil,
 			nil,
 		),
-		HealthOk: prometheus.NewDesc(
+		HealthOk: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("health"), "ok"),
 			"This counter represents the number of virtual machines with ok health",
 			nil,
@@ -149,19 +155,19 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		PhysicalPagesAllocated: prometheus.NewDesc(
+		PhysicalPagesAllocated: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vid"), "physical_pages_allocated"),
 			"The number of physical pages allocated",
 			[]string{"vm"},
 			nil,
 		),
-		PreferredNUMANodeIndex: prometheus.NewDesc(
+		PreferredNUMANodeIndex: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vid"), "preferred_numa_node_index"),
 			"The preferred NUMA node index associated with this partition",
 			[]string{"vm"},
 			nil,
 		),
-		RemotePhysicalPages: prometheus.NewDesc(
+		RemotePhysicalPages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vid"), "remote_physical_pages"),
 			"The number of physical pages not allocated from the preferred NUMA node",
 			[]string{"vm"},
@@ -170,127 +176,127 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		AddressSpaces: prometheus.NewDesc(
+		AddressSpaces: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "address_spaces"),
 			"The number of address spaces in the virtual TLB of the partition",
 			nil,
 			nil,
 		),
-		AttachedDevices: prometheus.NewDesc(
+		AttachedDevices: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "attached_devices"),
 			"The number of devices attached to the partition",
 			nil,
 			nil,
 		),
-		DepositedPages: prometheus.NewDesc(
+		DepositedPages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "deposited_pages"),
 			"The number of pages deposited into the partition",
 			nil,
 			nil,
 		),
-		DeviceDMAErrors: prometheus.NewDesc(
+		DeviceDMAErrors: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "device_dma_errors"),
 			"An indicator of illegal DMA requests generated by all devices assigned to the partition",
 			nil,
 			nil,
 		),
-		DeviceInterruptErrors: prometheus.NewDesc(
+		DeviceInterruptErrors: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "device_interrupt_errors"),
 			"An indicator of illegal interrupt requests generated by all devices assigned to the partition",
 			nil,
 			nil,
 		),
-		DeviceInterruptMappings: prometheus.NewDesc(
+		DeviceInterruptMappings: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "device_interrupt_mappings"),
 			"The number of device interrupt mappings used by the partition",
 			nil,
 			nil,
 		),
-		DeviceInterruptThrottleEvents: prometheus.NewDesc(
+		DeviceInterruptThrottleEvents: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "device_interrupt_throttle_events"),
 			"The number of times an interrupt from a device assigned to the partition was temporarily throttled because the device was generating too many interrupts",
 			nil,
 			nil,
 		),
-		GPAPages: prometheus.NewDesc(
+		GPAPages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "preferred_numa_node_index"),
 			"The number of pages present in the GPA space of the partition (zero for root partition)",
 			nil,
 			nil,
 		),
-		GPASpaceModifications: prometheus.NewDesc(
+		GPASpaceModifications: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "gpa_space_modifications"),
 			"The rate of modifications to the GPA space of the partition",
 			nil,
 			nil,
 		),
-		IOTLBFlushCost: prometheus.NewDesc(
+		IOTLBFlushCost: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "io_tlb_flush_cost"),
 			"The average time (in nanoseconds) spent processing an I/O TLB flush",
 			nil,
 			nil,
 		),
-		IOTLBFlushes: prometheus.NewDesc(
+		IOTLBFlushes: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "io_tlb_flush"),
 			"The rate of flushes of I/O TLBs of the partition",
 			nil,
 			nil,
 		),
-		RecommendedVirtualTLBSize: prometheus.NewDesc(
+		RecommendedVirtualTLBSize: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "recommended_virtual_tlb_size"),
 			"The recommended number of pages to be deposited for the virtual TLB",
 			nil,
 			nil,
 		),
-		SkippedTimerTicks: prometheus.NewDesc(
+		SkippedTimerTicks: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "physical_pages_allocated"),
 			"The number of timer interrupts skipped for the partition",
 			nil,
 			nil,
 		),
-		Value1Gdevicepages: prometheus.NewDesc(
+		Value1Gdevicepages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "1G_device_pages"),
 			"The number of 1G pages present in the device space of the partition",
 			nil,
 			nil,
 		),
-		Value1GGPApages: prometheus.NewDesc(
+		Value1GGPApages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "1G_gpa_pages"),
 			"The number of 1G pages present in the GPA space of the partition",
 			nil,
 			nil,
 		),
-		Value2Mdevicepages: prometheus.NewDesc(
+		Value2Mdevicepages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "2M_device_pages"),
 			"The number of 2M pages present in the device space of the partition",
 			nil,
 			nil,
 		),
-		Value2MGPApages: prometheus.NewDesc(
+		Value2MGPApages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "2M_gpa_pages"),
 			"The number of 2M pages present in the GPA space of the partition",
 			nil,
 			nil,
 		),
-		Value4Kdevicepages: prometheus.NewDesc(
+		Value4Kdevicepages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "4K_device_pages"),
 			"The number of 4K pages present in the device space of the partition",
 			nil,
 			nil,
 		),
-		Value4KGPApages: prometheus.NewDesc(
+		Value4KGPApages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "4K_gpa_pages"),
 			"The number of 4K pages present in the GPA space of the partition",
 			nil,
 			nil,
 		),
-		VirtualTLBFlushEntires: prometheus.NewDesc(
+		VirtualTLBFlushEntires: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "virtual_tlb_flush_entires"),
 			"The rate of flushes of the entire virtual TLB",
 			nil,
 			nil,
 		),
-		VirtualTLBPages: prometheus.NewDesc(
+		VirtualTLBPages: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("root_partition"), "virtual_tlb_pages"),
 			"The number of pages used by the virtual TLB of the partition",
 			nil,
@@ -299,13 +305,13 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		VirtualProcessors: prometheus.NewDesc(
+		VirtualProcessors: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("hypervisor"), "virtual_processors"),
 			"The number of virtual processors present in the system",
 			nil,
 			nil,
 		),
-		LogicalProcessors: prometheus.NewDesc(
+		LogicalProcessors: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("hypervisor"), "logical_processors"),
 			"The number of logical processors present in the system",
 			nil,
@@ -314,19 +320,19 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		HostLPGuestRunTimePercent: prometheus.NewDesc(
+		HostLPGuestRunTimePercent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("host_lp"), "guest_run_time_percent"),
 			"The percentage of time spent by the processor in guest code",
 			[]string{"core"},
 			nil,
 		),
-		HostLPHypervisorRunTimePercent: prometheus.NewDesc(
+		HostLPHypervisorRunTimePercent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("host_lp"), "hypervisor_run_time_percent"),
 			"The percentage of time spent by the processor in hypervisor code",
 			[]string{"core"},
 			nil,
 		),
-		HostLPTotalRunTimePercent: prometheus.NewDesc(
+		HostLPTotalRunTimePercent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("host_lp"), "total_run_time_percent"),
 			"The percentage of time spent by the processor in guest and hypervisor code",
 			[]string{"core"},
@@ -335,25 +341,25 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		HostGuestRunTime: prometheus.NewDesc(
+		HostGuestRunTime: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("host_cpu"), "guest_run_time"),
 			"The time spent by the virtual processor in guest code",
 			[]string{"core"},
 			nil,
 		),
-		HostHypervisorRunTime: prometheus.NewDesc(
+		HostHypervisorRunTime: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("host_cpu"), "hypervisor_run_time"),
 			"The time spent by the virtual processor in hypervisor code",
 			[]string{"core"},
 			nil,
 		),
-		HostRemoteRunTime: prometheus.NewDesc(
+		HostRemoteRunTime: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("host_cpu"), "remote_run_time"),
 			"The time spent by the virtual processor running on a remote node",
 			[]string{"core"},
 			nil,
 		),
-		HostTotalRunTime: prometheus.NewDesc(
+		HostTotalRunTime: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("host_cpu"), "total_run_time"),
 			"The time spent by the virtual processor in guest and hypervisor code",
 			[]string{"core"},
@@ -362,25 +368,25 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		VMGuestRunTime: prometheus.NewDesc(
+		VMGuestRunTime: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_cpu"), "guest_run_time"),
 			"The time spent by the virtual processor in guest code",
 			[]string{"vm", "core"},
 			nil,
 		),
-		VMHypervisorRunTime: prometheus.NewDesc(
+		VMHypervisorRunTime: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_cpu"), "hypervisor_run_time"),
 			"The time spent by the virtual processor in hypervisor code",
 			[]string{"vm", "core"},
 			nil,
 		),
-		VMRemoteRunTime: prometheus.NewDesc(
+		VMRemoteRunTime: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_cpu"), "remote_run_time"),
 			"The time spent by the virtual processor running on a remote node",
 			[]string{"vm", "core"},
 			nil,
 		),
-		VMTotalRunTime: prometheus.NewDesc(
+		VMTotalRunTime: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_cpu"), "total_run_time"),
 			"The time spent by the virtual processor in guest and hypervisor code",
 			[]string{"vm", "core"},
@@ -388,127 +394,127 @@ func NewHyperVCollector() (Collector, error) {
 		),
 
 		//
-		BroadcastPacketsReceived: prometheus.NewDesc(
+		BroadcastPacketsReceived: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "broadcast_packets_received_total"),
 			"This represents the total number of broadcast packets received per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		BroadcastPacketsSent: prometheus.NewDesc(
+		BroadcastPacketsSent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "broadcast_packets_sent_total"),
 			"This represents the total number of broadcast packets sent per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		Bytes: prometheus.NewDesc(
+		Bytes: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "bytes_total"),
 			"This represents the total number of bytes per second traversing the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		BytesReceived: prometheus.NewDesc(
+		BytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "bytes_received_total"),
 			"This represents the total number of bytes received per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		BytesSent: prometheus.NewDesc(
+		BytesSent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "bytes_sent_total"),
 			"This represents the total number of bytes sent per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		DirectedPacketsReceived: prometheus.NewDesc(
+		DirectedPacketsReceived: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "directed_packets_received_total"),
 			"This represents the total number of directed packets received per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		DirectedPacketsSent: prometheus.NewDesc(
+		DirectedPacketsSent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "directed_packets_send_total"),
 			"This represents the total number of directed packets sent per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		DroppedPacketsIncoming: prometheus.NewDesc(
+		DroppedPacketsIncoming: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "dropped_packets_incoming_total"),
 			"This represents the total number of packet dropped per second by the virtual switch in the incoming direction",
 			[]string{"vswitch"},
 			nil,
 		),
-		DroppedPacketsOutgoing: prometheus.NewDesc(
+		DroppedPacketsOutgoing: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "dropped_packets_outcoming_total"),
 			"This represents the total number of packet dropped per second by the virtual switch in the outgoing direction",
 			[]string{"vswitch"},
 			nil,
 		),
-		ExtensionsDroppedPacketsIncoming: prometheus.NewDesc(
+		ExtensionsDroppedPacketsIncoming: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "extensions_dropped_packets_incoming_total"),
 			"This represents the total number of packet dropped per second by the virtual switch extensions in the incoming direction",
 			[]string{"vswitch"},
 			nil,
 		),
-		ExtensionsDroppedPacketsOutgoing: prometheus.NewDesc(
+		ExtensionsDroppedPacketsOutgoing: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "extensions_dropped_packets_outcoming_total"),
 			"This represents the total number of packet dropped per second by the virtual switch extensions in the outgoing direction",
 			[]string{"vswitch"},
 			nil,
 		),
-		LearnedMacAddresses: prometheus.NewDesc(
+		LearnedMacAddresses: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "learned_mac_addresses_total"),
 			"This counter represents the total number of learned MAC addresses of the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		MulticastPacketsReceived: prometheus.NewDesc(
+		MulticastPacketsReceived: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "multicast_packets_received_total"),
 			"This represents the total number of multicast packets received per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		MulticastPacketsSent: prometheus.NewDesc(
+		MulticastPacketsSent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "multicast_packets_sent_total"),
 			"This represents the total number of multicast packets sent per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		NumberofSendChannelMoves: prometheus.NewDesc(
+		NumberofSendChannelMoves: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "number_of_send_channel_moves_total"),
 			"This represents the total number of send channel moves per second on this virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		NumberofVMQMoves: prometheus.NewDesc(
+		NumberofVMQMoves: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "number_of_vmq_moves_total"),
 			"This represents the total number of VMQ moves per second on this virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		PacketsFlooded: prometheus.NewDesc(
+		PacketsFlooded: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "packets_flooded_total"),
 			"This counter represents the total number of packets flooded by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		Packets: prometheus.NewDesc(
+		Packets: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "packets_total"),
 			"This represents the total number of packets per second traversing the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		PacketsReceived: prometheus.NewDesc(
+		PacketsReceived: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "packets_received_total"),
 			"This represents the total number of packets received per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		PacketsSent: prometheus.NewDesc(
+		PacketsSent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "packets_sent_total"),
 			"This represents the total number of packets send per second by the virtual switch",
 			[]string{"vswitch"},
 			nil,
 		),
-		PurgedMacAddresses: prometheus.NewDesc(
+		PurgedMacAddresses: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vswitch"), "purged_mac_addresses_total"),
 			"This counter represents the total number of purged MAC addresses of the virtual switch",
 			[]string{"vswitch"},
@@ -517,37 +523,37 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		AdapterBytesDropped: prometheus.NewDesc(
+		AdapterBytesDropped: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("ethernet"), "bytes_dropped"),
 			"Bytes Dropped is the number of bytes dropped on the network adapter",
 			[]string{"adapter"},
 			nil,
 		),
-		AdapterBytesReceived: prometheus.NewDesc(
+		AdapterBytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("ethernet"), "bytes_received"),
 			"Bytes received is the number of bytes received on the network adapter",
 			[]string{"adapter"},
 			nil,
 		),
-		AdapterBytesSent: prometheus.NewDesc(
+		AdapterBytesSent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("ethernet"), "bytes_sent"),
 			"Bytes sent is the number of bytes sent over the network adapter",
 			[]string{"adapter"},
 			nil,
 		),
-		AdapterFramesDropped: prometheus.NewDesc(
+		AdapterFramesDropped: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("ethernet"), "frames_dropped"),
 			"Frames Dropped is the number of frames dropped on the network adapter",
 			[]string{"adapter"},
 			nil,
 		),
-		AdapterFramesReceived: prometheus.NewDesc(
+		AdapterFramesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("ethernet"), "frames_received"),
 			"Frames received is the number of frames received on the network adapter",
 			[]string{"adapter"},
 			nil,
 		),
-		AdapterFramesSent: prometheus.NewDesc(
+		AdapterFramesSent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("ethernet"), "frames_sent"),
 			"Frames sent is the number of frames sent over the network adapter",
 			[]string{"adapter"},
@@ -556,37 +562,37 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		VMStorageErrorCount: prometheus.NewDesc(
+		VMStorageErrorCount: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_device"), "error_count"),
 			"This counter represents the total number of errors that have occurred on this virtual device",
 			[]string{"vm_device"},
 			nil,
 		),
-		VMStorageQueueLength: prometheus.NewDesc(
+		VMStorageQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_device"), "queue_length"),
 			"This counter represents the current queue length on this virtual device",
 			[]string{"vm_device"},
 			nil,
 		),
-		VMStorageReadBytes: prometheus.NewDesc(
+		VMStorageReadBytes: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_device"), "bytes_read"),
 			"This counter represents the total number of bytes that have been read per second on this virtual device",
 			[]string{"vm_device"},
 			nil,
 		),
-		VMStorageReadOperations: prometheus.NewDesc(
+		VMStorageReadOperations: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_device"), "operations_read"),
 			"This counter represents the number of read operations that have occurred per second on this virtual device",
 			[]string{"vm_device"},
 			nil,
 		),
-		VMStorageWriteBytes: prometheus.NewDesc(
+		VMStorageWriteBytes: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_device"), "bytes_written"),
 			"This counter represents the total number of bytes that have been written per second on this virtual device",
 			[]string{"vm_device"},
 			nil,
 		),
-		VMStorageWriteOperations: prometheus.NewDesc(
+		VMStorageWriteOperations: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_device"), "operations_written"),
 			"This counter represents the number of write operations that have occurred per second on this virtual device",
 			[]string{"vm_device"},
@@ -595,37 +601,37 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		VMNetworkBytesReceived: prometheus.NewDesc(
+		VMNetworkBytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_interface"), "bytes_received"),
 			"This counter represents the total number of bytes received per second by the network adapter",
 			[]string{"vm_interface"},
 			nil,
 		),
-		VMNetworkBytesSent: prometheus.NewDesc(
+		VMNetworkBytesSent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_interface"), "bytes_sent"),
 			"This counter represents the total number of bytes sent per second by the network adapter",
 			[]string{"vm_interface"},
 			nil,
 		),
-		VMNetworkDroppedPacketsIncoming: prometheus.NewDesc(
+		VMNetworkDroppedPacketsIncoming: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_interface"), "packets_incoming_dropped"),
 			"This counter represents the total number of dropped packets per second in the incoming direction of the network adapter",
 			[]string{"vm_interface"},
 			nil,
 		),
-		VMNetworkDroppedPacketsOutgoing: prometheus.NewDesc(
+		VMNetworkDroppedPacketsOutgoing: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_interface"), "packets_outgoing_dropped"),
 			"This counter represents the total number of dropped packets per second in the outgoing direction of the network adapter",
 			[]string{"vm_interface"},
 			nil,
 		),
-		VMNetworkPacketsReceived: prometheus.NewDesc(
+		VMNetworkPacketsReceived: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_interface"), "packets_received"),
 			"This counter represents the total number of packets received per second by the network adapter",
 			[]string{"vm_interface"},
 			nil,
 		),
-		VMNetworkPacketsSent: prometheus.NewDesc(
+		VMNetworkPacketsSent: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_interface"), "packets_sent"),
 			"This counter represents the total number of packets sent per second by the network adapter",
 			[]string{"vm_interface"},
@@ -634,61 +640,61 @@ func NewHyperVCollector() (Collector, error) {
 
 		//
 
-		VMMemoryAddedMemory: prometheus.NewDesc(
+		VMMemoryAddedMemory: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "added_total"),
 			"This counter represents memory in MB added to the VM",
 			[]string{"vm"},
 			nil,
 		),
-		VMMemoryAveragePressure: prometheus.NewDesc(
+		VMMemoryAveragePressure: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "pressure_average"),
 			"This gauge represents the average pressure in the VM.",
 			[]string{"vm"},
 			nil,
 		),
-		VMMemoryCurrentPressure: prometheus.NewDesc(
+		VMMemoryCurrentPressure: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "pressure_current"),
 			"This gauge represents the current pressure in the VM.",
 			[]string{"vm"},
 			nil,
 		),
-		VMMemoryGuestVisiblePhysicalMemory: prometheus.NewDesc(
+		VMMemoryGuestVisiblePhysicalMemory: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "physical_guest_visible"),
 			"'This gauge represents the amount of memory in MB visible to the VM guest.'",
 			[]string{"vm"},
 			nil,
 		),
-		VMMemoryMaximumPressure: prometheus.NewDesc(
+		VMMemoryMaximumPressure: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "pressure_maximum"),
 			"This gauge represents the maximum pressure band in the VM.",
 			[]string{"vm"},
 			nil,
 		),
-		VMMemoryMemoryAddOperations: prometheus.NewDesc(
+		VMMemoryMemoryAddOperations: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "add_operations_total"),
 			"This counter represents the number of operations adding memory to the VM.",
 			[]string{"vm"},
 			nil,
 		),
-		VMMemoryMemoryRemoveOperations: prometheus.NewDesc(
+		VMMemoryMemoryRemoveOperations: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "remove_operations_total"),
 			"This counter represents the number of operations removing memory from the VM.",
 			[]string{"vm"},
 			nil,
 		),
-		VMMemoryMinimumPressure: prometheus.NewDesc(
+		VMMemoryMinimumPressure: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "pressure_minimum"),
 			"This gauge represents the minimum pressure band in the VM.",
 			[]string{"vm"},
 			nil,
 		),
-		VMMemoryPhysicalMemory: prometheus.NewDesc(
+		VMMemoryPhysicalMemory: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "physical"),
 			"This gauge represents the current amount of memory in MB assigned to the VM.",
 			[]string{"vm"},
 			nil,
 		),
-		VMMemoryRemovedMemory: prometheus.NewDesc(
+		VMMemoryRemovedMemory: newDesc(
 			prometheus.BuildFQName(Namespace, buildSubsystemName("vm_memory"), "removed_total"),
 			"This counter represents memory in MB removed from the VM",
 			[]string{"vm"},
@@ -697,65 +703,157 @@ func NewHyperVCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *HyperVCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.HealthCritical
+	ch <- c.HealthOk
+	ch <- c.PhysicalPagesAllocated
+	ch <- c.PreferredNUMANodeIndex
+	ch <- c.RemotePhysicalPages
+	ch <- c.AddressSpaces
+	ch <- c.AttachedDevices
+	ch <- c.DepositedPages
+	ch <- c.DeviceDMAErrors
+	ch <- c.DeviceInterruptErrors
+	ch <- c.DeviceInterruptMappings
+	ch <- c.DeviceInterruptThrottleEvents
+	ch <- c.GPAPages
+	ch <- c.GPASpaceModifications
+	ch <- c.IOTLBFlushCost
+	ch <- c.IOTLBFlushes
+	ch <- c.RecommendedVirtualTLBSize
+	ch <- c.SkippedTimerTicks
+	ch <- c.Value1Gdevicepages
+	ch <- c.Value1GGPApages
+	ch <- c.Value2Mdevicepages
+	ch <- c.Value2MGPApages
+	ch <- c.Value4Kdevicepages
+	ch <- c.Value4KGPApages
+	ch <- c.VirtualTLBFlushEntires
+	ch <- c.VirtualTLBPages
+	ch <- c.LogicalProcessors
+	ch <- c.VirtualProcessors
+	ch <- c.HostLPGuestRunTimePercent
+	ch <- c.HostLPHypervisorRunTimePercent
+	ch <- c.HostLPTotalRunTimePercent
+	ch <- c.HostGuestRunTime
+	ch <- c.HostHypervisorRunTime
+	ch <- c.HostRemoteRunTime
+	ch <- c.HostTotalRunTime
+	ch <- c.VMGuestRunTime
+	ch <- c.VMHypervisorRunTime
+	ch <- c.VMRemoteRunTime
+	ch <- c.VMTotalRunTime
+	ch <- c.BroadcastPacketsReceived
+	ch <- c.BroadcastPacketsSent
+	ch <- c.Bytes
+	ch <- c.BytesReceived
+	ch <- c.BytesSent
+	ch <- c.DirectedPacketsReceived
+	ch <- c.DirectedPacketsSent
+	ch <- c.DroppedPacketsIncoming
+	ch <- c.DroppedPacketsOutgoing
+	ch <- c.ExtensionsDroppedPacketsIncoming
+	ch <- c.ExtensionsDroppedPacketsOutgoing
+	ch <- c.LearnedMacAddresses
+	ch <- c.MulticastPacketsReceived
+	ch <- c.MulticastPacketsSent
+	ch <- c.NumberofSendChannelMoves
+	ch <- c.NumberofVMQMoves
+	ch <- c.PacketsFlooded
+	ch <- c.Packets
+	ch <- c.PacketsReceived
+	ch <- c.PacketsSent
+	ch <- c.PurgedMacAddresses
+	ch <- c.AdapterBytesDropped
+	ch <- c.AdapterBytesReceived
+	ch <- c.AdapterBytesSent
+	ch <- c.AdapterFramesDropped
+	ch <- c.AdapterFramesReceived
+	ch <- c.AdapterFramesSent
+	ch <- c.VMStorageErrorCount
+	ch <- c.VMStorageQueueLength
+	ch <- c.VMStorageReadBytes
+	ch <- c.VMStorageReadOperations
+	ch <- c.VMStorageWriteBytes
+	ch <- c.VMStorageWriteOperations
+	ch <- c.VMNetworkBytesReceived
+	ch <- c.VMNetworkBytesSent
+	ch <- c.VMNetworkDroppedPacketsIncoming
+	ch <- c.VMNetworkDroppedPacketsOutgoing
+	ch <- c.VMNetworkPacketsReceived
+	ch <- c.VMNetworkPacketsSent
+	ch <- c.VMMemoryAddedMemory
+	ch <- c.VMMemoryAveragePressure
+	ch <- c.VMMemoryCurrentPressure
+	ch <- c.VMMemoryGuestVisiblePhysicalMemory
+	ch <- c.VMMemoryMaximumPressure
+	ch <- c.VMMemoryMemoryAddOperations
+	ch <- c.VMMemoryMemoryRemoveOperations
+	ch <- c.VMMemoryMinimumPressure
+	ch <- c.VMMemoryPhysicalMemory
+	ch <- c.VMMemoryRemovedMemory
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *HyperVCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collectVmHealth(ch); err != nil {
+	if desc, err := c.collectVmHealth(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV health status metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectVmVid(ch); err != nil {
+	if desc, err := c.collectVmVid(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV pages metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectVmHv(ch); err != nil {
+	if desc, err := c.collectVmHv(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV hv status metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectVmProcessor(ch); err != nil {
+	if desc, err := c.collectVmProcessor(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV processor metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectHostLPUsage(ch); err != nil {
+	if desc, err := c.collectHostLPUsage(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV host logical processors metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectHostCpuUsage(ch); err != nil {
+	if desc, err := c.collectHostCpuUsage(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV host CPU metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectVmCpuUsage(ch); err != nil {
+	if desc, err := c.collectVmCpuUsage(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV VM CPU metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectVmSwitch(ch); err != nil {
+	if desc, err := c.collectVmSwitch(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV switch metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectVmEthernet(ch); err != nil {
+	if desc, err := c.collectVmEthernet(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV ethernet metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectVmStorage(ch); err != nil {
+	if desc, err := c.collectVmStorage(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV virtual storage metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectVmNetwork(ch); err != nil {
+	if desc, err := c.collectVmNetwork(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV virtual network metrics:", desc, err)
 		return err
 	}
 
-	if desc, err := c.collectVmMemory(ch); err != nil {
+	if desc, err := c.collectVmMemory(ctx, ch); err != nil {
 		log.Error("failed collecting hyperV virtual memory metrics:", desc, err)
 		return err
 	}
@@ -769,10 +867,10 @@ type Win32_PerfRawData_VmmsVirtualMachineStats_HyperVVirtualMachineHealthSummary
 	HealthOk       uint32
 }
 
-func (c *HyperVCollector) collectVmHealth(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmHealth(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_VmmsVirtualMachineStats_HyperVVirtualMachineHealthSummary
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -802,10 +900,10 @@ type Win32_PerfRawData_VidPerfProvider_HyperVVMVidPartition struct {
 	RemotePhysicalPages    uint64
 }
 
-func (c *HyperVCollector) collectVmVid(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmVid(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_VidPerfProvider_HyperVVMVidPartition
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -866,10 +964,10 @@ type Win32_PerfRawData_HvStats_HyperVHypervisorRootPartition struct {
 	VirtualTLBPages               uint64
 }
 
-func (c *HyperVCollector) collectVmHv(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmHv(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_HvStats_HyperVHypervisorRootPartition
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1004,10 +1102,10 @@ type Win32_PerfRawData_HvStats_HyperVHypervisor struct {
 	VirtualProcessors uint64
 }
 
-func (c *HyperVCollector) collectVmProcessor(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmProcessor(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_HvStats_HyperVHypervisor
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1038,10 +1136,10 @@ type Win32_PerfRawData_HvStats_HyperVHypervisorLogicalProcessor struct {
 	PercentTotalRunTime      uint
 }
 
-func (c *HyperVCollector) collectHostLPUsage(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectHostLPUsage(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_HvStats_HyperVHypervisorLogicalProcessor
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1092,10 +1190,10 @@ type Win32_PerfRawData_HvStats_HyperVHypervisorRootVirtualProcessor struct {
 	PercentTotalRunTime      uint64
 }
 
-func (c *HyperVCollector) collectHostCpuUsage(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectHostCpuUsage(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_HvStats_HyperVHypervisorRootVirtualProcessor
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1153,10 +1251,10 @@ type Win32_PerfRawData_HvStats_HyperVHypervisorVirtualProcessor struct {
 	PercentTotalRunTime      uint64
 }
 
-func (c *HyperVCollector) collectVmCpuUsage(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmCpuUsage(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_HvStats_HyperVHypervisorVirtualProcessor
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1240,10 +1338,10 @@ type Win32_PerfRawData_NvspSwitchStats_HyperVVirtualSwitch struct {
 	PurgedMacAddressesPersec               uint64
 }
 
-func (c *HyperVCollector) collectVmSwitch(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmSwitch(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NvspSwitchStats_HyperVVirtualSwitch
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1399,10 +1497,10 @@ type Win32_PerfRawData_EthernetPerfProvider_HyperVLegacyNetworkAdapter struct {
 	FramesSentPersec     uint64
 }
 
-func (c *HyperVCollector) collectVmEthernet(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmEthernet(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_EthernetPerfProvider_HyperVLegacyNetworkAdapter
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1469,10 +1567,10 @@ type Win32_PerfRawData_Counters_HyperVVirtualStorageDevice struct {
 	WriteOperationsPerSec uint64
 }
 
-func (c *HyperVCollector) collectVmStorage(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmStorage(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_Counters_HyperVVirtualStorageDevice
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1538,10 +1636,10 @@ type Win32_PerfRawData_NvspNicStats_HyperVVirtualNetworkAdapter struct {
 	PacketsSentPersec            uint64
 }
 
-func (c *HyperVCollector) collectVmNetwork(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmNetwork(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NvspNicStats_HyperVVirtualNetworkAdapter
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1611,10 +1709,10 @@ type Win32_PerfRawData_BalancerStats_HyperVDynamicMemoryVM struct {
 	RemovedMemory              uint64
 }
 
-func (c *HyperVCollector) collectVmMemory(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *HyperVCollector) collectVmMemory(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_BalancerStats_HyperVDynamicMemoryVM
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -4,26 +4,37 @@
 package collector
 
 import (
-	"fmt"
 	"regexp"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sys/windows/registry"
 )
 
+const (
+	siteWhitelistFlag = "collector.iis.site-whitelist"
+	siteBlacklistFlag = "collector.iis.site-blacklist"
+	appWhitelistFlag  = "collector.iis.app-whitelist"
+	appBlacklistFlag  = "collector.iis.app-blacklist"
+)
+
 func init() {
-	registerCollector("iis", NewIISCollector, "Web Service", "APP_POOL_WAS", "Web Service Cache", "W3SVC_W3WP")
+	registerCollectorWithSettings("iis", NewIISCollector, "Web Service", "APP_POOL_WAS", "Web Service Cache", "W3SVC_W3WP")
+	registerCollectorInfo("iis", CollectorInfo{
+		Description: "IIS sites and applications",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "IIS",
+	})
+	registerApplicabilityCheck("iis", perflibObjectsPresent("Web Service"))
+
+	kingpin.Flag(siteWhitelistFlag, "Regexp of sites to whitelist. Site name must both match whitelist and not match blacklist to be included.").Default(".+").String()
+	kingpin.Flag(siteBlacklistFlag, "Regexp of sites to blacklist. Site name must both match whitelist and not match blacklist to be included.").String()
+	kingpin.Flag(appWhitelistFlag, "Regexp of apps to whitelist. App name must both match whitelist and not match blacklist to be included.").Default(".+").String()
+	kingpin.Flag(appBlacklistFlag, "Regexp of apps to blacklist. App name must both match whitelist and not match blacklist to be included.").String()
 }
 
-var (
-	siteWhitelist = kingpin.Flag("collector.iis.site-whitelist", "Regexp of sites to whitelist. Site name must both match whitelist and not match blacklist to be included.").Default(".+").String()
-	siteBlacklist = kingpin.Flag("collector.iis.site-blacklist", "Regexp of sites to blacklist. Site name must both match whitelist and not match blacklist to be included.").String()
-	appWhitelist  = kingpin.Flag("collector.iis.app-whitelist", "Regexp of apps to whitelist. App name must both match whitelist and not match blacklist to be included.").Default(".+").String()
-	appBlacklist  = kingpin.Flag("collector.iis.app-blacklist", "Regexp of apps to blacklist. App name must both match whitelist and not match blacklist to be included.").String()
-)
-
 type simple_version struct {
 	major uint64
 	minor uint64
@@ -195,145 +206,145 @@ type IISCollector struct {
 	iis_version simple_version
 }
 
-func NewIISCollector() (Collector, error) {
+func NewIISCollector(settings *config.Config) (Collector, error) {
 	const subsystem = "iis"
 
 	return &IISCollector{
 		iis_version: getIISVersion(),
 
-		siteWhitelistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *siteWhitelist)),
-		siteBlacklistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *siteBlacklist)),
-		appWhitelistPattern:  regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *appWhitelist)),
-		appBlacklistPattern:  regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *appBlacklist)),
+		siteWhitelistPattern: settings.Collector.IIS.SiteWhitelist.Regexp(),
+		siteBlacklistPattern: settings.Collector.IIS.SiteBlacklist.Regexp(),
+		appWhitelistPattern:  settings.Collector.IIS.AppWhitelist.Regexp(),
+		appBlacklistPattern:  settings.Collector.IIS.AppBlacklist.Regexp(),
 
 		// Web Service
-		CurrentAnonymousUsers: prometheus.NewDesc(
+		CurrentAnonymousUsers: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_anonymous_users"),
 			"Number of users who currently have an anonymous connection using the Web service (WebService.CurrentAnonymousUsers)",
 			[]string{"site"},
 			nil,
 		),
-		CurrentBlockedAsyncIORequests: prometheus.NewDesc(
+		CurrentBlockedAsyncIORequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_blocked_async_io_requests"),
 			"Current requests temporarily blocked due to bandwidth throttling settings (WebService.CurrentBlockedAsyncIORequests)",
 			[]string{"site"},
 			nil,
 		),
-		CurrentCGIRequests: prometheus.NewDesc(
+		CurrentCGIRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_cgi_requests"),
 			"Current number of CGI requests being simultaneously processed by the Web service (WebService.CurrentCGIRequests)",
 			[]string{"site"},
 			nil,
 		),
-		CurrentConnections: prometheus.NewDesc(
+		CurrentConnections: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_connections"),
 			"Current number of connections established with the Web service (WebService.CurrentConnections)",
 			[]string{"site"},
 			nil,
 		),
-		CurrentISAPIExtensionRequests: prometheus.NewDesc(
+		CurrentISAPIExtensionRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_isapi_extension_requests"),
 			"Current number of ISAPI requests being simultaneously processed by the Web service (WebService.CurrentISAPIExtensionRequests)",
 			[]string{"site"},
 			nil,
 		),
-		CurrentNonAnonymousUsers: prometheus.NewDesc(
+		CurrentNonAnonymousUsers: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_non_anonymous_users"),
 			"Number of users who currently have a non-anonymous connection using the Web service (WebService.CurrentNonAnonymousUsers)",
 			[]string{"site"},
 			nil,
 		),
-		ServiceUptime: prometheus.NewDesc(
+		ServiceUptime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "service_uptime"),
 			"Number of seconds the WebService is up (WebService.ServiceUptime)",
 			[]string{"site"},
 			nil,
 		),
-		TotalBytesReceived: prometheus.NewDesc(
+		TotalBytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "received_bytes_total"),
 			"Number of data bytes that have been received by the Web service (WebService.TotalBytesReceived)",
 			[]string{"site"},
 			nil,
 		),
-		TotalBytesSent: prometheus.NewDesc(
+		TotalBytesSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sent_bytes_total"),
 			"Number of data bytes that have been sent by the Web service (WebService.TotalBytesSent)",
 			[]string{"site"},
 			nil,
 		),
-		TotalAnonymousUsers: prometheus.NewDesc(
+		TotalAnonymousUsers: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "anonymous_users_total"),
 			"Total number of users who established an anonymous connection with the Web service (WebService.TotalAnonymousUsers)",
 			[]string{"site"},
 			nil,
 		),
-		TotalBlockedAsyncIORequests: prometheus.NewDesc(
+		TotalBlockedAsyncIORequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "blocked_async_io_requests_total"),
 			"Total requests temporarily blocked due to bandwidth throttling settings (WebService.TotalBlockedAsyncIORequests)",
 			[]string{"site"},
 			nil,
 		),
-		TotalCGIRequests: prometheus.NewDesc(
+		TotalCGIRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cgi_requests_total"),
 			"Total CGI requests is the total number of CGI requests (WebService.TotalCGIRequests)",
 			[]string{"site"},
 			nil,
 		),
-		TotalConnectionAttemptsAllInstances: prometheus.NewDesc(
+		TotalConnectionAttemptsAllInstances: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_attempts_all_instances_total"),
 			"Number of connections that have been attempted using the Web service (WebService.TotalConnectionAttemptsAllInstances)",
 			[]string{"site"},
 			nil,
 		),
-		TotalRequests: prometheus.NewDesc(
+		TotalRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "requests_total"),
 			"Number of HTTP requests (WebService.TotalRequests)",
 			[]string{"site", "method"},
 			nil,
 		),
-		TotalFilesReceived: prometheus.NewDesc(
+		TotalFilesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "files_received_total"),
 			"Number of files received by the Web service (WebService.TotalFilesReceived)",
 			[]string{"site"},
 			nil,
 		),
-		TotalFilesSent: prometheus.NewDesc(
+		TotalFilesSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "files_sent_total"),
 			"Number of files sent by the Web service (WebService.TotalFilesSent)",
 			[]string{"site"},
 			nil,
 		),
-		TotalISAPIExtensionRequests: prometheus.NewDesc(
+		TotalISAPIExtensionRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ipapi_extension_requests_total"),
 			"ISAPI Extension Requests received (WebService.TotalISAPIExtensionRequests)",
 			[]string{"site"},
 			nil,
 		),
-		TotalLockedErrors: prometheus.NewDesc(
+		TotalLockedErrors: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "locked_errors_total"),
 			"Number of requests that couldn't be satisfied by the server because the requested resource was locked (WebService.TotalLockedErrors)",
 			[]string{"site"},
 			nil,
 		),
-		TotalLogonAttempts: prometheus.NewDesc(
+		TotalLogonAttempts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "logon_attempts_total"),
 			"Number of logons attempts to the Web Service (WebService.TotalLogonAttempts)",
 			[]string{"site"},
 			nil,
 		),
-		TotalNonAnonymousUsers: prometheus.NewDesc(
+		TotalNonAnonymousUsers: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "non_anonymous_users_total"),
 			"Number of users who established a non-anonymous connection with the Web service (WebService.TotalNonAnonymousUsers)",
 			[]string{"site"},
 			nil,
 		),
-		TotalNotFoundErrors: prometheus.NewDesc(
+		TotalNotFoundErrors: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "not_found_errors_total"),
 			"Number of requests that couldn't be satisfied by the server because the requested document could not be found (WebService.TotalNotFoundErrors)",
 			[]string{"site"},
 			nil,
 		),
-		TotalRejectedAsyncIORequests: prometheus.NewDesc(
+		TotalRejectedAsyncIORequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rejected_async_io_requests_total"),
 			"Requests rejected due to bandwidth throttling settings (WebService.TotalRejectedAsyncIORequests)",
 			[]string{"site"},
@@ -341,79 +352,79 @@ func NewIISCollector() (Collector, error) {
 		),
 
 		// APP_POOL_WAS
-		CurrentApplicationPoolState: prometheus.NewDesc(
+		CurrentApplicationPoolState: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_application_pool_state"),
 			"The current status of the application pool (1 - Uninitialized, 2 - Initialized, 3 - Running, 4 - Disabling, 5 - Disabled, 6 - Shutdown Pending, 7 - Delete Pending) (CurrentApplicationPoolState)",
 			[]string{"app", "state"},
 			nil,
 		),
-		CurrentApplicationPoolUptime: prometheus.NewDesc(
+		CurrentApplicationPoolUptime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_application_pool_start_time"),
 			"The unix timestamp for the application pool start time (CurrentApplicationPoolUptime)",
 			[]string{"app"},
 			nil,
 		),
-		CurrentWorkerProcesses: prometheus.NewDesc(
+		CurrentWorkerProcesses: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_worker_processes"),
 			"The current number of worker processes that are running in the application pool (CurrentWorkerProcesses)",
 			[]string{"app"},
 			nil,
 		),
-		MaximumWorkerProcesses: prometheus.NewDesc(
+		MaximumWorkerProcesses: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "maximum_worker_processes"),
 			"The maximum number of worker processes that have been created for the application pool since Windows Process Activation Service (WAS) started (MaximumWorkerProcesses)",
 			[]string{"app"},
 			nil,
 		),
-		RecentWorkerProcessFailures: prometheus.NewDesc(
+		RecentWorkerProcessFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "recent_worker_process_failures"),
 			"The number of times that worker processes for the application pool failed during the rapid-fail protection interval (RecentWorkerProcessFailures)",
 			[]string{"app"},
 			nil,
 		),
-		TimeSinceLastWorkerProcessFailure: prometheus.NewDesc(
+		TimeSinceLastWorkerProcessFailure: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "time_since_last_worker_process_failure"),
 			"The length of time, in seconds, since the last worker process failure occurred for the application pool (TimeSinceLastWorkerProcessFailure)",
 			[]string{"app"},
 			nil,
 		),
-		TotalApplicationPoolRecycles: prometheus.NewDesc(
+		TotalApplicationPoolRecycles: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "total_application_pool_recycles"),
 			"The number of times that the application pool has been recycled since Windows Process Activation Service (WAS) started (TotalApplicationPoolRecycles)",
 			[]string{"app"},
 			nil,
 		),
-		TotalApplicationPoolUptime: prometheus.NewDesc(
+		TotalApplicationPoolUptime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "total_application_pool_start_time"),
 			"The unix timestamp for the application pool of when the Windows Process Activation Service (WAS) started (TotalApplicationPoolUptime)",
 			[]string{"app"},
 			nil,
 		),
-		TotalWorkerProcessesCreated: prometheus.NewDesc(
+		TotalWorkerProcessesCreated: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "total_worker_processes_created"),
 			"The number of worker processes created for the application pool since Windows Process Activation Service (WAS) started (TotalWorkerProcessesCreated)",
 			[]string{"app"},
 			nil,
 		),
-		TotalWorkerProcessFailures: prometheus.NewDesc(
+		TotalWorkerProcessFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "total_worker_process_failures"),
 			"The number of times that worker processes have crashed since the application pool was started (TotalWorkerProcessFailures)",
 			[]string{"app"},
 			nil,
 		),
-		TotalWorkerProcessPingFailures: prometheus.NewDesc(
+		TotalWorkerProcessPingFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "total_worker_process_ping_failures"),
 			"The number of times that Windows Process Activation Service (WAS) did not receive a response to ping messages sent to a worker process (TotalWorkerProcessPingFailures)",
 			[]string{"app"},
 			nil,
 		),
-		TotalWorkerProcessShutdownFailures: prometheus.NewDesc(
+		TotalWorkerProcessShutdownFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "total_worker_process_shutdown_failures"),
 			"The number of times that Windows Process Activation Service (WAS) failed to shut down a worker process (TotalWorkerProcessShutdownFailures)",
 			[]string{"app"},
 			nil,
 		),
-		TotalWorkerProcessStartupFailures: prometheus.NewDesc(
+		TotalWorkerProcessStartupFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "total_worker_process_startup_failures"),
 			"The number of times that Windows Process Activation Service (WAS) failed to start a worker process (TotalWorkerProcessStartupFailures)",
 			[]string{"app"},
@@ -421,224 +432,224 @@ func NewIISCollector() (Collector, error) {
 		),
 
 		// W3SVC_W3WP
-		Threads: prometheus.NewDesc(
+		Threads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_threads"),
 			"Number of threads actively processing requests in the worker process",
 			[]string{"app", "pid", "state"},
 			nil,
 		),
-		MaximumThreads: prometheus.NewDesc(
+		MaximumThreads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_max_threads"),
 			"Maximum number of threads to which the thread pool can grow as needed",
 			[]string{"app", "pid"},
 			nil,
 		),
-		RequestsTotal: prometheus.NewDesc(
+		RequestsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_requests_total"),
 			"Total number of HTTP requests served by the worker process",
 			[]string{"app", "pid"},
 			nil,
 		),
-		RequestsActive: prometheus.NewDesc(
+		RequestsActive: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_current_requests"),
 			"Current number of requests being processed by the worker process",
 			[]string{"app", "pid"},
 			nil,
 		),
-		ActiveFlushedEntries: prometheus.NewDesc(
+		ActiveFlushedEntries: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_cache_active_flushed_entries"),
 			"Number of file handles cached in user-mode that will be closed when all current transfers complete.",
 			[]string{"app", "pid"},
 			nil,
 		),
-		CurrentFileCacheMemoryUsage: prometheus.NewDesc(
+		CurrentFileCacheMemoryUsage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_file_cache_memory_bytes"),
 			"Current number of bytes used by user-mode file cache",
 			[]string{"app", "pid"},
 			nil,
 		),
-		MaximumFileCacheMemoryUsage: prometheus.NewDesc(
+		MaximumFileCacheMemoryUsage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_file_cache_max_memory_bytes"),
 			"Maximum number of bytes used by user-mode file cache",
 			[]string{"app", "pid"},
 			nil,
 		),
-		FileCacheFlushesTotal: prometheus.NewDesc(
+		FileCacheFlushesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_file_cache_flushes_total"),
 			"Total number of files removed from the user-mode cache",
 			[]string{"app", "pid"},
 			nil,
 		),
-		FileCacheQueriesTotal: prometheus.NewDesc(
+		FileCacheQueriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_file_cache_queries_total"),
 			"Total file cache queries (hits + misses)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		FileCacheHitsTotal: prometheus.NewDesc(
+		FileCacheHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_file_cache_hits_total"),
 			"Total number of successful lookups in the user-mode file cache",
 			[]string{"app", "pid"},
 			nil,
 		),
-		FilesCached: prometheus.NewDesc(
+		FilesCached: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_file_cache_items"),
 			"Current number of files whose contents are present in user-mode cache",
 			[]string{"app", "pid"},
 			nil,
 		),
-		FilesCachedTotal: prometheus.NewDesc(
+		FilesCachedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_file_cache_items_total"),
 			"Total number of files whose contents were ever added to the user-mode cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		FilesFlushedTotal: prometheus.NewDesc(
+		FilesFlushedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_file_cache_items_flushed_total"),
 			"Total number of file handles that have been removed from the user-mode cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		URICacheFlushesTotal: prometheus.NewDesc(
+		URICacheFlushesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_uri_cache_flushes_total"),
 			"Total number of URI cache flushes (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		URICacheQueriesTotal: prometheus.NewDesc(
+		URICacheQueriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_uri_cache_queries_total"),
 			"Total number of uri cache queries (hits + misses)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		URICacheHitsTotal: prometheus.NewDesc(
+		URICacheHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_uri_cache_hits_total"),
 			"Total number of successful lookups in the user-mode URI cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		URIsCached: prometheus.NewDesc(
+		URIsCached: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_uri_cache_items"),
 			"Number of URI information blocks currently in the user-mode cache",
 			[]string{"app", "pid"},
 			nil,
 		),
-		URIsCachedTotal: prometheus.NewDesc(
+		URIsCachedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_uri_cache_items_total"),
 			"Total number of URI information blocks added to the user-mode cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		URIsFlushedTotal: prometheus.NewDesc(
+		URIsFlushedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_uri_cache_items_flushed_total"),
 			"The number of URI information blocks that have been removed from the user-mode cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		MetadataCached: prometheus.NewDesc(
+		MetadataCached: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_metadata_cache_items"),
 			"Number of metadata information blocks currently present in user-mode cache",
 			[]string{"app", "pid"},
 			nil,
 		),
-		MetadataCacheFlushes: prometheus.NewDesc(
+		MetadataCacheFlushes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_metadata_cache_flushes_total"),
 			"Total number of user-mode metadata cache flushes (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		MetadataCacheQueriesTotal: prometheus.NewDesc(
+		MetadataCacheQueriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_metadata_cache_queries_total"),
 			"Total metadata cache queries (hits + misses)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		MetadataCacheHitsTotal: prometheus.NewDesc(
+		MetadataCacheHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_metadata_cache_hits_total"),
 			"Total number of successful lookups in the user-mode metadata cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		MetadataCachedTotal: prometheus.NewDesc(
+		MetadataCachedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_metadata_cache_items_cached_total"),
 			"Total number of metadata information blocks added to the user-mode cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		MetadataFlushedTotal: prometheus.NewDesc(
+		MetadataFlushedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_metadata_cache_items_flushed_total"),
 			"Total number of metadata information blocks removed from the user-mode cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		OutputCacheActiveFlushedItems: prometheus.NewDesc(
+		OutputCacheActiveFlushedItems: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_output_cache_active_flushed_items"),
 			"",
 			[]string{"app", "pid"},
 			nil,
 		),
-		OutputCacheItems: prometheus.NewDesc(
+		OutputCacheItems: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_output_cache_items"),
 			"Number of items current present in output cache",
 			[]string{"app", "pid"},
 			nil,
 		),
-		OutputCacheMemoryUsage: prometheus.NewDesc(
+		OutputCacheMemoryUsage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_output_cache_memory_bytes"),
 			"Current number of bytes used by output cache",
 			[]string{"app", "pid"},
 			nil,
 		),
-		OutputCacheQueriesTotal: prometheus.NewDesc(
+		OutputCacheQueriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_output_queries_total"),
 			"Total number of output cache queries (hits + misses)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		OutputCacheHitsTotal: prometheus.NewDesc(
+		OutputCacheHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_output_cache_hits_total"),
 			"Total number of successful lookups in output cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		OutputCacheFlushedItemsTotal: prometheus.NewDesc(
+		OutputCacheFlushedItemsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_output_cache_items_flushed_total"),
 			"Total number of items flushed from output cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
-		OutputCacheFlushesTotal: prometheus.NewDesc(
+		OutputCacheFlushesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_output_cache_flushes_total"),
 			"Total number of flushes of output cache (since service startup)",
 			[]string{"app", "pid"},
 			nil,
 		),
 		// W3SVC_W3WP_IIS8
-		RequestErrorsTotal: prometheus.NewDesc(
+		RequestErrorsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_request_errors_total"),
 			"Total number of requests that returned an error",
 			[]string{"app", "pid", "status_code"},
 			nil,
 		),
-		WebSocketRequestsActive: prometheus.NewDesc(
+		WebSocketRequestsActive: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_current_websocket_requests"),
 			"",
 			[]string{"app", "pid"},
 			nil,
 		),
-		WebSocketConnectionAttempts: prometheus.NewDesc(
+		WebSocketConnectionAttempts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_websocket_connection_attempts_total"),
 			"",
 			[]string{"app", "pid"},
 			nil,
 		),
-		WebSocketConnectionsAccepted: prometheus.NewDesc(
+		WebSocketConnectionsAccepted: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_websocket_connection_accepted_total"),
 			"",
 			[]string{"app", "pid"},
 			nil,
 		),
-		WebSocketConnectionsRejected: prometheus.NewDesc(
+		WebSocketConnectionsRejected: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "worker_websocket_connection_rejected_total"),
 			"",
 			[]string{"app", "pid"},
@@ -646,169 +657,169 @@ func NewIISCollector() (Collector, error) {
 		),
 
 		// Web Service Cache
-		ServiceCache_ActiveFlushedEntries: prometheus.NewDesc(
+		ServiceCache_ActiveFlushedEntries: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_cache_active_flushed_entries"),
 			"Number of file handles cached that will be closed when all current transfers complete.",
 			nil,
 			nil,
 		),
-		ServiceCache_CurrentFileCacheMemoryUsage: prometheus.NewDesc(
+		ServiceCache_CurrentFileCacheMemoryUsage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_file_cache_memory_bytes"),
 			"Current number of bytes used by file cache",
 			nil,
 			nil,
 		),
-		ServiceCache_MaximumFileCacheMemoryUsage: prometheus.NewDesc(
+		ServiceCache_MaximumFileCacheMemoryUsage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_file_cache_max_memory_bytes"),
 			"Maximum number of bytes used by file cache",
 			nil,
 			nil,
 		),
-		ServiceCache_FileCacheFlushesTotal: prometheus.NewDesc(
+		ServiceCache_FileCacheFlushesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_file_cache_flushes_total"),
 			"Total number of file cache flushes (since service startup)",
 			nil,
 			nil,
 		),
-		ServiceCache_FileCacheQueriesTotal: prometheus.NewDesc(
+		ServiceCache_FileCacheQueriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_file_cache_queries_total"),
 			"Total number of file cache queries (hits + misses)",
 			nil,
 			nil,
 		),
-		ServiceCache_FileCacheHitsTotal: prometheus.NewDesc(
+		ServiceCache_FileCacheHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_file_cache_hits_total"),
 			"Total number of successful lookups in the user-mode file cache",
 			nil,
 			nil,
 		),
-		ServiceCache_FilesCached: prometheus.NewDesc(
+		ServiceCache_FilesCached: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_file_cache_items"),
 			"Current number of files whose contents are present in cache",
 			nil,
 			nil,
 		),
-		ServiceCache_FilesCachedTotal: prometheus.NewDesc(
+		ServiceCache_FilesCachedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_file_cache_items_total"),
 			"Total number of files whose contents were ever added to the cache (since service startup)",
 			nil,
 			nil,
 		),
-		ServiceCache_FilesFlushedTotal: prometheus.NewDesc(
+		ServiceCache_FilesFlushedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_file_cache_items_flushed_total"),
 			"Total number of file handles that have been removed from the cache (since service startup)",
 			nil,
 			nil,
 		),
-		ServiceCache_URICacheFlushesTotal: prometheus.NewDesc(
+		ServiceCache_URICacheFlushesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_uri_cache_flushes_total"),
 			"Total number of URI cache flushes (since service startup)",
 			[]string{"mode"},
 			nil,
 		),
-		ServiceCache_URICacheQueriesTotal: prometheus.NewDesc(
+		ServiceCache_URICacheQueriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_uri_cache_queries_total"),
 			"Total number of uri cache queries (hits + misses)",
 			[]string{"mode"},
 			nil,
 		),
-		ServiceCache_URICacheHitsTotal: prometheus.NewDesc(
+		ServiceCache_URICacheHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_uri_cache_hits_total"),
 			"Total number of successful lookups in the URI cache (since service startup)",
 			[]string{"mode"},
 			nil,
 		),
-		ServiceCache_URIsCached: prometheus.NewDesc(
+		ServiceCache_URIsCached: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_uri_cache_items"),
 			"Number of URI information blocks currently in the cache",
 			[]string{"mode"},
 			nil,
 		),
-		ServiceCache_URIsCachedTotal: prometheus.NewDesc(
+		ServiceCache_URIsCachedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_uri_cache_items_total"),
 			"Total number of URI information blocks added to the cache (since service startup)",
 			[]string{"mode"},
 			nil,
 		),
-		ServiceCache_URIsFlushedTotal: prometheus.NewDesc(
+		ServiceCache_URIsFlushedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_uri_cache_items_flushed_total"),
 			"The number of URI information blocks that have been removed from the cache (since service startup)",
 			[]string{"mode"},
 			nil,
 		),
-		ServiceCache_MetadataCached: prometheus.NewDesc(
+		ServiceCache_MetadataCached: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_metadata_cache_items"),
 			"Number of metadata information blocks currently present in cache",
 			nil,
 			nil,
 		),
-		ServiceCache_MetadataCacheFlushes: prometheus.NewDesc(
+		ServiceCache_MetadataCacheFlushes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_metadata_cache_flushes_total"),
 			"Total number of metadata cache flushes (since service startup)",
 			nil,
 			nil,
 		),
-		ServiceCache_MetadataCacheQueriesTotal: prometheus.NewDesc(
+		ServiceCache_MetadataCacheQueriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_metadata_cache_queries_total"),
 			"Total metadata cache queries (hits + misses)",
 			nil,
 			nil,
 		),
-		ServiceCache_MetadataCacheHitsTotal: prometheus.NewDesc(
+		ServiceCache_MetadataCacheHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_metadata_cache_hits_total"),
 			"Total number of successful lookups in the metadata cache (since service startup)",
 			nil,
 			nil,
 		),
-		ServiceCache_MetadataCachedTotal: prometheus.NewDesc(
+		ServiceCache_MetadataCachedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_metadata_cache_items_cached_total"),
 			"Total number of metadata information blocks added to the cache (since service startup)",
 			nil,
 			nil,
 		),
-		ServiceCache_MetadataFlushedTotal: prometheus.NewDesc(
+		ServiceCache_MetadataFlushedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_metadata_cache_items_flushed_total"),
 			"Total number of metadata information blocks removed from the cache (since service startup)",
 			nil,
 			nil,
 		),
-		ServiceCache_OutputCacheActiveFlushedItems: prometheus.NewDesc(
+		ServiceCache_OutputCacheActiveFlushedItems: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_output_cache_active_flushed_items"),
 			"",
 			nil,
 			nil,
 		),
-		ServiceCache_OutputCacheItems: prometheus.NewDesc(
+		ServiceCache_OutputCacheItems: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_output_cache_items"),
 			"Number of items current present in output cache",
 			nil,
 			nil,
 		),
-		ServiceCache_OutputCacheMemoryUsage: prometheus.NewDesc(
+		ServiceCache_OutputCacheMemoryUsage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_output_cache_memory_bytes"),
 			"Current number of bytes used by output cache",
 			nil,
 			nil,
 		),
-		ServiceCache_OutputCacheQueriesTotal: prometheus.NewDesc(
+		ServiceCache_OutputCacheQueriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_output_cache_queries_total"),
 			"Total output cache queries (hits + misses)",
 			nil,
 			nil,
 		),
-		ServiceCache_OutputCacheHitsTotal: prometheus.NewDesc(
+		ServiceCache_OutputCacheHitsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_output_cache_hits_total"),
 			"Total number of successful lookups in output cache (since service startup)",
 			nil,
 			nil,
 		),
-		ServiceCache_OutputCacheFlushedItemsTotal: prometheus.NewDesc(
+		ServiceCache_OutputCacheFlushedItemsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_output_cache_items_flushed_total"),
 			"Total number of items flushed from output cache (since service startup)",
 			nil,
 			nil,
 		),
-		ServiceCache_OutputCacheFlushesTotal: prometheus.NewDesc(
+		ServiceCache_OutputCacheFlushesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "server_output_cache_flushes_total"),
 			"Total number of flushes of output cache (since service startup)",
 			nil,
@@ -817,6 +828,114 @@ func NewIISCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *IISCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.CurrentAnonymousUsers
+	ch <- c.CurrentBlockedAsyncIORequests
+	ch <- c.CurrentCGIRequests
+	ch <- c.CurrentConnections
+	ch <- c.CurrentISAPIExtensionRequests
+	ch <- c.CurrentNonAnonymousUsers
+	ch <- c.ServiceUptime
+	ch <- c.TotalBytesReceived
+	ch <- c.TotalBytesSent
+	ch <- c.TotalAnonymousUsers
+	ch <- c.TotalBlockedAsyncIORequests
+	ch <- c.TotalCGIRequests
+	ch <- c.TotalConnectionAttemptsAllInstances
+	ch <- c.TotalRequests
+	ch <- c.TotalFilesReceived
+	ch <- c.TotalFilesSent
+	ch <- c.TotalISAPIExtensionRequests
+	ch <- c.TotalLockedErrors
+	ch <- c.TotalLogonAttempts
+	ch <- c.TotalNonAnonymousUsers
+	ch <- c.TotalNotFoundErrors
+	ch <- c.TotalRejectedAsyncIORequests
+	ch <- c.CurrentApplicationPoolState
+	ch <- c.CurrentApplicationPoolUptime
+	ch <- c.CurrentWorkerProcesses
+	ch <- c.MaximumWorkerProcesses
+	ch <- c.RecentWorkerProcessFailures
+	ch <- c.TimeSinceLastWorkerProcessFailure
+	ch <- c.TotalApplicationPoolRecycles
+	ch <- c.TotalApplicationPoolUptime
+	ch <- c.TotalWorkerProcessesCreated
+	ch <- c.TotalWorkerProcessFailures
+	ch <- c.TotalWorkerProcessPingFailures
+	ch <- c.TotalWorkerProcessShutdownFailures
+	ch <- c.TotalWorkerProcessStartupFailures
+	ch <- c.Threads
+	ch <- c.MaximumThreads
+	ch <- c.RequestsTotal
+	ch <- c.RequestsActive
+	ch <- c.ActiveFlushedEntries
+	ch <- c.CurrentFileCacheMemoryUsage
+	ch <- c.MaximumFileCacheMemoryUsage
+	ch <- c.FileCacheFlushesTotal
+	ch <- c.FileCacheQueriesTotal
+	ch <- c.FilesCachedMissesTotal
+	ch <- c.FileCacheHitsTotal
+	ch <- c.FilesCached
+	ch <- c.FilesCachedTotal
+	ch <- c.FilesFlushedTotal
+	ch <- c.URICacheFlushesTotal
+	ch <- c.URICacheQueriesTotal
+	ch <- c.URICacheHitsTotal
+	ch <- c.URICacheMissesTotal
+	ch <- c.URIsCached
+	ch <- c.URIsCachedTotal
+	ch <- c.URIsFlushedTotal
+	ch <- c.MetadataCached
+	ch <- c.MetadataCacheFlushes
+	ch <- c.MetadataCacheQueriesTotal
+	ch <- c.MetadataCacheHitsTotal
+	ch <- c.MetadataCacheMissesTotal
+	ch <- c.MetadataCachedTotal
+	ch <- c.MetadataFlushedTotal
+	ch <- c.OutputCacheActiveFlushedItems
+	ch <- c.OutputCacheItems
+	ch <- c.OutputCacheMemoryUsage
+	ch <- c.OutputCacheQueriesTotal
+	ch <- c.OutputCacheHitsTotal
+	ch <- c.OutputCacheMissesTotal
+	ch <- c.OutputCacheFlushedItemsTotal
+	ch <- c.OutputCacheFlushesTotal
+	ch <- c.RequestErrorsTotal
+	ch <- c.WebSocketRequestsActive
+	ch <- c.WebSocketConnectionAttempts
+	ch <- c.WebSocketConnectionsAccepted
+	ch <- c.WebSocketConnectionsRejected
+	ch <- c.ServiceCache_ActiveFlushedEntries
+	ch <- c.ServiceCache_CurrentFileCacheMemoryUsage
+	ch <- c.ServiceCache_MaximumFileCacheMemoryUsage
+	ch <- c.ServiceCache_FileCacheFlushesTotal
+	ch <- c.ServiceCache_FileCacheQueriesTotal
+	ch <- c.ServiceCache_FileCacheHitsTotal
+	ch <- c.ServiceCache_FilesCached
+	ch <- c.ServiceCache_FilesCachedTotal
+	ch <- c.ServiceCache_FilesFlushedTotal
+	ch <- c.ServiceCache_URICacheFlushesTotal
+	ch <- c.ServiceCache_URICacheQueriesTotal
+	ch <- c.ServiceCache_URICacheHitsTotal
+	ch <- c.ServiceCache_URIsCached
+	ch <- c.ServiceCache_URIsCachedTotal
+	ch <- c.ServiceCache_URIsFlushedTotal
+	ch <- c.ServiceCache_MetadataCached
+	ch <- c.ServiceCache_MetadataCacheFlushes
+	ch <- c.ServiceCache_MetadataCacheQueriesTotal
+	ch <- c.ServiceCache_MetadataCacheHitsTotal
+	ch <- c.ServiceCache_MetadataCachedTotal
+	ch <- c.ServiceCache_MetadataFlushedTotal
+	ch <- c.ServiceCache_OutputCacheActiveFlushedItems
+	ch <- c.ServiceCache_OutputCacheItems
+	ch <- c.ServiceCache_OutputCacheMemoryUsage
+	ch <- c.ServiceCache_OutputCacheQueriesTotal
+	ch <- c.ServiceCache_OutputCacheHitsTotal
+	ch <- c.ServiceCache_OutputCacheFlushedItemsTotal
+	ch <- c.ServiceCache_OutputCacheFlushesTotal
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *IISCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
@@ -4,28 +4,35 @@
 package collector
 
 import (
-	"fmt"
 	"regexp"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-func init() {
-	registerCollector("logical_disk", NewLogicalDiskCollector, "LogicalDisk")
-}
+const (
+	volumeWhitelistFlag = "collector.logical_disk.volume-whitelist"
+	volumeBlacklistFlag = "collector.logical_disk.volume-blacklist"
+)
 
-var (
-	volumeWhitelist = kingpin.Flag(
-		"collector.logical_disk.volume-whitelist",
+func init() {
+	registerCollectorWithSettings("logical_disk", NewLogicalDiskCollector, "LogicalDisk")
+	registerCollectorInfo("logical_disk", CollectorInfo{
+		Description: "Logical disks, disk I/O",
+		Sources:     []DataSource{SourcePerflib},
+	})
+
+	kingpin.Flag(
+		volumeWhitelistFlag,
 		"Regexp of volumes to whitelist. Volume name must both match whitelist and not match blacklist to be included.",
 	).Default(".+").String()
-	volumeBlacklist = kingpin.Flag(
-		"collector.logical_disk.volume-blacklist",
+	kingpin.Flag(
+		volumeBlacklistFlag,
 		"Regexp of volumes to blacklist. Volume name must both match whitelist and not match blacklist to be included.",
 	).Default("").String()
-)
+}
 
 // A LogicalDiskCollector is a Prometheus collector for perflib logicalDisk metrics
 type LogicalDiskCollector struct {
@@ -51,127 +58,147 @@ type LogicalDiskCollector struct {
 }
 
 // NewLogicalDiskCollector ...
-func NewLogicalDiskCollector() (Collector, error) {
+func NewLogicalDiskCollector(settings *config.Config) (Collector, error) {
 	const subsystem = "logical_disk"
 
 	return &LogicalDiskCollector{
-		RequestsQueued: prometheus.NewDesc(
+		RequestsQueued: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "requests_queued"),
 			"The number of requests queued to the disk (LogicalDisk.CurrentDiskQueueLength)",
 			[]string{"volume"},
 			nil,
 		),
 
-		AvgReadQueue: prometheus.NewDesc(
+		AvgReadQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "avg_read_requests_queued"),
 			"Average number of read requests that were queued for the selected disk during the sample interval (LogicalDisk.AvgDiskReadQueueLength)",
 			[]string{"volume"},
 			nil,
 		),
 
-		AvgWriteQueue: prometheus.NewDesc(
+		AvgWriteQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "avg_write_requests_queued"),
 			"Average number of write requests that were queued for the selected disk during the sample interval (LogicalDisk.AvgDiskWriteQueueLength)",
 			[]string{"volume"},
 			nil,
 		),
 
-		ReadBytesTotal: prometheus.NewDesc(
+		ReadBytesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "read_bytes_total"),
 			"The number of bytes transferred from the disk during read operations (LogicalDisk.DiskReadBytesPerSec)",
 			[]string{"volume"},
 			nil,
 		),
 
-		ReadsTotal: prometheus.NewDesc(
+		ReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "reads_total"),
 			"The number of read operations on the disk (LogicalDisk.DiskReadsPerSec)",
 			[]string{"volume"},
 			nil,
 		),
 
-		WriteBytesTotal: prometheus.NewDesc(
+		WriteBytesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "write_bytes_total"),
 			"The number of bytes transferred to the disk during write operations (LogicalDisk.DiskWriteBytesPerSec)",
 			[]string{"volume"},
 			nil,
 		),
 
-		WritesTotal: prometheus.NewDesc(
+		WritesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "writes_total"),
 			"The number of write operations on the disk (LogicalDisk.DiskWritesPerSec)",
 			[]string{"volume"},
 			nil,
 		),
 
-		ReadTime: prometheus.NewDesc(
+		ReadTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "read_seconds_total"),
 			"Seconds that the disk was busy servicing read requests (LogicalDisk.PercentDiskReadTime)",
 			[]string{"volume"},
 			nil,
 		),
 
-		WriteTime: prometheus.NewDesc(
+		WriteTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "write_seconds_total"),
 			"Seconds that the disk was busy servicing write requests (LogicalDisk.PercentDiskWriteTime)",
 			[]string{"volume"},
 			nil,
 		),
 
-		FreeSpace: prometheus.NewDesc(
+		FreeSpace: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "free_bytes"),
 			"Free space in bytes, updates every 10-15 min (LogicalDisk.PercentFreeSpace)",
 			[]string{"volume"},
 			nil,
 		),
 
-		TotalSpace: prometheus.NewDesc(
+		TotalSpace: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "size_bytes"),
 			"Total space in bytes, updates every 10-15 min (LogicalDisk.PercentFreeSpace_Base)",
 			[]string{"volume"},
 			nil,
 		),
 
-		IdleTime: prometheus.NewDesc(
+		IdleTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "idle_seconds_total"),
 			"Seconds that the disk was idle (LogicalDisk.PercentIdleTime)",
 			[]string{"volume"},
 			nil,
 		),
 
-		SplitIOs: prometheus.NewDesc(
+		SplitIOs: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "split_ios_total"),
 			"The number of I/Os to the disk were split into multiple I/Os (LogicalDisk.SplitIOPerSec)",
 			[]string{"volume"},
 			nil,
 		),
 
-		ReadLatency: prometheus.NewDesc(
+		ReadLatency: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "read_latency_seconds_total"),
 			"Shows the average time, in seconds, of a read operation from the disk (LogicalDisk.AvgDiskSecPerRead)",
 			[]string{"volume"},
 			nil,
 		),
 
-		WriteLatency: prometheus.NewDesc(
+		WriteLatency: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "write_latency_seconds_total"),
 			"Shows the average time, in seconds, of a write operation to the disk (LogicalDisk.AvgDiskSecPerWrite)",
 			[]string{"volume"},
 			nil,
 		),
 
-		ReadWriteLatency: prometheus.NewDesc(
+		ReadWriteLatency: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "read_write_latency_seconds_total"),
 			"Shows the time, in seconds, of the average disk transfer (LogicalDisk.AvgDiskSecPerTransfer)",
 			[]string{"volume"},
 			nil,
 		),
 
-		volumeWhitelistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *volumeWhitelist)),
-		volumeBlacklistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *volumeBlacklist)),
+		volumeWhitelistPattern: settings.Collector.LogicalDisk.VolumeWhitelist.Regexp(),
+		volumeBlacklistPattern: settings.Collector.LogicalDisk.VolumeBlacklist.Regexp(),
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *LogicalDiskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.RequestsQueued
+	ch <- c.AvgReadQueue
+	ch <- c.AvgWriteQueue
+	ch <- c.ReadBytesTotal
+	ch <- c.ReadsTotal
+	ch <- c.WriteBytesTotal
+	ch <- c.WritesTotal
+	ch <- c.ReadTime
+	ch <- c.WriteTime
+	ch <- c.TotalSpace
+	ch <- c.FreeSpace
+	ch <- c.IdleTime
+	ch <- c.SplitIOs
+	ch <- c.ReadLatency
+	ch <- c.WriteLatency
+	ch <- c.ReadWriteLatency
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *LogicalDiskCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
@@ -8,11 +8,15 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("logon", NewLogonCollector)
+	registerCollectorInfo("logon", CollectorInfo{
+		Description: "User logon sessions",
+		Sources:     []DataSource{SourceWMI},
+		Expensive:   true,
+	})
 }
 
 // A LogonCollector is a Prometheus collector for WMI metrics
@@ -25,7 +29,7 @@ func NewLogonCollector() (Collector, error) {
 	const subsystem = "logon"
 
 	return &LogonCollector{
-		LogonType: prometheus.NewDesc(
+		LogonType: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "logon_type"),
 			"Number of active logon sessions (LogonSession.LogonType)",
 			[]string{"status"},
@@ -34,10 +38,15 @@ func NewLogonCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *LogonCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.LogonType
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *LogonCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting user metrics:", desc, err)
 		return err
 	}
@@ -50,10 +59,10 @@ type Win32_LogonSession struct {
 	LogonType uint32
 }
 
-func (c *LogonCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *LogonCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_LogonSession
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -13,6 +13,10 @@ import (
 
 func init() {
 	registerCollector("memory", NewMemoryCollector, "Memory")
+	registerCollectorInfo("memory", CollectorInfo{
+		Description: "Memory usage metrics",
+		Sources:     []DataSource{SourcePerflib},
+	})
 }
 
 // A MemoryCollector is a Prometheus collector for perflib Memory metrics
@@ -56,205 +60,205 @@ func NewMemoryCollector() (Collector, error) {
 	const subsystem = "memory"
 
 	return &MemoryCollector{
-		AvailableBytes: prometheus.NewDesc(
+		AvailableBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "available_bytes"),
 			"The amount of physical memory immediately available for allocation to a process or for system use. It is equal to the sum of memory assigned to"+
 				" the standby (cached), free and zero page lists (AvailableBytes)",
 			nil,
 			nil,
 		),
-		CacheBytes: prometheus.NewDesc(
+		CacheBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cache_bytes"),
 			"(CacheBytes)",
 			nil,
 			nil,
 		),
-		CacheBytesPeak: prometheus.NewDesc(
+		CacheBytesPeak: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cache_bytes_peak"),
 			"(CacheBytesPeak)",
 			nil,
 			nil,
 		),
-		CacheFaultsTotal: prometheus.NewDesc(
+		CacheFaultsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cache_faults_total"),
 			"Number of faults which occur when a page sought in the file system cache is not found there and must be retrieved from elsewhere in memory (soft fault) "+
 				"or from disk (hard fault) (Cache Faults/sec)",
 			nil,
 			nil,
 		),
-		CommitLimit: prometheus.NewDesc(
+		CommitLimit: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "commit_limit"),
 			"(CommitLimit)",
 			nil,
 			nil,
 		),
-		CommittedBytes: prometheus.NewDesc(
+		CommittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "committed_bytes"),
 			"(CommittedBytes)",
 			nil,
 			nil,
 		),
-		DemandZeroFaultsTotal: prometheus.NewDesc(
+		DemandZeroFaultsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "demand_zero_faults_total"),
 			"The number of zeroed pages required to satisfy faults. Zeroed pages, pages emptied of previously stored data and filled with zeros, are a security"+
 				" feature of Windows that prevent processes from seeing data stored by earlier processes that used the memory space (Demand Zero Faults/sec)",
 			nil,
 			nil,
 		),
-		FreeAndZeroPageListBytes: prometheus.NewDesc(
+		FreeAndZeroPageListBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "free_and_zero_page_list_bytes"),
 			"The amount of physical memory, in bytes, that is assigned to the free and zero page lists. This memory does not contain cached data. It is immediately"+
 				" available for allocation to a process or for system use (FreeAndZeroPageListBytes)",
 			nil,
 			nil,
 		),
-		FreeSystemPageTableEntries: prometheus.NewDesc(
+		FreeSystemPageTableEntries: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "free_system_page_table_entries"),
 			"(FreeSystemPageTableEntries)",
 			nil,
 			nil,
 		),
-		ModifiedPageListBytes: prometheus.NewDesc(
+		ModifiedPageListBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "modified_page_list_bytes"),
 			"The amount of physical memory, in bytes, that is assigned to the modified page list. This memory contains cached data and code that is not actively in "+
 				"use by processes, the system and the system cache (ModifiedPageListBytes)",
 			nil,
 			nil,
 		),
-		PageFaultsTotal: prometheus.NewDesc(
+		PageFaultsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "page_faults_total"),
 			"Overall rate at which faulted pages are handled by the processor (Page Faults/sec)",
 			nil,
 			nil,
 		),
-		SwapPageReadsTotal: prometheus.NewDesc(
+		SwapPageReadsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "swap_page_reads_total"),
 			"Number of disk page reads (a single read operation reading several pages is still only counted once) (PageReadsPersec)",
 			nil,
 			nil,
 		),
-		SwapPagesReadTotal: prometheus.NewDesc(
+		SwapPagesReadTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "swap_pages_read_total"),
 			"Number of pages read across all page reads (ie counting all pages read even if they are read in a single operation) (PagesInputPersec)",
 			nil,
 			nil,
 		),
-		SwapPagesWrittenTotal: prometheus.NewDesc(
+		SwapPagesWrittenTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "swap_pages_written_total"),
 			"Number of pages written across all page writes (ie counting all pages written even if they are written in a single operation) (PagesOutputPersec)",
 			nil,
 			nil,
 		),
-		SwapPageOperationsTotal: prometheus.NewDesc(
+		SwapPageOperationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "swap_page_operations_total"),
 			"Total number of swap page read and writes (PagesPersec)",
 			nil,
 			nil,
 		),
-		SwapPageWritesTotal: prometheus.NewDesc(
+		SwapPageWritesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "swap_page_writes_total"),
 			"Number of disk page writes (a single write operation writing several pages is still only counted once) (PageWritesPersec)",
 			nil,
 			nil,
 		),
-		PoolNonpagedAllocsTotal: prometheus.NewDesc(
+		PoolNonpagedAllocsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pool_nonpaged_allocs_total"),
 			"The number of calls to allocate space in the nonpaged pool. The nonpaged pool is an area of system memory area for objects that cannot be written"+
 				" to disk, and must remain in physical memory as long as they are allocated (PoolNonpagedAllocs)",
 			nil,
 			nil,
 		),
-		PoolNonpagedBytes: prometheus.NewDesc(
+		PoolNonpagedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pool_nonpaged_bytes"),
 			"Number of bytes in the non-paged pool, an area of the system virtual memory that is used for objects that cannot be written to disk, but must "+
 				"remain in physical memory as long as they are allocated (PoolNonpagedBytes)",
 			nil,
 			nil,
 		),
-		PoolPagedAllocsTotal: prometheus.NewDesc(
+		PoolPagedAllocsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pool_paged_allocs_total"),
 			"Number of calls to allocate space in the paged pool, regardless of the amount of space allocated in each call (PoolPagedAllocs)",
 			nil,
 			nil,
 		),
-		PoolPagedBytes: prometheus.NewDesc(
+		PoolPagedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pool_paged_bytes"),
 			"(PoolPagedBytes)",
 			nil,
 			nil,
 		),
-		PoolPagedResidentBytes: prometheus.NewDesc(
+		PoolPagedResidentBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pool_paged_resident_bytes"),
 			"The size, in bytes, of the portion of the paged pool that is currently resident and active in physical memory. The paged pool is an area of the "+
 				"system virtual memory that is used for objects that can be written to disk when they are not being used (PoolPagedResidentBytes)",
 			nil,
 			nil,
 		),
-		StandbyCacheCoreBytes: prometheus.NewDesc(
+		StandbyCacheCoreBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "standby_cache_core_bytes"),
 			"The amount of physical memory, in bytes, that is assigned to the core standby cache page lists. This memory contains cached data and code that is "+
 				"not actively in use by processes, the system and the system cache (StandbyCacheCoreBytes)",
 			nil,
 			nil,
 		),
-		StandbyCacheNormalPriorityBytes: prometheus.NewDesc(
+		StandbyCacheNormalPriorityBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "standby_cache_normal_priority_bytes"),
 			"The amount of physical memory, in bytes, that is assigned to the normal priority standby cache page lists. This memory contains cached data and "+
 				"code that is not actively in use by processes, the system and the system cache (StandbyCacheNormalPriorityBytes)",
 			nil,
 			nil,
 		),
-		StandbyCacheReserveBytes: prometheus.NewDesc(
+		StandbyCacheReserveBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "standby_cache_reserve_bytes"),
 			"The amount of physical memory, in bytes, that is assigned to the reserve standby cache page lists. This memory contains cached data and code "+
 				"that is not actively in use by processes, the system and the system cache (StandbyCacheReserveBytes)",
 			nil,
 			nil,
 		),
-		SystemCacheResidentBytes: prometheus.NewDesc(
+		SystemCacheResidentBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "system_cache_resident_bytes"),
 			"The size, in bytes, of the portion of the system file cache which is currently resident and active in physical memory (SystemCacheResidentBytes)",
 			nil,
 			nil,
 		),
-		SystemCodeResidentBytes: prometheus.NewDesc(
+		SystemCodeResidentBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "system_code_resident_bytes"),
 			"The size, in bytes, of the pageable operating system code that is currently resident and active in physical memory (SystemCodeResidentBytes)",
 			nil,
 			nil,
 		),
-		SystemCodeTotalBytes: prometheus.NewDesc(
+		SystemCodeTotalBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "system_code_total_bytes"),
 			"The size, in bytes, of the pageable operating system code currently mapped into the system virtual address space (SystemCodeTotalBytes)",
 			nil,
 			nil,
 		),
-		SystemDriverResidentBytes: prometheus.NewDesc(
+		SystemDriverResidentBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "system_driver_resident_bytes"),
 			"The size, in bytes, of the pageable physical memory being used by device drivers. It is the working set (physical memory area) of the drivers (SystemDriverResidentBytes)",
 			nil,
 			nil,
 		),
-		SystemDriverTotalBytes: prometheus.NewDesc(
+		SystemDriverTotalBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "system_driver_total_bytes"),
 			"The size, in bytes, of the pageable virtual memory currently being used by device drivers. Pageable memory can be written to disk when it is not being used (SystemDriverTotalBytes)",
 			nil,
 			nil,
 		),
-		TransitionFaultsTotal: prometheus.NewDesc(
+		TransitionFaultsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transition_faults_total"),
 			"Number of faults rate at which page faults are resolved by recovering pages that were being used by another process sharing the page, or were on the "+
 				"modified page list or the standby list, or were being written to disk at the time of the page fault (TransitionFaultsPersec)",
 			nil,
 			nil,
 		),
-		TransitionPagesRepurposedTotal: prometheus.NewDesc(
+		TransitionPagesRepurposedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transition_pages_repurposed_total"),
 			"Transition Pages RePurposed is the rate at which the number of transition cache pages were reused for a different purpose (TransitionPagesRePurposedPersec)",
 			nil,
 			nil,
 		),
-		WriteCopiesTotal: prometheus.NewDesc(
+		WriteCopiesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "write_copies_total"),
 			"The number of page faults caused by attempting to write that were satisfied by copying the page from elsewhere in physical memory (WriteCopiesPersec)",
 			nil,
@@ -263,6 +267,42 @@ func NewMemoryCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *MemoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.AvailableBytes
+	ch <- c.CacheBytes
+	ch <- c.CacheBytesPeak
+	ch <- c.CacheFaultsTotal
+	ch <- c.CommitLimit
+	ch <- c.CommittedBytes
+	ch <- c.DemandZeroFaultsTotal
+	ch <- c.FreeAndZeroPageListBytes
+	ch <- c.FreeSystemPageTableEntries
+	ch <- c.ModifiedPageListBytes
+	ch <- c.PageFaultsTotal
+	ch <- c.SwapPageReadsTotal
+	ch <- c.SwapPagesReadTotal
+	ch <- c.SwapPagesWrittenTotal
+	ch <- c.SwapPageOperationsTotal
+	ch <- c.SwapPageWritesTotal
+	ch <- c.PoolNonpagedAllocsTotal
+	ch <- c.PoolNonpagedBytes
+	ch <- c.PoolPagedAllocsTotal
+	ch <- c.PoolPagedBytes
+	ch <- c.PoolPagedResidentBytes
+	ch <- c.StandbyCacheCoreBytes
+	ch <- c.StandbyCacheNormalPriorityBytes
+	ch <- c.StandbyCacheReserveBytes
+	ch <- c.SystemCacheResidentBytes
+	ch <- c.SystemCodeResidentBytes
+	ch <- c.SystemCodeTotalBytes
+	ch <- c.SystemDriverResidentBytes
+	ch <- c.SystemDriverTotalBytes
+	ch <- c.TransitionFaultsTotal
+	ch <- c.TransitionPagesRepurposedTotal
+	ch <- c.WriteCopiesTotal
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *MemoryCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
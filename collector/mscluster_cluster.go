@@ -2,11 +2,16 @@ package collector
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("mscluster_cluster", newMSCluster_ClusterCollector)
+	registerCollectorInfo("mscluster_cluster", CollectorInfo{
+		Description: "MSCluster cluster metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "Failover Clustering feature",
+	})
+	registerApplicabilityCheck("mscluster_cluster", serviceInstalled("ClusSvc"))
 }
 
 // A MSCluster_ClusterCollector is a Prometheus collector for WMI MSCluster_Cluster metrics
@@ -93,463 +98,463 @@ type MSCluster_ClusterCollector struct {
 func newMSCluster_ClusterCollector() (Collector, error) {
 	const subsystem = "mscluster_cluster"
 	return &MSCluster_ClusterCollector{
-		AddEvictDelay: prometheus.NewDesc(
+		AddEvictDelay: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "add_evict_delay"),
 			"Provides access to the cluster's AddEvictDelay property, which is the number a seconds that a new node is delayed after an eviction of another node.",
 			[]string{"name"},
 			nil,
 		),
-		AdminAccessPoint: prometheus.NewDesc(
+		AdminAccessPoint: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "admin_access_point"),
 			"The type of the cluster administrative access point.",
 			[]string{"name"},
 			nil,
 		),
-		AutoAssignNodeSite: prometheus.NewDesc(
+		AutoAssignNodeSite: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "auto_assign_node_site"),
 			"Determines whether or not the cluster will attempt to automatically assign nodes to sites based on networks and Active Directory Site information.",
 			[]string{"name"},
 			nil,
 		),
-		AutoBalancerLevel: prometheus.NewDesc(
+		AutoBalancerLevel: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "auto_balancer_level"),
 			"Determines the level of aggressiveness of AutoBalancer.",
 			[]string{"name"},
 			nil,
 		),
-		AutoBalancerMode: prometheus.NewDesc(
+		AutoBalancerMode: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "auto_balancer_mode"),
 			"Determines whether or not the auto balancer is enabled.",
 			[]string{"name"},
 			nil,
 		),
-		BackupInProgress: prometheus.NewDesc(
+		BackupInProgress: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "backup_in_progress"),
 			"Indicates whether a backup is in progress.",
 			[]string{"name"},
 			nil,
 		),
-		BlockCacheSize: prometheus.NewDesc(
+		BlockCacheSize: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "block_cache_size"),
 			"CSV BlockCache Size in MB.",
 			[]string{"name"},
 			nil,
 		),
-		ClusSvcHangTimeout: prometheus.NewDesc(
+		ClusSvcHangTimeout: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clus_svc_hang_timeout"),
 			"Controls how long the cluster network driver waits between Failover Cluster Service heartbeats before it determines that the Failover Cluster Service has stopped responding.",
 			[]string{"name"},
 			nil,
 		),
-		ClusSvcRegroupOpeningTimeout: prometheus.NewDesc(
+		ClusSvcRegroupOpeningTimeout: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clus_svc_regroup_opening_timeout"),
 			"Controls how long a node will wait on other nodes in the opening stage before deciding that they failed.",
 			[]string{"name"},
 			nil,
 		),
-		ClusSvcRegroupPruningTimeout: prometheus.NewDesc(
+		ClusSvcRegroupPruningTimeout: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clus_svc_regroup_pruning_timeout"),
 			"Controls how long the membership leader will wait to reach full connectivity between cluster nodes.",
 			[]string{"name"},
 			nil,
 		),
-		ClusSvcRegroupStageTimeout: prometheus.NewDesc(
+		ClusSvcRegroupStageTimeout: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clus_svc_regroup_stage_timeout"),
 			"Controls how long a node will wait on other nodes in a membership stage before deciding that they failed.",
 			[]string{"name"},
 			nil,
 		),
-		ClusSvcRegroupTickInMilliseconds: prometheus.NewDesc(
+		ClusSvcRegroupTickInMilliseconds: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clus_svc_regroup_tick_in_milliseconds"),
 			"Controls how frequently the membership algorithm is sending periodic membership messages.",
 			[]string{"name"},
 			nil,
 		),
-		ClusterEnforcedAntiAffinity: prometheus.NewDesc(
+		ClusterEnforcedAntiAffinity: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cluster_enforced_anti_affinity"),
 			"Enables or disables hard enforcement of group anti-affinity classes.",
 			[]string{"name"},
 			nil,
 		),
-		ClusterFunctionalLevel: prometheus.NewDesc(
+		ClusterFunctionalLevel: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cluster_functional_level"),
 			"The functional level the cluster is currently running in.",
 			[]string{"name"},
 			nil,
 		),
-		ClusterGroupWaitDelay: prometheus.NewDesc(
+		ClusterGroupWaitDelay: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cluster_group_wait_delay"),
 			"Maximum time in seconds that a group waits for its preferred node to come online during cluster startup before coming online on a different node.",
 			[]string{"name"},
 			nil,
 		),
-		ClusterLogLevel: prometheus.NewDesc(
+		ClusterLogLevel: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cluster_log_level"),
 			"Controls the level of cluster logging.",
 			[]string{"name"},
 			nil,
 		),
-		ClusterLogSize: prometheus.NewDesc(
+		ClusterLogSize: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cluster_log_size"),
 			"Controls the maximum size of the cluster log files on each of the nodes.",
 			[]string{"name"},
 			nil,
 		),
-		ClusterUpgradeVersion: prometheus.NewDesc(
+		ClusterUpgradeVersion: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cluster_upgrade_version"),
 			"Specifies the upgrade version the cluster is currently running in.",
 			[]string{"name"},
 			nil,
 		),
-		CrossSiteDelay: prometheus.NewDesc(
+		CrossSiteDelay: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cross_site_delay"),
 			"Controls how long the cluster network driver waits in milliseconds between sending Cluster Service heartbeats across sites.",
 			[]string{"name"},
 			nil,
 		),
-		CrossSiteThreshold: prometheus.NewDesc(
+		CrossSiteThreshold: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cross_site_threshold"),
 			"Controls how many Cluster Service heartbeats can be missed across sites before it determines that Cluster Service has stopped responding.",
 			[]string{"name"},
 			nil,
 		),
-		CrossSubnetDelay: prometheus.NewDesc(
+		CrossSubnetDelay: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cross_subnet_delay"),
 			"Controls how long the cluster network driver waits in milliseconds between sending Cluster Service heartbeats across subnets.",
 			[]string{"name"},
 			nil,
 		),
-		CrossSubnetThreshold: prometheus.NewDesc(
+		CrossSubnetThreshold: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cross_subnet_threshold"),
 			"Controls how many Cluster Service heartbeats can be missed across subnets before it determines that Cluster Service has stopped responding.",
 			[]string{"name"},
 			nil,
 		),
-		CsvBalancer: prometheus.NewDesc(
+		CsvBalancer: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "csv_balancer"),
 			"Whether automatic balancing for CSV is enabled.",
 			[]string{"name"},
 			nil,
 		),
-		DatabaseReadWriteMode: prometheus.NewDesc(
+		DatabaseReadWriteMode: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "database_read_write_mode"),
 			"Sets the database read and write mode.",
 			[]string{"name"},
 			nil,
 		),
-		DefaultNetworkRole: prometheus.NewDesc(
+		DefaultNetworkRole: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "default_network_role"),
 			"Provides access to the cluster's DefaultNetworkRole property.",
 			[]string{"name"},
 			nil,
 		),
-		DetectedCloudPlatform: prometheus.NewDesc(
+		DetectedCloudPlatform: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "detected_cloud_platform"),
 			"(DetectedCloudPlatform)",
 			[]string{"name"},
 			nil,
 		),
-		DetectManagedEvents: prometheus.NewDesc(
+		DetectManagedEvents: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "detect_managed_events"),
 			"(DetectManagedEvents)",
 			[]string{"name"},
 			nil,
 		),
-		DetectManagedEventsThreshold: prometheus.NewDesc(
+		DetectManagedEventsThreshold: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "detect_managed_events_threshold"),
 			"(DetectManagedEventsThreshold)",
 			[]string{"name"},
 			nil,
 		),
-		DisableGroupPreferredOwnerRandomization: prometheus.NewDesc(
+		DisableGroupPreferredOwnerRandomization: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "disable_group_preferred_owner_randomization"),
 			"(DisableGroupPreferredOwnerRandomization)",
 			[]string{"name"},
 			nil,
 		),
-		DrainOnShutdown: prometheus.NewDesc(
+		DrainOnShutdown: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "drain_on_shutdown"),
 			"Whether to drain the node when cluster service is being stopped.",
 			[]string{"name"},
 			nil,
 		),
-		DynamicQuorumEnabled: prometheus.NewDesc(
+		DynamicQuorumEnabled: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dynamic_quorum_enabled"),
 			"Allows cluster service to adjust node weights as needed to increase availability.",
 			[]string{"name"},
 			nil,
 		),
-		EnableSharedVolumes: prometheus.NewDesc(
+		EnableSharedVolumes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "enable_shared_volumes"),
 			"Enables or disables cluster shared volumes on this cluster.",
 			[]string{"name"},
 			nil,
 		),
-		FixQuorum: prometheus.NewDesc(
+		FixQuorum: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "fix_quorum"),
 			"Provides access to the cluster's FixQuorum property, which specifies if the cluster is in a fix quorum state.",
 			[]string{"name"},
 			nil,
 		),
-		GracePeriodEnabled: prometheus.NewDesc(
+		GracePeriodEnabled: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "grace_period_enabled"),
 			"Whether the node grace period feature of this cluster is enabled.",
 			[]string{"name"},
 			nil,
 		),
-		GracePeriodTimeout: prometheus.NewDesc(
+		GracePeriodTimeout: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "grace_period_timeout"),
 			"The grace period timeout in milliseconds.",
 			[]string{"name"},
 			nil,
 		),
-		GroupDependencyTimeout: prometheus.NewDesc(
+		GroupDependencyTimeout: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "group_dependency_timeout"),
 			"The timeout after which a group will be brought online despite unsatisfied dependencies",
 			[]string{"name"},
 			nil,
 		),
-		HangRecoveryAction: prometheus.NewDesc(
+		HangRecoveryAction: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "hang_recovery_action"),
 			"Controls the action to take if the user-mode processes have stopped responding.",
 			[]string{"name"},
 			nil,
 		),
-		IgnorePersistentStateOnStartup: prometheus.NewDesc(
+		IgnorePersistentStateOnStartup: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ignore_persistent_state_on_startup"),
 			"Provides access to the cluster's IgnorePersistentStateOnStartup property, which specifies whether the cluster will bring online groups that were online when the cluster was shut down.",
 			[]string{"name"},
 			nil,
 		),
-		LogResourceControls: prometheus.NewDesc(
+		LogResourceControls: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "log_resource_controls"),
 			"Controls the logging of resource controls.",
 			[]string{"name"},
 			nil,
 		),
-		LowerQuorumPriorityNodeId: prometheus.NewDesc(
+		LowerQuorumPriorityNodeId: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "lower_quorum_priority_node_id"),
 			"Specifies the Node ID that has a lower priority when voting for quorum is performed. If the quorum vote is split 50/50%, the specified node's vote would be ignored to break the tie. If this is not set then the cluster will pick a node at random to break the tie.",
 			[]string{"name"},
 			nil,
 		),
-		MaxNumberOfNodes: prometheus.NewDesc(
+		MaxNumberOfNodes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "max_number_of_nodes"),
 			"Indicates the maximum number of nodes that may participate in the Cluster.",
 			[]string{"name"},
 			nil,
 		),
-		MessageBufferLength: prometheus.NewDesc(
+		MessageBufferLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "message_buffer_length"),
 			"The maximum unacknowledged message count for GEM.",
 			[]string{"name"},
 			nil,
 		),
-		MinimumNeverPreemptPriority: prometheus.NewDesc(
+		MinimumNeverPreemptPriority: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "minimum_never_preempt_priority"),
 			"Groups with this priority or higher cannot be preempted.",
 			[]string{"name"},
 			nil,
 		),
-		MinimumPreemptorPriority: prometheus.NewDesc(
+		MinimumPreemptorPriority: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "minimum_preemptor_priority"),
 			"Minimum priority a cluster group must have to be able to preempt another group.",
 			[]string{"name"},
 			nil,
 		),
-		NetftIPSecEnabled: prometheus.NewDesc(
+		NetftIPSecEnabled: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "netft_ip_sec_enabled"),
 			"Whether IPSec is enabled for cluster internal traffic.",
 			[]string{"name"},
 			nil,
 		),
-		PlacementOptions: prometheus.NewDesc(
+		PlacementOptions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "placement_options"),
 			"Various option flags to modify default placement behavior.",
 			[]string{"name"},
 			nil,
 		),
-		PlumbAllCrossSubnetRoutes: prometheus.NewDesc(
+		PlumbAllCrossSubnetRoutes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "plumb_all_cross_subnet_routes"),
 			"Plumbs all possible cross subnet routes to all nodes.",
 			[]string{"name"},
 			nil,
 		),
-		PreventQuorum: prometheus.NewDesc(
+		PreventQuorum: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "prevent_quorum"),
 			"Whether the cluster will ignore group persistent state on startup.",
 			[]string{"name"},
 			nil,
 		),
-		QuarantineDuration: prometheus.NewDesc(
+		QuarantineDuration: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "quarantine_duration"),
 			"The quarantine period timeout in milliseconds.",
 			[]string{"name"},
 			nil,
 		),
-		QuarantineThreshold: prometheus.NewDesc(
+		QuarantineThreshold: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "quarantine_threshold"),
 			"Number of node failures before it will be quarantined.",
 			[]string{"name"},
 			nil,
 		),
-		QuorumArbitrationTimeMax: prometheus.NewDesc(
+		QuorumArbitrationTimeMax: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "quorum_arbitration_time_max"),
 			"Controls the maximum time necessary to decide the Quorum owner node.",
 			[]string{"name"},
 			nil,
 		),
-		QuorumArbitrationTimeMin: prometheus.NewDesc(
+		QuorumArbitrationTimeMin: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "quorum_arbitration_time_min"),
 			"Controls the minimum time necessary to decide the Quorum owner node.",
 			[]string{"name"},
 			nil,
 		),
-		QuorumLogFileSize: prometheus.NewDesc(
+		QuorumLogFileSize: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "quorum_log_file_size"),
 			"This property is obsolete.",
 			[]string{"name"},
 			nil,
 		),
-		QuorumTypeValue: prometheus.NewDesc(
+		QuorumTypeValue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "quorum_type_value"),
 			"Get the current quorum type value. -1: Unknown; 1: Node; 2: FileShareWitness; 3: Storage; 4: None",
 			[]string{"name"},
 			nil,
 		),
-		RequestReplyTimeout: prometheus.NewDesc(
+		RequestReplyTimeout: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "request_reply_timeout"),
 			"Controls the request reply time-out period.",
 			[]string{"name"},
 			nil,
 		),
-		ResiliencyDefaultPeriod: prometheus.NewDesc(
+		ResiliencyDefaultPeriod: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "resiliency_default_period"),
 			"The default resiliency period, in seconds, for the cluster.",
 			[]string{"name"},
 			nil,
 		),
-		ResiliencyLevel: prometheus.NewDesc(
+		ResiliencyLevel: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "resiliency_level"),
 			"The resiliency level for the cluster.",
 			[]string{"name"},
 			nil,
 		),
-		ResourceDllDeadlockPeriod: prometheus.NewDesc(
+		ResourceDllDeadlockPeriod: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "resource_dll_deadlock_period"),
 			"This property is obsolete.",
 			[]string{"name"},
 			nil,
 		),
-		RootMemoryReserved: prometheus.NewDesc(
+		RootMemoryReserved: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "root_memory_reserved"),
 			"Controls the amount of memory reserved for the parent partition on all cluster nodes.",
 			[]string{"name"},
 			nil,
 		),
-		RouteHistoryLength: prometheus.NewDesc(
+		RouteHistoryLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "route_history_length"),
 			"The history length for routes to help finding network issues.",
 			[]string{"name"},
 			nil,
 		),
-		S2DBusTypes: prometheus.NewDesc(
+		S2DBusTypes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "s2d_bus_types"),
 			"Bus types for storage spaces direct.",
 			[]string{"name"},
 			nil,
 		),
-		S2DCacheDesiredState: prometheus.NewDesc(
+		S2DCacheDesiredState: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "s2d_cache_desired_state"),
 			"Desired state of the storage spaces direct cache.",
 			[]string{"name"},
 			nil,
 		),
-		S2DCacheFlashReservePercent: prometheus.NewDesc(
+		S2DCacheFlashReservePercent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "s2d_cache_flash_reserve_percent"),
 			"Percentage of allocated flash space to utilize when caching.",
 			[]string{"name"},
 			nil,
 		),
-		S2DCachePageSizeKBytes: prometheus.NewDesc(
+		S2DCachePageSizeKBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "s2d_cache_page_size_k_bytes"),
 			"Page size in KB used by S2D cache.",
 			[]string{"name"},
 			nil,
 		),
-		S2DEnabled: prometheus.NewDesc(
+		S2DEnabled: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "s2d_enabled"),
 			"Whether direct attached storage (DAS) is enabled.",
 			[]string{"name"},
 			nil,
 		),
-		S2DIOLatencyThreshold: prometheus.NewDesc(
+		S2DIOLatencyThreshold: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "s2dio_latency_threshold"),
 			"The I/O latency threshold for storage spaces direct.",
 			[]string{"name"},
 			nil,
 		),
-		S2DOptimizations: prometheus.NewDesc(
+		S2DOptimizations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "s2d_optimizations"),
 			"Optimization flags for storage spaces direct.",
 			[]string{"name"},
 			nil,
 		),
-		SameSubnetDelay: prometheus.NewDesc(
+		SameSubnetDelay: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "same_subnet_delay"),
 			"Controls how long the cluster network driver waits in milliseconds between sending Cluster Service heartbeats on the same subnet.",
 			[]string{"name"},
 			nil,
 		),
-		SameSubnetThreshold: prometheus.NewDesc(
+		SameSubnetThreshold: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "same_subnet_threshold"),
 			"Controls how many Cluster Service heartbeats can be missed on the same subnet before it determines that Cluster Service has stopped responding.",
 			[]string{"name"},
 			nil,
 		),
-		SecurityLevel: prometheus.NewDesc(
+		SecurityLevel: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "security_level"),
 			"Controls the level of security that should apply to intracluster messages. 0: Clear Text; 1: Sign; 2: Encrypt ",
 			[]string{"name"},
 			nil,
 		),
-		SecurityLevelForStorage: prometheus.NewDesc(
+		SecurityLevelForStorage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "security_level_for_storage"),
 			"(SecurityLevelForStorage)",
 			[]string{"name"},
 			nil,
 		),
-		SharedVolumeVssWriterOperationTimeout: prometheus.NewDesc(
+		SharedVolumeVssWriterOperationTimeout: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "shared_volume_vss_writer_operation_timeout"),
 			"CSV VSS Writer operation timeout in seconds.",
 			[]string{"name"},
 			nil,
 		),
-		ShutdownTimeoutInMinutes: prometheus.NewDesc(
+		ShutdownTimeoutInMinutes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "shutdown_timeout_in_minutes"),
 			"The maximum time in minutes allowed for cluster resources to come offline during cluster service shutdown.",
 			[]string{"name"},
 			nil,
 		),
-		UseClientAccessNetworksForSharedVolumes: prometheus.NewDesc(
+		UseClientAccessNetworksForSharedVolumes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "use_client_access_networks_for_shared_volumes"),
 			"Whether the use of client access networks for cluster shared volumes feature of this cluster is enabled. 0: Disabled; 1: Enabled; 2: Auto",
 			[]string{"name"},
 			nil,
 		),
-		WitnessDatabaseWriteTimeout: prometheus.NewDesc(
+		WitnessDatabaseWriteTimeout: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "witness_database_write_timeout"),
 			"Controls the maximum time in seconds that a cluster database write to a witness can take before the write is abandoned.",
 			[]string{"name"},
 			nil,
 		),
-		WitnessDynamicWeight: prometheus.NewDesc(
+		WitnessDynamicWeight: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "witness_dynamic_weight"),
 			"The weight of the configured witness.",
 			[]string{"name"},
 			nil,
 		),
-		WitnessRestartInterval: prometheus.NewDesc(
+		WitnessRestartInterval: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "witness_restart_interval"),
 			"Controls the witness restart interval.",
 			[]string{"name"},
@@ -642,12 +647,93 @@ type MSCluster_Cluster struct {
 	WitnessRestartInterval                  uint
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *MSCluster_ClusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.AddEvictDelay
+	ch <- c.AdminAccessPoint
+	ch <- c.AutoAssignNodeSite
+	ch <- c.AutoBalancerLevel
+	ch <- c.AutoBalancerMode
+	ch <- c.BackupInProgress
+	ch <- c.BlockCacheSize
+	ch <- c.ClusSvcHangTimeout
+	ch <- c.ClusSvcRegroupOpeningTimeout
+	ch <- c.ClusSvcRegroupPruningTimeout
+	ch <- c.ClusSvcRegroupStageTimeout
+	ch <- c.ClusSvcRegroupTickInMilliseconds
+	ch <- c.ClusterEnforcedAntiAffinity
+	ch <- c.ClusterFunctionalLevel
+	ch <- c.ClusterGroupWaitDelay
+	ch <- c.ClusterLogLevel
+	ch <- c.ClusterLogSize
+	ch <- c.ClusterUpgradeVersion
+	ch <- c.CrossSiteDelay
+	ch <- c.CrossSiteThreshold
+	ch <- c.CrossSubnetDelay
+	ch <- c.CrossSubnetThreshold
+	ch <- c.CsvBalancer
+	ch <- c.DatabaseReadWriteMode
+	ch <- c.DefaultNetworkRole
+	ch <- c.DetectedCloudPlatform
+	ch <- c.DetectManagedEvents
+	ch <- c.DetectManagedEventsThreshold
+	ch <- c.DisableGroupPreferredOwnerRandomization
+	ch <- c.DrainOnShutdown
+	ch <- c.DynamicQuorumEnabled
+	ch <- c.EnableSharedVolumes
+	ch <- c.FixQuorum
+	ch <- c.GracePeriodEnabled
+	ch <- c.GracePeriodTimeout
+	ch <- c.GroupDependencyTimeout
+	ch <- c.HangRecoveryAction
+	ch <- c.IgnorePersistentStateOnStartup
+	ch <- c.LogResourceControls
+	ch <- c.LowerQuorumPriorityNodeId
+	ch <- c.MaxNumberOfNodes
+	ch <- c.MessageBufferLength
+	ch <- c.MinimumNeverPreemptPriority
+	ch <- c.MinimumPreemptorPriority
+	ch <- c.NetftIPSecEnabled
+	ch <- c.PlacementOptions
+	ch <- c.PlumbAllCrossSubnetRoutes
+	ch <- c.PreventQuorum
+	ch <- c.QuarantineDuration
+	ch <- c.QuarantineThreshold
+	ch <- c.QuorumArbitrationTimeMax
+	ch <- c.QuorumArbitrationTimeMin
+	ch <- c.QuorumLogFileSize
+	ch <- c.QuorumTypeValue
+	ch <- c.RequestReplyTimeout
+	ch <- c.ResiliencyDefaultPeriod
+	ch <- c.ResiliencyLevel
+	ch <- c.ResourceDllDeadlockPeriod
+	ch <- c.RootMemoryReserved
+	ch <- c.RouteHistoryLength
+	ch <- c.S2DBusTypes
+	ch <- c.S2DCacheDesiredState
+	ch <- c.S2DCacheFlashReservePercent
+	ch <- c.S2DCachePageSizeKBytes
+	ch <- c.S2DEnabled
+	ch <- c.S2DIOLatencyThreshold
+	ch <- c.S2DOptimizations
+	ch <- c.SameSubnetDelay
+	ch <- c.SameSubnetThreshold
+	ch <- c.SecurityLevel
+	ch <- c.SecurityLevelForStorage
+	ch <- c.SharedVolumeVssWriterOperationTimeout
+	ch <- c.ShutdownTimeoutInMinutes
+	ch <- c.UseClientAccessNetworksForSharedVolumes
+	ch <- c.WitnessDatabaseWriteTimeout
+	ch <- c.WitnessDynamicWeight
+	ch <- c.WitnessRestartInterval
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *MSCluster_ClusterCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	var dst []MSCluster_Cluster
 	q := queryAll(&dst)
-	if err := wmi.QueryNamespace(q, &dst, "root/MSCluster"); err != nil {
+	if err := wmiQueryNamespace(ctx, q, &dst, "root/MSCluster"); err != nil {
 		return err
 	}
 
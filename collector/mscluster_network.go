@@ -2,11 +2,16 @@ package collector
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("mscluster_network", newMSCluster_NetworkCollector)
+	registerCollectorInfo("mscluster_network", CollectorInfo{
+		Description: "MSCluster network metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "Failover Clustering feature",
+	})
+	registerApplicabilityCheck("mscluster_network", serviceInstalled("ClusSvc"))
 }
 
 // A MSCluster_NetworkCollector is a Prometheus collector for WMI MSCluster_Network metrics
@@ -21,31 +26,31 @@ type MSCluster_NetworkCollector struct {
 func newMSCluster_NetworkCollector() (Collector, error) {
 	const subsystem = "mscluster_network"
 	return &MSCluster_NetworkCollector{
-		Characteristics: prometheus.NewDesc(
+		Characteristics: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "characteristics"),
 			"Provides the characteristics of the network.",
 			[]string{"name"},
 			nil,
 		),
-		Flags: prometheus.NewDesc(
+		Flags: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "flags"),
 			"Provides access to the flags set for the node. ",
 			[]string{"name"},
 			nil,
 		),
-		Metric: prometheus.NewDesc(
+		Metric: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "metric"),
 			"The metric of a cluster network (networks with lower values are used first). If this value is set, then the AutoMetric property is set to false.",
 			[]string{"name"},
 			nil,
 		),
-		Role: prometheus.NewDesc(
+		Role: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "role"),
 			"Provides access to the network's Role property. The Role property describes the role of the network in the cluster. 0: None; 1: Cluster; 2: Client; 3: Both ",
 			[]string{"name"},
 			nil,
 		),
-		State: prometheus.NewDesc(
+		State: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "state"),
 			"Provides the current state of the network. 1-1: Unknown; 0: Unavailable; 1: Down; 2: Partitioned; 3: Up",
 			[]string{"name"},
@@ -66,12 +71,21 @@ type MSCluster_Network struct {
 	State           uint
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *MSCluster_NetworkCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Characteristics
+	ch <- c.Flags
+	ch <- c.Metric
+	ch <- c.Role
+	ch <- c.State
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *MSCluster_NetworkCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	var dst []MSCluster_Network
 	q := queryAll(&dst)
-	if err := wmi.QueryNamespace(q, &dst, "root/MSCluster"); err != nil {
+	if err := wmiQueryNamespace(ctx, q, &dst, "root/MSCluster"); err != nil {
 		return err
 	}
 
@@ -2,11 +2,16 @@ package collector
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("mscluster_node", newMSCluster_NodeCollector)
+	registerCollectorInfo("mscluster_node", CollectorInfo{
+		Description: "MSCluster Node metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "Failover Clustering feature",
+	})
+	registerApplicabilityCheck("mscluster_node", serviceInstalled("ClusSvc"))
 }
 
 // A MSCluster_NodeCollector is a Prometheus collector for WMI MSCluster_Node metrics
@@ -30,85 +35,85 @@ type MSCluster_NodeCollector struct {
 func newMSCluster_NodeCollector() (Collector, error) {
 	const subsystem = "mscluster_node"
 	return &MSCluster_NodeCollector{
-		BuildNumber: prometheus.NewDesc(
+		BuildNumber: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "build_number"),
 			"Provides access to the node's BuildNumber property.",
 			[]string{"name"},
 			nil,
 		),
-		Characteristics: prometheus.NewDesc(
+		Characteristics: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "characteristics"),
 			"Provides access to the characteristics set for the node.",
 			[]string{"name"},
 			nil,
 		),
-		DetectedCloudPlatform: prometheus.NewDesc(
+		DetectedCloudPlatform: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "detected_cloud_platform"),
 			"(DetectedCloudPlatform)",
 			[]string{"name"},
 			nil,
 		),
-		DynamicWeight: prometheus.NewDesc(
+		DynamicWeight: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dynamic_weight"),
 			"The dynamic vote weight of the node adjusted by dynamic quorum feature.",
 			[]string{"name"},
 			nil,
 		),
-		Flags: prometheus.NewDesc(
+		Flags: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "flags"),
 			"Provides access to the flags set for the node.",
 			[]string{"name"},
 			nil,
 		),
-		MajorVersion: prometheus.NewDesc(
+		MajorVersion: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "major_version"),
 			"Provides access to the node's MajorVersion property, which specifies the major portion of the Windows version installed.",
 			[]string{"name"},
 			nil,
 		),
-		MinorVersion: prometheus.NewDesc(
+		MinorVersion: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "minor_version"),
 			"Provides access to the node's MinorVersion property, which specifies the minor portion of the Windows version installed.",
 			[]string{"name"},
 			nil,
 		),
-		NeedsPreventQuorum: prometheus.NewDesc(
+		NeedsPreventQuorum: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "needs_prevent_quorum"),
 			"Whether the cluster service on that node should be started with prevent quorum flag.",
 			[]string{"name"},
 			nil,
 		),
-		NodeDrainStatus: prometheus.NewDesc(
+		NodeDrainStatus: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "node_drain_status"),
 			"The current node drain status of a node. 0: Not Initiated; 1: In Progress; 2: Completed; 3: Failed",
 			[]string{"name"},
 			nil,
 		),
-		NodeHighestVersion: prometheus.NewDesc(
+		NodeHighestVersion: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "node_highest_version"),
 			"Provides access to the node's NodeHighestVersion property, which specifies the highest possible version of the cluster service with which the node can join or communicate.",
 			[]string{"name"},
 			nil,
 		),
-		NodeLowestVersion: prometheus.NewDesc(
+		NodeLowestVersion: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "node_lowest_version"),
 			"Provides access to the node's NodeLowestVersion property, which specifies the lowest possible version of the cluster service with which the node can join or communicate.",
 			[]string{"name"},
 			nil,
 		),
-		NodeWeight: prometheus.NewDesc(
+		NodeWeight: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "node_weight"),
 			"The vote weight of the node.",
 			[]string{"name"},
 			nil,
 		),
-		State: prometheus.NewDesc(
+		State: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "state"),
 			"Returns the current state of a node. -1: Unknown; 0: Up; 1: Down; 2: Paused; 3: Joining",
 			[]string{"name"},
 			nil,
 		),
-		StatusInformation: prometheus.NewDesc(
+		StatusInformation: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "status_information"),
 			"The isolation or quarantine status of the node.",
 			[]string{"name"},
@@ -138,12 +143,30 @@ type MSCluster_Node struct {
 	StatusInformation     uint
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *MSCluster_NodeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.BuildNumber
+	ch <- c.Characteristics
+	ch <- c.DetectedCloudPlatform
+	ch <- c.DynamicWeight
+	ch <- c.Flags
+	ch <- c.MajorVersion
+	ch <- c.MinorVersion
+	ch <- c.NeedsPreventQuorum
+	ch <- c.NodeDrainStatus
+	ch <- c.NodeHighestVersion
+	ch <- c.NodeLowestVersion
+	ch <- c.NodeWeight
+	ch <- c.State
+	ch <- c.StatusInformation
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *MSCluster_NodeCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	var dst []MSCluster_Node
 	q := queryAll(&dst)
-	if err := wmi.QueryNamespace(q, &dst, "root/MSCluster"); err != nil {
+	if err := wmiQueryNamespace(ctx, q, &dst, "root/MSCluster"); err != nil {
 		return err
 	}
 
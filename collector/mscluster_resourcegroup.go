@@ -2,11 +2,16 @@ package collector
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("mscluster_resourcegroup", newMSCluster_ResourceGroupCollector)
+	registerCollectorInfo("mscluster_resourcegroup", CollectorInfo{
+		Description: "MSCluster ResourceGroup metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "Failover Clustering feature",
+	})
+	registerApplicabilityCheck("mscluster_resourcegroup", serviceInstalled("ClusSvc"))
 }
 
 // A MSCluster_ResourceGroupCollector is a Prometheus collector for WMI MSCluster_ResourceGroup metrics
@@ -31,79 +36,79 @@ type MSCluster_ResourceGroupCollector struct {
 func newMSCluster_ResourceGroupCollector() (Collector, error) {
 	const subsystem = "mscluster_resourcegroup"
 	return &MSCluster_ResourceGroupCollector{
-		AutoFailbackType: prometheus.NewDesc(
+		AutoFailbackType: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "auto_failback_type"),
 			"Provides access to the group's AutoFailbackType property.",
 			[]string{"name"},
 			nil,
 		),
-		Characteristics: prometheus.NewDesc(
+		Characteristics: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "characteristics"),
 			"Provides the characteristics of the group.",
 			[]string{"name"},
 			nil,
 		),
-		ColdStartSetting: prometheus.NewDesc(
+		ColdStartSetting: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cold_start_setting"),
 			"Indicates whether a group can start after a cluster cold start.",
 			[]string{"name"},
 			nil,
 		),
-		DefaultOwner: prometheus.NewDesc(
+		DefaultOwner: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "default_owner"),
 			"Number of the last node the resource group was activated on or explicitly moved to.",
 			[]string{"name"},
 			nil,
 		),
-		FailbackWindowEnd: prometheus.NewDesc(
+		FailbackWindowEnd: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failback_window_end"),
 			"The FailbackWindowEnd property provides the latest time that the group can be moved back to the node identified as its preferred node.",
 			[]string{"name"},
 			nil,
 		),
-		FailbackWindowStart: prometheus.NewDesc(
+		FailbackWindowStart: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failback_window_start"),
 			"The FailbackWindowStart property provides the earliest time (that is, local time as kept by the cluster) that the group can be moved back to the node identified as its preferred node.",
 			[]string{"name"},
 			nil,
 		),
-		FailoverPeriod: prometheus.NewDesc(
+		FailoverPeriod: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_period"),
 			"The FailoverPeriod property specifies a number of hours during which a maximum number of failover attempts, specified by the FailoverThreshold property, can occur.",
 			[]string{"name"},
 			nil,
 		),
-		FailoverThreshold: prometheus.NewDesc(
+		FailoverThreshold: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "failover_threshold"),
 			"The FailoverThreshold property specifies the maximum number of failover attempts.",
 			[]string{"name"},
 			nil,
 		),
-		Flags: prometheus.NewDesc(
+		Flags: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "flags"),
 			"Provides access to the flags set for the group. ",
 			[]string{"name"},
 			nil,
 		),
-		GroupType: prometheus.NewDesc(
+		GroupType: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "group_type"),
 			"The Type of the resource group.",
 			[]string{"name"},
 			nil,
 		),
-		Priority: prometheus.NewDesc(
+		Priority: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "priority"),
 			"Priority value of the resource group",
 			[]string{"name"},
 			nil,
 		),
-		ResiliencyPeriod: prometheus.NewDesc(
+		ResiliencyPeriod: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "resiliency_period"),
 			"The resiliency period for this group, in seconds.",
 			[]string{"name"},
 			nil,
 		),
-		State: prometheus.NewDesc(
+		State: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "state"),
 			"The current state of the resource group. -1: Unknown; 0: Online; 1: Offline; 2: Failed; 3: Partial Online; 4: Pending",
 			[]string{"name"},
@@ -132,12 +137,31 @@ type MSCluster_ResourceGroup struct {
 	State               uint
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *MSCluster_ResourceGroupCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.AutoFailbackType
+	ch <- c.Characteristics
+	ch <- c.ColdStartSetting
+	ch <- c.DefaultOwner
+	ch <- c.FailbackWindowEnd
+	ch <- c.FailbackWindowStart
+	ch <- c.FailoverPeriod
+	ch <- c.FailoverThreshold
+	ch <- c.FaultDomain
+	ch <- c.Flags
+	ch <- c.GroupType
+	ch <- c.PlacementOptions
+	ch <- c.Priority
+	ch <- c.ResiliencyPeriod
+	ch <- c.State
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *MSCluster_ResourceGroupCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	var dst []MSCluster_ResourceGroup
 	q := queryAll(&dst)
-	if err := wmi.QueryNamespace(q, &dst, "root/MSCluster"); err != nil {
+	if err := wmiQueryNamespace(ctx, q, &dst, "root/MSCluster"); err != nil {
 		return err
 	}
 
@@ -7,19 +7,25 @@ import (
 	"strings"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
+const msmqWhereClauseFlag = "collector.msmq.msmq-where"
+
 func init() {
-	registerCollector("msmq", NewMSMQCollector)
+	registerCollectorWithSettings("msmq", NewMSMQCollector)
+	registerCollectorInfo("msmq", CollectorInfo{
+		Description: "MSMQ queues",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "Message Queuing feature",
+	})
+	registerApplicabilityCheck("msmq", serviceInstalled("MSMQ"))
+
+	kingpin.Flag(msmqWhereClauseFlag, "WQL 'where' clause to use in WMI metrics query. Limits the response to the msmqs you specify and reduces the size of the response.").String()
 }
 
-var (
-	msmqWhereClause = kingpin.Flag("collector.msmq.msmq-where", "WQL 'where' clause to use in WMI metrics query. Limits the response to the msmqs you specify and reduces the size of the response.").String()
-)
-
 // A Win32_PerfRawData_MSMQ_MSMQQueueCollector is a Prometheus collector for WMI Win32_PerfRawData_MSMQ_MSMQQueue metrics
 type Win32_PerfRawData_MSMQ_MSMQQueueCollector struct {
 	BytesinJournalQueue    *prometheus.Desc
@@ -31,46 +37,54 @@ type Win32_PerfRawData_MSMQ_MSMQQueueCollector struct {
 }
 
 // NewWin32_PerfRawData_MSMQ_MSMQQueueCollector ...
-func NewMSMQCollector() (Collector, error) {
+func NewMSMQCollector(settings *config.Config) (Collector, error) {
 	const subsystem = "msmq"
 
-	if *msmqWhereClause == "" {
+	if settings.Collector.MSMQ.Where.String() == "" {
 		log.Warn("No where-clause specified for msmq collector. This will generate a very large number of metrics!")
 	}
 
 	return &Win32_PerfRawData_MSMQ_MSMQQueueCollector{
-		BytesinJournalQueue: prometheus.NewDesc(
+		BytesinJournalQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bytes_in_journal_queue"),
 			"Size of queue journal in bytes",
 			[]string{"name"},
 			nil,
 		),
-		BytesinQueue: prometheus.NewDesc(
+		BytesinQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bytes_in_queue"),
 			"Size of queue in bytes",
 			[]string{"name"},
 			nil,
 		),
-		MessagesinJournalQueue: prometheus.NewDesc(
+		MessagesinJournalQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_in_journal_queue"),
 			"Count messages in queue journal",
 			[]string{"name"},
 			nil,
 		),
-		MessagesinQueue: prometheus.NewDesc(
+		MessagesinQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_in_queue"),
 			"Count messages in queue",
 			[]string{"name"},
 			nil,
 		),
-		queryWhereClause: *msmqWhereClause,
+		queryWhereClause: settings.Collector.MSMQ.Where.String(),
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *Win32_PerfRawData_MSMQ_MSMQQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.BytesinJournalQueue
+	ch <- c.BytesinQueue
+	ch <- c.MessagesinJournalQueue
+	ch <- c.MessagesinQueue
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *Win32_PerfRawData_MSMQ_MSMQQueueCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting msmq metrics:", desc, err)
 		return err
 	}
@@ -86,10 +100,10 @@ type Win32_PerfRawData_MSMQ_MSMQQueue struct {
 	MessagesinQueue        uint64
 }
 
-func (c *Win32_PerfRawData_MSMQ_MSMQQueueCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *Win32_PerfRawData_MSMQ_MSMQQueueCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_MSMQ_MSMQQueue
 	q := queryAllWhere(&dst, c.queryWhereClause)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -12,23 +12,12 @@ import (
 	"time"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sys/windows/registry"
 )
 
-var (
-	mssqlEnabledCollectors = kingpin.Flag(
-		"collectors.mssql.classes-enabled",
-		"Comma-separated list of mssql WMI classes to use.").
-		Default(mssqlAvailableClassCollectors()).String()
-
-	mssqlPrintCollectors = kingpin.Flag(
-		"collectors.mssql.class-print",
-		"If true, print available mssql WMI classes and exit.  Only displays if the mssql collector is enabled.",
-	).Bool()
-)
-
 type mssqlInstancesType map[string]string
 
 func getMSSQLInstances() mssqlInstancesType {
@@ -129,8 +118,45 @@ func mssqlGetPerfObjectName(sqlInstance string, collector string) string {
 	return (prefix + suffix)
 }
 
+// mssqlInstalled reports whether any SQL Server instance is installed. Unlike
+// getMSSQLInstances, it does not fall back to the default instance.
+func mssqlInstalled() (bool, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `Software\Microsoft\Microsoft SQL Server\Instance Names\SQL`, registry.QUERY_VALUE)
+	if err == registry.ErrNotExist {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer k.Close() //nolint:errcheck
+
+	instanceNames, err := k.ReadValueNames(0)
+	return len(instanceNames) > 0, err
+}
+
+const (
+	mssqlEnabledCollectorsFlag = "collectors.mssql.classes-enabled"
+	mssqlPrintCollectorsFlag   = "collectors.mssql.class-print"
+)
+
 func init() {
-	registerCollector("mssql", NewMSSQLCollector)
+	registerCollectorWithSettings("mssql", NewMSSQLCollector)
+	registerCollectorInfo("mssql", CollectorInfo{
+		Description: "SQL Server Performance Objects metrics",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "SQL Server",
+		Expensive:   true,
+	})
+	registerApplicabilityCheck("mssql", mssqlInstalled)
+
+	kingpin.Flag(
+		mssqlEnabledCollectorsFlag,
+		"Comma-separated list of mssql WMI classes to use.").
+		Default(mssqlAvailableClassCollectors()).String()
+	kingpin.Flag(
+		mssqlPrintCollectorsFlag,
+		"If true, print available mssql WMI classes and exit.  Only displays if the mssql collector is enabled.",
+	).Bool()
 }
 
 // A MSSQLCollector is a Prometheus collector for various WMI Win32_PerfRawData_MSSQLSERVER_* metrics
@@ -401,16 +427,17 @@ type MSSQLCollector struct {
 	WaitStatsTransactionOwnershipWaits     *prometheus.Desc
 
 	mssqlInstances             mssqlInstancesType
+	mssqlEnabledCollectors     []string
 	mssqlCollectors            mssqlCollectorsMap
 	mssqlChildCollectorFailure int
 }
 
 // NewMSSQLCollector ...
-func NewMSSQLCollector() (Collector, error) {
+func NewMSSQLCollector(settings *config.Config) (Collector, error) {
 
 	const subsystem = "mssql"
 
-	enabled := expandEnabledChildCollectors(*mssqlEnabledCollectors)
+	enabled := expandEnabledChildCollectors(strings.Join(settings.Collectors.MSSQL.ClassesEnabled, ","))
 	mssqlInstances := getMSSQLInstances()
 	perfCounters := make([]string, 0, len(mssqlInstances)*len(enabled))
 	for instance := range mssqlInstances {
@@ -422,13 +449,13 @@ func NewMSSQLCollector() (Collector, error) {
 
 	mssqlCollector := MSSQLCollector{
 		// meta
-		mssqlScrapeDurationDesc: prometheus.NewDesc(
+		mssqlScrapeDurationDesc: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "collector_duration_seconds"),
 			"windows_exporter: Duration of an mssql child collection.",
 			[]string{"collector", "mssql_instance"},
 			nil,
 		),
-		mssqlScrapeSuccessDesc: prometheus.NewDesc(
+		mssqlScrapeSuccessDesc: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "collector_success"),
 			"windows_exporter: Whether a mssql child collector was successful.",
 			[]string{"collector", "mssql_instance"},
@@ -436,265 +463,265 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerAccessMethods
-		AccessMethodsAUcleanupbatches: prometheus.NewDesc(
+		AccessMethodsAUcleanupbatches: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_au_batch_cleanups"),
 			"(AccessMethods.AUcleanupbatches)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsAUcleanups: prometheus.NewDesc(
+		AccessMethodsAUcleanups: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_au_cleanups"),
 			"(AccessMethods.AUcleanups)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsByreferenceLobCreateCount: prometheus.NewDesc(
+		AccessMethodsByreferenceLobCreateCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_by_reference_lob_creates"),
 			"(AccessMethods.ByreferenceLobCreateCount)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsByreferenceLobUseCount: prometheus.NewDesc(
+		AccessMethodsByreferenceLobUseCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_by_reference_lob_uses"),
 			"(AccessMethods.ByreferenceLobUseCount)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsCountLobReadahead: prometheus.NewDesc(
+		AccessMethodsCountLobReadahead: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_lob_read_aheads"),
 			"(AccessMethods.CountLobReadahead)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsCountPullInRow: prometheus.NewDesc(
+		AccessMethodsCountPullInRow: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_column_value_pulls"),
 			"(AccessMethods.CountPullInRow)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsCountPushOffRow: prometheus.NewDesc(
+		AccessMethodsCountPushOffRow: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_column_value_pushes"),
 			"(AccessMethods.CountPushOffRow)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsDeferreddroppedAUs: prometheus.NewDesc(
+		AccessMethodsDeferreddroppedAUs: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_deferred_dropped_aus"),
 			"(AccessMethods.DeferreddroppedAUs)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsDeferredDroppedrowsets: prometheus.NewDesc(
+		AccessMethodsDeferredDroppedrowsets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_deferred_dropped_rowsets"),
 			"(AccessMethods.DeferredDroppedrowsets)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsDroppedrowsetcleanups: prometheus.NewDesc(
+		AccessMethodsDroppedrowsetcleanups: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_dropped_rowset_cleanups"),
 			"(AccessMethods.Droppedrowsetcleanups)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsDroppedrowsetsskipped: prometheus.NewDesc(
+		AccessMethodsDroppedrowsetsskipped: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_dropped_rowset_skips"),
 			"(AccessMethods.Droppedrowsetsskipped)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsExtentDeallocations: prometheus.NewDesc(
+		AccessMethodsExtentDeallocations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_extent_deallocations"),
 			"(AccessMethods.ExtentDeallocations)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsExtentsAllocated: prometheus.NewDesc(
+		AccessMethodsExtentsAllocated: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_extent_allocations"),
 			"(AccessMethods.ExtentsAllocated)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsFailedAUcleanupbatches: prometheus.NewDesc(
+		AccessMethodsFailedAUcleanupbatches: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_au_batch_cleanup_failures"),
 			"(AccessMethods.FailedAUcleanupbatches)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsFailedleafpagecookie: prometheus.NewDesc(
+		AccessMethodsFailedleafpagecookie: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_leaf_page_cookie_failures"),
 			"(AccessMethods.Failedleafpagecookie)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsFailedtreepagecookie: prometheus.NewDesc(
+		AccessMethodsFailedtreepagecookie: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_tree_page_cookie_failures"),
 			"(AccessMethods.Failedtreepagecookie)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsForwardedRecords: prometheus.NewDesc(
+		AccessMethodsForwardedRecords: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_forwarded_records"),
 			"(AccessMethods.ForwardedRecords)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsFreeSpacePageFetches: prometheus.NewDesc(
+		AccessMethodsFreeSpacePageFetches: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_free_space_page_fetches"),
 			"(AccessMethods.FreeSpacePageFetches)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsFreeSpaceScans: prometheus.NewDesc(
+		AccessMethodsFreeSpaceScans: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_free_space_scans"),
 			"(AccessMethods.FreeSpaceScans)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsFullScans: prometheus.NewDesc(
+		AccessMethodsFullScans: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_full_scans"),
 			"(AccessMethods.FullScans)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsIndexSearches: prometheus.NewDesc(
+		AccessMethodsIndexSearches: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_index_searches"),
 			"(AccessMethods.IndexSearches)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsInSysXactwaits: prometheus.NewDesc(
+		AccessMethodsInSysXactwaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_insysxact_waits"),
 			"(AccessMethods.InSysXactwaits)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsLobHandleCreateCount: prometheus.NewDesc(
+		AccessMethodsLobHandleCreateCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_lob_handle_creates"),
 			"(AccessMethods.LobHandleCreateCount)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsLobHandleDestroyCount: prometheus.NewDesc(
+		AccessMethodsLobHandleDestroyCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_lob_handle_destroys"),
 			"(AccessMethods.LobHandleDestroyCount)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsLobSSProviderCreateCount: prometheus.NewDesc(
+		AccessMethodsLobSSProviderCreateCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_lob_ss_provider_creates"),
 			"(AccessMethods.LobSSProviderCreateCount)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsLobSSProviderDestroyCount: prometheus.NewDesc(
+		AccessMethodsLobSSProviderDestroyCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_lob_ss_provider_destroys"),
 			"(AccessMethods.LobSSProviderDestroyCount)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsLobSSProviderTruncationCount: prometheus.NewDesc(
+		AccessMethodsLobSSProviderTruncationCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_lob_ss_provider_truncations"),
 			"(AccessMethods.LobSSProviderTruncationCount)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsMixedpageallocations: prometheus.NewDesc(
+		AccessMethodsMixedpageallocations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_mixed_page_allocations"),
 			"(AccessMethods.MixedpageallocationsPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsPagecompressionattempts: prometheus.NewDesc(
+		AccessMethodsPagecompressionattempts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_page_compression_attempts"),
 			"(AccessMethods.PagecompressionattemptsPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsPageDeallocations: prometheus.NewDesc(
+		AccessMethodsPageDeallocations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_page_deallocations"),
 			"(AccessMethods.PageDeallocationsPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsPagesAllocated: prometheus.NewDesc(
+		AccessMethodsPagesAllocated: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_page_allocations"),
 			"(AccessMethods.PagesAllocatedPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsPagescompressed: prometheus.NewDesc(
+		AccessMethodsPagescompressed: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_page_compressions"),
 			"(AccessMethods.PagescompressedPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsPageSplits: prometheus.NewDesc(
+		AccessMethodsPageSplits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_page_splits"),
 			"(AccessMethods.PageSplitsPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsProbeScans: prometheus.NewDesc(
+		AccessMethodsProbeScans: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_probe_scans"),
 			"(AccessMethods.ProbeScansPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsRangeScans: prometheus.NewDesc(
+		AccessMethodsRangeScans: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_range_scans"),
 			"(AccessMethods.RangeScansPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsScanPointRevalidations: prometheus.NewDesc(
+		AccessMethodsScanPointRevalidations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_scan_point_revalidations"),
 			"(AccessMethods.ScanPointRevalidationsPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsSkippedGhostedRecords: prometheus.NewDesc(
+		AccessMethodsSkippedGhostedRecords: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_ghost_record_skips"),
 			"(AccessMethods.SkippedGhostedRecordsPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsTableLockEscalations: prometheus.NewDesc(
+		AccessMethodsTableLockEscalations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_table_lock_escalations"),
 			"(AccessMethods.TableLockEscalationsPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsUsedleafpagecookie: prometheus.NewDesc(
+		AccessMethodsUsedleafpagecookie: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_leaf_page_cookie_uses"),
 			"(AccessMethods.Usedleafpagecookie)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsUsedtreepagecookie: prometheus.NewDesc(
+		AccessMethodsUsedtreepagecookie: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_tree_page_cookie_uses"),
 			"(AccessMethods.Usedtreepagecookie)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsWorkfilesCreated: prometheus.NewDesc(
+		AccessMethodsWorkfilesCreated: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_workfile_creates"),
 			"(AccessMethods.WorkfilesCreatedPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsWorktablesCreated: prometheus.NewDesc(
+		AccessMethodsWorktablesCreated: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_worktables_creates"),
 			"(AccessMethods.WorktablesCreatedPersec)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsWorktablesFromCacheHits: prometheus.NewDesc(
+		AccessMethodsWorktablesFromCacheHits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_worktables_from_cache_hits"),
 			"(AccessMethods.WorktablesFromCacheRatio)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		AccessMethodsWorktablesFromCacheLookups: prometheus.NewDesc(
+		AccessMethodsWorktablesFromCacheLookups: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "accessmethods_worktables_from_cache_lookups"),
 			"(AccessMethods.WorktablesFromCacheRatio_Base)",
 			[]string{"mssql_instance"},
@@ -702,55 +729,55 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerAvailabilityReplica
-		AvailReplicaBytesReceivedfromReplica: prometheus.NewDesc(
+		AvailReplicaBytesReceivedfromReplica: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availreplica_received_from_replica_bytes"),
 			"(AvailabilityReplica.BytesReceivedfromReplica)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		AvailReplicaBytesSenttoReplica: prometheus.NewDesc(
+		AvailReplicaBytesSenttoReplica: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availreplica_sent_to_replica_bytes"),
 			"(AvailabilityReplica.BytesSenttoReplica)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		AvailReplicaBytesSenttoTransport: prometheus.NewDesc(
+		AvailReplicaBytesSenttoTransport: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availreplica_sent_to_transport_bytes"),
 			"(AvailabilityReplica.BytesSenttoTransport)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		AvailReplicaFlowControl: prometheus.NewDesc(
+		AvailReplicaFlowControl: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availreplica_initiated_flow_controls"),
 			"(AvailabilityReplica.FlowControl)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		AvailReplicaFlowControlTimems: prometheus.NewDesc(
+		AvailReplicaFlowControlTimems: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availreplica_flow_control_wait_seconds"),
 			"(AvailabilityReplica.FlowControlTimems)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		AvailReplicaReceivesfromReplica: prometheus.NewDesc(
+		AvailReplicaReceivesfromReplica: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availreplica_receives_from_replica"),
 			"(AvailabilityReplica.ReceivesfromReplica)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		AvailReplicaResentMessages: prometheus.NewDesc(
+		AvailReplicaResentMessages: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availreplica_resent_messages"),
 			"(AvailabilityReplica.ResentMessages)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		AvailReplicaSendstoReplica: prometheus.NewDesc(
+		AvailReplicaSendstoReplica: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availreplica_sends_to_replica"),
 			"(AvailabilityReplica.SendstoReplica)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		AvailReplicaSendstoTransport: prometheus.NewDesc(
+		AvailReplicaSendstoTransport: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "availreplica_sends_to_transport"),
 			"(AvailabilityReplica.SendstoTransport)",
 			[]string{"mssql_instance", "replica"},
@@ -758,139 +785,139 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerBufferManager
-		BufManBackgroundwriterpages: prometheus.NewDesc(
+		BufManBackgroundwriterpages: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_background_writer_pages"),
 			"(BufferManager.Backgroundwriterpages)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManBuffercachehits: prometheus.NewDesc(
+		BufManBuffercachehits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_buffer_cache_hits"),
 			"(BufferManager.Buffercachehitratio)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManBuffercachelookups: prometheus.NewDesc(
+		BufManBuffercachelookups: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_buffer_cache_lookups"),
 			"(BufferManager.Buffercachehitratio_Base)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManCheckpointpages: prometheus.NewDesc(
+		BufManCheckpointpages: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_checkpoint_pages"),
 			"(BufferManager.Checkpointpages)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManDatabasepages: prometheus.NewDesc(
+		BufManDatabasepages: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_database_pages"),
 			"(BufferManager.Databasepages)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManExtensionallocatedpages: prometheus.NewDesc(
+		BufManExtensionallocatedpages: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_extension_allocated_pages"),
 			"(BufferManager.Extensionallocatedpages)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManExtensionfreepages: prometheus.NewDesc(
+		BufManExtensionfreepages: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_extension_free_pages"),
 			"(BufferManager.Extensionfreepages)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManExtensioninuseaspercentage: prometheus.NewDesc(
+		BufManExtensioninuseaspercentage: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_extension_in_use_as_percentage"),
 			"(BufferManager.Extensioninuseaspercentage)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManExtensionoutstandingIOcounter: prometheus.NewDesc(
+		BufManExtensionoutstandingIOcounter: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_extension_outstanding_io"),
 			"(BufferManager.ExtensionoutstandingIOcounter)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManExtensionpageevictions: prometheus.NewDesc(
+		BufManExtensionpageevictions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_extension_page_evictions"),
 			"(BufferManager.Extensionpageevictions)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManExtensionpagereads: prometheus.NewDesc(
+		BufManExtensionpagereads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_extension_page_reads"),
 			"(BufferManager.Extensionpagereads)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManExtensionpageunreferencedtime: prometheus.NewDesc(
+		BufManExtensionpageunreferencedtime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_extension_page_unreferenced_seconds"),
 			"(BufferManager.Extensionpageunreferencedtime)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManExtensionpagewrites: prometheus.NewDesc(
+		BufManExtensionpagewrites: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_extension_page_writes"),
 			"(BufferManager.Extensionpagewrites)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManFreeliststalls: prometheus.NewDesc(
+		BufManFreeliststalls: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_free_list_stalls"),
 			"(BufferManager.Freeliststalls)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManIntegralControllerSlope: prometheus.NewDesc(
+		BufManIntegralControllerSlope: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_integral_controller_slope"),
 			"(BufferManager.IntegralControllerSlope)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManLazywrites: prometheus.NewDesc(
+		BufManLazywrites: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_lazywrites"),
 			"(BufferManager.Lazywrites)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManPagelifeexpectancy: prometheus.NewDesc(
+		BufManPagelifeexpectancy: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_page_life_expectancy_seconds"),
 			"(BufferManager.Pagelifeexpectancy)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManPagelookups: prometheus.NewDesc(
+		BufManPagelookups: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_page_lookups"),
 			"(BufferManager.Pagelookups)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManPagereads: prometheus.NewDesc(
+		BufManPagereads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_page_reads"),
 			"(BufferManager.Pagereads)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManPagewrites: prometheus.NewDesc(
+		BufManPagewrites: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_page_writes"),
 			"(BufferManager.Pagewrites)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManReadaheadpages: prometheus.NewDesc(
+		BufManReadaheadpages: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_read_ahead_pages"),
 			"(BufferManager.Readaheadpages)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManReadaheadtime: prometheus.NewDesc(
+		BufManReadaheadtime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_read_ahead_issuing_seconds"),
 			"(BufferManager.Readaheadtime)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		BufManTargetpages: prometheus.NewDesc(
+		BufManTargetpages: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bufman_target_pages"),
 			"(BufferManager.Targetpages)",
 			[]string{"mssql_instance"},
@@ -898,145 +925,145 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerDatabaseReplica
-		DBReplicaDatabaseFlowControlDelay: prometheus.NewDesc(
+		DBReplicaDatabaseFlowControlDelay: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_database_flow_control_wait_seconds"),
 			"(DatabaseReplica.DatabaseFlowControlDelay)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaDatabaseFlowControls: prometheus.NewDesc(
+		DBReplicaDatabaseFlowControls: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_database_initiated_flow_controls"),
 			"(DatabaseReplica.DatabaseFlowControls)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaFileBytesReceived: prometheus.NewDesc(
+		DBReplicaFileBytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_received_file_bytes"),
 			"(DatabaseReplica.FileBytesReceived)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaGroupCommits: prometheus.NewDesc(
+		DBReplicaGroupCommits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_group_commits"),
 			"(DatabaseReplica.GroupCommits)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaGroupCommitTime: prometheus.NewDesc(
+		DBReplicaGroupCommitTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_group_commit_stall_seconds"),
 			"(DatabaseReplica.GroupCommitTime)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogApplyPendingQueue: prometheus.NewDesc(
+		DBReplicaLogApplyPendingQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_apply_pending_queue"),
 			"(DatabaseReplica.LogApplyPendingQueue)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogApplyReadyQueue: prometheus.NewDesc(
+		DBReplicaLogApplyReadyQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_apply_ready_queue"),
 			"(DatabaseReplica.LogApplyReadyQueue)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogBytesCompressed: prometheus.NewDesc(
+		DBReplicaLogBytesCompressed: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_compressed_bytes"),
 			"(DatabaseReplica.LogBytesCompressed)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogBytesDecompressed: prometheus.NewDesc(
+		DBReplicaLogBytesDecompressed: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_decompressed_bytes"),
 			"(DatabaseReplica.LogBytesDecompressed)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogBytesReceived: prometheus.NewDesc(
+		DBReplicaLogBytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_received_bytes"),
 			"(DatabaseReplica.LogBytesReceived)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogCompressionCachehits: prometheus.NewDesc(
+		DBReplicaLogCompressionCachehits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_compression_cachehits"),
 			"(DatabaseReplica.LogCompressionCachehits)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogCompressionCachemisses: prometheus.NewDesc(
+		DBReplicaLogCompressionCachemisses: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_compression_cachemisses"),
 			"(DatabaseReplica.LogCompressionCachemisses)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogCompressions: prometheus.NewDesc(
+		DBReplicaLogCompressions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_compressions"),
 			"(DatabaseReplica.LogCompressions)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogDecompressions: prometheus.NewDesc(
+		DBReplicaLogDecompressions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_decompressions"),
 			"(DatabaseReplica.LogDecompressions)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogremainingforundo: prometheus.NewDesc(
+		DBReplicaLogremainingforundo: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_remaining_for_undo"),
 			"(DatabaseReplica.Logremainingforundo)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaLogSendQueue: prometheus.NewDesc(
+		DBReplicaLogSendQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_log_send_queue"),
 			"(DatabaseReplica.LogSendQueue)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaMirroredWriteTransactions: prometheus.NewDesc(
+		DBReplicaMirroredWriteTransactions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_mirrored_write_transactions"),
 			"(DatabaseReplica.MirroredWriteTransactions)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaRecoveryQueue: prometheus.NewDesc(
+		DBReplicaRecoveryQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_recovery_queue_records"),
 			"(DatabaseReplica.RecoveryQueue)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaRedoblocked: prometheus.NewDesc(
+		DBReplicaRedoblocked: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_redo_blocks"),
 			"(DatabaseReplica.Redoblocked)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaRedoBytesRemaining: prometheus.NewDesc(
+		DBReplicaRedoBytesRemaining: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_redo_remaining_bytes"),
 			"(DatabaseReplica.RedoBytesRemaining)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaRedoneBytes: prometheus.NewDesc(
+		DBReplicaRedoneBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_redone_bytes"),
 			"(DatabaseReplica.RedoneBytes)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaRedones: prometheus.NewDesc(
+		DBReplicaRedones: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_redones"),
 			"(DatabaseReplica.Redones)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaTotalLogrequiringundo: prometheus.NewDesc(
+		DBReplicaTotalLogrequiringundo: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_total_log_requiring_undo"),
 			"(DatabaseReplica.TotalLogrequiringundo)",
 			[]string{"mssql_instance", "replica"},
 			nil,
 		),
-		DBReplicaTransactionDelay: prometheus.NewDesc(
+		DBReplicaTransactionDelay: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dbreplica_transaction_delay_seconds"),
 			"(DatabaseReplica.TransactionDelay)",
 			[]string{"mssql_instance", "replica"},
@@ -1044,289 +1071,289 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerDatabases
-		DatabasesActiveParallelredothreads: prometheus.NewDesc(
+		DatabasesActiveParallelredothreads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_active_parallel_redo_threads"),
 			"(Databases.ActiveParallelredothreads)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesActiveTransactions: prometheus.NewDesc(
+		DatabasesActiveTransactions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_active_transactions"),
 			"(Databases.ActiveTransactions)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesBackupPerRestoreThroughput: prometheus.NewDesc(
+		DatabasesBackupPerRestoreThroughput: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_backup_restore_operations"),
 			"(Databases.BackupPerRestoreThroughput)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesBulkCopyRows: prometheus.NewDesc(
+		DatabasesBulkCopyRows: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_bulk_copy_rows"),
 			"(Databases.BulkCopyRows)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesBulkCopyThroughput: prometheus.NewDesc(
+		DatabasesBulkCopyThroughput: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_bulk_copy_bytes"),
 			"(Databases.BulkCopyThroughput)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesCommittableentries: prometheus.NewDesc(
+		DatabasesCommittableentries: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_commit_table_entries"),
 			"(Databases.Committableentries)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesDataFilesSizeKB: prometheus.NewDesc(
+		DatabasesDataFilesSizeKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_data_files_size_bytes"),
 			"(Databases.DataFilesSizeKB)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesDBCCLogicalScanBytes: prometheus.NewDesc(
+		DatabasesDBCCLogicalScanBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_dbcc_logical_scan_bytes"),
 			"(Databases.DBCCLogicalScanBytes)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesGroupCommitTime: prometheus.NewDesc(
+		DatabasesGroupCommitTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_group_commit_stall_seconds"),
 			"(Databases.GroupCommitTime)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogBytesFlushed: prometheus.NewDesc(
+		DatabasesLogBytesFlushed: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_flushed_bytes"),
 			"(Databases.LogBytesFlushed)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogCacheHits: prometheus.NewDesc(
+		DatabasesLogCacheHits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_cache_hits"),
 			"(Databases.LogCacheHitRatio)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogCacheLookups: prometheus.NewDesc(
+		DatabasesLogCacheLookups: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_cache_lookups"),
 			"(Databases.LogCacheHitRatio_Base)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogCacheReads: prometheus.NewDesc(
+		DatabasesLogCacheReads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_cache_reads"),
 			"(Databases.LogCacheReads)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogFilesSizeKB: prometheus.NewDesc(
+		DatabasesLogFilesSizeKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_files_size_bytes"),
 			"(Databases.LogFilesSizeKB)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogFilesUsedSizeKB: prometheus.NewDesc(
+		DatabasesLogFilesUsedSizeKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_files_used_size_bytes"),
 			"(Databases.LogFilesUsedSizeKB)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogFlushes: prometheus.NewDesc(
+		DatabasesLogFlushes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_flushes"),
 			"(Databases.LogFlushes)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogFlushWaits: prometheus.NewDesc(
+		DatabasesLogFlushWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_flush_waits"),
 			"(Databases.LogFlushWaits)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogFlushWaitTime: prometheus.NewDesc(
+		DatabasesLogFlushWaitTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_flush_wait_seconds"),
 			"(Databases.LogFlushWaitTime)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogFlushWriteTimems: prometheus.NewDesc(
+		DatabasesLogFlushWriteTimems: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_flush_write_seconds"),
 			"(Databases.LogFlushWriteTimems)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogGrowths: prometheus.NewDesc(
+		DatabasesLogGrowths: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_growths"),
 			"(Databases.LogGrowths)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolCacheMisses: prometheus.NewDesc(
+		DatabasesLogPoolCacheMisses: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_cache_misses"),
 			"(Databases.LogPoolCacheMisses)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolDiskReads: prometheus.NewDesc(
+		DatabasesLogPoolDiskReads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_disk_reads"),
 			"(Databases.LogPoolDiskReads)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolHashDeletes: prometheus.NewDesc(
+		DatabasesLogPoolHashDeletes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_hash_deletes"),
 			"(Databases.LogPoolHashDeletes)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolHashInserts: prometheus.NewDesc(
+		DatabasesLogPoolHashInserts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_hash_inserts"),
 			"(Databases.LogPoolHashInserts)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolInvalidHashEntry: prometheus.NewDesc(
+		DatabasesLogPoolInvalidHashEntry: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_invalid_hash_entries"),
 			"(Databases.LogPoolInvalidHashEntry)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolLogScanPushes: prometheus.NewDesc(
+		DatabasesLogPoolLogScanPushes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_log_scan_pushes"),
 			"(Databases.LogPoolLogScanPushes)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolLogWriterPushes: prometheus.NewDesc(
+		DatabasesLogPoolLogWriterPushes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_log_writer_pushes"),
 			"(Databases.LogPoolLogWriterPushes)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolPushEmptyFreePool: prometheus.NewDesc(
+		DatabasesLogPoolPushEmptyFreePool: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_empty_free_pool_pushes"),
 			"(Databases.LogPoolPushEmptyFreePool)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolPushLowMemory: prometheus.NewDesc(
+		DatabasesLogPoolPushLowMemory: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_low_memory_pushes"),
 			"(Databases.LogPoolPushLowMemory)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolPushNoFreeBuffer: prometheus.NewDesc(
+		DatabasesLogPoolPushNoFreeBuffer: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_no_free_buffer_pushes"),
 			"(Databases.LogPoolPushNoFreeBuffer)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolReqBehindTrunc: prometheus.NewDesc(
+		DatabasesLogPoolReqBehindTrunc: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_req_behind_trunc"),
 			"(Databases.LogPoolReqBehindTrunc)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolRequestsOldVLF: prometheus.NewDesc(
+		DatabasesLogPoolRequestsOldVLF: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_requests_old_vlf"),
 			"(Databases.LogPoolRequestsOldVLF)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolRequests: prometheus.NewDesc(
+		DatabasesLogPoolRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_requests"),
 			"(Databases.LogPoolRequests)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolTotalActiveLogSize: prometheus.NewDesc(
+		DatabasesLogPoolTotalActiveLogSize: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_total_active_log_bytes"),
 			"(Databases.LogPoolTotalActiveLogSize)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogPoolTotalSharedPoolSize: prometheus.NewDesc(
+		DatabasesLogPoolTotalSharedPoolSize: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_pool_total_shared_pool_bytes"),
 			"(Databases.LogPoolTotalSharedPoolSize)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogShrinks: prometheus.NewDesc(
+		DatabasesLogShrinks: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_shrinks"),
 			"(Databases.LogShrinks)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesLogTruncations: prometheus.NewDesc(
+		DatabasesLogTruncations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_truncations"),
 			"(Databases.LogTruncations)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesPercentLogUsed: prometheus.NewDesc(
+		DatabasesPercentLogUsed: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_log_used_percent"),
 			"(Databases.PercentLogUsed)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesReplPendingXacts: prometheus.NewDesc(
+		DatabasesReplPendingXacts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_pending_repl_transactions"),
 			"(Databases.ReplPendingTransactions)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesReplTransRate: prometheus.NewDesc(
+		DatabasesReplTransRate: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_repl_transactions"),
 			"(Databases.ReplTranactions)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesShrinkDataMovementBytes: prometheus.NewDesc(
+		DatabasesShrinkDataMovementBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_shrink_data_movement_bytes"),
 			"(Databases.ShrinkDataMovementBytes)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesTrackedtransactions: prometheus.NewDesc(
+		DatabasesTrackedtransactions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_tracked_transactions"),
 			"(Databases.Trackedtransactions)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesTransactions: prometheus.NewDesc(
+		DatabasesTransactions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_transactions"),
 			"(Databases.Transactions)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesWriteTransactions: prometheus.NewDesc(
+		DatabasesWriteTransactions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_write_transactions"),
 			"(Databases.WriteTransactions)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesXTPControllerDLCLatencyPerFetch: prometheus.NewDesc(
+		DatabasesXTPControllerDLCLatencyPerFetch: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_xtp_controller_dlc_fetch_latency_seconds"),
 			"(Databases.XTPControllerDLCLatencyPerFetch)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesXTPControllerDLCPeakLatency: prometheus.NewDesc(
+		DatabasesXTPControllerDLCPeakLatency: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_xtp_controller_dlc_peak_latency_seconds"),
 			"(Databases.XTPControllerDLCPeakLatency)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesXTPControllerLogProcessed: prometheus.NewDesc(
+		DatabasesXTPControllerLogProcessed: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_xtp_controller_log_processed_bytes"),
 			"(Databases.XTPControllerLogProcessed)",
 			[]string{"mssql_instance", "database"},
 			nil,
 		),
-		DatabasesXTPMemoryUsedKB: prometheus.NewDesc(
+		DatabasesXTPMemoryUsedKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "databases_xtp_memory_used_bytes"),
 			"(Databases.XTPMemoryUsedKB)",
 			[]string{"mssql_instance", "database"},
@@ -1334,145 +1361,145 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerGeneralStatistics
-		GenStatsActiveTempTables: prometheus.NewDesc(
+		GenStatsActiveTempTables: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_active_temp_tables"),
 			"(GeneralStatistics.ActiveTempTables)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsConnectionReset: prometheus.NewDesc(
+		GenStatsConnectionReset: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_connection_resets"),
 			"(GeneralStatistics.ConnectionReset)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsEventNotificationsDelayedDrop: prometheus.NewDesc(
+		GenStatsEventNotificationsDelayedDrop: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_event_notifications_delayed_drop"),
 			"(GeneralStatistics.EventNotificationsDelayedDrop)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsHTTPAuthenticatedRequests: prometheus.NewDesc(
+		GenStatsHTTPAuthenticatedRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_http_authenticated_requests"),
 			"(GeneralStatistics.HTTPAuthenticatedRequests)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsLogicalConnections: prometheus.NewDesc(
+		GenStatsLogicalConnections: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_logical_connections"),
 			"(GeneralStatistics.LogicalConnections)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsLogins: prometheus.NewDesc(
+		GenStatsLogins: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_logins"),
 			"(GeneralStatistics.Logins)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsLogouts: prometheus.NewDesc(
+		GenStatsLogouts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_logouts"),
 			"(GeneralStatistics.Logouts)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsMarsDeadlocks: prometheus.NewDesc(
+		GenStatsMarsDeadlocks: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_mars_deadlocks"),
 			"(GeneralStatistics.MarsDeadlocks)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsNonatomicyieldrate: prometheus.NewDesc(
+		GenStatsNonatomicyieldrate: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_non_atomic_yields"),
 			"(GeneralStatistics.Nonatomicyields)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsProcessesblocked: prometheus.NewDesc(
+		GenStatsProcessesblocked: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_blocked_processes"),
 			"(GeneralStatistics.Processesblocked)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsSOAPEmptyRequests: prometheus.NewDesc(
+		GenStatsSOAPEmptyRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_soap_empty_requests"),
 			"(GeneralStatistics.SOAPEmptyRequests)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsSOAPMethodInvocations: prometheus.NewDesc(
+		GenStatsSOAPMethodInvocations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_soap_method_invocations"),
 			"(GeneralStatistics.SOAPMethodInvocations)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsSOAPSessionInitiateRequests: prometheus.NewDesc(
+		GenStatsSOAPSessionInitiateRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_soap_session_initiate_requests"),
 			"(GeneralStatistics.SOAPSessionInitiateRequests)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsSOAPSessionTerminateRequests: prometheus.NewDesc(
+		GenStatsSOAPSessionTerminateRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_soap_session_terminate_requests"),
 			"(GeneralStatistics.SOAPSessionTerminateRequests)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsSOAPSQLRequests: prometheus.NewDesc(
+		GenStatsSOAPSQLRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_soapsql_requests"),
 			"(GeneralStatistics.SOAPSQLRequests)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsSOAPWSDLRequests: prometheus.NewDesc(
+		GenStatsSOAPWSDLRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_soapwsdl_requests"),
 			"(GeneralStatistics.SOAPWSDLRequests)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsSQLTraceIOProviderLockWaits: prometheus.NewDesc(
+		GenStatsSQLTraceIOProviderLockWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_sql_trace_io_provider_lock_waits"),
 			"(GeneralStatistics.SQLTraceIOProviderLockWaits)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsTempdbrecoveryunitid: prometheus.NewDesc(
+		GenStatsTempdbrecoveryunitid: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_tempdb_recovery_unit_ids_generated"),
 			"(GeneralStatistics.Tempdbrecoveryunitid)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsTempdbrowsetid: prometheus.NewDesc(
+		GenStatsTempdbrowsetid: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_tempdb_rowset_ids_generated"),
 			"(GeneralStatistics.Tempdbrowsetid)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsTempTablesCreationRate: prometheus.NewDesc(
+		GenStatsTempTablesCreationRate: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_temp_tables_creations"),
 			"(GeneralStatistics.TempTablesCreations)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsTempTablesForDestruction: prometheus.NewDesc(
+		GenStatsTempTablesForDestruction: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_temp_tables_awaiting_destruction"),
 			"(GeneralStatistics.TempTablesForDestruction)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsTraceEventNotificationQueue: prometheus.NewDesc(
+		GenStatsTraceEventNotificationQueue: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_trace_event_notification_queue_size"),
 			"(GeneralStatistics.TraceEventNotificationQueue)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsTransactions: prometheus.NewDesc(
+		GenStatsTransactions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_transactions"),
 			"(GeneralStatistics.Transactions)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		GenStatsUserConnections: prometheus.NewDesc(
+		GenStatsUserConnections: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "genstats_user_connections"),
 			"(GeneralStatistics.UserConnections)",
 			[]string{"mssql_instance"},
@@ -1480,49 +1507,49 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerLocks
-		LocksWaitTime: prometheus.NewDesc(
+		LocksWaitTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "locks_wait_time_seconds"),
 			"(Locks.AverageWaitTimems Total time in seconds which locks have been holding resources)",
 			[]string{"mssql_instance", "resource"},
 			nil,
 		),
-		LocksCount: prometheus.NewDesc(
+		LocksCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "locks_count"),
 			"(Locks.AverageWaitTimems_Base count of how often requests have run into locks)",
 			[]string{"mssql_instance", "resource"},
 			nil,
 		),
-		LocksLockRequests: prometheus.NewDesc(
+		LocksLockRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "locks_lock_requests"),
 			"(Locks.LockRequests)",
 			[]string{"mssql_instance", "resource"},
 			nil,
 		),
-		LocksLockTimeouts: prometheus.NewDesc(
+		LocksLockTimeouts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "locks_lock_timeouts"),
 			"(Locks.LockTimeouts)",
 			[]string{"mssql_instance", "resource"},
 			nil,
 		),
-		LocksLockTimeoutstimeout0: prometheus.NewDesc(
+		LocksLockTimeoutstimeout0: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "locks_lock_timeouts_excluding_NOWAIT"),
 			"(Locks.LockTimeoutstimeout0)",
 			[]string{"mssql_instance", "resource"},
 			nil,
 		),
-		LocksLockWaits: prometheus.NewDesc(
+		LocksLockWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "locks_lock_waits"),
 			"(Locks.LockWaits)",
 			[]string{"mssql_instance", "resource"},
 			nil,
 		),
-		LocksLockWaitTimems: prometheus.NewDesc(
+		LocksLockWaitTimems: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "locks_lock_wait_seconds"),
 			"(Locks.LockWaitTimems)",
 			[]string{"mssql_instance", "resource"},
 			nil,
 		),
-		LocksNumberofDeadlocks: prometheus.NewDesc(
+		LocksNumberofDeadlocks: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "locks_deadlocks"),
 			"(Locks.NumberofDeadlocks)",
 			[]string{"mssql_instance", "resource"},
@@ -1530,121 +1557,121 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerMemoryManager
-		MemMgrConnectionMemoryKB: prometheus.NewDesc(
+		MemMgrConnectionMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_connection_memory_bytes"),
 			"(MemoryManager.ConnectionMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrDatabaseCacheMemoryKB: prometheus.NewDesc(
+		MemMgrDatabaseCacheMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_database_cache_memory_bytes"),
 			"(MemoryManager.DatabaseCacheMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrExternalbenefitofmemory: prometheus.NewDesc(
+		MemMgrExternalbenefitofmemory: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_external_benefit_of_memory"),
 			"(MemoryManager.Externalbenefitofmemory)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrFreeMemoryKB: prometheus.NewDesc(
+		MemMgrFreeMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_free_memory_bytes"),
 			"(MemoryManager.FreeMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrGrantedWorkspaceMemoryKB: prometheus.NewDesc(
+		MemMgrGrantedWorkspaceMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_granted_workspace_memory_bytes"),
 			"(MemoryManager.GrantedWorkspaceMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrLockBlocks: prometheus.NewDesc(
+		MemMgrLockBlocks: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_lock_blocks"),
 			"(MemoryManager.LockBlocks)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrLockBlocksAllocated: prometheus.NewDesc(
+		MemMgrLockBlocksAllocated: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_allocated_lock_blocks"),
 			"(MemoryManager.LockBlocksAllocated)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrLockMemoryKB: prometheus.NewDesc(
+		MemMgrLockMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_lock_memory_bytes"),
 			"(MemoryManager.LockMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrLockOwnerBlocks: prometheus.NewDesc(
+		MemMgrLockOwnerBlocks: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_lock_owner_blocks"),
 			"(MemoryManager.LockOwnerBlocks)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrLockOwnerBlocksAllocated: prometheus.NewDesc(
+		MemMgrLockOwnerBlocksAllocated: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_allocated_lock_owner_blocks"),
 			"(MemoryManager.LockOwnerBlocksAllocated)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrLogPoolMemoryKB: prometheus.NewDesc(
+		MemMgrLogPoolMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_log_pool_memory_bytes"),
 			"(MemoryManager.LogPoolMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrMaximumWorkspaceMemoryKB: prometheus.NewDesc(
+		MemMgrMaximumWorkspaceMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_maximum_workspace_memory_bytes"),
 			"(MemoryManager.MaximumWorkspaceMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrMemoryGrantsOutstanding: prometheus.NewDesc(
+		MemMgrMemoryGrantsOutstanding: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_outstanding_memory_grants"),
 			"(MemoryManager.MemoryGrantsOutstanding)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrMemoryGrantsPending: prometheus.NewDesc(
+		MemMgrMemoryGrantsPending: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_pending_memory_grants"),
 			"(MemoryManager.MemoryGrantsPending)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrOptimizerMemoryKB: prometheus.NewDesc(
+		MemMgrOptimizerMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_optimizer_memory_bytes"),
 			"(MemoryManager.OptimizerMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrReservedServerMemoryKB: prometheus.NewDesc(
+		MemMgrReservedServerMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_reserved_server_memory_bytes"),
 			"(MemoryManager.ReservedServerMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrSQLCacheMemoryKB: prometheus.NewDesc(
+		MemMgrSQLCacheMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_sql_cache_memory_bytes"),
 			"(MemoryManager.SQLCacheMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrStolenServerMemoryKB: prometheus.NewDesc(
+		MemMgrStolenServerMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_stolen_server_memory_bytes"),
 			"(MemoryManager.StolenServerMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrTargetServerMemoryKB: prometheus.NewDesc(
+		MemMgrTargetServerMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_target_server_memory_bytes"),
 			"(MemoryManager.TargetServerMemoryKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		MemMgrTotalServerMemoryKB: prometheus.NewDesc(
+		MemMgrTotalServerMemoryKB: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "memmgr_total_server_memory_bytes"),
 			"(MemoryManager.TotalServerMemoryKB)",
 			[]string{"mssql_instance"},
@@ -1652,67 +1679,67 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerSQLStatistics
-		SQLStatsAutoParamAttempts: prometheus.NewDesc(
+		SQLStatsAutoParamAttempts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_auto_parameterization_attempts"),
 			"(SQLStatistics.AutoParamAttempts)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsBatchRequests: prometheus.NewDesc(
+		SQLStatsBatchRequests: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_batch_requests"),
 			"(SQLStatistics.BatchRequests)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsFailedAutoParams: prometheus.NewDesc(
+		SQLStatsFailedAutoParams: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_failed_auto_parameterization_attempts"),
 			"(SQLStatistics.FailedAutoParams)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsForcedParameterizations: prometheus.NewDesc(
+		SQLStatsForcedParameterizations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_forced_parameterizations"),
 			"(SQLStatistics.ForcedParameterizations)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsGuidedplanexecutions: prometheus.NewDesc(
+		SQLStatsGuidedplanexecutions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_guided_plan_executions"),
 			"(SQLStatistics.Guidedplanexecutions)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsMisguidedplanexecutions: prometheus.NewDesc(
+		SQLStatsMisguidedplanexecutions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_misguided_plan_executions"),
 			"(SQLStatistics.Misguidedplanexecutions)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsSafeAutoParams: prometheus.NewDesc(
+		SQLStatsSafeAutoParams: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_safe_auto_parameterization_attempts"),
 			"(SQLStatistics.SafeAutoParams)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsSQLAttentionrate: prometheus.NewDesc(
+		SQLStatsSQLAttentionrate: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_sql_attentions"),
 			"(SQLStatistics.SQLAttentions)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsSQLCompilations: prometheus.NewDesc(
+		SQLStatsSQLCompilations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_sql_compilations"),
 			"(SQLStatistics.SQLCompilations)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsSQLReCompilations: prometheus.NewDesc(
+		SQLStatsSQLReCompilations: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_sql_recompilations"),
 			"(SQLStatistics.SQLReCompilations)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		SQLStatsUnsafeAutoParams: prometheus.NewDesc(
+		SQLStatsUnsafeAutoParams: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sqlstats_unsafe_auto_parameterization_attempts"),
 			"(SQLStatistics.UnsafeAutoParams)",
 			[]string{"mssql_instance"},
@@ -1720,7 +1747,7 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerSQLErrors
-		SQLErrorsTotal: prometheus.NewDesc(
+		SQLErrorsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "sql_errors_total"),
 			"(SQLErrors.Total)",
 			[]string{"mssql_instance", "resource"},
@@ -1728,79 +1755,79 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerTransactions
-		TransactionsTempDbFreeSpaceBytes: prometheus.NewDesc(
+		TransactionsTempDbFreeSpaceBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_tempdb_free_space_bytes"),
 			"(Transactions.FreeSpaceInTempDbKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsLongestTransactionRunningSeconds: prometheus.NewDesc(
+		TransactionsLongestTransactionRunningSeconds: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_longest_transaction_running_seconds"),
 			"(Transactions.LongestTransactionRunningTime)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsNonSnapshotVersionActiveTotal: prometheus.NewDesc(
+		TransactionsNonSnapshotVersionActiveTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_nonsnapshot_version_active_total"),
 			"(Transactions.NonSnapshotVersionTransactions)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsSnapshotActiveTotal: prometheus.NewDesc(
+		TransactionsSnapshotActiveTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_snapshot_active_total"),
 			"(Transactions.SnapshotTransactions)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsActive: prometheus.NewDesc(
+		TransactionsActive: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_active"),
 			"(Transactions.Transactions)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsUpdateConflictsTotal: prometheus.NewDesc(
+		TransactionsUpdateConflictsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_update_conflicts_total"),
 			"(Transactions.UpdateConflictRatio)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsUpdateSnapshotActiveTotal: prometheus.NewDesc(
+		TransactionsUpdateSnapshotActiveTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_update_snapshot_active_total"),
 			"(Transactions.UpdateSnapshotTransactions)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsVersionCleanupRateBytes: prometheus.NewDesc(
+		TransactionsVersionCleanupRateBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_version_cleanup_rate_bytes"),
 			"(Transactions.VersionCleanupRateKBs)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsVersionGenerationRateBytes: prometheus.NewDesc(
+		TransactionsVersionGenerationRateBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_version_generation_rate_bytes"),
 			"(Transactions.VersionGenerationRateKBs)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsVersionStoreSizeBytes: prometheus.NewDesc(
+		TransactionsVersionStoreSizeBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_version_store_size_bytes"),
 			"(Transactions.VersionStoreSizeKB)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsVersionStoreUnits: prometheus.NewDesc(
+		TransactionsVersionStoreUnits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_version_store_units"),
 			"(Transactions.VersionStoreUnitCount)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsVersionStoreCreationUnits: prometheus.NewDesc(
+		TransactionsVersionStoreCreationUnits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_version_store_creation_units"),
 			"(Transactions.VersionStoreUnitCreation)",
 			[]string{"mssql_instance"},
 			nil,
 		),
-		TransactionsVersionStoreTruncationUnits: prometheus.NewDesc(
+		TransactionsVersionStoreTruncationUnits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "transactions_version_store_truncation_units"),
 			"(Transactions.VersionStoreUnitTruncation)",
 			[]string{"mssql_instance"},
@@ -1808,96 +1835,97 @@ func NewMSSQLCollector() (Collector, error) {
 		),
 
 		// Win32_PerfRawData_{instance}_SQLServerWaitStatistics
-		WaitStatsLockWaits: prometheus.NewDesc(
+		WaitStatsLockWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_lock_waits"),
 			"(WaitStats.LockWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsMemoryGrantQueueWaits: prometheus.NewDesc(
+		WaitStatsMemoryGrantQueueWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_memory_grant_queue_waits"),
 			"(WaitStats.MemoryGrantQueueWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsThreadSafeMemoryObjectsWaits: prometheus.NewDesc(
+		WaitStatsThreadSafeMemoryObjectsWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_thread_safe_memory_objects_waits"),
 			"(WaitStats.ThreadSafeMemoryObjectsWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsLogWriteWaits: prometheus.NewDesc(
+		WaitStatsLogWriteWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_log_write_waits"),
 			"(WaitStats.LogWriteWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsLogBufferWaits: prometheus.NewDesc(
+		WaitStatsLogBufferWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_log_buffer_waits"),
 			"(WaitStats.LogBufferWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsNetworkIOWaits: prometheus.NewDesc(
+		WaitStatsNetworkIOWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_network_io_waits"),
 			"(WaitStats.NetworkIOWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsPageIOLatchWaits: prometheus.NewDesc(
+		WaitStatsPageIOLatchWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_page_io_latch_waits"),
 			"(WaitStats.PageIOLatchWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsPageLatchWaits: prometheus.NewDesc(
+		WaitStatsPageLatchWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_page_latch_waits"),
 			"(WaitStats.PageLatchWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsNonpageLatchWaits: prometheus.NewDesc(
+		WaitStatsNonpageLatchWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_nonpage_latch_waits"),
 			"(WaitStats.NonpageLatchWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsWaitForTheWorkerWaits: prometheus.NewDesc(
+		WaitStatsWaitForTheWorkerWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_wait_for_the_worker_waits"),
 			"(WaitStats.WaitForTheWorkerWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsWorkspaceSynchronizationWaits: prometheus.NewDesc(
+		WaitStatsWorkspaceSynchronizationWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_workspace_synchronization_waits"),
 			"(WaitStats.WorkspaceSynchronizationWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		WaitStatsTransactionOwnershipWaits: prometheus.NewDesc(
+		WaitStatsTransactionOwnershipWaits: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "waitstats_transaction_ownership_waits"),
 			"(WaitStats.TransactionOwnershipWaits)",
 			[]string{"mssql_instance", "item"},
 			nil,
 		),
 
-		mssqlInstances: mssqlInstances,
+		mssqlInstances:         mssqlInstances,
+		mssqlEnabledCollectors: enabled,
 	}
 
 	mssqlCollector.mssqlCollectors = mssqlCollector.getMSSQLCollectors()
 
-	if *mssqlPrintCollectors {
+	if settings.Collectors.MSSQL.ClassPrint {
 		fmt.Printf("Available SQLServer Classes:\n")
 		for name := range mssqlCollector.mssqlCollectors {
 			fmt.Printf(" - %s\n", name)
@@ -1942,14 +1970,256 @@ func (c *MSSQLCollector) execute(ctx *ScrapeContext, name string, fn mssqlCollec
 	)
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *MSSQLCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.mssqlScrapeDurationDesc
+	ch <- c.mssqlScrapeSuccessDesc
+	ch <- c.AccessMethodsAUcleanupbatches
+	ch <- c.AccessMethodsAUcleanups
+	ch <- c.AccessMethodsByreferenceLobCreateCount
+	ch <- c.AccessMethodsByreferenceLobUseCount
+	ch <- c.AccessMethodsCountLobReadahead
+	ch <- c.AccessMethodsCountPullInRow
+	ch <- c.AccessMethodsCountPushOffRow
+	ch <- c.AccessMethodsDeferreddroppedAUs
+	ch <- c.AccessMethodsDeferredDroppedrowsets
+	ch <- c.AccessMethodsDroppedrowsetcleanups
+	ch <- c.AccessMethodsDroppedrowsetsskipped
+	ch <- c.AccessMethodsExtentDeallocations
+	ch <- c.AccessMethodsExtentsAllocated
+	ch <- c.AccessMethodsFailedAUcleanupbatches
+	ch <- c.AccessMethodsFailedleafpagecookie
+	ch <- c.AccessMethodsFailedtreepagecookie
+	ch <- c.AccessMethodsForwardedRecords
+	ch <- c.AccessMethodsFreeSpacePageFetches
+	ch <- c.AccessMethodsFreeSpaceScans
+	ch <- c.AccessMethodsFullScans
+	ch <- c.AccessMethodsIndexSearches
+	ch <- c.AccessMethodsInSysXactwaits
+	ch <- c.AccessMethodsLobHandleCreateCount
+	ch <- c.AccessMethodsLobHandleDestroyCount
+	ch <- c.AccessMethodsLobSSProviderCreateCount
+	ch <- c.AccessMethodsLobSSProviderDestroyCount
+	ch <- c.AccessMethodsLobSSProviderTruncationCount
+	ch <- c.AccessMethodsMixedpageallocations
+	ch <- c.AccessMethodsPagecompressionattempts
+	ch <- c.AccessMethodsPageDeallocations
+	ch <- c.AccessMethodsPagesAllocated
+	ch <- c.AccessMethodsPagescompressed
+	ch <- c.AccessMethodsPageSplits
+	ch <- c.AccessMethodsProbeScans
+	ch <- c.AccessMethodsRangeScans
+	ch <- c.AccessMethodsScanPointRevalidations
+	ch <- c.AccessMethodsSkippedGhostedRecords
+	ch <- c.AccessMethodsTableLockEscalations
+	ch <- c.AccessMethodsUsedleafpagecookie
+	ch <- c.AccessMethodsUsedtreepagecookie
+	ch <- c.AccessMethodsWorkfilesCreated
+	ch <- c.AccessMethodsWorktablesCreated
+	ch <- c.AccessMethodsWorktablesFromCacheHits
+	ch <- c.AccessMethodsWorktablesFromCacheLookups
+	ch <- c.AvailReplicaBytesReceivedfromReplica
+	ch <- c.AvailReplicaBytesSenttoReplica
+	ch <- c.AvailReplicaBytesSenttoTransport
+	ch <- c.AvailReplicaFlowControl
+	ch <- c.AvailReplicaFlowControlTimems
+	ch <- c.AvailReplicaReceivesfromReplica
+	ch <- c.AvailReplicaResentMessages
+	ch <- c.AvailReplicaSendstoReplica
+	ch <- c.AvailReplicaSendstoTransport
+	ch <- c.BufManBackgroundwriterpages
+	ch <- c.BufManBuffercachehits
+	ch <- c.BufManBuffercachelookups
+	ch <- c.BufManCheckpointpages
+	ch <- c.BufManDatabasepages
+	ch <- c.BufManExtensionallocatedpages
+	ch <- c.BufManExtensionfreepages
+	ch <- c.BufManExtensioninuseaspercentage
+	ch <- c.BufManExtensionoutstandingIOcounter
+	ch <- c.BufManExtensionpageevictions
+	ch <- c.BufManExtensionpagereads
+	ch <- c.BufManExtensionpageunreferencedtime
+	ch <- c.BufManExtensionpagewrites
+	ch <- c.BufManFreeliststalls
+	ch <- c.BufManIntegralControllerSlope
+	ch <- c.BufManLazywrites
+	ch <- c.BufManPagelifeexpectancy
+	ch <- c.BufManPagelookups
+	ch <- c.BufManPagereads
+	ch <- c.BufManPagewrites
+	ch <- c.BufManReadaheadpages
+	ch <- c.BufManReadaheadtime
+	ch <- c.BufManTargetpages
+	ch <- c.DBReplicaDatabaseFlowControlDelay
+	ch <- c.DBReplicaDatabaseFlowControls
+	ch <- c.DBReplicaFileBytesReceived
+	ch <- c.DBReplicaGroupCommits
+	ch <- c.DBReplicaGroupCommitTime
+	ch <- c.DBReplicaLogApplyPendingQueue
+	ch <- c.DBReplicaLogApplyReadyQueue
+	ch <- c.DBReplicaLogBytesCompressed
+	ch <- c.DBReplicaLogBytesDecompressed
+	ch <- c.DBReplicaLogBytesReceived
+	ch <- c.DBReplicaLogCompressionCachehits
+	ch <- c.DBReplicaLogCompressionCachemisses
+	ch <- c.DBReplicaLogCompressions
+	ch <- c.DBReplicaLogDecompressions
+	ch <- c.DBReplicaLogremainingforundo
+	ch <- c.DBReplicaLogSendQueue
+	ch <- c.DBReplicaMirroredWriteTransactions
+	ch <- c.DBReplicaRecoveryQueue
+	ch <- c.DBReplicaRedoblocked
+	ch <- c.DBReplicaRedoBytesRemaining
+	ch <- c.DBReplicaRedoneBytes
+	ch <- c.DBReplicaRedones
+	ch <- c.DBReplicaTotalLogrequiringundo
+	ch <- c.DBReplicaTransactionDelay
+	ch <- c.DatabasesActiveParallelredothreads
+	ch <- c.DatabasesActiveTransactions
+	ch <- c.DatabasesBackupPerRestoreThroughput
+	ch <- c.DatabasesBulkCopyRows
+	ch <- c.DatabasesBulkCopyThroughput
+	ch <- c.DatabasesCommittableentries
+	ch <- c.DatabasesDataFilesSizeKB
+	ch <- c.DatabasesDBCCLogicalScanBytes
+	ch <- c.DatabasesGroupCommitTime
+	ch <- c.DatabasesLogBytesFlushed
+	ch <- c.DatabasesLogCacheHits
+	ch <- c.DatabasesLogCacheLookups
+	ch <- c.DatabasesLogCacheReads
+	ch <- c.DatabasesLogFilesSizeKB
+	ch <- c.DatabasesLogFilesUsedSizeKB
+	ch <- c.DatabasesLogFlushes
+	ch <- c.DatabasesLogFlushWaits
+	ch <- c.DatabasesLogFlushWaitTime
+	ch <- c.DatabasesLogFlushWriteTimems
+	ch <- c.DatabasesLogGrowths
+	ch <- c.DatabasesLogPoolCacheMisses
+	ch <- c.DatabasesLogPoolDiskReads
+	ch <- c.DatabasesLogPoolHashDeletes
+	ch <- c.DatabasesLogPoolHashInserts
+	ch <- c.DatabasesLogPoolInvalidHashEntry
+	ch <- c.DatabasesLogPoolLogScanPushes
+	ch <- c.DatabasesLogPoolLogWriterPushes
+	ch <- c.DatabasesLogPoolPushEmptyFreePool
+	ch <- c.DatabasesLogPoolPushLowMemory
+	ch <- c.DatabasesLogPoolPushNoFreeBuffer
+	ch <- c.DatabasesLogPoolReqBehindTrunc
+	ch <- c.DatabasesLogPoolRequestsOldVLF
+	ch <- c.DatabasesLogPoolRequests
+	ch <- c.DatabasesLogPoolTotalActiveLogSize
+	ch <- c.DatabasesLogPoolTotalSharedPoolSize
+	ch <- c.DatabasesLogShrinks
+	ch <- c.DatabasesLogTruncations
+	ch <- c.DatabasesPercentLogUsed
+	ch <- c.DatabasesReplPendingXacts
+	ch <- c.DatabasesReplTransRate
+	ch <- c.DatabasesShrinkDataMovementBytes
+	ch <- c.DatabasesTrackedtransactions
+	ch <- c.DatabasesTransactions
+	ch <- c.DatabasesWriteTransactions
+	ch <- c.DatabasesXTPControllerDLCLatencyPerFetch
+	ch <- c.DatabasesXTPControllerDLCPeakLatency
+	ch <- c.DatabasesXTPControllerLogProcessed
+	ch <- c.DatabasesXTPMemoryUsedKB
+	ch <- c.GenStatsActiveTempTables
+	ch <- c.GenStatsConnectionReset
+	ch <- c.GenStatsEventNotificationsDelayedDrop
+	ch <- c.GenStatsHTTPAuthenticatedRequests
+	ch <- c.GenStatsLogicalConnections
+	ch <- c.GenStatsLogins
+	ch <- c.GenStatsLogouts
+	ch <- c.GenStatsMarsDeadlocks
+	ch <- c.GenStatsNonatomicyieldrate
+	ch <- c.GenStatsProcessesblocked
+	ch <- c.GenStatsSOAPEmptyRequests
+	ch <- c.GenStatsSOAPMethodInvocations
+	ch <- c.GenStatsSOAPSessionInitiateRequests
+	ch <- c.GenStatsSOAPSessionTerminateRequests
+	ch <- c.GenStatsSOAPSQLRequests
+	ch <- c.GenStatsSOAPWSDLRequests
+	ch <- c.GenStatsSQLTraceIOProviderLockWaits
+	ch <- c.GenStatsTempdbrecoveryunitid
+	ch <- c.GenStatsTempdbrowsetid
+	ch <- c.GenStatsTempTablesCreationRate
+	ch <- c.GenStatsTempTablesForDestruction
+	ch <- c.GenStatsTraceEventNotificationQueue
+	ch <- c.GenStatsTransactions
+	ch <- c.GenStatsUserConnections
+	ch <- c.LocksWaitTime
+	ch <- c.LocksCount
+	ch <- c.LocksLockRequests
+	ch <- c.LocksLockTimeouts
+	ch <- c.LocksLockTimeoutstimeout0
+	ch <- c.LocksLockWaits
+	ch <- c.LocksLockWaitTimems
+	ch <- c.LocksNumberofDeadlocks
+	ch <- c.MemMgrConnectionMemoryKB
+	ch <- c.MemMgrDatabaseCacheMemoryKB
+	ch <- c.MemMgrExternalbenefitofmemory
+	ch <- c.MemMgrFreeMemoryKB
+	ch <- c.MemMgrGrantedWorkspaceMemoryKB
+	ch <- c.MemMgrLockBlocks
+	ch <- c.MemMgrLockBlocksAllocated
+	ch <- c.MemMgrLockMemoryKB
+	ch <- c.MemMgrLockOwnerBlocks
+	ch <- c.MemMgrLockOwnerBlocksAllocated
+	ch <- c.MemMgrLogPoolMemoryKB
+	ch <- c.MemMgrMaximumWorkspaceMemoryKB
+	ch <- c.MemMgrMemoryGrantsOutstanding
+	ch <- c.MemMgrMemoryGrantsPending
+	ch <- c.MemMgrOptimizerMemoryKB
+	ch <- c.MemMgrReservedServerMemoryKB
+	ch <- c.MemMgrSQLCacheMemoryKB
+	ch <- c.MemMgrStolenServerMemoryKB
+	ch <- c.MemMgrTargetServerMemoryKB
+	ch <- c.MemMgrTotalServerMemoryKB
+	ch <- c.SQLStatsAutoParamAttempts
+	ch <- c.SQLStatsBatchRequests
+	ch <- c.SQLStatsFailedAutoParams
+	ch <- c.SQLStatsForcedParameterizations
+	ch <- c.SQLStatsGuidedplanexecutions
+	ch <- c.SQLStatsMisguidedplanexecutions
+	ch <- c.SQLStatsSafeAutoParams
+	ch <- c.SQLStatsSQLAttentionrate
+	ch <- c.SQLStatsSQLCompilations
+	ch <- c.SQLStatsSQLReCompilations
+	ch <- c.SQLStatsUnsafeAutoParams
+	ch <- c.SQLErrorsTotal
+	ch <- c.TransactionsTempDbFreeSpaceBytes
+	ch <- c.TransactionsLongestTransactionRunningSeconds
+	ch <- c.TransactionsNonSnapshotVersionActiveTotal
+	ch <- c.TransactionsSnapshotActiveTotal
+	ch <- c.TransactionsActive
+	ch <- c.TransactionsUpdateConflictsTotal
+	ch <- c.TransactionsUpdateSnapshotActiveTotal
+	ch <- c.TransactionsVersionCleanupRateBytes
+	ch <- c.TransactionsVersionGenerationRateBytes
+	ch <- c.TransactionsVersionStoreSizeBytes
+	ch <- c.TransactionsVersionStoreUnits
+	ch <- c.TransactionsVersionStoreCreationUnits
+	ch <- c.TransactionsVersionStoreTruncationUnits
+	ch <- c.WaitStatsLockWaits
+	ch <- c.WaitStatsMemoryGrantQueueWaits
+	ch <- c.WaitStatsThreadSafeMemoryObjectsWaits
+	ch <- c.WaitStatsLogWriteWaits
+	ch <- c.WaitStatsLogBufferWaits
+	ch <- c.WaitStatsNetworkIOWaits
+	ch <- c.WaitStatsPageIOLatchWaits
+	ch <- c.WaitStatsPageLatchWaits
+	ch <- c.WaitStatsNonpageLatchWaits
+	ch <- c.WaitStatsWaitForTheWorkerWaits
+	ch <- c.WaitStatsWorkspaceSynchronizationWaits
+	ch <- c.WaitStatsTransactionOwnershipWaits
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *MSSQLCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	wg := sync.WaitGroup{}
 
-	enabled := expandEnabledChildCollectors(*mssqlEnabledCollectors)
 	for sqlInstance := range c.mssqlInstances {
-		for _, name := range enabled {
+		for _, name := range c.mssqlEnabledCollectors {
 			function := c.mssqlCollectors[name]
 
 			wg.Add(1)
@@ -4,27 +4,37 @@
 package collector
 
 import (
-	"fmt"
 	"regexp"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+const (
+	nicWhitelistFlag = "collector.net.nic-whitelist"
+	nicBlacklistFlag = "collector.net.nic-blacklist"
+)
+
 func init() {
-	registerCollector("net", NewNetworkCollector, "Network Interface")
-}
+	registerCollectorWithSettings("net", NewNetworkCollector, "Network Interface")
+	registerCollectorInfo("net", CollectorInfo{
+		Description: "Network interface I/O",
+		Sources:     []DataSource{SourcePerflib},
+	})
 
-var (
-	nicWhitelist = kingpin.Flag(
-		"collector.net.nic-whitelist",
+	kingpin.Flag(
+		nicWhitelistFlag,
 		"Regexp of NIC:s to whitelist. NIC name must both match whitelist and not match blacklist to be included.",
 	).Default(".+").String()
-	nicBlacklist = kingpin.Flag(
-		"collector.net.nic-blacklist",
+	kingpin.Flag(
+		nicBlacklistFlag,
 		"Regexp of NIC:s to blacklist. NIC name must both match whitelist and not match blacklist to be included.",
 	).Default("").String()
+}
+
+var (
 	nicNameToUnderscore = regexp.MustCompile("[^a-zA-Z0-9]")
 )
 
@@ -48,88 +58,104 @@ type NetworkCollector struct {
 }
 
 // NewNetworkCollector ...
-func NewNetworkCollector() (Collector, error) {
+func NewNetworkCollector(settings *config.Config) (Collector, error) {
 	const subsystem = "net"
 
 	return &NetworkCollector{
-		BytesReceivedTotal: prometheus.NewDesc(
+		BytesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bytes_received_total"),
 			"(Network.BytesReceivedPerSec)",
 			[]string{"nic"},
 			nil,
 		),
-		BytesSentTotal: prometheus.NewDesc(
+		BytesSentTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bytes_sent_total"),
 			"(Network.BytesSentPerSec)",
 			[]string{"nic"},
 			nil,
 		),
-		BytesTotal: prometheus.NewDesc(
+		BytesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bytes_total"),
 			"(Network.BytesTotalPerSec)",
 			[]string{"nic"},
 			nil,
 		),
-		PacketsOutboundDiscarded: prometheus.NewDesc(
+		PacketsOutboundDiscarded: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_outbound_discarded_total"),
 			"(Network.PacketsOutboundDiscarded)",
 			[]string{"nic"},
 			nil,
 		),
-		PacketsOutboundErrors: prometheus.NewDesc(
+		PacketsOutboundErrors: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_outbound_errors_total"),
 			"(Network.PacketsOutboundErrors)",
 			[]string{"nic"},
 			nil,
 		),
-		PacketsReceivedDiscarded: prometheus.NewDesc(
+		PacketsReceivedDiscarded: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_received_discarded_total"),
 			"(Network.PacketsReceivedDiscarded)",
 			[]string{"nic"},
 			nil,
 		),
-		PacketsReceivedErrors: prometheus.NewDesc(
+		PacketsReceivedErrors: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_received_errors_total"),
 			"(Network.PacketsReceivedErrors)",
 			[]string{"nic"},
 			nil,
 		),
-		PacketsReceivedTotal: prometheus.NewDesc(
+		PacketsReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_received_total"),
 			"(Network.PacketsReceivedPerSec)",
 			[]string{"nic"},
 			nil,
 		),
-		PacketsReceivedUnknown: prometheus.NewDesc(
+		PacketsReceivedUnknown: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_received_unknown_total"),
 			"(Network.PacketsReceivedUnknown)",
 			[]string{"nic"},
 			nil,
 		),
-		PacketsTotal: prometheus.NewDesc(
+		PacketsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_total"),
 			"(Network.PacketsPerSec)",
 			[]string{"nic"},
 			nil,
 		),
-		PacketsSentTotal: prometheus.NewDesc(
+		PacketsSentTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_sent_total"),
 			"(Network.PacketsSentPerSec)",
 			[]string{"nic"},
 			nil,
 		),
-		CurrentBandwidth: prometheus.NewDesc(
+		CurrentBandwidth: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_bandwidth_bytes"),
 			"(Network.CurrentBandwidth)",
 			[]string{"nic"},
 			nil,
 		),
 
-		nicWhitelistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *nicWhitelist)),
-		nicBlacklistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *nicBlacklist)),
+		nicWhitelistPattern: settings.Collector.Net.NICWhitelist.Regexp(),
+		nicBlacklistPattern: settings.Collector.Net.NICBlacklist.Regexp(),
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *NetworkCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.BytesReceivedTotal
+	ch <- c.BytesSentTotal
+	ch <- c.BytesTotal
+	ch <- c.PacketsOutboundDiscarded
+	ch <- c.PacketsOutboundErrors
+	ch <- c.PacketsTotal
+	ch <- c.PacketsReceivedDiscarded
+	ch <- c.PacketsReceivedErrors
+	ch <- c.PacketsReceivedTotal
+	ch <- c.PacketsReceivedUnknown
+	ch <- c.PacketsSentTotal
+	ch <- c.CurrentBandwidth
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *NetworkCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
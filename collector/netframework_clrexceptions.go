@@ -6,11 +6,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("netframework_clrexceptions", NewNETFramework_NETCLRExceptionsCollector)
+	registerCollectorInfo("netframework_clrexceptions", CollectorInfo{
+		Description: ".NET Framework CLR Exceptions",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    ".NET Framework",
+		Expensive:   true,
+	})
 }
 
 // A NETFramework_NETCLRExceptionsCollector is a Prometheus collector for WMI Win32_PerfRawData_NETFramework_NETCLRExceptions metrics
@@ -25,25 +30,25 @@ type NETFramework_NETCLRExceptionsCollector struct {
 func NewNETFramework_NETCLRExceptionsCollector() (Collector, error) {
 	const subsystem = "netframework_clrexceptions"
 	return &NETFramework_NETCLRExceptionsCollector{
-		NumberofExcepsThrown: prometheus.NewDesc(
+		NumberofExcepsThrown: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "exceptions_thrown_total"),
 			"Displays the total number of exceptions thrown since the application started. This includes both .NET exceptions and unmanaged exceptions that are converted into .NET exceptions.",
 			[]string{"process"},
 			nil,
 		),
-		NumberofFilters: prometheus.NewDesc(
+		NumberofFilters: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "exceptions_filters_total"),
 			"Displays the total number of .NET exception filters executed. An exception filter evaluates regardless of whether an exception is handled.",
 			[]string{"process"},
 			nil,
 		),
-		NumberofFinallys: prometheus.NewDesc(
+		NumberofFinallys: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "exceptions_finallys_total"),
 			"Displays the total number of finally blocks executed. Only the finally blocks executed for an exception are counted; finally blocks on normal code paths are not counted by this counter.",
 			[]string{"process"},
 			nil,
 		),
-		ThrowToCatchDepth: prometheus.NewDesc(
+		ThrowToCatchDepth: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "throw_to_catch_depth_total"),
 			"Displays the total number of stack frames traversed, from the frame that threw the exception to the frame that handled the exception.",
 			[]string{"process"},
@@ -52,10 +57,18 @@ func NewNETFramework_NETCLRExceptionsCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *NETFramework_NETCLRExceptionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.NumberofExcepsThrown
+	ch <- c.NumberofFilters
+	ch <- c.NumberofFinallys
+	ch <- c.ThrowToCatchDepth
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *NETFramework_NETCLRExceptionsCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting win32_perfrawdata_netframework_netclrexceptions metrics:", desc, err)
 		return err
 	}
@@ -72,10 +85,10 @@ type Win32_PerfRawData_NETFramework_NETCLRExceptions struct {
 	ThrowToCatchDepthPersec    uint32
 }
 
-func (c *NETFramework_NETCLRExceptionsCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *NETFramework_NETCLRExceptionsCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NETFramework_NETCLRExceptions
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
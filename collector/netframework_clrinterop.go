@@ -6,11 +6,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("netframework_clrinterop", NewNETFramework_NETCLRInteropCollector)
+	registerCollectorInfo("netframework_clrinterop", CollectorInfo{
+		Description: ".NET Framework Interop Metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    ".NET Framework",
+		Expensive:   true,
+	})
 }
 
 // A NETFramework_NETCLRInteropCollector is a Prometheus collector for WMI Win32_PerfRawData_NETFramework_NETCLRInterop metrics
@@ -24,19 +29,19 @@ type NETFramework_NETCLRInteropCollector struct {
 func NewNETFramework_NETCLRInteropCollector() (Collector, error) {
 	const subsystem = "netframework_clrinterop"
 	return &NETFramework_NETCLRInteropCollector{
-		NumberofCCWs: prometheus.NewDesc(
+		NumberofCCWs: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "com_callable_wrappers_total"),
 			"Displays the current number of COM callable wrappers (CCWs). A CCW is a proxy for a managed object being referenced from an unmanaged COM client.",
 			[]string{"process"},
 			nil,
 		),
-		Numberofmarshalling: prometheus.NewDesc(
+		Numberofmarshalling: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "interop_marshalling_total"),
 			"Displays the total number of times arguments and return values have been marshaled from managed to unmanaged code, and vice versa, since the application started.",
 			[]string{"process"},
 			nil,
 		),
-		NumberofStubs: prometheus.NewDesc(
+		NumberofStubs: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "interop_stubs_created_total"),
 			"Displays the current number of stubs created by the common language runtime. Stubs are responsible for marshaling arguments and return values from managed to unmanaged code, and vice versa, during a COM interop call or a platform invoke call.",
 			[]string{"process"},
@@ -45,10 +50,17 @@ func NewNETFramework_NETCLRInteropCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *NETFramework_NETCLRInteropCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.NumberofCCWs
+	ch <- c.Numberofmarshalling
+	ch <- c.NumberofStubs
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *NETFramework_NETCLRInteropCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting win32_perfrawdata_netframework_netclrinterop metrics:", desc, err)
 		return err
 	}
@@ -65,10 +77,10 @@ type Win32_PerfRawData_NETFramework_NETCLRInterop struct {
 	NumberofTLBimportsPersec uint32
 }
 
-func (c *NETFramework_NETCLRInteropCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *NETFramework_NETCLRInteropCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NETFramework_NETCLRInterop
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -6,11 +6,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("netframework_clrjit", NewNETFramework_NETCLRJitCollector)
+	registerCollectorInfo("netframework_clrjit", CollectorInfo{
+		Description: ".NET Framework JIT metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    ".NET Framework",
+		Expensive:   true,
+	})
 }
 
 // A NETFramework_NETCLRJitCollector is a Prometheus collector for WMI Win32_PerfRawData_NETFramework_NETCLRJit metrics
@@ -25,25 +30,25 @@ type NETFramework_NETCLRJitCollector struct {
 func NewNETFramework_NETCLRJitCollector() (Collector, error) {
 	const subsystem = "netframework_clrjit"
 	return &NETFramework_NETCLRJitCollector{
-		NumberofMethodsJitted: prometheus.NewDesc(
+		NumberofMethodsJitted: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "jit_methods_total"),
 			"Displays the total number of methods JIT-compiled since the application started. This counter does not include pre-JIT-compiled methods.",
 			[]string{"process"},
 			nil,
 		),
-		TimeinJit: prometheus.NewDesc(
+		TimeinJit: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "jit_time_percent"),
 			"Displays the percentage of time spent in JIT compilation. This counter is updated at the end of every JIT compilation phase. A JIT compilation phase occurs when a method and its dependencies are compiled.",
 			[]string{"process"},
 			nil,
 		),
-		StandardJitFailures: prometheus.NewDesc(
+		StandardJitFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "jit_standard_failures_total"),
 			"Displays the peak number of methods the JIT compiler has failed to compile since the application started. This failure can occur if the MSIL cannot be verified or if there is an internal error in the JIT compiler.",
 			[]string{"process"},
 			nil,
 		),
-		TotalNumberofILBytesJitted: prometheus.NewDesc(
+		TotalNumberofILBytesJitted: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "jit_il_bytes_total"),
 			"Displays the total number of Microsoft intermediate language (MSIL) bytes compiled by the just-in-time (JIT) compiler since the application started",
 			[]string{"process"},
@@ -52,10 +57,18 @@ func NewNETFramework_NETCLRJitCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *NETFramework_NETCLRJitCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.NumberofMethodsJitted
+	ch <- c.TimeinJit
+	ch <- c.StandardJitFailures
+	ch <- c.TotalNumberofILBytesJitted
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *NETFramework_NETCLRJitCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting win32_perfrawdata_netframework_netclrjit metrics:", desc, err)
 		return err
 	}
@@ -74,10 +87,10 @@ type Win32_PerfRawData_NETFramework_NETCLRJit struct {
 	TotalNumberofILBytesJitted uint32
 }
 
-func (c *NETFramework_NETCLRJitCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *NETFramework_NETCLRJitCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NETFramework_NETCLRJit
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
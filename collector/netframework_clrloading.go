@@ -6,11 +6,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("netframework_clrloading", NewNETFramework_NETCLRLoadingCollector)
+	registerCollectorInfo("netframework_clrloading", CollectorInfo{
+		Description: ".NET Framework CLR Loading metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    ".NET Framework",
+		Expensive:   true,
+	})
 }
 
 // A NETFramework_NETCLRLoadingCollector is a Prometheus collector for WMI Win32_PerfRawData_NETFramework_NETCLRLoading metrics
@@ -30,55 +35,55 @@ type NETFramework_NETCLRLoadingCollector struct {
 func NewNETFramework_NETCLRLoadingCollector() (Collector, error) {
 	const subsystem = "netframework_clrloading"
 	return &NETFramework_NETCLRLoadingCollector{
-		BytesinLoaderHeap: prometheus.NewDesc(
+		BytesinLoaderHeap: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "loader_heap_size_bytes"),
 			"Displays the current size, in bytes, of the memory committed by the class loader across all application domains. Committed memory is the physical space reserved in the disk paging file.",
 			[]string{"process"},
 			nil,
 		),
-		Currentappdomains: prometheus.NewDesc(
+		Currentappdomains: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "appdomains_loaded_current"),
 			"Displays the current number of application domains loaded in this application.",
 			[]string{"process"},
 			nil,
 		),
-		CurrentAssemblies: prometheus.NewDesc(
+		CurrentAssemblies: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "assemblies_loaded_current"),
 			"Displays the current number of assemblies loaded across all application domains in the currently running application. If the assembly is loaded as domain-neutral from multiple application domains, this counter is incremented only once.",
 			[]string{"process"},
 			nil,
 		),
-		CurrentClassesLoaded: prometheus.NewDesc(
+		CurrentClassesLoaded: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "classes_loaded_current"),
 			"Displays the current number of classes loaded in all assemblies.",
 			[]string{"process"},
 			nil,
 		),
-		TotalAppdomains: prometheus.NewDesc(
+		TotalAppdomains: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "appdomains_loaded_total"),
 			"Displays the peak number of application domains loaded since the application started.",
 			[]string{"process"},
 			nil,
 		),
-		Totalappdomainsunloaded: prometheus.NewDesc(
+		Totalappdomainsunloaded: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "appdomains_unloaded_total"),
 			"Displays the total number of application domains unloaded since the application started. If an application domain is loaded and unloaded multiple times, this counter increments each time the application domain is unloaded.",
 			[]string{"process"},
 			nil,
 		),
-		TotalAssemblies: prometheus.NewDesc(
+		TotalAssemblies: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "assemblies_loaded_total"),
 			"Displays the total number of assemblies loaded since the application started. If the assembly is loaded as domain-neutral from multiple application domains, this counter is incremented only once.",
 			[]string{"process"},
 			nil,
 		),
-		TotalClassesLoaded: prometheus.NewDesc(
+		TotalClassesLoaded: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "classes_loaded_total"),
 			"Displays the cumulative number of classes loaded in all assemblies since the application started.",
 			[]string{"process"},
 			nil,
 		),
-		TotalNumberofLoadFailures: prometheus.NewDesc(
+		TotalNumberofLoadFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "class_load_failures_total"),
 			"Displays the peak number of classes that have failed to load since the application started.",
 			[]string{"process"},
@@ -87,10 +92,23 @@ func NewNETFramework_NETCLRLoadingCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *NETFramework_NETCLRLoadingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.BytesinLoaderHeap
+	ch <- c.Currentappdomains
+	ch <- c.CurrentAssemblies
+	ch <- c.CurrentClassesLoaded
+	ch <- c.TotalAppdomains
+	ch <- c.Totalappdomainsunloaded
+	ch <- c.TotalAssemblies
+	ch <- c.TotalClassesLoaded
+	ch <- c.TotalNumberofLoadFailures
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *NETFramework_NETCLRLoadingCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting win32_perfrawdata_netframework_netclrloading metrics:", desc, err)
 		return err
 	}
@@ -118,10 +136,10 @@ type Win32_PerfRawData_NETFramework_NETCLRLoading struct {
 	TotalNumberofLoadFailures uint32
 }
 
-func (c *NETFramework_NETCLRLoadingCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *NETFramework_NETCLRLoadingCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NETFramework_NETCLRLoading
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -6,11 +6,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("netframework_clrlocksandthreads", NewNETFramework_NETCLRLocksAndThreadsCollector)
+	registerCollectorInfo("netframework_clrlocksandthreads", CollectorInfo{
+		Description: ".NET Framework locks and metrics threads",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    ".NET Framework",
+		Expensive:   true,
+	})
 }
 
 // A NETFramework_NETCLRLocksAndThreadsCollector is a Prometheus collector for WMI Win32_PerfRawData_NETFramework_NETCLRLocksAndThreads metrics
@@ -28,43 +33,43 @@ type NETFramework_NETCLRLocksAndThreadsCollector struct {
 func NewNETFramework_NETCLRLocksAndThreadsCollector() (Collector, error) {
 	const subsystem = "netframework_clrlocksandthreads"
 	return &NETFramework_NETCLRLocksAndThreadsCollector{
-		CurrentQueueLength: prometheus.NewDesc(
+		CurrentQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_queue_length"),
 			"Displays the total number of threads that are currently waiting to acquire a managed lock in the application.",
 			[]string{"process"},
 			nil,
 		),
-		NumberofcurrentlogicalThreads: prometheus.NewDesc(
+		NumberofcurrentlogicalThreads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_logical_threads"),
 			"Displays the number of current managed thread objects in the application. This counter maintains the count of both running and stopped threads. ",
 			[]string{"process"},
 			nil,
 		),
-		NumberofcurrentphysicalThreads: prometheus.NewDesc(
+		NumberofcurrentphysicalThreads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "physical_threads_current"),
 			"Displays the number of native operating system threads created and owned by the common language runtime to act as underlying threads for managed thread objects. This counter's value does not include the threads used by the runtime in its internal operations; it is a subset of the threads in the operating system process.",
 			[]string{"process"},
 			nil,
 		),
-		Numberofcurrentrecognizedthreads: prometheus.NewDesc(
+		Numberofcurrentrecognizedthreads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "recognized_threads_current"),
 			"Displays the number of threads that are currently recognized by the runtime. These threads are associated with a corresponding managed thread object. The runtime does not create these threads, but they have run inside the runtime at least once.",
 			[]string{"process"},
 			nil,
 		),
-		Numberoftotalrecognizedthreads: prometheus.NewDesc(
+		Numberoftotalrecognizedthreads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "recognized_threads_total"),
 			"Displays the total number of threads that have been recognized by the runtime since the application started. These threads are associated with a corresponding managed thread object. The runtime does not create these threads, but they have run inside the runtime at least once.",
 			[]string{"process"},
 			nil,
 		),
-		QueueLengthPeak: prometheus.NewDesc(
+		QueueLengthPeak: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "queue_length_total"),
 			"Displays the total number of threads that waited to acquire a managed lock since the application started.",
 			[]string{"process"},
 			nil,
 		),
-		TotalNumberofContentions: prometheus.NewDesc(
+		TotalNumberofContentions: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "contentions_total"),
 			"Displays the total number of times that threads in the runtime have attempted to acquire a managed lock unsuccessfully.",
 			[]string{"process"},
@@ -73,10 +78,21 @@ func NewNETFramework_NETCLRLocksAndThreadsCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *NETFramework_NETCLRLocksAndThreadsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.CurrentQueueLength
+	ch <- c.NumberofcurrentlogicalThreads
+	ch <- c.NumberofcurrentphysicalThreads
+	ch <- c.Numberofcurrentrecognizedthreads
+	ch <- c.Numberoftotalrecognizedthreads
+	ch <- c.QueueLengthPeak
+	ch <- c.TotalNumberofContentions
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *NETFramework_NETCLRLocksAndThreadsCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting win32_perfrawdata_netframework_netclrlocksandthreads metrics:", desc, err)
 		return err
 	}
@@ -98,10 +114,10 @@ type Win32_PerfRawData_NETFramework_NETCLRLocksAndThreads struct {
 	TotalNumberofContentions         uint32
 }
 
-func (c *NETFramework_NETCLRLocksAndThreadsCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *NETFramework_NETCLRLocksAndThreadsCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NETFramework_NETCLRLocksAndThreads
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -6,11 +6,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("netframework_clrmemory", NewNETFramework_NETCLRMemoryCollector)
+	registerCollectorInfo("netframework_clrmemory", CollectorInfo{
+		Description: ".NET Framework Memory metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    ".NET Framework",
+		Expensive:   true,
+	})
 }
 
 // A NETFramework_NETCLRMemoryCollector is a Prometheus collector for WMI Win32_PerfRawData_NETFramework_NETCLRMemory metrics
@@ -36,73 +41,73 @@ type NETFramework_NETCLRMemoryCollector struct {
 func NewNETFramework_NETCLRMemoryCollector() (Collector, error) {
 	const subsystem = "netframework_clrmemory"
 	return &NETFramework_NETCLRMemoryCollector{
-		AllocatedBytes: prometheus.NewDesc(
+		AllocatedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "allocated_bytes_total"),
 			"Displays the total number of bytes allocated on the garbage collection heap.",
 			[]string{"process"},
 			nil,
 		),
-		FinalizationSurvivors: prometheus.NewDesc(
+		FinalizationSurvivors: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "finalization_survivors"),
 			"Displays the number of garbage-collected objects that survive a collection because they are waiting to be finalized.",
 			[]string{"process"},
 			nil,
 		),
-		HeapSize: prometheus.NewDesc(
+		HeapSize: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "heap_size_bytes"),
 			"Displays the maximum bytes that can be allocated; it does not indicate the current number of bytes allocated.",
 			[]string{"process", "area"},
 			nil,
 		),
-		PromotedBytes: prometheus.NewDesc(
+		PromotedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "promoted_bytes"),
 			"Displays the bytes that were promoted from the generation to the next one during the last GC. Memory is promoted when it survives a garbage collection.",
 			[]string{"process", "area"},
 			nil,
 		),
-		NumberGCHandles: prometheus.NewDesc(
+		NumberGCHandles: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "number_gc_handles"),
 			"Displays the current number of garbage collection handles in use. Garbage collection handles are handles to resources external to the common language runtime and the managed environment.",
 			[]string{"process"},
 			nil,
 		),
-		NumberCollections: prometheus.NewDesc(
+		NumberCollections: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "collections_total"),
 			"Displays the number of times the generation objects are garbage collected since the application started.",
 			[]string{"process", "area"},
 			nil,
 		),
-		NumberInducedGC: prometheus.NewDesc(
+		NumberInducedGC: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "induced_gc_total"),
 			"Displays the peak number of times garbage collection was performed because of an explicit call to GC.Collect.",
 			[]string{"process"},
 			nil,
 		),
-		NumberofPinnedObjects: prometheus.NewDesc(
+		NumberofPinnedObjects: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "number_pinned_objects"),
 			"Displays the number of pinned objects encountered in the last garbage collection.",
 			[]string{"process"},
 			nil,
 		),
-		NumberofSinkBlocksinuse: prometheus.NewDesc(
+		NumberofSinkBlocksinuse: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "number_sink_blocksinuse"),
 			"Displays the current number of synchronization blocks in use. Synchronization blocks are per-object data structures allocated for storing synchronization information. They hold weak references to managed objects and must be scanned by the garbage collector.",
 			[]string{"process"},
 			nil,
 		),
-		NumberTotalCommittedBytes: prometheus.NewDesc(
+		NumberTotalCommittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "committed_bytes"),
 			"Displays the amount of virtual memory, in bytes, currently committed by the garbage collector. Committed memory is the physical memory for which space has been reserved in the disk paging file.",
 			[]string{"process"},
 			nil,
 		),
-		NumberTotalreservedBytes: prometheus.NewDesc(
+		NumberTotalreservedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "reserved_bytes"),
 			"Displays the amount of virtual memory, in bytes, currently reserved by the garbage collector. Reserved memory is the virtual memory space reserved for the application when no disk or main memory pages have been used.",
 			[]string{"process"},
 			nil,
 		),
-		TimeinGC: prometheus.NewDesc(
+		TimeinGC: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "gc_time_percent"),
 			"Displays the percentage of time that was spent performing a garbage collection in the last sample.",
 			[]string{"process"},
@@ -111,10 +116,29 @@ func NewNETFramework_NETCLRMemoryCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *NETFramework_NETCLRMemoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.AllocatedBytes
+	ch <- c.FinalizationSurvivors
+	ch <- c.HeapSize
+	ch <- c.PromotedBytes
+	ch <- c.NumberGCHandles
+	ch <- c.NumberCollections
+	ch <- c.NumberInducedGC
+	ch <- c.NumberofPinnedObjects
+	ch <- c.NumberofSinkBlocksinuse
+	ch <- c.NumberTotalCommittedBytes
+	ch <- c.NumberTotalreservedBytes
+	ch <- c.TimeinGC
+	ch <- c.PromotedFinalizationMemoryfromGen0
+	ch <- c.PromotedMemoryfromGen0
+	ch <- c.PromotedMemoryfromGen1
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *NETFramework_NETCLRMemoryCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting win32_perfrawdata_netframework_netclrmemory metrics:", desc, err)
 		return err
 	}
@@ -155,10 +179,10 @@ type Win32_PerfRawData_NETFramework_NETCLRMemory struct {
 	PromotedMemoryfromGen1             uint64
 }
 
-func (c *NETFramework_NETCLRMemoryCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *NETFramework_NETCLRMemoryCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NETFramework_NETCLRMemory
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -6,11 +6,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("netframework_clrremoting", NewNETFramework_NETCLRRemotingCollector)
+	registerCollectorInfo("netframework_clrremoting", CollectorInfo{
+		Description: ".NET Framework Remoting metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    ".NET Framework",
+		Expensive:   true,
+	})
 }
 
 // A NETFramework_NETCLRRemotingCollector is a Prometheus collector for WMI Win32_PerfRawData_NETFramework_NETCLRRemoting metrics
@@ -27,37 +32,37 @@ type NETFramework_NETCLRRemotingCollector struct {
 func NewNETFramework_NETCLRRemotingCollector() (Collector, error) {
 	const subsystem = "netframework_clrremoting"
 	return &NETFramework_NETCLRRemotingCollector{
-		Channels: prometheus.NewDesc(
+		Channels: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "channels_total"),
 			"Displays the total number of remoting channels registered across all application domains since application started.",
 			[]string{"process"},
 			nil,
 		),
-		ContextBoundClassesLoaded: prometheus.NewDesc(
+		ContextBoundClassesLoaded: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "context_bound_classes_loaded"),
 			"Displays the current number of context-bound classes that are loaded.",
 			[]string{"process"},
 			nil,
 		),
-		ContextBoundObjects: prometheus.NewDesc(
+		ContextBoundObjects: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "context_bound_objects_total"),
 			"Displays the total number of context-bound objects allocated.",
 			[]string{"process"},
 			nil,
 		),
-		ContextProxies: prometheus.NewDesc(
+		ContextProxies: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "context_proxies_total"),
 			"Displays the total number of remoting proxy objects in this process since it started.",
 			[]string{"process"},
 			nil,
 		),
-		Contexts: prometheus.NewDesc(
+		Contexts: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "contexts"),
 			"Displays the current number of remoting contexts in the application.",
 			[]string{"process"},
 			nil,
 		),
-		TotalRemoteCalls: prometheus.NewDesc(
+		TotalRemoteCalls: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "remote_calls_total"),
 			"Displays the total number of remote procedure calls invoked since the application started.",
 			[]string{"process"},
@@ -66,10 +71,20 @@ func NewNETFramework_NETCLRRemotingCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *NETFramework_NETCLRRemotingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Channels
+	ch <- c.ContextBoundClassesLoaded
+	ch <- c.ContextBoundObjects
+	ch <- c.ContextProxies
+	ch <- c.Contexts
+	ch <- c.TotalRemoteCalls
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *NETFramework_NETCLRRemotingCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting win32_perfrawdata_netframework_netclrremoting metrics:", desc, err)
 		return err
 	}
@@ -88,10 +103,10 @@ type Win32_PerfRawData_NETFramework_NETCLRRemoting struct {
 	TotalRemoteCalls               uint32
 }
 
-func (c *NETFramework_NETCLRRemotingCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *NETFramework_NETCLRRemotingCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NETFramework_NETCLRRemoting
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
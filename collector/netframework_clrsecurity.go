@@ -6,11 +6,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("netframework_clrsecurity", NewNETFramework_NETCLRSecurityCollector)
+	registerCollectorInfo("netframework_clrsecurity", CollectorInfo{
+		Description: ".NET Framework Security Check metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    ".NET Framework",
+		Expensive:   true,
+	})
 }
 
 // A NETFramework_NETCLRSecurityCollector is a Prometheus collector for WMI Win32_PerfRawData_NETFramework_NETCLRSecurity metrics
@@ -25,25 +30,25 @@ type NETFramework_NETCLRSecurityCollector struct {
 func NewNETFramework_NETCLRSecurityCollector() (Collector, error) {
 	const subsystem = "netframework_clrsecurity"
 	return &NETFramework_NETCLRSecurityCollector{
-		NumberLinkTimeChecks: prometheus.NewDesc(
+		NumberLinkTimeChecks: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "link_time_checks_total"),
 			"Displays the total number of link-time code access security checks since the application started.",
 			[]string{"process"},
 			nil,
 		),
-		TimeinRTchecks: prometheus.NewDesc(
+		TimeinRTchecks: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rt_checks_time_percent"),
 			"Displays the percentage of time spent performing runtime code access security checks in the last sample.",
 			[]string{"process"},
 			nil,
 		),
-		StackWalkDepth: prometheus.NewDesc(
+		StackWalkDepth: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "stack_walk_depth"),
 			"Displays the depth of the stack during that last runtime code access security check.",
 			[]string{"process"},
 			nil,
 		),
-		TotalRuntimeChecks: prometheus.NewDesc(
+		TotalRuntimeChecks: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "runtime_checks_total"),
 			"Displays the total number of runtime code access security checks performed since the application started.",
 			[]string{"process"},
@@ -52,10 +57,18 @@ func NewNETFramework_NETCLRSecurityCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *NETFramework_NETCLRSecurityCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.NumberLinkTimeChecks
+	ch <- c.TimeinRTchecks
+	ch <- c.StackWalkDepth
+	ch <- c.TotalRuntimeChecks
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *NETFramework_NETCLRSecurityCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting win32_perfrawdata_netframework_netclrsecurity metrics:", desc, err)
 		return err
 	}
@@ -73,10 +86,10 @@ type Win32_PerfRawData_NETFramework_NETCLRSecurity struct {
 	TotalRuntimeChecks           uint32
 }
 
-func (c *NETFramework_NETCLRSecurityCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *NETFramework_NETCLRSecurityCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_NETFramework_NETCLRSecurity
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -19,6 +19,10 @@ import (
 
 func init() {
 	registerCollector("os", NewOSCollector, "Paging File")
+	registerCollectorInfo("os", CollectorInfo{
+		Description: "OS metrics (memory, processes, users)",
+		Sources:     []DataSource{SourcePerflib, SourceWin32},
+	})
 }
 
 // A OSCollector is a Prometheus collector for WMI metrics
@@ -49,79 +53,79 @@ func NewOSCollector() (Collector, error) {
 	const subsystem = "os"
 
 	return &OSCollector{
-		OSInformation: prometheus.NewDesc(
+		OSInformation: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "info"),
 			"OperatingSystem.Caption, OperatingSystem.Version",
 			[]string{"product", "version", "major_version", "minor_version", "build_number"},
 			nil,
 		),
-		PagingLimitBytes: prometheus.NewDesc(
+		PagingLimitBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "paging_limit_bytes"),
 			"OperatingSystem.SizeStoredInPagingFiles",
 			nil,
 			nil,
 		),
-		PagingFreeBytes: prometheus.NewDesc(
+		PagingFreeBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "paging_free_bytes"),
 			"OperatingSystem.FreeSpaceInPagingFiles",
 			nil,
 			nil,
 		),
-		PhysicalMemoryFreeBytes: prometheus.NewDesc(
+		PhysicalMemoryFreeBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "physical_memory_free_bytes"),
 			"OperatingSystem.FreePhysicalMemory",
 			nil,
 			nil,
 		),
-		Time: prometheus.NewDesc(
+		Time: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "time"),
 			"OperatingSystem.LocalDateTime",
 			nil,
 			nil,
 		),
-		Timezone: prometheus.NewDesc(
+		Timezone: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "timezone"),
 			"OperatingSystem.LocalDateTime",
 			[]string{"timezone"},
 			nil,
 		),
-		Processes: prometheus.NewDesc(
+		Processes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "processes"),
 			"OperatingSystem.NumberOfProcesses",
 			nil,
 			nil,
 		),
-		ProcessesLimit: prometheus.NewDesc(
+		ProcessesLimit: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "processes_limit"),
 			"OperatingSystem.MaxNumberOfProcesses",
 			nil,
 			nil,
 		),
-		ProcessMemoryLimitBytes: prometheus.NewDesc(
+		ProcessMemoryLimitBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "process_memory_limit_bytes"),
 			"OperatingSystem.MaxProcessMemorySize",
 			nil,
 			nil,
 		),
-		Users: prometheus.NewDesc(
+		Users: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "users"),
 			"OperatingSystem.NumberOfUsers",
 			nil,
 			nil,
 		),
-		VirtualMemoryBytes: prometheus.NewDesc(
+		VirtualMemoryBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "virtual_memory_bytes"),
 			"OperatingSystem.TotalVirtualMemorySize",
 			nil,
 			nil,
 		),
-		VisibleMemoryBytes: prometheus.NewDesc(
+		VisibleMemoryBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "visible_memory_bytes"),
 			"OperatingSystem.TotalVisibleMemorySize",
 			nil,
 			nil,
 		),
-		VirtualMemoryFreeBytes: prometheus.NewDesc(
+		VirtualMemoryFreeBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "virtual_memory_free_bytes"),
 			"OperatingSystem.FreeVirtualMemory",
 			nil,
@@ -130,6 +134,23 @@ func NewOSCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *OSCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.OSInformation
+	ch <- c.PhysicalMemoryFreeBytes
+	ch <- c.PagingFreeBytes
+	ch <- c.VirtualMemoryFreeBytes
+	ch <- c.ProcessesLimit
+	ch <- c.ProcessMemoryLimitBytes
+	ch <- c.Processes
+	ch <- c.Users
+	ch <- c.PagingLimitBytes
+	ch <- c.VirtualMemoryBytes
+	ch <- c.VisibleMemoryBytes
+	ch <- c.Time
+	ch <- c.Timezone
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *OSCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -17,8 +18,41 @@ func MapCounterToIndex(name string) string {
 	return strconv.Itoa(int(nametable.LookupIndex(name)))
 }
 
-func getPerflibSnapshot(objNames string) (map[string]*perflib.PerfObject, error) {
-	objects, err := perflib.QueryPerformanceData(objNames)
+// PerflibNameTableLoaded reports whether the English perflib counter names
+// could be read from the registry. Without them, no perflib object can be
+// queried.
+func PerflibNameTableLoaded() bool {
+	return nametable.LookupIndex("System") != 0
+}
+
+// perflibObjectsPresent returns an applicabilityCheck reporting whether any of
+// the named perflib objects is registered on this host.
+func perflibObjectsPresent(names ...string) applicabilityCheck {
+	return func() (bool, error) {
+		for _, name := range names {
+			if nametable.LookupIndex(name) != 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+func getPerflibSnapshot(ctx context.Context, objNames string) (map[string]*perflib.PerfObject, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// The registry query cannot be interrupted, so it is run in the background and
+	// abandoned if the context is done first, see runQuery.
+	var objects []*perflib.PerfObject
+	err := runQuery(ctx, PerflibSnapshotQueries, func() error {
+		var err error
+		if objects, err = perflib.QueryPerformanceData(objNames); err != nil {
+			return classifiedError{ErrorClassPerflib, err}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +64,13 @@ func getPerflibSnapshot(objNames string) (map[string]*perflib.PerfObject, error)
 	return indexed, nil
 }
 
-func unmarshalObject(obj *perflib.PerfObject, vs interface{}) error {
+func unmarshalObject(obj *perflib.PerfObject, vs interface{}) (err error) {
+	defer func() {
+		if err != nil {
+			err = classifiedError{ErrorClassPerflib, err}
+		}
+	}()
+
 	if obj == nil {
 		return fmt.Errorf("counter not found")
 	}
@@ -4,31 +4,39 @@
 package collector
 
 import (
-	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
-func init() {
-	registerCollector("process", newProcessCollector, "Process")
-}
+const (
+	processWhitelistFlag = "collector.process.whitelist"
+	processBlacklistFlag = "collector.process.blacklist"
+)
 
-var (
-	processWhitelist = kingpin.Flag(
-		"collector.process.whitelist",
+func init() {
+	registerCollectorWithSettings("process", newProcessCollector, "Process")
+	registerCollectorInfo("process", CollectorInfo{
+		Description: "Per-process metrics",
+		Sources:     []DataSource{SourcePerflib, SourceWMI},
+		Expensive:   true,
+	})
+
+	kingpin.Flag(
+		processWhitelistFlag,
 		"Regexp of processes to include. Process name must both match whitelist and not match blacklist to be included.",
 	).Default(".*").String()
-	processBlacklist = kingpin.Flag(
-		"collector.process.blacklist",
+	kingpin.Flag(
+		processBlacklistFlag,
 		"Regexp of processes to exclude. Process name must both match whitelist and not match blacklist to be included.",
 	).Default("").String()
-)
+}
 
 type processCollector struct {
 	StartTime         *prometheus.Desc
@@ -52,106 +60,106 @@ type processCollector struct {
 }
 
 // NewProcessCollector ...
-func newProcessCollector() (Collector, error) {
+func newProcessCollector(settings *config.Config) (Collector, error) {
 	const subsystem = "process"
 
-	if *processWhitelist == ".*" && *processBlacklist == "" {
+	if settings.Collector.Process.Whitelist.String() == ".*" && len(settings.Collector.Process.Blacklist) == 0 {
 		log.Warn("No filters specified for process collector. This will generate a very large number of metrics!")
 	}
 
 	return &processCollector{
-		StartTime: prometheus.NewDesc(
+		StartTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "start_time"),
 			"Time of process start.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		CPUTimeTotal: prometheus.NewDesc(
+		CPUTimeTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cpu_time_total"),
 			"Returns elapsed time that all of the threads of this process used the processor to execute instructions by mode (privileged, user).",
 			[]string{"process", "process_id", "creating_process_id", "mode"},
 			nil,
 		),
-		HandleCount: prometheus.NewDesc(
+		HandleCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "handles"),
 			"Total number of handles the process has open. This number is the sum of the handles currently open by each thread in the process.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		IOBytesTotal: prometheus.NewDesc(
+		IOBytesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "io_bytes_total"),
 			"Bytes issued to I/O operations in different modes (read, write, other).",
 			[]string{"process", "process_id", "creating_process_id", "mode"},
 			nil,
 		),
-		IOOperationsTotal: prometheus.NewDesc(
+		IOOperationsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "io_operations_total"),
 			"I/O operations issued in different modes (read, write, other).",
 			[]string{"process", "process_id", "creating_process_id", "mode"},
 			nil,
 		),
-		PageFaultsTotal: prometheus.NewDesc(
+		PageFaultsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "page_faults_total"),
 			"Page faults by the threads executing in this process.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		PageFileBytes: prometheus.NewDesc(
+		PageFileBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "page_file_bytes"),
 			"Current number of bytes this process has used in the paging file(s).",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		PoolBytes: prometheus.NewDesc(
+		PoolBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pool_bytes"),
 			"Pool Bytes is the last observed number of bytes in the paged or nonpaged pool.",
 			[]string{"process", "process_id", "creating_process_id", "pool"},
 			nil,
 		),
-		PriorityBase: prometheus.NewDesc(
+		PriorityBase: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "priority_base"),
 			"Current base priority of this process. Threads within a process can raise and lower their own base priority relative to the process base priority of the process.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		PrivateBytes: prometheus.NewDesc(
+		PrivateBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "private_bytes"),
 			"Current number of bytes this process has allocated that cannot be shared with other processes.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		ThreadCount: prometheus.NewDesc(
+		ThreadCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "threads"),
 			"Number of threads currently active in this process.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		VirtualBytes: prometheus.NewDesc(
+		VirtualBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "virtual_bytes"),
 			"Current size, in bytes, of the virtual address space that the process is using.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		WorkingSetPrivate: prometheus.NewDesc(
+		WorkingSetPrivate: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "working_set_private_bytes"),
 			"Size of the working set, in bytes, that is use for this process only and not shared nor shareable by other processes.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		WorkingSetPeak: prometheus.NewDesc(
+		WorkingSetPeak: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "working_set_peak_bytes"),
 			"Maximum size, in bytes, of the Working Set of this process at any point in time. The Working Set is the set of memory pages touched recently by the threads in the process.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		WorkingSet: prometheus.NewDesc(
+		WorkingSet: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "working_set_bytes"),
 			"Maximum number of bytes in the working set of this process at any point in time. The working set is the set of memory pages touched recently by the threads in the process.",
 			[]string{"process", "process_id", "creating_process_id"},
 			nil,
 		),
-		processWhitelistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *processWhitelist)),
-		processBlacklistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *processBlacklist)),
+		processWhitelistPattern: settings.Collector.Process.Whitelist.Regexp(),
+		processBlacklistPattern: settings.Collector.Process.Blacklist.Regexp(),
 	}, nil
 }
 
@@ -192,6 +200,25 @@ type WorkerProcess struct {
 	ProcessId   uint64
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *processCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.StartTime
+	ch <- c.CPUTimeTotal
+	ch <- c.HandleCount
+	ch <- c.IOBytesTotal
+	ch <- c.IOOperationsTotal
+	ch <- c.PageFaultsTotal
+	ch <- c.PageFileBytes
+	ch <- c.PoolBytes
+	ch <- c.PriorityBase
+	ch <- c.PrivateBytes
+	ch <- c.ThreadCount
+	ch <- c.VirtualBytes
+	ch <- c.WorkingSetPrivate
+	ch <- c.WorkingSetPeak
+	ch <- c.WorkingSet
+}
+
 func (c *processCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	data := make([]perflibProcess, 0)
 	err := unmarshalObject(ctx.perfObjects["Process"], &data)
@@ -201,7 +228,7 @@ func (c *processCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metr
 
 	var dst_wp []WorkerProcess
 	q_wp := queryAll(&dst_wp)
-	if err := wmi.QueryNamespace(q_wp, &dst_wp, "root\\WebAdministration"); err != nil {
+	if err := wmiQueryNamespace(ctx, q_wp, &dst_wp, "root\\WebAdministration"); err != nil {
 		log.Debugf("Could not query WebAdministration namespace for IIS worker processes: %v. Skipping", err)
 	}
 
@@ -223,6 +250,10 @@ func (c *processCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metr
 			}
 		}
 
+		// The counters of a process start from zero when it is created. Elapsed
+		// Time holds the process start time.
+		startTime := time.Unix(0, int64(process.ElapsedTime*1e9))
+
 		ch <- prometheus.MustNewConstMetric(
 			c.StartTime,
 			prometheus.GaugeValue,
@@ -241,90 +272,99 @@ func (c *processCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metr
 			cpid,
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.CPUTimeTotal,
 			prometheus.CounterValue,
 			process.PercentPrivilegedTime,
+			startTime,
 			processName,
 			pid,
 			cpid,
 			"privileged",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.CPUTimeTotal,
 			prometheus.CounterValue,
 			process.PercentUserTime,
+			startTime,
 			processName,
 			pid,
 			cpid,
 			"user",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.IOBytesTotal,
 			prometheus.CounterValue,
 			process.IOOtherBytesPerSec,
+			startTime,
 			processName,
 			pid,
 			cpid,
 			"other",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.IOOperationsTotal,
 			prometheus.CounterValue,
 			process.IOOtherOperationsPerSec,
+			startTime,
 			processName,
 			pid,
 			cpid,
 			"other",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.IOBytesTotal,
 			prometheus.CounterValue,
 			process.IOReadBytesPerSec,
+			startTime,
 			processName,
 			pid,
 			cpid,
 			"read",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.IOOperationsTotal,
 			prometheus.CounterValue,
 			process.IOReadOperationsPerSec,
+			startTime,
 			processName,
 			pid,
 			cpid,
 			"read",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.IOBytesTotal,
 			prometheus.CounterValue,
 			process.IOWriteBytesPerSec,
+			startTime,
 			processName,
 			pid,
 			cpid,
 			"write",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.IOOperationsTotal,
 			prometheus.CounterValue,
 			process.IOWriteOperationsPerSec,
+			startTime,
 			processName,
 			pid,
 			cpid,
 			"write",
 		)
 
-		ch <- prometheus.MustNewConstMetric(
+		ch <- prometheus.MustNewConstMetricWithCreatedTimestamp(
 			c.PageFaultsTotal,
 			prometheus.CounterValue,
 			process.PageFaultsPerSec,
+			startTime,
 			processName,
 			pid,
 			cpid,
@@ -0,0 +1,68 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// PerflibSnapshotQueries is the name the perflib queries of PrepareScrapeContext,
+// which are shared by the collectors of a scrape, are counted under by
+// OrphanedQueries.
+const PerflibSnapshotQueries = "perflib_snapshot"
+
+// orphanedQueries counts, per collector, the WMI and perflib queries which were
+// still running when the scrape waiting for them gave up.
+var orphanedQueries = struct {
+	sync.Mutex
+	count map[string]int
+}{count: make(map[string]int)}
+
+// OrphanedQueries returns the number of queries of the named collector still
+// running after the scrape which started them gave up on them.
+func OrphanedQueries(name string) int {
+	orphanedQueries.Lock()
+	defer orphanedQueries.Unlock()
+	return orphanedQueries.count[name]
+}
+
+// runQuery runs query, which cannot be interrupted, in the background, and
+// returns its error, or the context error as soon as ctx is done. A query
+// given up on is counted as orphaned until it returns, and no other query of
+// the same collector is started meanwhile, so that a hung WMI provider or
+// registry call does not pile up goroutines.
+func runQuery(ctx context.Context, name string, query func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if n := OrphanedQueries(name); n > 0 {
+		return classifiedError{ErrorClassTimeout, fmt.Errorf("%d earlier queries of %s are still running", n, name)}
+	}
+
+	// Both guarded by orphanedQueries.
+	var finished, orphaned bool
+	errCh := make(chan error, 1)
+	go func() {
+		err := query()
+		orphanedQueries.Lock()
+		finished = true
+		if orphaned {
+			orphanedQueries.count[name]--
+		}
+		orphanedQueries.Unlock()
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		orphanedQueries.Lock()
+		if !finished {
+			orphaned = true
+			orphanedQueries.count[name]++
+		}
+		orphanedQueries.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunQueryOrphans(t *testing.T) {
+	const name = "query_test"
+	release := make(chan struct{})
+	returned := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// A query cancelled before it started is not run.
+	if err := runQuery(ctx, name, func() error { t.Error("unexpected query"); return nil }); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := runQuery(ctx, name, func() error {
+		<-release
+		defer close(returned)
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+	if n := OrphanedQueries(name); n != 1 {
+		t.Errorf("expected 1 orphaned query, got %d", n)
+	}
+
+	// No other query of the collector is started while one is stuck.
+	err = runQuery(context.Background(), name, func() error { t.Error("unexpected query"); return nil })
+	if ClassifyError(err) != ErrorClassTimeout {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+
+	close(release)
+	<-returned
+	for i := 0; OrphanedQueries(name) != 0; i++ {
+		if i == 100 {
+			t.Fatalf("expected the orphaned query to be counted out, got %d", OrphanedQueries(name))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := runQuery(context.Background(), name, func() error { return nil }); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -12,6 +12,12 @@ import (
 
 func init() {
 	registerCollector("remote_fx", NewRemoteFx, "RemoteFX Network", "RemoteFX Graphics")
+	registerCollectorInfo("remote_fx", CollectorInfo{
+		Description: "RemoteFX protocol (RDP) metrics",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "Remote Desktop Services with RemoteFX",
+	})
+	registerApplicabilityCheck("remote_fx", perflibObjectsPresent("RemoteFX Network"))
 }
 
 // A RemoteFxNetworkCollector is a Prometheus collector for
@@ -47,61 +53,61 @@ func NewRemoteFx() (Collector, error) {
 	const subsystem = "remote_fx"
 	return &RemoteFxCollector{
 		// net
-		BaseTCPRTT: prometheus.NewDesc(
+		BaseTCPRTT: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_base_tcp_rtt_seconds"),
 			"Base TCP round-trip time (RTT) detected in seconds",
 			[]string{"session_name"},
 			nil,
 		),
-		BaseUDPRTT: prometheus.NewDesc(
+		BaseUDPRTT: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_base_udp_rtt_seconds"),
 			"Base UDP round-trip time (RTT) detected in seconds.",
 			[]string{"session_name"},
 			nil,
 		),
-		CurrentTCPBandwidth: prometheus.NewDesc(
+		CurrentTCPBandwidth: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_current_tcp_bandwidth"),
 			"TCP Bandwidth detected in bytes per second.",
 			[]string{"session_name"},
 			nil,
 		),
-		CurrentTCPRTT: prometheus.NewDesc(
+		CurrentTCPRTT: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_current_tcp_rtt_seconds"),
 			"Average TCP round-trip time (RTT) detected in seconds.",
 			[]string{"session_name"},
 			nil,
 		),
-		CurrentUDPBandwidth: prometheus.NewDesc(
+		CurrentUDPBandwidth: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_current_udp_bandwidth"),
 			"UDP Bandwidth detected in bytes per second.",
 			[]string{"session_name"},
 			nil,
 		),
-		CurrentUDPRTT: prometheus.NewDesc(
+		CurrentUDPRTT: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_current_udp_rtt_seconds"),
 			"Average UDP round-trip time (RTT) detected in seconds.",
 			[]string{"session_name"},
 			nil,
 		),
-		TotalReceivedBytes: prometheus.NewDesc(
+		TotalReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_received_bytes_total"),
 			"(TotalReceivedBytes)",
 			[]string{"session_name"},
 			nil,
 		),
-		TotalSentBytes: prometheus.NewDesc(
+		TotalSentBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_sent_bytes_total"),
 			"(TotalSentBytes)",
 			[]string{"session_name"},
 			nil,
 		),
-		UDPPacketsReceivedPersec: prometheus.NewDesc(
+		UDPPacketsReceivedPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_udp_packets_received_total"),
 			"Rate in packets per second at which packets are received over UDP.",
 			[]string{"session_name"},
 			nil,
 		),
-		UDPPacketsSentPersec: prometheus.NewDesc(
+		UDPPacketsSentPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "net_udp_packets_sent_total"),
 			"Rate in packets per second at which packets are sent over UDP.",
 			[]string{"session_name"},
@@ -109,43 +115,43 @@ func NewRemoteFx() (Collector, error) {
 		),
 
 		//gfx
-		AverageEncodingTime: prometheus.NewDesc(
+		AverageEncodingTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "gfx_average_encoding_time_seconds"),
 			"Average frame encoding time in seconds",
 			[]string{"session_name"},
 			nil,
 		),
-		FrameQuality: prometheus.NewDesc(
+		FrameQuality: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "gfx_frame_quality"),
 			"Quality of the output frame expressed as a percentage of the quality of the source frame.",
 			[]string{"session_name"},
 			nil,
 		),
-		FramesSkippedPerSecondInsufficientResources: prometheus.NewDesc(
+		FramesSkippedPerSecondInsufficientResources: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "gfx_frames_skipped_insufficient_resource_total"),
 			"Number of frames skipped per second due to insufficient client resources.",
 			[]string{"session_name", "resource"},
 			nil,
 		),
-		GraphicsCompressionratio: prometheus.NewDesc(
+		GraphicsCompressionratio: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "gfx_graphics_compression_ratio"),
 			"Ratio of the number of bytes encoded to the number of bytes input.",
 			[]string{"session_name"},
 			nil,
 		),
-		InputFramesPerSecond: prometheus.NewDesc(
+		InputFramesPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "gfx_input_frames_total"),
 			"Number of sources frames provided as input to RemoteFX graphics per second.",
 			[]string{"session_name"},
 			nil,
 		),
-		OutputFramesPerSecond: prometheus.NewDesc(
+		OutputFramesPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "gfx_output_frames_total"),
 			"Number of frames sent to the client per second.",
 			[]string{"session_name"},
 			nil,
 		),
-		SourceFramesPerSecond: prometheus.NewDesc(
+		SourceFramesPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "gfx_source_frames_total"),
 			"Number of frames composed by the source (DWM) per second.",
 			[]string{"session_name"},
@@ -154,6 +160,27 @@ func NewRemoteFx() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *RemoteFxCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.BaseTCPRTT
+	ch <- c.BaseUDPRTT
+	ch <- c.CurrentTCPBandwidth
+	ch <- c.CurrentTCPRTT
+	ch <- c.CurrentUDPBandwidth
+	ch <- c.CurrentUDPRTT
+	ch <- c.TotalReceivedBytes
+	ch <- c.TotalSentBytes
+	ch <- c.UDPPacketsReceivedPersec
+	ch <- c.UDPPacketsSentPersec
+	ch <- c.AverageEncodingTime
+	ch <- c.FrameQuality
+	ch <- c.FramesSkippedPerSecondInsufficientResources
+	ch <- c.GraphicsCompressionratio
+	ch <- c.InputFramesPerSecond
+	ch <- c.OutputFramesPerSecond
+	ch <- c.SourceFramesPerSecond
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *RemoteFxCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
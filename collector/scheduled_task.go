@@ -4,7 +4,6 @@
 package collector
 
 import (
-	"fmt"
 	"regexp"
 	"runtime"
 	"strings"
@@ -12,21 +11,11 @@ import (
 	"github.com/alecthomas/kingpin/v2"
 	ole "github.com/go-ole/go-ole"
 	"github.com/go-ole/go-ole/oleutil"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-var (
-	taskWhitelist = kingpin.Flag(
-		"collector.scheduled_task.whitelist",
-		"Regexp of tasks to whitelist. Task path must both match whitelist and not match blacklist to be included.",
-	).Default(".+").String()
-	taskBlacklist = kingpin.Flag(
-		"collector.scheduled_task.blacklist",
-		"Regexp of tasks to blacklist. Task path must both match whitelist and not match blacklist to be included.",
-	).String()
-)
-
 type ScheduledTaskCollector struct {
 	LastResult *prometheus.Desc
 	MissedRuns *prometheus.Desc
@@ -63,12 +52,31 @@ type ScheduledTask struct {
 
 type ScheduledTasks []ScheduledTask
 
+const (
+	taskWhitelistFlag = "collector.scheduled_task.whitelist"
+	taskBlacklistFlag = "collector.scheduled_task.blacklist"
+)
+
 func init() {
-	registerCollector("scheduled_task", NewScheduledTask)
+	registerCollectorWithSettings("scheduled_task", NewScheduledTask)
+	registerCollectorInfo("scheduled_task", CollectorInfo{
+		Description: "Scheduled Tasks metrics",
+		Sources:     []DataSource{SourceCOM},
+		Expensive:   true,
+	})
+
+	kingpin.Flag(
+		taskWhitelistFlag,
+		"Regexp of tasks to whitelist. Task path must both match whitelist and not match blacklist to be included.",
+	).Default(".+").String()
+	kingpin.Flag(
+		taskBlacklistFlag,
+		"Regexp of tasks to blacklist. Task path must both match whitelist and not match blacklist to be included.",
+	).String()
 }
 
 // NewScheduledTask ...
-func NewScheduledTask() (Collector, error) {
+func NewScheduledTask(settings *config.Config) (Collector, error) {
 	const subsystem = "scheduled_task"
 
 	runtime.LockOSThread()
@@ -84,32 +92,39 @@ func NewScheduledTask() (Collector, error) {
 	defer ole.CoUninitialize()
 
 	return &ScheduledTaskCollector{
-		LastResult: prometheus.NewDesc(
+		LastResult: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "last_result"),
 			"The result that was returned the last time the registered task was run",
 			[]string{"task"},
 			nil,
 		),
 
-		MissedRuns: prometheus.NewDesc(
+		MissedRuns: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "missed_runs"),
 			"The number of times the registered task missed a scheduled run",
 			[]string{"task"},
 			nil,
 		),
 
-		State: prometheus.NewDesc(
+		State: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "state"),
 			"The current state of a scheduled task",
 			[]string{"task", "state"},
 			nil,
 		),
 
-		taskWhitelistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *taskWhitelist)),
-		taskBlacklistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *taskBlacklist)),
+		taskWhitelistPattern: settings.Collector.ScheduledTask.Whitelist.Regexp(),
+		taskBlacklistPattern: settings.Collector.ScheduledTask.Blacklist.Regexp(),
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *ScheduledTaskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.LastResult
+	ch <- c.MissedRuns
+	ch <- c.State
+}
+
 func (c *ScheduledTaskCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	if desc, err := c.collect(ch); err != nil {
 		log.Error("failed collecting user metrics:", desc, err)
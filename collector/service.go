@@ -9,27 +9,35 @@ import (
 	"syscall"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/svc/mgr"
 )
 
+const (
+	serviceWhereClauseFlag = "collector.service.services-where"
+	serviceUseAPIFlag      = "collector.service.use-api"
+)
+
 func init() {
-	registerCollector("service", NewserviceCollector)
-}
+	registerCollectorWithSettings("service", NewserviceCollector)
+	registerCollectorInfo("service", CollectorInfo{
+		Description: "Service state metrics",
+		Sources:     []DataSource{SourceWMI, SourceWin32},
+		Expensive:   true,
+	})
 
-var (
-	serviceWhereClause = kingpin.Flag(
-		"collector.service.services-where",
+	kingpin.Flag(
+		serviceWhereClauseFlag,
 		"WQL 'where' clause to use in WMI metrics query. Limits the response to the services you specify and reduces the size of the response.",
 	).Default("").String()
-	useAPI = kingpin.Flag(
-		"collector.service.use-api",
+	kingpin.Flag(
+		serviceUseAPIFlag,
 		"Use API calls to collect service data instead of WMI. Flag 'collector.service.services-where' won't be effective.",
 	).Default("false").Bool()
-)
+}
 
 // A serviceCollector is a Prometheus collector for WMI Win32_Service metrics
 type serviceCollector struct {
@@ -39,58 +47,68 @@ type serviceCollector struct {
 	Status      *prometheus.Desc
 
 	queryWhereClause string
+	useAPI           bool
 }
 
 // NewserviceCollector ...
-func NewserviceCollector() (Collector, error) {
+func NewserviceCollector(settings *config.Config) (Collector, error) {
 	const subsystem = "service"
 
-	if *serviceWhereClause == "" {
+	if settings.Collector.Service.Where.String() == "" {
 		log.Warn("No where-clause specified for service collector. This will generate a very large number of metrics!")
 	}
-	if *useAPI {
+	if settings.Collector.Service.UseAPI {
 		log.Warn("API collection is enabled.")
 	}
 
 	return &serviceCollector{
-		Information: prometheus.NewDesc(
+		Information: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "info"),
 			"A metric with a constant '1' value labeled with service information",
 			[]string{"name", "display_name", "process_id", "run_as"},
 			nil,
 		),
-		State: prometheus.NewDesc(
+		State: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "state"),
 			"The state of the service (State)",
 			[]string{"name", "state"},
 			nil,
 		),
-		StartMode: prometheus.NewDesc(
+		StartMode: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "start_mode"),
 			"The start mode of the service (StartMode)",
 			[]string{"name", "start_mode"},
 			nil,
 		),
-		Status: prometheus.NewDesc(
+		Status: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "status"),
 			"The status of the service (Status)",
 			[]string{"name", "status"},
 			nil,
 		),
-		queryWhereClause: *serviceWhereClause,
+		queryWhereClause: settings.Collector.Service.Where.String(),
+		useAPI:           settings.Collector.Service.UseAPI,
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *serviceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Information
+	ch <- c.State
+	ch <- c.StartMode
+	ch <- c.Status
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *serviceCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if *useAPI {
+	if c.useAPI {
 		if err := c.collectAPI(ch); err != nil {
 			log.Error("failed collecting API service metrics:", err)
 			return err
 		}
 	} else {
-		if err := c.collectWMI(ch); err != nil {
+		if err := c.collectWMI(ctx, ch); err != nil {
 			log.Error("failed collecting WMI service metrics:", err)
 			return err
 		}
@@ -160,10 +178,10 @@ var (
 	}
 )
 
-func (c *serviceCollector) collectWMI(ch chan<- prometheus.Metric) error {
+func (c *serviceCollector) collectWMI(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
 	var dst []Win32_Service
 	q := queryAllWhere(&dst, c.queryWhereClause)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return err
 	}
 	for _, service := range dst {
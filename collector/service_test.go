@@ -5,5 +5,5 @@ import (
 )
 
 func BenchmarkServiceCollector(b *testing.B) {
-	benchmarkCollector(b, "service", NewserviceCollector)
+	benchmarkCollector(b, "service", withSettings(b, NewserviceCollector))
 }
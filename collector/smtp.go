@@ -4,22 +4,31 @@
 package collector
 
 import (
-	"fmt"
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"regexp"
 )
 
+const (
+	serverWhitelistFlag = "collector.smtp.server-whitelist"
+	serverBlacklistFlag = "collector.smtp.server-blacklist"
+)
+
 func init() {
-	registerCollector("smtp", NewSMTPCollector, "SMTP Server")
+	registerCollectorWithSettings("smtp", NewSMTPCollector, "SMTP Server")
+	registerCollectorInfo("smtp", CollectorInfo{
+		Description: "IIS SMTP Server",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "IIS SMTP Server",
+	})
+	registerApplicabilityCheck("smtp", perflibObjectsPresent("SMTP Server"))
+
+	kingpin.Flag(serverWhitelistFlag, "Regexp of virtual servers to whitelist. Server name must both match whitelist and not match blacklist to be included.").Default(".+").String()
+	kingpin.Flag(serverBlacklistFlag, "Regexp of virtual servers to blacklist. Server name must both match whitelist and not match blacklist to be included.").String()
 }
 
-var (
-	serverWhitelist = kingpin.Flag("collector.smtp.server-whitelist", "Regexp of virtual servers to whitelist. Server name must both match whitelist and not match blacklist to be included.").Default(".+").String()
-	serverBlacklist = kingpin.Flag("collector.smtp.server-blacklist", "Regexp of virtual servers to blacklist. Server name must both match whitelist and not match blacklist to be included.").String()
-)
-
 type SMTPCollector struct {
 	BadmailedMessagesBadPickupFileTotal     *prometheus.Desc
 	BadmailedMessagesGeneralFailureTotal    *prometheus.Desc
@@ -68,269 +77,315 @@ type SMTPCollector struct {
 	serverBlacklistPattern *regexp.Regexp
 }
 
-func NewSMTPCollector() (Collector, error) {
+func NewSMTPCollector(settings *config.Config) (Collector, error) {
 	log.Info("smtp collector is in an experimental state! Metrics for this collector have not been tested.")
 	const subsystem = "smtp"
 
 	return &SMTPCollector{
-		BadmailedMessagesBadPickupFileTotal: prometheus.NewDesc(
+		BadmailedMessagesBadPickupFileTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "badmailed_messages_bad_pickup_file_total"),
 			"Total number of malformed pickup messages sent to badmail",
 			[]string{"site"},
 			nil,
 		),
-		BadmailedMessagesGeneralFailureTotal: prometheus.NewDesc(
+		BadmailedMessagesGeneralFailureTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "badmailed_messages_general_failure_total"),
 			"Total number of messages sent to badmail for reasons not associated with a specific counter",
 			[]string{"site"},
 			nil,
 		),
-		BadmailedMessagesHopCountExceededTotal: prometheus.NewDesc(
+		BadmailedMessagesHopCountExceededTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "badmailed_messages_hop_count_exceeded_total"),
 			"Total number of messages sent to badmail because they had exceeded the maximum hop count",
 			[]string{"site"},
 			nil,
 		),
-		BadmailedMessagesNDROfDSNTotal: prometheus.NewDesc(
+		BadmailedMessagesNDROfDSNTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "badmailed_messages_ndr_of_dns_total"),
 			"Total number of Delivery Status Notifications sent to badmail because they could not be delivered",
 			[]string{"site"},
 			nil,
 		),
-		BadmailedMessagesNoRecipientsTotal: prometheus.NewDesc(
+		BadmailedMessagesNoRecipientsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "badmailed_messages_no_recipients_total"),
 			"Total number of messages sent to badmail because they had no recipients",
 			[]string{"site"},
 			nil,
 		),
-		BadmailedMessagesTriggeredViaEventTotal: prometheus.NewDesc(
+		BadmailedMessagesTriggeredViaEventTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "badmailed_messages_triggered_via_event_total"),
 			"Total number of messages sent to badmail at the request of a server event sink",
 			[]string{"site"},
 			nil,
 		),
-		BytesSentTotal: prometheus.NewDesc(
+		BytesSentTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bytes_sent_total"),
 			"Total number of bytes sent",
 			[]string{"site"},
 			nil,
 		),
-		BytesReceivedTotal: prometheus.NewDesc(
+		BytesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bytes_received_total"),
 			"Total number of bytes received",
 			[]string{"site"},
 			nil,
 		),
-		CategorizerQueueLength: prometheus.NewDesc(
+		CategorizerQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "categorizer_queue_length"),
 			"Number of messages in the categorizer queue",
 			[]string{"site"},
 			nil,
 		),
-		ConnectionErrorsTotal: prometheus.NewDesc(
+		ConnectionErrorsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_errors_total"),
 			"Total number of connection errors",
 			[]string{"site"},
 			nil,
 		),
-		CurrentMessagesInLocalDelivery: prometheus.NewDesc(
+		CurrentMessagesInLocalDelivery: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "current_messages_in_local_delivery"),
 			"Number of messages that are currently being processed by a server event sink for local delivery",
 			[]string{"site"},
 			nil,
 		),
-		DirectoryDropsTotal: prometheus.NewDesc(
+		DirectoryDropsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "directory_drops_total"),
 			"Total number of messages placed in a drop directory",
 			[]string{"site"},
 			nil,
 		),
-		DSNFailuresTotal: prometheus.NewDesc(
+		DSNFailuresTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dsn_failures_total"),
 			"Total number of failed DSN generation attempts",
 			[]string{"site"},
 			nil,
 		),
-		DNSQueriesTotal: prometheus.NewDesc(
+		DNSQueriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "dns_queries_total"),
 			"Total number of DNS lookups",
 			[]string{"site"},
 			nil,
 		),
-		ETRNMessagesTotal: prometheus.NewDesc(
+		ETRNMessagesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "etrn_messages_total"),
 			"Total number of ETRN messages received by the server",
 			[]string{"site"},
 			nil,
 		),
-		InboundConnectionsCurrent: prometheus.NewDesc(
+		InboundConnectionsCurrent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "inbound_connections_current"),
 			"Total number of connections currently inbound",
 			[]string{"site"},
 			nil,
 		),
-		InboundConnectionsTotal: prometheus.NewDesc(
+		InboundConnectionsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "inbound_connections_total"),
 			"Total number of inbound connections received",
 			[]string{"site"},
 			nil,
 		),
-		LocalQueueLength: prometheus.NewDesc(
+		LocalQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "local_queue_length"),
 			"Number of messages in the local queue",
 			[]string{"site"},
 			nil,
 		),
-		LocalRetryQueueLength: prometheus.NewDesc(
+		LocalRetryQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "local_retry_queue_length"),
 			"Number of messages in the local retry queue",
 			[]string{"site"},
 			nil,
 		),
-		MailFilesOpen: prometheus.NewDesc(
+		MailFilesOpen: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mail_files_open"),
 			"Number of handles to open mail files",
 			[]string{"site"},
 			nil,
 		),
-		MessageBytesReceivedTotal: prometheus.NewDesc(
+		MessageBytesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "message_bytes_received_total"),
 			"Total number of bytes received in messages",
 			[]string{"site"},
 			nil,
 		),
-		MessageBytesSentTotal: prometheus.NewDesc(
+		MessageBytesSentTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "message_bytes_sent_total"),
 			"Total number of bytes sent in messages",
 			[]string{"site"},
 			nil,
 		),
-		MessageDeliveryRetriesTotal: prometheus.NewDesc(
+		MessageDeliveryRetriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "message_delivery_retries_total"),
 			"Total number of local deliveries that were retried",
 			[]string{"site"},
 			nil,
 		),
-		MessageSendRetriesTotal: prometheus.NewDesc(
+		MessageSendRetriesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "message_send_retries_total"),
 			"Total number of outbound message sends that were retried",
 			[]string{"site"},
 			nil,
 		),
-		MessagesCurrentlyUndeliverable: prometheus.NewDesc(
+		MessagesCurrentlyUndeliverable: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_currently_undeliverable"),
 			"Number of messages that have been reported as currently undeliverable by routing",
 			[]string{"site"},
 			nil,
 		),
-		MessagesDeliveredTotal: prometheus.NewDesc(
+		MessagesDeliveredTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_delivered_total"),
 			"Total number of messages delivered to local mailboxes",
 			[]string{"site"},
 			nil,
 		),
-		MessagesPendingRouting: prometheus.NewDesc(
+		MessagesPendingRouting: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_pending_routing"),
 			"Number of messages that have been categorized but not routed",
 			[]string{"site"},
 			nil,
 		),
-		MessagesReceivedTotal: prometheus.NewDesc(
+		MessagesReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_received_total"),
 			"Total number of inbound messages accepted",
 			[]string{"site"},
 			nil,
 		),
-		MessagesRefusedForAddressObjectsTotal: prometheus.NewDesc(
+		MessagesRefusedForAddressObjectsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_refused_for_address_objects_total"),
 			"Total number of messages refused due to no address objects",
 			[]string{"site"},
 			nil,
 		),
-		MessagesRefusedForMailObjectsTotal: prometheus.NewDesc(
+		MessagesRefusedForMailObjectsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_refused_for_mail_objects_total"),
 			"Total number of messages refused due to no mail objects",
 			[]string{"site"},
 			nil,
 		),
-		MessagesRefusedForSizeTotal: prometheus.NewDesc(
+		MessagesRefusedForSizeTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_refused_for_size_total"),
 			"Total number of messages rejected because they were too big",
 			[]string{"site"},
 			nil,
 		),
-		MessagesSentTotal: prometheus.NewDesc(
+		MessagesSentTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_sent_total"),
 			"Total number of outbound messages sent",
 			[]string{"site"},
 			nil,
 		),
-		MessagesSubmittedTotal: prometheus.NewDesc(
+		MessagesSubmittedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "messages_submitted_total"),
 			"Total number of messages submitted to queuing for delivery",
 			[]string{"site"},
 			nil,
 		),
-		NDRsGeneratedTotal: prometheus.NewDesc(
+		NDRsGeneratedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ndrs_generated_total"),
 			"Total number of non-delivery reports that have been generated",
 			[]string{"site"},
 			nil,
 		),
-		OutboundConnectionsCurrent: prometheus.NewDesc(
+		OutboundConnectionsCurrent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "outbound_connections_current"),
 			"Number of connections currently outbound",
 			[]string{"site"},
 			nil,
 		),
-		OutboundConnectionsRefusedTotal: prometheus.NewDesc(
+		OutboundConnectionsRefusedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "outbound_connections_refused_total"),
 			"Total number of connection attempts refused by remote sites",
 			[]string{"site"},
 			nil,
 		),
-		OutboundConnectionsTotal: prometheus.NewDesc(
+		OutboundConnectionsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "outbound_connections_total"),
 			"Total number of outbound connections attempted",
 			[]string{"site"},
 			nil,
 		),
-		PickupDirectoryMessagesRetrievedTotal: prometheus.NewDesc(
+		PickupDirectoryMessagesRetrievedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pickup_directory_messages_retrieved_total"),
 			"Total number of messages retrieved from the mail pick-up directory",
 			[]string{"site"},
 			nil,
 		),
-		QueueFilesOpen: prometheus.NewDesc(
+		QueueFilesOpen: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "queue_files_open"),
 			"Number of handles to open queue files",
 			[]string{"site"},
 			nil,
 		),
-		RemoteQueueLength: prometheus.NewDesc(
+		RemoteQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "remote_queue_length"),
 			"Number of messages in the remote queue",
 			[]string{"site"},
 			nil,
 		),
-		RemoteRetryQueueLength: prometheus.NewDesc(
+		RemoteRetryQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "remote_retry_queue_length"),
 			"Number of messages in the retry queue for remote delivery",
 			[]string{"site"},
 			nil,
 		),
-		RoutingTableLookupsTotal: prometheus.NewDesc(
+		RoutingTableLookupsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "routing_table_lookups_total"),
 			"Total number of routing table lookups",
 			[]string{"site"},
 			nil,
 		),
 
-		serverWhitelistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *serverWhitelist)),
-		serverBlacklistPattern: regexp.MustCompile(fmt.Sprintf("^(?:%s)$", *serverBlacklist)),
+		serverWhitelistPattern: settings.Collector.SMTP.ServerWhitelist.Regexp(),
+		serverBlacklistPattern: settings.Collector.SMTP.ServerBlacklist.Regexp(),
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *SMTPCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.BadmailedMessagesBadPickupFileTotal
+	ch <- c.BadmailedMessagesGeneralFailureTotal
+	ch <- c.BadmailedMessagesHopCountExceededTotal
+	ch <- c.BadmailedMessagesNDROfDSNTotal
+	ch <- c.BadmailedMessagesNoRecipientsTotal
+	ch <- c.BadmailedMessagesTriggeredViaEventTotal
+	ch <- c.BytesSentTotal
+	ch <- c.BytesReceivedTotal
+	ch <- c.CategorizerQueueLength
+	ch <- c.ConnectionErrorsTotal
+	ch <- c.CurrentMessagesInLocalDelivery
+	ch <- c.DirectoryDropsTotal
+	ch <- c.DNSQueriesTotal
+	ch <- c.DSNFailuresTotal
+	ch <- c.ETRNMessagesTotal
+	ch <- c.InboundConnectionsCurrent
+	ch <- c.InboundConnectionsTotal
+	ch <- c.LocalQueueLength
+	ch <- c.LocalRetryQueueLength
+	ch <- c.MailFilesOpen
+	ch <- c.MessageBytesReceivedTotal
+	ch <- c.MessageBytesSentTotal
+	ch <- c.MessageDeliveryRetriesTotal
+	ch <- c.MessageSendRetriesTotal
+	ch <- c.MessagesCurrentlyUndeliverable
+	ch <- c.MessagesDeliveredTotal
+	ch <- c.MessagesPendingRouting
+	ch <- c.MessagesReceivedTotal
+	ch <- c.MessagesRefusedForAddressObjectsTotal
+	ch <- c.MessagesRefusedForMailObjectsTotal
+	ch <- c.MessagesRefusedForSizeTotal
+	ch <- c.MessagesSentTotal
+	ch <- c.MessagesSubmittedTotal
+	ch <- c.NDRsGeneratedTotal
+	ch <- c.OutboundConnectionsCurrent
+	ch <- c.OutboundConnectionsRefusedTotal
+	ch <- c.OutboundConnectionsTotal
+	ch <- c.QueueFilesOpen
+	ch <- c.PickupDirectoryMessagesRetrievedTotal
+	ch <- c.RemoteQueueLength
+	ch <- c.RemoteRetryQueueLength
+	ch <- c.RoutingTableLookupsTotal
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *SMTPCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
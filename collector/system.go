@@ -10,6 +10,10 @@ import (
 
 func init() {
 	registerCollector("system", NewSystemCollector, "System")
+	registerCollectorInfo("system", CollectorInfo{
+		Description: "System calls",
+		Sources:     []DataSource{SourcePerflib},
+	})
 }
 
 // A SystemCollector is a Prometheus collector for WMI metrics
@@ -27,37 +31,37 @@ func NewSystemCollector() (Collector, error) {
 	const subsystem = "system"
 
 	return &SystemCollector{
-		ContextSwitchesTotal: prometheus.NewDesc(
+		ContextSwitchesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "context_switches_total"),
 			"Total number of context switches (WMI source: PerfOS_System.ContextSwitchesPersec)",
 			nil,
 			nil,
 		),
-		ExceptionDispatchesTotal: prometheus.NewDesc(
+		ExceptionDispatchesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "exception_dispatches_total"),
 			"Total number of exceptions dispatched (WMI source: PerfOS_System.ExceptionDispatchesPersec)",
 			nil,
 			nil,
 		),
-		ProcessorQueueLength: prometheus.NewDesc(
+		ProcessorQueueLength: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "processor_queue_length"),
 			"Length of processor queue (WMI source: PerfOS_System.ProcessorQueueLength)",
 			nil,
 			nil,
 		),
-		SystemCallsTotal: prometheus.NewDesc(
+		SystemCallsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "system_calls_total"),
 			"Total number of system calls (WMI source: PerfOS_System.SystemCallsPersec)",
 			nil,
 			nil,
 		),
-		SystemUpTime: prometheus.NewDesc(
+		SystemUpTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "system_up_time"),
 			"System boot time (WMI source: PerfOS_System.SystemUpTime)",
 			nil,
 			nil,
 		),
-		Threads: prometheus.NewDesc(
+		Threads: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "threads"),
 			"Current number of threads (WMI source: PerfOS_System.Threads)",
 			nil,
@@ -66,6 +70,16 @@ func NewSystemCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *SystemCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ContextSwitchesTotal
+	ch <- c.ExceptionDispatchesTotal
+	ch <- c.ProcessorQueueLength
+	ch <- c.SystemCallsTotal
+	ch <- c.SystemUpTime
+	ch <- c.Threads
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *SystemCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
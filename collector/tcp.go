@@ -10,6 +10,10 @@ import (
 
 func init() {
 	registerCollector("tcp", NewTCPCollector, "TCPv4", "TCPv6")
+	registerCollectorInfo("tcp", CollectorInfo{
+		Description: "TCP connections",
+		Sources:     []DataSource{SourcePerflib},
+	})
 }
 
 // A TCPCollector is a Prometheus collector for WMI Win32_PerfRawData_Tcpip_TCPv{4,6} metrics
@@ -30,55 +34,55 @@ func NewTCPCollector() (Collector, error) {
 	const subsystem = "tcp"
 
 	return &TCPCollector{
-		ConnectionFailures: prometheus.NewDesc(
+		ConnectionFailures: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_failures_total"),
 			"(TCP.ConnectionFailures)",
 			[]string{"af"},
 			nil,
 		),
-		ConnectionsActive: prometheus.NewDesc(
+		ConnectionsActive: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connections_active_total"),
 			"(TCP.ConnectionsActive)",
 			[]string{"af"},
 			nil,
 		),
-		ConnectionsEstablished: prometheus.NewDesc(
+		ConnectionsEstablished: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connections_established"),
 			"(TCP.ConnectionsEstablished)",
 			[]string{"af"},
 			nil,
 		),
-		ConnectionsPassive: prometheus.NewDesc(
+		ConnectionsPassive: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connections_passive_total"),
 			"(TCP.ConnectionsPassive)",
 			[]string{"af"},
 			nil,
 		),
-		ConnectionsReset: prometheus.NewDesc(
+		ConnectionsReset: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connections_reset_total"),
 			"(TCP.ConnectionsReset)",
 			[]string{"af"},
 			nil,
 		),
-		SegmentsTotal: prometheus.NewDesc(
+		SegmentsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "segments_total"),
 			"(TCP.SegmentsTotal)",
 			[]string{"af"},
 			nil,
 		),
-		SegmentsReceivedTotal: prometheus.NewDesc(
+		SegmentsReceivedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "segments_received_total"),
 			"(TCP.SegmentsReceivedTotal)",
 			[]string{"af"},
 			nil,
 		),
-		SegmentsRetransmittedTotal: prometheus.NewDesc(
+		SegmentsRetransmittedTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "segments_retransmitted_total"),
 			"(TCP.SegmentsRetransmittedTotal)",
 			[]string{"af"},
 			nil,
 		),
-		SegmentsSentTotal: prometheus.NewDesc(
+		SegmentsSentTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "segments_sent_total"),
 			"(TCP.SegmentsSentTotal)",
 			[]string{"af"},
@@ -87,6 +91,19 @@ func NewTCPCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *TCPCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ConnectionFailures
+	ch <- c.ConnectionsActive
+	ch <- c.ConnectionsEstablished
+	ch <- c.ConnectionsPassive
+	ch <- c.ConnectionsReset
+	ch <- c.SegmentsTotal
+	ch <- c.SegmentsReceivedTotal
+	ch <- c.SegmentsRetransmittedTotal
+	ch <- c.SegmentsSentTotal
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *TCPCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
@@ -8,11 +8,15 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("teradici_pcoip", newTeradiciPcoipCollector)
+	registerCollectorInfo("teradici_pcoip", CollectorInfo{
+		Description: "Teradici PCoIP session metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "Teradici PCoIP agent",
+	})
 }
 
 // A teradiciPcoipCollector is a Prometheus collector for WMI metrics:
@@ -70,227 +74,227 @@ type teradiciPcoipCollector struct {
 func newTeradiciPcoipCollector() (Collector, error) {
 	const subsystem = "teradici_pcoip"
 	return &teradiciPcoipCollector{
-		AudioBytesReceived: prometheus.NewDesc(
+		AudioBytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "audio_bytes_received_total"),
 			"(AudioBytesReceived)",
 			nil,
 			nil,
 		),
-		AudioBytesSent: prometheus.NewDesc(
+		AudioBytesSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "audio_bytes_sent_total"),
 			"(AudioBytesSent)",
 			nil,
 			nil,
 		),
-		AudioRXBWkbitPersec: prometheus.NewDesc(
+		AudioRXBWkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "audio_rx_bw_kbit_persec"),
 			"(AudioRXBWkbitPersec)",
 			nil,
 			nil,
 		),
-		AudioTXBWkbitPersec: prometheus.NewDesc(
+		AudioTXBWkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "audio_tx_bw_kbit_persec"),
 			"(AudioTXBWkbitPersec)",
 			nil,
 			nil,
 		),
-		AudioTXBWLimitkbitPersec: prometheus.NewDesc(
+		AudioTXBWLimitkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "audio_tx_bw_limit_kbit_persec"),
 			"(AudioTXBWLimitkbitPersec)",
 			nil,
 			nil,
 		),
 
-		BytesReceived: prometheus.NewDesc(
+		BytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bytes_received_total"),
 			"(BytesReceived)",
 			nil,
 			nil,
 		),
-		BytesSent: prometheus.NewDesc(
+		BytesSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "bytes_sent_total"),
 			"(BytesSent)",
 			nil,
 			nil,
 		),
-		PacketsReceived: prometheus.NewDesc(
+		PacketsReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_received_total"),
 			"(PacketsReceived)",
 			nil,
 			nil,
 		),
-		PacketsSent: prometheus.NewDesc(
+		PacketsSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "packets_sent_total"),
 			"(PacketsSent)",
 			nil,
 			nil,
 		),
-		RXPacketsLost: prometheus.NewDesc(
+		RXPacketsLost: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rx_packets_lost_total"),
 			"(RXPacketsLost)",
 			nil,
 			nil,
 		),
-		SessionDurationSeconds: prometheus.NewDesc(
+		SessionDurationSeconds: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_duration_seconds_total"),
 			"(SessionDurationSeconds)",
 			nil,
 			nil,
 		),
-		TXPacketsLost: prometheus.NewDesc(
+		TXPacketsLost: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "tx_packets_lost_total"),
 			"(TXPacketsLost)",
 			nil,
 			nil,
 		),
 
-		ImagingActiveMinimumQuality: prometheus.NewDesc(
+		ImagingActiveMinimumQuality: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_active_min_quality"),
 			"(ImagingActiveMinimumQuality)",
 			nil,
 			nil,
 		),
-		ImagingApex2800Offload: prometheus.NewDesc(
+		ImagingApex2800Offload: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_apex2800_offload"),
 			"(ImagingApex2800Offload)",
 			nil,
 			nil,
 		),
-		ImagingBytesReceived: prometheus.NewDesc(
+		ImagingBytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_bytes_received_total"),
 			"(ImagingBytesReceived)",
 			nil,
 			nil,
 		),
-		ImagingBytesSent: prometheus.NewDesc(
+		ImagingBytesSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_bytes_sent_total"),
 			"(ImagingBytesSent)",
 			nil,
 			nil,
 		),
-		ImagingDecoderCapabilitykbitPersec: prometheus.NewDesc(
+		ImagingDecoderCapabilitykbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_decoder_capability_kbit_persec"),
 			"(ImagingDecoderCapabilitykbitPersec)",
 			nil,
 			nil,
 		),
-		ImagingEncodedFramesPersec: prometheus.NewDesc(
+		ImagingEncodedFramesPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_encoded_frames_persec"),
 			"(ImagingEncodedFramesPersec)",
 			nil,
 			nil,
 		),
-		ImagingMegapixelPersec: prometheus.NewDesc(
+		ImagingMegapixelPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_megapixel_persec"),
 			"(ImagingMegapixelPersec)",
 			nil,
 			nil,
 		),
-		ImagingNegativeAcknowledgements: prometheus.NewDesc(
+		ImagingNegativeAcknowledgements: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_negative_acks_total"),
 			"(ImagingNegativeAcknowledgements)",
 			nil,
 			nil,
 		),
-		ImagingRXBWkbitPersec: prometheus.NewDesc(
+		ImagingRXBWkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_rx_bw_kbit_persec"),
 			"(ImagingRXBWkbitPersec)",
 			nil,
 			nil,
 		),
-		ImagingSVGAdevTapframesPersec: prometheus.NewDesc(
+		ImagingSVGAdevTapframesPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_svga_devtap_frames_persec"),
 			"(ImagingSVGAdevTapframesPersec)",
 			nil,
 			nil,
 		),
-		ImagingTXBWkbitPersec: prometheus.NewDesc(
+		ImagingTXBWkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_tx_bw_kbit_persec"),
 			"(ImagingTXBWkbitPersec)",
 			nil,
 			nil,
 		),
 
-		RoundTripLatencyms: prometheus.NewDesc(
+		RoundTripLatencyms: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "round_trip_latency_ms"),
 			"(RoundTripLatencyms)",
 			nil,
 			nil,
 		),
-		RXBWkbitPersec: prometheus.NewDesc(
+		RXBWkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rx_bw_kbit_persec"),
 			"(RXBWkbitPersec)",
 			nil,
 			nil,
 		),
-		RXBWPeakkbitPersec: prometheus.NewDesc(
+		RXBWPeakkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rx_bw_peak_kbit_persec"),
 			"(RXBWPeakkbitPersec)",
 			nil,
 			nil,
 		),
-		RXPacketLossPercent: prometheus.NewDesc(
+		RXPacketLossPercent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rx_packet_loss_percent"),
 			"(RXPacketLossPercent)",
 			nil,
 			nil,
 		),
-		RXPacketLossPercent_Base: prometheus.NewDesc(
+		RXPacketLossPercent_Base: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rx_packet_loss_percent_base"),
 			"(RXPacketLossPercent_Base)",
 			nil,
 			nil,
 		),
-		TXBWActiveLimitkbitPersec: prometheus.NewDesc(
+		TXBWActiveLimitkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "tx_bw_active_limit_kbit_persec"),
 			"(TXBWActiveLimitkbitPersec)",
 			nil,
 			nil,
 		),
-		TXBWkbitPersec: prometheus.NewDesc(
+		TXBWkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "tx_bw_kbit_persec"),
 			"(TXBWkbitPersec)",
 			nil,
 			nil,
 		),
-		TXBWLimitkbitPersec: prometheus.NewDesc(
+		TXBWLimitkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "tx_bw_limit_kbit_persec"),
 			"(TXBWLimitkbitPersec)",
 			nil,
 			nil,
 		),
-		TXPacketLossPercent: prometheus.NewDesc(
+		TXPacketLossPercent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "tx_packet_loss_percent"),
 			"(TXPacketLossPercent)",
 			nil,
 			nil,
 		),
-		TXPacketLossPercent_Base: prometheus.NewDesc(
+		TXPacketLossPercent_Base: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "tx_packet_loss_percent_base"),
 			"(TXPacketLossPercent_Base)",
 			nil,
 			nil,
 		),
 
-		USBBytesReceived: prometheus.NewDesc(
+		USBBytesReceived: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "usb_bytes_received_total"),
 			"(USBBytesReceived)",
 			nil,
 			nil,
 		),
-		USBBytesSent: prometheus.NewDesc(
+		USBBytesSent: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "usb_bytes_sent_total"),
 			"(USBBytesSent)",
 			nil,
 			nil,
 		),
-		USBRXBWkbitPersec: prometheus.NewDesc(
+		USBRXBWkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "usb_rx_bw_kbit_persec"),
 			"(USBRXBWkbitPersec)",
 			nil,
 			nil,
 		),
-		USBTXBWkbitPersec: prometheus.NewDesc(
+		USBTXBWkbitPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "usb_tx_bw_kbit_persec"),
 			"(USBTXBWkbitPersec)",
 			nil,
@@ -299,26 +303,67 @@ func newTeradiciPcoipCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *teradiciPcoipCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.AudioBytesReceived
+	ch <- c.AudioBytesSent
+	ch <- c.AudioRXBWkbitPersec
+	ch <- c.AudioTXBWkbitPersec
+	ch <- c.AudioTXBWLimitkbitPersec
+	ch <- c.BytesReceived
+	ch <- c.BytesSent
+	ch <- c.PacketsReceived
+	ch <- c.PacketsSent
+	ch <- c.RXPacketsLost
+	ch <- c.SessionDurationSeconds
+	ch <- c.TXPacketsLost
+	ch <- c.ImagingActiveMinimumQuality
+	ch <- c.ImagingApex2800Offload
+	ch <- c.ImagingBytesReceived
+	ch <- c.ImagingBytesSent
+	ch <- c.ImagingDecoderCapabilitykbitPersec
+	ch <- c.ImagingEncodedFramesPersec
+	ch <- c.ImagingMegapixelPersec
+	ch <- c.ImagingNegativeAcknowledgements
+	ch <- c.ImagingRXBWkbitPersec
+	ch <- c.ImagingSVGAdevTapframesPersec
+	ch <- c.ImagingTXBWkbitPersec
+	ch <- c.RoundTripLatencyms
+	ch <- c.RXBWkbitPersec
+	ch <- c.RXBWPeakkbitPersec
+	ch <- c.RXPacketLossPercent
+	ch <- c.RXPacketLossPercent_Base
+	ch <- c.TXBWActiveLimitkbitPersec
+	ch <- c.TXBWkbitPersec
+	ch <- c.TXBWLimitkbitPersec
+	ch <- c.TXPacketLossPercent
+	ch <- c.TXPacketLossPercent_Base
+	ch <- c.USBBytesReceived
+	ch <- c.USBBytesSent
+	ch <- c.USBRXBWkbitPersec
+	ch <- c.USBTXBWkbitPersec
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *teradiciPcoipCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collectAudio(ch); err != nil {
+	if desc, err := c.collectAudio(ctx, ch); err != nil {
 		log.Error("failed collecting teradici session audio metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectGeneral(ch); err != nil {
+	if desc, err := c.collectGeneral(ctx, ch); err != nil {
 		log.Error("failed collecting teradici session general metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectImaging(ch); err != nil {
+	if desc, err := c.collectImaging(ctx, ch); err != nil {
 		log.Error("failed collecting teradici session imaging metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectNetwork(ch); err != nil {
+	if desc, err := c.collectNetwork(ctx, ch); err != nil {
 		log.Error("failed collecting teradici session network metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectUsb(ch); err != nil {
+	if desc, err := c.collectUsb(ctx, ch); err != nil {
 		log.Error("failed collecting teradici session USB metrics:", desc, err)
 		return err
 	}
@@ -377,10 +422,10 @@ type win32_PerfRawData_TeradiciPerf_PCoIPSessionUsbStatistics struct {
 	USBTXBWkbitPersec uint64
 }
 
-func (c *teradiciPcoipCollector) collectAudio(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *teradiciPcoipCollector) collectAudio(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_TeradiciPerf_PCoIPSessionAudioStatistics
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -420,10 +465,10 @@ func (c *teradiciPcoipCollector) collectAudio(ch chan<- prometheus.Metric) (*pro
 	return nil, nil
 }
 
-func (c *teradiciPcoipCollector) collectGeneral(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *teradiciPcoipCollector) collectGeneral(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_TeradiciPerf_PCoIPSessionGeneralStatistics
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -475,10 +520,10 @@ func (c *teradiciPcoipCollector) collectGeneral(ch chan<- prometheus.Metric) (*p
 	return nil, nil
 }
 
-func (c *teradiciPcoipCollector) collectImaging(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *teradiciPcoipCollector) collectImaging(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_TeradiciPerf_PCoIPSessionImagingStatistics
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -554,10 +599,10 @@ func (c *teradiciPcoipCollector) collectImaging(ch chan<- prometheus.Metric) (*p
 	return nil, nil
 }
 
-func (c *teradiciPcoipCollector) collectNetwork(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *teradiciPcoipCollector) collectNetwork(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_TeradiciPerf_PCoIPSessionNetworkStatistics
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -627,10 +672,10 @@ func (c *teradiciPcoipCollector) collectNetwork(ch chan<- prometheus.Metric) (*p
 	return nil, nil
 }
 
-func (c *teradiciPcoipCollector) collectUsb(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *teradiciPcoipCollector) collectUsb(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_TeradiciPerf_PCoIPSessionUsbStatistics
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
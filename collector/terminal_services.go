@@ -16,6 +16,11 @@ const ConnectionBrokerFeatureID uint32 = 133
 
 func init() {
 	registerCollector("terminal_services", NewTerminalServicesCollector, "Terminal Services", "Terminal Services Session", "Remote Desktop Connection Broker Counterset")
+	registerCollectorInfo("terminal_services", CollectorInfo{
+		Description: "Terminal services (RDS)",
+		Sources:     []DataSource{SourcePerflib, SourceWMI},
+		Requires:    "Remote Desktop Services",
+	})
 }
 
 var (
@@ -69,103 +74,103 @@ type TerminalServicesCollector struct {
 func NewTerminalServicesCollector() (Collector, error) {
 	const subsystem = "terminal_services"
 	return &TerminalServicesCollector{
-		LocalSessionCount: prometheus.NewDesc(
+		LocalSessionCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "local_session_count"),
 			"Number of Terminal Services sessions",
 			[]string{"session"},
 			nil,
 		),
-		ConnectionBrokerPerformance: prometheus.NewDesc(
+		ConnectionBrokerPerformance: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "connection_broker_performance_total"),
 			"The total number of connections handled by the Connection Brokers since the service started.",
 			[]string{"connection"},
 			nil,
 		),
-		HandleCount: prometheus.NewDesc(
+		HandleCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "handles"),
 			"Total number of handles currently opened by this process. This number is the sum of the handles currently opened by each thread in this process.",
 			[]string{"session_name"},
 			nil,
 		),
-		PageFaultsPersec: prometheus.NewDesc(
+		PageFaultsPersec: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "page_fault_total"),
 			"Rate at which page faults occur in the threads executing in this process. A page fault occurs when a thread refers to a virtual memory page that is not in its working set in main memory. The page may not be retrieved from disk if it is on the standby list and therefore already in main memory. The page also may not be retrieved if it is in use by another process which shares the page.",
 			[]string{"session_name"},
 			nil,
 		),
-		PageFileBytes: prometheus.NewDesc(
+		PageFileBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "page_file_bytes"),
 			"Current number of bytes this process has used in the paging file(s). Paging files are used to store pages of memory used by the process that are not contained in other files. Paging files are shared by all processes, and lack of space in paging files can prevent other processes from allocating memory.",
 			[]string{"session_name"},
 			nil,
 		),
-		PageFileBytesPeak: prometheus.NewDesc(
+		PageFileBytesPeak: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "page_file_bytes_peak"),
 			"Maximum number of bytes this process has used in the paging file(s). Paging files are used to store pages of memory used by the process that are not contained in other files. Paging files are shared by all processes, and lack of space in paging files can prevent other processes from allocating memory.",
 			[]string{"session_name"},
 			nil,
 		),
-		PercentPrivilegedTime: prometheus.NewDesc(
+		PercentPrivilegedTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "privileged_time_seconds_total"),
 			"Total elapsed time that the threads of the process have spent executing code in privileged mode.",
 			[]string{"session_name"},
 			nil,
 		),
-		PercentProcessorTime: prometheus.NewDesc(
+		PercentProcessorTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "processor_time_seconds_total"),
 			"Total elapsed time that all of the threads of this process used the processor to execute instructions.",
 			[]string{"session_name"},
 			nil,
 		),
-		PercentUserTime: prometheus.NewDesc(
+		PercentUserTime: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "user_time_seconds_total"),
 			"Total elapsed time that this process's threads have spent executing code in user mode. Applications, environment subsystems, and integral subsystems execute in user mode.",
 			[]string{"session_name"},
 			nil,
 		),
-		PoolNonpagedBytes: prometheus.NewDesc(
+		PoolNonpagedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pool_non_paged_bytes"),
 			"Number of bytes in the non-paged pool, an area of system memory (physical memory used by the operating system) for objects that cannot be written to disk, but must remain in physical memory as long as they are allocated. This property displays the last observed value only; it is not an average.",
 			[]string{"session_name"},
 			nil,
 		),
-		PoolPagedBytes: prometheus.NewDesc(
+		PoolPagedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "pool_paged_bytes"),
 			"Number of bytes in the paged pool, an area of system memory (physical memory used by the operating system) for objects that can be written to disk when they are not being used. This property displays the last observed value only; it is not an average.",
 			[]string{"session_name"},
 			nil,
 		),
-		PrivateBytes: prometheus.NewDesc(
+		PrivateBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "private_bytes"),
 			"Current number of bytes this process has allocated that cannot be shared with other processes.",
 			[]string{"session_name"},
 			nil,
 		),
-		ThreadCount: prometheus.NewDesc(
+		ThreadCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "threads"),
 			"Number of threads currently active in this process. An instruction is the basic unit of execution in a processor, and a thread is the object that executes instructions. Every running process has at least one thread.",
 			[]string{"session_name"},
 			nil,
 		),
-		VirtualBytes: prometheus.NewDesc(
+		VirtualBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "virtual_bytes"),
 			"Current size, in bytes, of the virtual address space the process is using. Use of virtual address space does not necessarily imply corresponding use of either disk or main memory pages. Virtual space is finite and, by using too much, the process can limit its ability to load libraries.",
 			[]string{"session_name"},
 			nil,
 		),
-		VirtualBytesPeak: prometheus.NewDesc(
+		VirtualBytesPeak: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "virtual_bytes_peak"),
 			"Maximum number of bytes of virtual address space the process has used at any one time. Use of virtual address space does not necessarily imply corresponding use of either disk or main memory pages. Virtual space is finite and, by using too much, the process might limit its ability to load libraries.",
 			[]string{"session_name"},
 			nil,
 		),
-		WorkingSet: prometheus.NewDesc(
+		WorkingSet: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "working_set_bytes"),
 			"Current number of bytes in the working set of this process. The working set is the set of memory pages touched recently by the threads in the process. If free memory in the computer is above a threshold, pages are left in the working set of a process even if they are not in use. When free memory falls below a threshold, pages are trimmed from working sets. If they are needed, they are then soft-faulted back into the working set before they leave main memory.",
 			[]string{"session_name"},
 			nil,
 		),
-		WorkingSetPeak: prometheus.NewDesc(
+		WorkingSetPeak: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "working_set_bytes_peak"),
 			"Maximum number of bytes in the working set of this process at any point in time. The working set is the set of memory pages touched recently by the threads in the process. If free memory in the computer is above a threshold, pages are left in the working set of a process even if they are not in use. When free memory falls below a threshold, pages are trimmed from working sets. If they are needed, they are then soft-faulted back into the working set before they leave main memory.",
 			[]string{"session_name"},
@@ -174,6 +179,27 @@ func NewTerminalServicesCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *TerminalServicesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.LocalSessionCount
+	ch <- c.ConnectionBrokerPerformance
+	ch <- c.HandleCount
+	ch <- c.PageFaultsPersec
+	ch <- c.PageFileBytes
+	ch <- c.PageFileBytesPeak
+	ch <- c.PercentPrivilegedTime
+	ch <- c.PercentProcessorTime
+	ch <- c.PercentUserTime
+	ch <- c.PoolNonpagedBytes
+	ch <- c.PoolPagedBytes
+	ch <- c.PrivateBytes
+	ch <- c.ThreadCount
+	ch <- c.VirtualBytes
+	ch <- c.VirtualBytesPeak
+	ch <- c.WorkingSet
+	ch <- c.WorkingSetPeak
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *TerminalServicesCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
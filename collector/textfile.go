@@ -17,147 +17,317 @@
 package collector
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	kingpin "github.com/alecthomas/kingpin/v2"
 	"github.com/dimchansky/utfbom"
+	"github.com/prometheus-community/windows_exporter/config"
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 )
 
-var (
-	textFileDirectory = kingpin.Flag(
-		"collector.textfile.directory",
-		"Directory to read text files with metrics from.",
-	).Default(getDefaultPath()).String()
+// The flags of the textfile collector.
+const (
+	textFileDirectoryFlag  = "collector.textfile.directory"
+	textFileMaxAgeFlag     = "collector.textfile.max-age"
+	textFileTimestampsFlag = "collector.textfile.timestamps"
+)
 
-	mtimeDesc = prometheus.NewDesc(
+var (
+	mtimeDesc = newDesc(
 		prometheus.BuildFQName(Namespace, "textfile", "mtime_seconds"),
 		"Unixtime mtime of textfiles successfully read.",
-		[]string{"file"},
+		[]string{"directory", "file"},
+		nil,
+	)
+	scrapeErrorDesc = newDesc(
+		prometheus.BuildFQName(Namespace, "textfile", "scrape_error"),
+		"1 if there was an error opening or reading a file of the directory, 0 otherwise",
+		[]string{"directory"},
+		nil,
+	)
+	fileErrorDesc = newDesc(
+		prometheus.BuildFQName(Namespace, "textfile", "file_error"),
+		"1 for each file whose metrics were skipped, with the reason why",
+		[]string{"directory", "file", "reason"},
+		nil,
+	)
+	familyConflictDesc = newDesc(
+		prometheus.BuildFQName(Namespace, "textfile", "family_conflict"),
+		"1 for each file holding a metric family with another help or type than in the files read before",
+		[]string{"directory", "family", "file", "field"},
+		nil,
+	)
+	staleFilesDesc = newDesc(
+		prometheus.BuildFQName(Namespace, "textfile", "stale_files"),
+		"Number of files of the directory skipped because they are older than their maximum age",
+		[]string{"directory"},
+		nil,
+	)
+	futureFilesDesc = newDesc(
+		prometheus.BuildFQName(Namespace, "textfile", "future_mtime_files"),
+		"Number of files of the directory with a modification time in the future",
+		[]string{"directory"},
 		nil,
 	)
 )
 
 type textFileCollector struct {
-	path string
+	// directories are the directories and glob patterns to read files from.
+	directories []string
+	// maxAge is the age of files after which they are skipped, unless they
+	// set their own. 0 means no maximum.
+	maxAge time.Duration
+	// timestamps is whether the timestamps of samples are kept, unless files
+	// set it themselves. Files with timestamps are skipped otherwise.
+	timestamps bool
 	// Only set for testing to get predictable output.
 	mtime *float64
 }
 
+// textFile is a file read by the textfile collector.
+type textFile struct {
+	path string
+	// directory is the directory the file was found in, which labels its
+	// series and errors.
+	directory string
+	modTime   time.Time
+}
+
 func init() {
-	registerCollector("textfile", NewTextFileCollector)
+	registerCollectorWithSettings("textfile", NewTextFileCollector)
+	registerCollectorInfo("textfile", CollectorInfo{
+		Description: "Read prometheus metrics from a text file",
+		Sources:     []DataSource{SourceFile},
+	})
+
+	kingpin.Flag(
+		textFileDirectoryFlag,
+		"Comma-separated list of directories, or glob patterns matching directories or files, to read text files with metrics from.",
+	).Default(getDefaultPath()).String()
+	kingpin.Flag(
+		textFileMaxAgeFlag,
+		"Maximum age of the files read, after which their metrics are no longer exposed. 0 disables the check. Files may override it with a \"# windows_exporter: max-age=<duration>\" header.",
+	).Default("0s").Duration()
+	kingpin.Flag(
+		textFileTimestampsFlag,
+		"If true, keep the timestamps of samples instead of skipping files with timestamps. Files may override it with a \"# windows_exporter: timestamps=<bool>\" header.",
+	).Default("false").Bool()
 }
 
 // NewTextFileCollector returns a new Collector exposing metrics read from files
 // in the given textfile directory.
-func NewTextFileCollector() (Collector, error) {
+func NewTextFileCollector(settings *config.Config) (Collector, error) {
+	var directories []string
+	for _, directory := range settings.Collector.Textfile.Directories {
+		if directory = strings.TrimSpace(directory); directory != "" {
+			directories = append(directories, directory)
+		}
+	}
 	return &textFileCollector{
-		path: *textFileDirectory,
+		directories: directories,
+		maxAge:      settings.Collector.Textfile.MaxAge,
+		timestamps:  settings.Collector.Textfile.Timestamps,
 	}, nil
 }
 
+// Reasons why the metrics of a file are skipped, reported by
+// windows_textfile_file_error.
+const (
+	fileErrorRead       = "read"
+	fileErrorHeader     = "header"
+	fileErrorParse      = "parse"
+	fileErrorTimestamps = "timestamps"
+	// fileErrorDuplicate is reported for files holding a series twice, or a
+	// series already read from another file.
+	fileErrorDuplicate = "duplicate"
+	// fileErrorConflict is reported for files holding a metric family also
+	// read from another file, with a different type.
+	fileErrorConflict = "conflict"
+)
+
+// familyConflict is a metric family read from a file with another help or
+// type than in the files read before.
+type familyConflict struct {
+	family string
+	file   textFile
+	// field is the field which differs, help or type.
+	field string
+}
+
+// seriesKey identifies a series of the named metric family. Labels with an
+// empty value are left out, as they are the same as missing labels.
+func seriesKey(name string, metric *dto.Metric) string {
+	pairs := make([]string, 0, len(metric.GetLabel()))
+	for _, label := range metric.GetLabel() {
+		if label.GetValue() != "" {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", label.GetName(), label.GetValue()))
+		}
+	}
+	sort.Strings(pairs)
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
 // Given a slice of metric families, determine if any two entries are duplicates.
 // Duplicates will be detected where the metric name, labels and label values are identical.
 func duplicateMetricEntry(metricFamilies []*dto.MetricFamily) bool {
-	uniqueMetrics := make(map[string]map[string]string)
+	seen := make(map[string]bool)
 	for _, metricFamily := range metricFamilies {
-		metric_name := *metricFamily.Name
 		for _, metric := range metricFamily.Metric {
-			metric_labels := metric.GetLabel()
-			labels := make(map[string]string)
-			for _, label := range metric_labels {
-				labels[label.GetName()] = label.GetValue()
-			}
-			// Check if key is present before appending
-			_, mapContainsKey := uniqueMetrics[metric_name]
-
-			// Duplicate metric found with identical labels & label values
-			if mapContainsKey == true && reflect.DeepEqual(uniqueMetrics[metric_name], labels) {
+			key := seriesKey(metricFamily.GetName(), metric)
+			if seen[key] {
 				return true
 			}
-			uniqueMetrics[metric_name] = labels
+			seen[key] = true
 		}
 	}
 	return false
 }
 
-func convertMetricFamily(metricFamily *dto.MetricFamily, ch chan<- prometheus.Metric) {
-	var valType prometheus.ValueType
-	var val float64
+// textFileFamilies merges the metric families read from several files.
+type textFileFamilies struct {
+	families map[string]*mergedFamily
+	// names holds the names of the families in the order they were read.
+	names []string
+	// series maps the series read to the file they were read from.
+	series map[string]string
+	// conflicts are the families read with another help or type than in the
+	// files read before.
+	conflicts []familyConflict
+}
 
-	allLabelNames := map[string]struct{}{}
-	for _, metric := range metricFamily.Metric {
-		labels := metric.GetLabel()
-		for _, label := range labels {
-			if _, ok := allLabelNames[label.GetName()]; !ok {
-				allLabelNames[label.GetName()] = struct{}{}
+// mergedFamily is a metric family merged from one or more files.
+type mergedFamily struct {
+	*dto.MetricFamily
+	// file is the first file the family was read from.
+	file string
+	// hasHelp is whether a file gave the help of the family, which is made up
+	// otherwise.
+	hasHelp bool
+}
+
+func newTextFileFamilies() *textFileFamilies {
+	return &textFileFamilies{
+		families: make(map[string]*mergedFamily),
+		series:   make(map[string]string),
+	}
+}
+
+// add merges the families read from the file f. Nothing is merged if
+// the file holds a series already read from another file, or a family with
+// another type than the one read from another file; the reason is returned
+// with the error. Families with another help are merged under the help read
+// first, and the conflict is recorded.
+func (m *textFileFamilies) add(f textFile, families map[string]*dto.MetricFamily) (string, error) {
+	path := f.path
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var helpConflicts []string
+	for _, name := range names {
+		mf := families[name]
+		if merged, ok := m.families[name]; ok {
+			if mf.GetType() != merged.GetType() {
+				m.conflicts = append(m.conflicts, familyConflict{family: name, file: f, field: "type"})
+				return fileErrorConflict, fmt.Errorf("metric %s has type %s, but %s in %q", name, mf.GetType(), merged.GetType(), merged.file)
+			}
+			if mf.Help != nil && merged.hasHelp && mf.GetHelp() != merged.GetHelp() {
+				helpConflicts = append(helpConflicts, name)
+			}
+		}
+		for _, metric := range mf.Metric {
+			if file, ok := m.series[seriesKey(name, metric)]; ok {
+				return fileErrorDuplicate, fmt.Errorf("series %s was already read from %q", seriesKey(name, metric), file)
 			}
 		}
 	}
 
-	for _, metric := range metricFamily.Metric {
-		if metric.TimestampMs != nil {
-			log.Warnf("Ignoring unsupported custom timestamp on textfile collector metric %v", metric)
-		}
+	for _, name := range helpConflicts {
+		merged := m.families[name]
+		log.Warnf("Metric %s of textfile %q has help %q, but %q in %q, keeping the latter", name, path, families[name].GetHelp(), merged.GetHelp(), merged.file)
+		m.conflicts = append(m.conflicts, familyConflict{family: name, file: f, field: "help"})
+	}
 
-		labels := metric.GetLabel()
-		var names []string
-		var values []string
-		for _, label := range labels {
-			names = append(names, label.GetName())
-			values = append(values, label.GetValue())
+	for _, name := range names {
+		mf := families[name]
+		for _, metric := range mf.Metric {
+			m.series[seriesKey(name, metric)] = path
 		}
-
-		for k := range allLabelNames {
-			present := false
-			for _, name := range names {
-				if k == name {
-					present = true
-					break
-				}
+		merged, ok := m.families[name]
+		if !ok {
+			merged = &mergedFamily{MetricFamily: mf, file: path, hasHelp: mf.Help != nil}
+			if mf.Help == nil {
+				help := fmt.Sprintf("Metric read from %s", path)
+				mf.Help = &help
 			}
-			if present == false {
-				names = append(names, k)
-				values = append(values, "")
+			m.families[name] = merged
+			m.names = append(m.names, name)
+			continue
+		}
+		if !merged.hasHelp && mf.Help != nil {
+			merged.Help, merged.hasHelp = mf.Help, true
+		}
+		merged.Metric = append(merged.Metric, mf.Metric...)
+	}
+	return "", nil
+}
+
+// convertMetricFamily sends the series of metricFamily to ch. They share a
+// single descriptor, whose labels are those of all the series, sorted by name;
+// series without one of the labels have it set to an empty value.
+func convertMetricFamily(metricFamily *dto.MetricFamily, ch chan<- prometheus.Metric) {
+	var names []string
+	seen := map[string]bool{}
+	for _, metric := range metricFamily.Metric {
+		for _, label := range metric.GetLabel() {
+			if !seen[label.GetName()] {
+				seen[label.GetName()] = true
+				names = append(names, label.GetName())
 			}
 		}
+	}
+	sort.Strings(names)
+	desc := prometheus.NewDesc(metricFamily.GetName(), metricFamily.GetHelp(), names, nil)
 
-		metricType := metricFamily.GetType()
-		switch metricType {
-		case dto.MetricType_COUNTER:
-			valType = prometheus.CounterValue
-			val = metric.Counter.GetValue()
+	for _, metric := range metricFamily.Metric {
+		labels := make(map[string]string, len(metric.GetLabel()))
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = labels[name]
+		}
 
+		var m prometheus.Metric
+		switch metricFamily.GetType() {
+		case dto.MetricType_COUNTER:
+			m = prometheus.MustNewConstMetric(desc, prometheus.CounterValue, metric.Counter.GetValue(), values...)
 		case dto.MetricType_GAUGE:
-			valType = prometheus.GaugeValue
-			val = metric.Gauge.GetValue()
-
+			m = prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, metric.Gauge.GetValue(), values...)
 		case dto.MetricType_UNTYPED:
-			valType = prometheus.UntypedValue
-			val = metric.Untyped.GetValue()
-
+			m = prometheus.MustNewConstMetric(desc, prometheus.UntypedValue, metric.Untyped.GetValue(), values...)
 		case dto.MetricType_SUMMARY:
 			quantiles := map[float64]float64{}
 			for _, q := range metric.Summary.Quantile {
 				quantiles[q.GetQuantile()] = q.GetValue()
 			}
-			ch <- prometheus.MustNewConstSummary(
-				prometheus.NewDesc(
-					*metricFamily.Name,
-					metricFamily.GetHelp(),
-					names, nil,
-				),
+			m = prometheus.MustNewConstSummary(
+				desc,
 				metric.Summary.GetSampleCount(),
 				metric.Summary.GetSampleSum(),
 				quantiles, values...,
@@ -167,12 +337,8 @@ func convertMetricFamily(metricFamily *dto.MetricFamily, ch chan<- prometheus.Me
 			for _, b := range metric.Histogram.Bucket {
 				buckets[b.GetUpperBound()] = b.GetCumulativeCount()
 			}
-			ch <- prometheus.MustNewConstHistogram(
-				prometheus.NewDesc(
-					*metricFamily.Name,
-					metricFamily.GetHelp(),
-					names, nil,
-				),
+			m = prometheus.MustNewConstHistogram(
+				desc,
 				metric.Histogram.GetSampleCount(),
 				metric.Histogram.GetSampleSum(),
 				buckets, values...,
@@ -181,37 +347,97 @@ func convertMetricFamily(metricFamily *dto.MetricFamily, ch chan<- prometheus.Me
 			log.Errorf("unknown metric type for file")
 			continue
 		}
-		if metricType == dto.MetricType_GAUGE || metricType == dto.MetricType_COUNTER || metricType == dto.MetricType_UNTYPED {
-			ch <- prometheus.MustNewConstMetric(
-				prometheus.NewDesc(
-					*metricFamily.Name,
-					metricFamily.GetHelp(),
-					names, nil,
-				),
-				valType, val, values...,
-			)
+		// Files with timestamps are only read if they are kept.
+		if metric.TimestampMs != nil {
+			m = prometheus.NewMetricWithTimestamp(time.UnixMilli(metric.GetTimestampMs()), m)
+		}
+		ch <- namedMetric{Metric: m, name: metricFamily.GetName()}
+	}
+}
+
+func (c *textFileCollector) exportMTimes(files []textFile, ch chan<- prometheus.Metric) {
+	// Sorting is needed for predictable output comparison in tests.
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	for _, f := range files {
+		mtime := float64(f.modTime.UnixNano() / 1e9)
+		if c.mtime != nil {
+			mtime = *c.mtime
 		}
+		ch <- prometheus.MustNewConstMetric(mtimeDesc, prometheus.GaugeValue, mtime, f.directory, filepath.Base(f.path))
 	}
 }
 
-func (c *textFileCollector) exportMTimes(mtimes map[string]time.Time, ch chan<- prometheus.Metric) {
-	// Export the mtimes of the successful files.
-	if len(mtimes) > 0 {
-		// Sorting is needed for predictable output comparison in tests.
-		filenames := make([]string, 0, len(mtimes))
-		for filename := range mtimes {
-			filenames = append(filenames, filename)
+// isGlob reports whether path is a glob pattern rather than a directory.
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// findFiles returns the .prom and .om files of the configured directories, and
+// of the directories matching the configured glob patterns, plus the .prom and
+// .om files matching the glob patterns. Errors are recorded by directory, or by pattern
+// if no directory is known. A file found more than once is only returned once.
+func (c *textFileCollector) findFiles(errors map[string]float64) []textFile {
+	var files []textFile
+	seen := make(map[string]bool)
+	addFile := func(path, directory string, info os.FileInfo) {
+		if !(strings.HasSuffix(info.Name(), ".prom") || strings.HasSuffix(info.Name(), ".om")) || !info.Mode().IsRegular() {
+			return
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			if seen[abs] {
+				return
+			}
+			seen[abs] = true
+		}
+		files = append(files, textFile{path: path, directory: directory, modTime: info.ModTime()})
+	}
+	readDir := func(directory string) {
+		if _, ok := errors[directory]; !ok {
+			errors[directory] = 0
+		}
+		entries, err := ioutil.ReadDir(directory)
+		if err != nil {
+			log.Errorf("Error reading textfile collector directory %q: %s", directory, err)
+			errors[directory] = 1.0
+			return
 		}
-		sort.Strings(filenames)
+		for _, info := range entries {
+			addFile(filepath.Join(directory, info.Name()), directory, info)
+		}
+	}
 
-		for _, filename := range filenames {
-			mtime := float64(mtimes[filename].UnixNano() / 1e9)
-			if c.mtime != nil {
-				mtime = *c.mtime
+	for _, pattern := range c.directories {
+		if !isGlob(pattern) {
+			readDir(pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			log.Errorf("Invalid textfile collector glob pattern %q: %s", pattern, err)
+			errors[pattern] = 1.0
+			continue
+		}
+		if len(matches) == 0 {
+			log.Debugf("Textfile collector glob pattern %q matches nothing", pattern)
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				log.Errorf("Error reading %q: %s", match, err)
+				errors[filepath.Dir(match)] = 1.0
+				continue
+			}
+			if info.IsDir() {
+				readDir(match)
+				continue
 			}
-			ch <- prometheus.MustNewConstMetric(mtimeDesc, prometheus.GaugeValue, mtime, filename)
+			if _, ok := errors[filepath.Dir(match)]; !ok {
+				errors[filepath.Dir(match)] = 0
+			}
+			addFile(match, filepath.Dir(match), info)
 		}
 	}
+	return files
 }
 
 type carriageReturnFilteringReader struct {
@@ -238,111 +464,221 @@ func (cr carriageReturnFilteringReader) Read(p []byte) (int, error) {
 	return pi, err
 }
 
+// Describe sends the descriptors of the metrics the collector emits about the
+// files it reads. The metric families of the files are only known once read.
+func (c *textFileCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- mtimeDesc
+	ch <- scrapeErrorDesc
+	ch <- fileErrorDesc
+	ch <- familyConflictDesc
+	ch <- staleFilesDesc
+	ch <- futureFilesDesc
+}
+
 // Update implements the Collector interface.
 func (c *textFileCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	error := 0.0
-	mtimes := map[string]time.Time{}
-
-	// Iterate over files and accumulate their metrics.
-	files, err := ioutil.ReadDir(c.path)
-	if err != nil && c.path != "" {
-		log.Errorf("Error reading textfile collector directory %q: %s", c.path, err)
-		error = 1.0
-	}
-
-	// Create empty metricFamily slice here and append parsedFamilies to it inside the loop.
-	// Once loop is complete, raise error if any duplicates are present.
-	// This will ensure that duplicate metrics are correctly detected between multiple .prom files.
-	var metricFamilies = []*dto.MetricFamily{}
+	// errors holds whether there was an error reading the files of each
+	// directory.
+	errors := map[string]float64{}
+	stale := map[string]float64{}
+	future := map[string]float64{}
+	var mtimes []textFile
+	now := time.Now()
+
+	// fileErrors maps the files whose metrics are skipped to the reason why.
+	fileErrors := map[textFile]string{}
+
+	// Files are merged in order, so that a file conflicting with the files
+	// read before is skipped, and the others are kept.
+	families := newTextFileFamilies()
 fileLoop:
-	for _, f := range files {
-		if !strings.HasSuffix(f.Name(), ".prom") {
+	for _, f := range c.findFiles(errors) {
+		path := f.path
+		log.Debugf("Processing file %q", path)
+		content, err := readTextFile(path)
+		if err != nil {
+			log.Errorf("%s", err)
+			errors[f.directory] = 1.0
+			fileErrors[f] = fileErrorRead
 			continue
 		}
-		path := filepath.Join(c.path, f.Name())
-		log.Debugf("Processing file %q", path)
-		file, err := os.Open(path)
+		header, err := parseHeader(content)
 		if err != nil {
-			log.Errorf("Error opening %q: %v", path, err)
-			error = 1.0
+			log.Errorf("Error parsing header of %q: %v", path, err)
+			errors[f.directory] = 1.0
+			fileErrors[f] = fileErrorHeader
 			continue
 		}
-		var parser expfmt.TextParser
-		r, encoding := utfbom.Skip(carriageReturnFilteringReader{r: file})
-		if err = checkBOM(encoding); err != nil {
-			log.Errorf("Invalid file encoding detected in %s: %s - file must be UTF8", path, err.Error())
-			error = 1.0
+
+		if f.modTime.After(now) {
+			log.Warnf("Textfile %q has a modification time in the future: %s", path, f.modTime.Format(time.RFC3339))
+			future[f.directory]++
+		}
+		maxAge := c.maxAge
+		if header.hasMaxAge {
+			maxAge = header.maxAge
+		}
+		if age := now.Sub(f.modTime); maxAge > 0 && age > maxAge {
+			log.Warnf("Textfile %q was last modified %s ago, more than its maximum age of %s, skipping", path, age.Round(time.Second), maxAge)
+			stale[f.directory]++
 			continue
 		}
-		parsedFamilies, err := parser.TextToMetricFamilies(r)
-		closeErr := file.Close()
-		if closeErr != nil {
-			log.Warnf("Error closing file: %v", err)
+
+		var parsedFamilies map[string]*dto.MetricFamily
+		if header.format == "openmetrics" || header.format == "" && strings.HasSuffix(path, ".om") {
+			parsedFamilies, err = parseOpenMetrics(content)
+		} else {
+			var parser expfmt.TextParser
+			parsedFamilies, err = parser.TextToMetricFamilies(bytes.NewReader(content))
 		}
 		if err != nil {
 			log.Errorf("Error parsing %q: %v", path, err)
-			error = 1.0
+			errors[f.directory] = 1.0
+			fileErrors[f] = fileErrorParse
 			continue
 		}
 
+		keepTimestamps := c.timestamps
+		if header.timestamps != nil {
+			keepTimestamps = *header.timestamps
+		}
+
 		// Use temporary array to check for duplicates
 		var families_array []*dto.MetricFamily
 
 		for _, mf := range parsedFamilies {
 			families_array = append(families_array, mf)
 			for _, m := range mf.Metric {
-				if m.TimestampMs != nil {
-					log.Errorf("Textfile %q contains unsupported client-side timestamps, skipping entire file", path)
-					error = 1.0
+				if m.TimestampMs != nil && !keepTimestamps {
+					log.Errorf("Textfile %q contains client-side timestamps, which are only kept with --collector.textfile.timestamps, skipping entire file", path)
+					errors[f.directory] = 1.0
+					fileErrors[f] = fileErrorTimestamps
 					continue fileLoop
 				}
 			}
-			if mf.Help == nil {
-				help := fmt.Sprintf("Metric read from %s", path)
-				mf.Help = &help
-			}
 		}
 
 		// If duplicate metrics are detected in a *single* file, skip processing of file metrics
 		if duplicateMetricEntry(families_array) {
-			log.Errorf("Duplicate metrics detected in file %s. Skipping file processing.", f.Name())
-			error = 1.0
+			log.Errorf("Duplicate metrics detected in file %s. Skipping file processing.", path)
+			errors[f.directory] = 1.0
+			fileErrors[f] = fileErrorDuplicate
+			continue
+		}
+
+		// Files conflicting with the files read before are skipped, leaving
+		// the metrics of the others.
+		if reason, err := families.add(f, parsedFamilies); err != nil {
+			log.Errorf("Textfile %q conflicts with the files read before, skipping entire file: %s", path, err)
+			errors[f.directory] = 1.0
+			fileErrors[f] = reason
 			continue
 		}
 
 		// Only set this once it has been parsed and validated, so that
 		// a failure does not appear fresh.
-		mtimes[f.Name()] = f.ModTime()
-
-		for _, metricFamily := range parsedFamilies {
-			metricFamilies = append(metricFamilies, metricFamily)
-		}
+		mtimes = append(mtimes, f)
 	}
 
-	// If duplicates are detected across *multiple* files, return error.
-	if duplicateMetricEntry(metricFamilies) {
-		log.Errorf("Duplicate metrics detected across multiple files")
-		error = 1.0
-	} else {
-		for _, mf := range metricFamilies {
-			convertMetricFamily(mf, ch)
-		}
+	for _, name := range families.names {
+		convertMetricFamily(families.families[name].MetricFamily, ch)
 	}
 
 	c.exportMTimes(mtimes, ch)
 
+	for _, conflict := range families.conflicts {
+		ch <- prometheus.MustNewConstMetric(familyConflictDesc, prometheus.GaugeValue, 1, conflict.file.directory, conflict.family, filepath.Base(conflict.file.path), conflict.field)
+	}
+	for f, reason := range fileErrors {
+		ch <- prometheus.MustNewConstMetric(fileErrorDesc, prometheus.GaugeValue, 1, f.directory, filepath.Base(f.path), reason)
+	}
+
 	// Export if there were errors.
-	ch <- prometheus.MustNewConstMetric(
-		prometheus.NewDesc(
-			prometheus.BuildFQName(Namespace, "textfile", "scrape_error"),
-			"1 if there was an error opening or reading a file, 0 otherwise",
-			nil, nil,
-		),
-		prometheus.GaugeValue, error,
-	)
+	for directory, scrapeError := range errors {
+		ch <- prometheus.MustNewConstMetric(scrapeErrorDesc, prometheus.GaugeValue, scrapeError, directory)
+		ch <- prometheus.MustNewConstMetric(staleFilesDesc, prometheus.GaugeValue, stale[directory], directory)
+		ch <- prometheus.MustNewConstMetric(futureFilesDesc, prometheus.GaugeValue, future[directory], directory)
+	}
 	return nil
 }
 
+// headerPrefix starts the header comments of a file holding settings of the
+// textfile collector, such as "# windows_exporter: max-age=1h".
+const headerPrefix = "# windows_exporter:"
+
+// textFileHeader holds the settings of a file given in its header comments.
+type textFileHeader struct {
+	maxAge    time.Duration
+	hasMaxAge bool
+	// timestamps is whether the timestamps of the file are kept, if set.
+	timestamps *bool
+	// format is the format of the file, openmetrics or text, if set.
+	format string
+}
+
+// parseHeader parses the header comments of content, which are the comments
+// before its first sample. Settings are space separated key=value pairs.
+func parseHeader(content []byte) (textFileHeader, error) {
+	var header textFileHeader
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		settings, ok := strings.CutPrefix(line, headerPrefix)
+		if !ok {
+			continue
+		}
+		for _, setting := range strings.Fields(settings) {
+			key, value, _ := strings.Cut(setting, "=")
+			switch key {
+			case "max-age":
+				d, err := time.ParseDuration(value)
+				if err != nil {
+					return header, fmt.Errorf("invalid max-age in header: %w", err)
+				}
+				header.maxAge, header.hasMaxAge = d, true
+			case "timestamps":
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return header, fmt.Errorf("invalid timestamps in header: %w", err)
+				}
+				header.timestamps = &b
+			case "format":
+				if value != "openmetrics" && value != "text" {
+					return header, fmt.Errorf("invalid format in header: %q, expected openmetrics or text", value)
+				}
+				header.format = value
+			default:
+				return header, fmt.Errorf("unknown header setting %q", key)
+			}
+		}
+	}
+	return header, nil
+}
+
+// readTextFile returns the content of the file at path, without byte order mark
+// and carriage returns.
+func readTextFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %q: %w", path, err)
+	}
+	defer file.Close()
+	r, encoding := utfbom.Skip(carriageReturnFilteringReader{r: file})
+	if err = checkBOM(encoding); err != nil {
+		return nil, fmt.Errorf("invalid file encoding detected in %s: %s - file must be UTF8", path, err.Error())
+	}
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", path, err)
+	}
+	return content, nil
+}
+
 func checkBOM(encoding utfbom.Encoding) error {
 	if encoding == utfbom.Unknown || encoding == utfbom.UTF8 {
 		return nil
@@ -0,0 +1,404 @@
+//go:build !notextfile
+// +build !notextfile
+
+package collector
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+var (
+	metricNameRE = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRE  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// openMetricsSuffixes are the suffixes of the samples of each OpenMetrics
+// type, appended to the name of their family.
+var openMetricsSuffixes = map[string][]string{
+	"counter":        {"_total", "_created"},
+	"gauge":          {""},
+	"unknown":        {""},
+	"stateset":       {""},
+	"info":           {"_info"},
+	"histogram":      {"_bucket", "_count", "_sum", "_created"},
+	"gaugehistogram": {"_bucket", "_gcount", "_gsum"},
+	"summary":        {"", "_count", "_sum", "_created"},
+}
+
+// openMetricsParser parses the OpenMetrics text format into metric families
+// named as in the Prometheus text format: counters have the _total suffix and
+// info metrics the _info suffix. Info and stateset metrics are gauges, and the
+// samples of gauge histograms are gauges of their own. _created samples and
+// exemplars are dropped.
+type openMetricsParser struct {
+	families map[string]*dto.MetricFamily
+	// seen holds the names of the OpenMetrics families parsed, which must not
+	// appear again once another family started.
+	seen map[string]bool
+	// exportedBy maps the names of the metric families parsed to the
+	// OpenMetrics family they were parsed from.
+	exportedBy map[string]string
+
+	// The OpenMetrics family being parsed.
+	name, typ, unit string
+	help            *string
+	// created holds the metric families parsed from the family being parsed.
+	created []*dto.MetricFamily
+	// grouped holds the histograms and summaries of the family being parsed,
+	// by their labels.
+	grouped map[string]*dto.Metric
+}
+
+// parseOpenMetrics parses content in the OpenMetrics text format, which must
+// end with # EOF.
+func parseOpenMetrics(content []byte) (map[string]*dto.MetricFamily, error) {
+	p := &openMetricsParser{
+		families:   make(map[string]*dto.MetricFamily),
+		seen:       make(map[string]bool),
+		exportedBy: make(map[string]string),
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	for i, line := range lines {
+		if line == "# EOF" {
+			if i != len(lines)-1 {
+				return nil, fmt.Errorf("line %d: content after # EOF", i+2)
+			}
+			if err := p.endFamily(); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			return p.families, nil
+		}
+		var err error
+		switch {
+		case strings.HasPrefix(line, headerPrefix):
+		case strings.HasPrefix(line, "#"):
+			err = p.parseMetadata(line)
+		default:
+			err = p.parseSample(line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	return nil, fmt.Errorf("missing # EOF")
+}
+
+// startFamily ends the family being parsed and starts the named one.
+func (p *openMetricsParser) startFamily(name, typ string) error {
+	if err := p.endFamily(); err != nil {
+		return err
+	}
+	if p.seen[name] {
+		return fmt.Errorf("metric family %s appears more than once", name)
+	}
+	p.seen[name] = true
+	p.name, p.typ, p.unit, p.help = name, typ, "", nil
+	p.created = nil
+	p.grouped = make(map[string]*dto.Metric)
+	return nil
+}
+
+func (p *openMetricsParser) endFamily() error {
+	if p.name == "" {
+		return nil
+	}
+	if p.unit != "" && !strings.HasSuffix(p.name, "_"+p.unit) {
+		return fmt.Errorf("metric family %s must have the suffix of its unit %s", p.name, p.unit)
+	}
+	for _, mf := range p.created {
+		mf.Help = p.help
+		if p.unit != "" {
+			unit := p.unit
+			mf.Unit = &unit
+		}
+	}
+	p.name = ""
+	return nil
+}
+
+func (p *openMetricsParser) hasSuffix(suffix string) bool {
+	for _, s := range openMetricsSuffixes[p.typ] {
+		if s == suffix {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *openMetricsParser) parseMetadata(line string) error {
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) < 3 || fields[0] != "#" {
+		return fmt.Errorf("invalid comment %q", line)
+	}
+	keyword, name := fields[1], fields[2]
+	var value string
+	if len(fields) == 4 {
+		value = fields[3]
+	}
+	if !metricNameRE.MatchString(name) {
+		return fmt.Errorf("invalid metric name %q", name)
+	}
+	if name != p.name {
+		if err := p.startFamily(name, "unknown"); err != nil {
+			return err
+		}
+	}
+
+	switch keyword {
+	case "HELP":
+		help := unescapeOpenMetrics(value)
+		p.help = &help
+	case "TYPE":
+		if _, ok := openMetricsSuffixes[value]; !ok {
+			return fmt.Errorf("unknown type %q of metric family %s", value, name)
+		}
+		p.typ = value
+	case "UNIT":
+		p.unit = value
+	default:
+		return fmt.Errorf("invalid comment %q", line)
+	}
+	return nil
+}
+
+func (p *openMetricsParser) parseSample(line string) error {
+	name, rest := line, ""
+	if i := strings.IndexAny(line, "{ "); i >= 0 {
+		name, rest = line[:i], line[i:]
+	}
+	if !metricNameRE.MatchString(name) {
+		return fmt.Errorf("invalid metric name %q", name)
+	}
+	var labels []*dto.LabelPair
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		if labels, rest, err = parseOpenMetricsLabels(rest[1:]); err != nil {
+			return err
+		}
+	}
+	// Exemplars are dropped.
+	rest, _, _ = strings.Cut(rest, " # ")
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return fmt.Errorf("expected a value and an optional timestamp for %s", name)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return fmt.Errorf("invalid value of %s: %w", name, err)
+	}
+	var timestampMs *int64
+	if len(fields) == 2 {
+		ts, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || math.IsNaN(ts) || math.IsInf(ts, 0) {
+			return fmt.Errorf("invalid timestamp of %s: %q", name, fields[1])
+		}
+		ms := int64(math.Round(ts * 1000))
+		timestampMs = &ms
+	}
+
+	suffix, ok := "", false
+	if p.name != "" {
+		if suffix, ok = strings.CutPrefix(name, p.name); ok {
+			ok = p.hasSuffix(suffix)
+		}
+	}
+	if !ok {
+		if err := p.startFamily(name, "unknown"); err != nil {
+			return err
+		}
+	}
+
+	switch p.typ {
+	case "counter":
+		if suffix == "_total" {
+			return p.add(p.name+"_total", dto.MetricType_COUNTER, &dto.Metric{Label: labels, Counter: &dto.Counter{Value: &value}, TimestampMs: timestampMs})
+		}
+	case "stateset":
+		if value != 0 && value != 1 {
+			return fmt.Errorf("value of state of %s must be 0 or 1, got %v", p.name, value)
+		}
+		if !hasLabel(labels, p.name) {
+			return fmt.Errorf("sample of stateset %s without %s label", p.name, p.name)
+		}
+		return p.add(p.name, dto.MetricType_GAUGE, &dto.Metric{Label: labels, Gauge: &dto.Gauge{Value: &value}, TimestampMs: timestampMs})
+	case "gauge":
+		return p.add(p.name, dto.MetricType_GAUGE, &dto.Metric{Label: labels, Gauge: &dto.Gauge{Value: &value}, TimestampMs: timestampMs})
+	case "info":
+		return p.add(p.name+"_info", dto.MetricType_GAUGE, &dto.Metric{Label: labels, Gauge: &dto.Gauge{Value: &value}, TimestampMs: timestampMs})
+	case "unknown":
+		return p.add(p.name, dto.MetricType_UNTYPED, &dto.Metric{Label: labels, Untyped: &dto.Untyped{Value: &value}, TimestampMs: timestampMs})
+	case "gaugehistogram":
+		return p.add(name, dto.MetricType_GAUGE, &dto.Metric{Label: labels, Gauge: &dto.Gauge{Value: &value}, TimestampMs: timestampMs})
+	case "histogram", "summary":
+		return p.addGrouped(suffix, labels, value, timestampMs)
+	}
+	return nil
+}
+
+// add appends metric to the named family. Families are only added to by the
+// OpenMetrics family they were created from: another one with the same name
+// once suffixed, such as counter a and unknown a_total, is an error.
+func (p *openMetricsParser) add(name string, typ dto.MetricType, metric *dto.Metric) error {
+	if from, ok := p.exportedBy[name]; ok && from != p.name {
+		return fmt.Errorf("metric family %s of %s was already parsed from metric family %s", name, p.name, from)
+	}
+	mf, ok := p.families[name]
+	if !ok {
+		mf = &dto.MetricFamily{Name: &name, Type: &typ}
+		p.families[name] = mf
+		p.exportedBy[name] = p.name
+		p.created = append(p.created, mf)
+	}
+	mf.Metric = append(mf.Metric, metric)
+	return nil
+}
+
+// hasLabel returns whether labels has the named label.
+func hasLabel(labels []*dto.LabelPair, name string) bool {
+	for _, l := range labels {
+		if l.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addGrouped adds a sample to the histogram or summary of its labels, leaving
+// out le and quantile.
+func (p *openMetricsParser) addGrouped(suffix string, labels []*dto.LabelPair, value float64, timestampMs *int64) error {
+	bound := "le"
+	if p.typ == "summary" {
+		bound = "quantile"
+	}
+	var boundValue *string
+	var pairs []string
+	grouping := make([]*dto.LabelPair, 0, len(labels))
+	for _, l := range labels {
+		if l.GetName() == bound {
+			boundValue = l.Value
+			continue
+		}
+		grouping = append(grouping, l)
+		pairs = append(pairs, fmt.Sprintf("%s=%q", l.GetName(), l.GetValue()))
+	}
+	sort.Strings(pairs)
+	key := strings.Join(pairs, ",")
+
+	metric, ok := p.grouped[key]
+	if !ok {
+		metric = &dto.Metric{Label: grouping}
+		typ := dto.MetricType_HISTOGRAM
+		if p.typ == "summary" {
+			metric.Summary = &dto.Summary{}
+			typ = dto.MetricType_SUMMARY
+		} else {
+			metric.Histogram = &dto.Histogram{}
+		}
+		if err := p.add(p.name, typ, metric); err != nil {
+			return err
+		}
+		p.grouped[key] = metric
+	}
+	if timestampMs != nil {
+		metric.TimestampMs = timestampMs
+	}
+
+	count := uint64(value)
+	switch {
+	case suffix == "_count" && metric.Summary != nil:
+		metric.Summary.SampleCount = &count
+	case suffix == "_sum" && metric.Summary != nil:
+		metric.Summary.SampleSum = &value
+	case suffix == "_count":
+		metric.Histogram.SampleCount = &count
+	case suffix == "_sum":
+		metric.Histogram.SampleSum = &value
+	case suffix == "_created":
+	case boundValue == nil:
+		return fmt.Errorf("sample of %s%s without %s label", p.name, suffix, bound)
+	default:
+		b, err := strconv.ParseFloat(*boundValue, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s label of %s%s: %w", bound, p.name, suffix, err)
+		}
+		if metric.Summary != nil {
+			metric.Summary.Quantile = append(metric.Summary.Quantile, &dto.Quantile{Quantile: &b, Value: &value})
+		} else {
+			metric.Histogram.Bucket = append(metric.Histogram.Bucket, &dto.Bucket{UpperBound: &b, CumulativeCount: &count})
+		}
+	}
+	return nil
+}
+
+// parseOpenMetricsLabels parses the labels of a sample, after the opening
+// brace, and returns the rest of the line.
+func parseOpenMetricsLabels(s string) ([]*dto.LabelPair, string, error) {
+	var labels []*dto.LabelPair
+	seen := make(map[string]bool)
+	for {
+		if rest, ok := strings.CutPrefix(s, "}"); ok {
+			return labels, rest, nil
+		}
+		name, value, ok := strings.Cut(s, "=\"")
+		if !ok || !labelNameRE.MatchString(name) {
+			return nil, "", fmt.Errorf("invalid labels {%s", s)
+		}
+		if seen[name] {
+			return nil, "", fmt.Errorf("duplicate label %s", name)
+		}
+		seen[name] = true
+
+		end := -1
+		for i := 0; i < len(value); i++ {
+			if value[i] == '\\' {
+				i++
+				continue
+			}
+			if value[i] == '"' {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated value of label %s", name)
+		}
+		unescaped := unescapeOpenMetrics(value[:end])
+		labels = append(labels, &dto.LabelPair{Name: &name, Value: &unescaped})
+		s = strings.TrimPrefix(value[end+1:], ",")
+	}
+}
+
+// unescapeOpenMetrics unescapes \\, \n and \" in help texts and label values.
+func unescapeOpenMetrics(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch c := s[i+1]; {
+			case c == '\\':
+				b.WriteByte('\\')
+				i++
+				continue
+			case c == 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			case c == '"':
+				b.WriteByte('"')
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestParseOpenMetrics(t *testing.T) {
+	families, err := parseOpenMetrics([]byte(`# windows_exporter: max-age=1h
+# TYPE backup_duration_seconds counter
+# UNIT backup_duration_seconds seconds
+# HELP backup_duration_seconds Time spent in \"backups\".
+backup_duration_seconds_total{job="db"} 17.5 1700000000.5 # {trace_id="a"} 1.0
+backup_duration_seconds_created{job="db"} 1600000000
+# TYPE queue_length gauge
+queue_length{queue="a\\b\nc"} 3
+# TYPE build info
+build_info{version="1.2"} 1
+# TYPE service_state stateset
+service_state{service_state="running"} 1
+service_state{service_state="stopped"} 0
+# TYPE request_size_bytes histogram
+request_size_bytes_bucket{path="/",le="1"} 1
+request_size_bytes_bucket{path="/",le="+Inf"} 3
+request_size_bytes_count{path="/"} 3
+request_size_bytes_sum{path="/"} 5.5
+# TYPE latency_seconds summary
+latency_seconds{quantile="0.5"} 0.2
+latency_seconds_count 10
+latency_seconds_sum 2
+# TYPE queue_items gaugehistogram
+queue_items_bucket{le="+Inf"} 4
+queue_items_gcount 4
+queue_items_gsum 9
+untyped_metric 42
+# EOF
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, typ := range map[string]dto.MetricType{
+		"backup_duration_seconds_total": dto.MetricType_COUNTER,
+		"queue_length":                  dto.MetricType_GAUGE,
+		"build_info":                    dto.MetricType_GAUGE,
+		"service_state":                 dto.MetricType_GAUGE,
+		"request_size_bytes":            dto.MetricType_HISTOGRAM,
+		"latency_seconds":               dto.MetricType_SUMMARY,
+		"queue_items_bucket":            dto.MetricType_GAUGE,
+		"queue_items_gcount":            dto.MetricType_GAUGE,
+		"queue_items_gsum":              dto.MetricType_GAUGE,
+		"untyped_metric":                dto.MetricType_UNTYPED,
+	} {
+		mf, ok := families[name]
+		if !ok {
+			t.Errorf("expected metric family %s", name)
+			continue
+		}
+		if mf.GetType() != typ {
+			t.Errorf("expected %s to be a %s, got %s", name, typ, mf.GetType())
+		}
+	}
+	if len(families) != 10 {
+		t.Errorf("expected 10 metric families, got %d", len(families))
+	}
+
+	counter := families["backup_duration_seconds_total"]
+	if counter.GetHelp() != `Time spent in "backups".` || counter.GetUnit() != "seconds" {
+		t.Errorf("unexpected help %q or unit %q", counter.GetHelp(), counter.GetUnit())
+	}
+	if len(counter.Metric) != 1 || counter.Metric[0].GetCounter().GetValue() != 17.5 || counter.Metric[0].GetTimestampMs() != 1700000000500 {
+		t.Errorf("unexpected counter %v", counter.Metric)
+	}
+	if value := families["queue_length"].Metric[0].Label[0].GetValue(); value != "a\\b\nc" {
+		t.Errorf("unexpected label value %q", value)
+	}
+	if n := len(families["service_state"].Metric); n != 2 {
+		t.Errorf("expected 2 states, got %d", n)
+	}
+	histogram := families["request_size_bytes"].Metric[0]
+	if len(histogram.Label) != 1 || len(histogram.Histogram.Bucket) != 2 || histogram.Histogram.GetSampleCount() != 3 || histogram.Histogram.GetSampleSum() != 5.5 {
+		t.Errorf("unexpected histogram %v", histogram)
+	}
+	summary := families["latency_seconds"].Metric[0]
+	if len(summary.Summary.Quantile) != 1 || summary.Summary.GetSampleCount() != 10 || summary.Summary.GetSampleSum() != 2 {
+		t.Errorf("unexpected summary %v", summary)
+	}
+}
+
+func TestParseOpenMetricsErrors(t *testing.T) {
+	for content, expected := range map[string]string{
+		"a 1\n":             "missing # EOF",
+		"a 1\n# EOF\nb 1\n": "line 3: content after # EOF",
+		"# TYPE a_bytes gauge\n# UNIT a_bytes seconds\na_bytes 1\n# EOF\n": "line 4: metric family a_bytes must have the suffix of its unit seconds",
+		"a 1\nb 1\na 2\n# EOF\n":                               "line 3: metric family a appears more than once",
+		"# TYPE a histogram\na_bucket 1\n# EOF\n":              "line 2: sample of a_bucket without le label",
+		"# TYPE a enum\n# EOF\n":                               `line 1: unknown type "enum" of metric family a`,
+		"a{b=\"c} 1\n# EOF\n":                                  "line 1: unterminated value of label b",
+		"a one\n# EOF\n":                                       "line 1: invalid value of a",
+		"# TYPE a counter\na_total 1\nb 1\na_total 2\n# EOF\n": "line 4: metric family a_total of a_total was already parsed from metric family a",
+		"# TYPE a_total gauge\na_total 1\n# TYPE a counter\na_total 2\n# EOF\n": "line 4: metric family a_total of a was already parsed from metric family a_total",
+		"# TYPE a stateset\na{a=\"on\"} 2\n# EOF\n":                             "line 2: value of state of a must be 0 or 1, got 2",
+		"# TYPE a stateset\na{state=\"on\"} 1\n# EOF\n":                         "line 2: sample of stateset a without a label",
+	} {
+		_, err := parseOpenMetrics([]byte(content))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error %q parsing %q, got %v", expected, content, err)
+		}
+	}
+}
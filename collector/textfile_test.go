@@ -1,12 +1,19 @@
 package collector
 
 import (
+	"bytes"
+	"fmt"
 	"github.com/dimchansky/utfbom"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 )
 
 func TestCRFilter(t *testing.T) {
@@ -152,3 +159,255 @@ func TestDuplicateMetricEntry(t *testing.T) {
 		t.Errorf("Unexpected duplicate found in differentValues")
 	}
 }
+
+// textFileCollectorAdapter registers a textFileCollector with a registry.
+type textFileCollectorAdapter struct {
+	c *textFileCollector
+}
+
+func (a textFileCollectorAdapter) Describe(chan<- *prometheus.Desc) {}
+
+func (a textFileCollectorAdapter) Collect(ch chan<- prometheus.Metric) {
+	_ = a.c.Collect(&ScrapeContext{}, ch)
+}
+
+// collectTextFiles returns the output of c in the text format, and the error
+// of the registry gathering it.
+func collectTextFiles(t *testing.T, c *textFileCollector) (string, error) {
+	t.Helper()
+	mtime := 1.0
+	c.mtime = &mtime
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(textFileCollectorAdapter{c})
+	mfs, err := reg.Gather()
+	var buf bytes.Buffer
+	for _, mf := range mfs {
+		if _, err := expfmt.MetricFamilyToText(&buf, mf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.String(), err
+}
+
+func writeTextFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTextFileDirectories(t *testing.T) {
+	dir := t.TempDir()
+	writeTextFiles(t, dir, map[string]string{
+		"base/base.prom":         "base_metric 1\n",
+		"base/ignored.txt":       "ignored_metric 1\n",
+		"teams/a/metrics/a.prom": "team_a_metric 1\n",
+		"teams/b/metrics/b.prom": "team_b_metric{\n",
+		"single/c.prom":          "single_metric 1\n",
+	})
+	base := filepath.Join(dir, "base")
+	teamA := filepath.Join(dir, "teams", "a", "metrics")
+	teamB := filepath.Join(dir, "teams", "b", "metrics")
+	single := filepath.Join(dir, "single")
+	missing := filepath.Join(dir, "missing")
+
+	out, err := collectTextFiles(t, &textFileCollector{directories: []string{
+		base,
+		filepath.Join(dir, "teams", "*", "metrics"),
+		filepath.Join(single, "*.prom"),
+		missing,
+		// Files found twice are read once.
+		filepath.Join(base, "*.prom"),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"base_metric 1\n",
+		"team_a_metric 1\n",
+		"single_metric 1\n",
+		fmt.Sprintf("windows_textfile_mtime_seconds{directory=%q,file=\"base.prom\"} 1\n", base),
+		fmt.Sprintf("windows_textfile_mtime_seconds{directory=%q,file=\"a.prom\"} 1\n", teamA),
+		fmt.Sprintf("windows_textfile_mtime_seconds{directory=%q,file=\"c.prom\"} 1\n", single),
+		fmt.Sprintf("windows_textfile_scrape_error{directory=%q} 0\n", base),
+		fmt.Sprintf("windows_textfile_scrape_error{directory=%q} 0\n", teamA),
+		fmt.Sprintf("windows_textfile_scrape_error{directory=%q} 1\n", teamB),
+		fmt.Sprintf("windows_textfile_scrape_error{directory=%q} 0\n", single),
+		fmt.Sprintf("windows_textfile_scrape_error{directory=%q} 1\n", missing),
+		fmt.Sprintf("windows_textfile_file_error{directory=%q,file=\"b.prom\",reason=\"parse\"} 1\n", teamB),
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "ignored_metric") || strings.Contains(out, fmt.Sprintf("windows_textfile_mtime_seconds{directory=%q,file=\"b.prom\"}", teamB)) {
+		t.Errorf("unexpected series in output:\n%s", out)
+	}
+}
+
+func TestTextFileMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	writeTextFiles(t, dir, map[string]string{
+		"fresh.prom":    "fresh_metric 1\n",
+		"old.prom":      "old_metric 1\n",
+		"override.prom": "# windows_exporter: max-age=3h\n# HELP override_metric Help.\noverride_metric 1\n",
+		"disabled.prom": "# windows_exporter: max-age=0s\nancient_metric 1\n",
+		"future.prom":   "future_metric 1\n",
+		"invalid.prom":  "# windows_exporter: max-age=soon\ninvalid_metric 1\n",
+	})
+	now := time.Now()
+	for name, mtime := range map[string]time.Time{
+		"old.prom":      now.Add(-2 * time.Hour),
+		"override.prom": now.Add(-2 * time.Hour),
+		"disabled.prom": now.Add(-24 * time.Hour),
+		"future.prom":   now.Add(time.Hour),
+	} {
+		if err := os.Chtimes(filepath.Join(dir, name), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := collectTextFiles(t, &textFileCollector{directories: []string{dir}, maxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"fresh_metric 1\n",
+		"override_metric 1\n",
+		"ancient_metric 1\n",
+		"future_metric 1\n",
+		fmt.Sprintf("windows_textfile_stale_files{directory=%q} 1\n", dir),
+		fmt.Sprintf("windows_textfile_future_mtime_files{directory=%q} 1\n", dir),
+		fmt.Sprintf("windows_textfile_scrape_error{directory=%q} 1\n", dir),
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	for _, unexpected := range []string{"old_metric", "invalid_metric", `file="old.prom"`} {
+		if strings.Contains(out, unexpected) {
+			t.Errorf("expected output not to contain %q, got:\n%s", unexpected, out)
+		}
+	}
+}
+
+func TestTextFileDuplicateIsolation(t *testing.T) {
+	dir := t.TempDir()
+	writeTextFiles(t, dir, map[string]string{
+		"a.prom": "# HELP shared_series Shared.\n# TYPE shared_series gauge\nshared_series{instance=\"1\"} 1\n# TYPE a_metric counter\na_metric 1\n",
+		// Duplicates a series of a.prom.
+		"b.prom": "# TYPE shared_series gauge\nshared_series{instance=\"1\"} 2\nb_only_metric 1\n",
+		// Adds a series to the family of a.prom.
+		"c.prom": "# HELP shared_series Shared.\n# TYPE shared_series gauge\nshared_series{instance=\"2\"} 3\n",
+		// Conflicts with the type of a.prom.
+		"d.prom": "# TYPE a_metric gauge\na_metric{instance=\"2\"} 1\nd_only_metric 1\n",
+		// Holds a series twice.
+		"e.prom": "e_only_metric 1\ne_only_metric 2\n",
+	})
+
+	out, err := collectTextFiles(t, &textFileCollector{directories: []string{dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"shared_series{instance=\"1\"} 1\n",
+		"shared_series{instance=\"2\"} 3\n",
+		"a_metric 1\n",
+		fmt.Sprintf("windows_textfile_file_error{directory=%q,file=\"b.prom\",reason=\"duplicate\"} 1\n", dir),
+		fmt.Sprintf("windows_textfile_file_error{directory=%q,file=\"d.prom\",reason=\"conflict\"} 1\n", dir),
+		fmt.Sprintf("windows_textfile_file_error{directory=%q,file=\"e.prom\",reason=\"duplicate\"} 1\n", dir),
+		fmt.Sprintf("windows_textfile_mtime_seconds{directory=%q,file=\"c.prom\"} 1\n", dir),
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	for _, unexpected := range []string{"b_only_metric", "d_only_metric", "e_only_metric", "shared_series{instance=\"1\"} 2", fmt.Sprintf("windows_textfile_mtime_seconds{directory=%q,file=\"b.prom\"}", dir)} {
+		if strings.Contains(out, unexpected) {
+			t.Errorf("expected output not to contain %q, got:\n%s", unexpected, out)
+		}
+	}
+}
+
+func TestTextFileFamilyMerging(t *testing.T) {
+	dir := t.TempDir()
+	writeTextFiles(t, dir, map[string]string{
+		"a.prom": "# HELP merged_series Merged.\n# TYPE merged_series gauge\nmerged_series{disk=\"C:\"} 1\n",
+		// Other labels and another help.
+		"b.prom": "# HELP merged_series Other.\n# TYPE merged_series gauge\nmerged_series{disk=\"D:\",host=\"b\"} 2\nmerged_series{host=\"b\"} 3\n",
+		// Another type.
+		"c.prom": "# TYPE merged_series counter\nmerged_series{disk=\"E:\"} 4\n",
+		"d.prom": "# TYPE merged_histogram histogram\nmerged_histogram_bucket{le=\"1\"} 1\nmerged_histogram_bucket{le=\"+Inf\"} 2\nmerged_histogram_sum 3\nmerged_histogram_count 2\n",
+		"e.prom": "# TYPE merged_histogram histogram\nmerged_histogram_bucket{job=\"e\",le=\"+Inf\"} 1\nmerged_histogram_sum{job=\"e\"} 1\nmerged_histogram_count{job=\"e\"} 1\n",
+	})
+
+	out, err := collectTextFiles(t, &textFileCollector{directories: []string{dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"# HELP merged_series Merged.\n# TYPE merged_series gauge\n",
+		"merged_series{disk=\"C:\",host=\"\"} 1\n",
+		"merged_series{disk=\"D:\",host=\"b\"} 2\n",
+		"merged_series{disk=\"\",host=\"b\"} 3\n",
+		"merged_histogram_count{job=\"\"} 2\n",
+		"merged_histogram_count{job=\"e\"} 1\n",
+		fmt.Sprintf("windows_textfile_family_conflict{directory=%q,family=\"merged_series\",field=\"help\",file=\"b.prom\"} 1\n", dir),
+		fmt.Sprintf("windows_textfile_family_conflict{directory=%q,family=\"merged_series\",field=\"type\",file=\"c.prom\"} 1\n", dir),
+		fmt.Sprintf("windows_textfile_file_error{directory=%q,file=\"c.prom\",reason=\"conflict\"} 1\n", dir),
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "} 4\n") {
+		t.Errorf("expected the series of c.prom to be skipped, got:\n%s", out)
+	}
+}
+
+func TestTextFileTimestamps(t *testing.T) {
+	dir := t.TempDir()
+	writeTextFiles(t, dir, map[string]string{
+		"text.prom":        "text_metric 1 1700000000000\n",
+		"optin.prom":       "# windows_exporter: timestamps=true\noptin_metric 1 1700000000000\n",
+		"metrics.om":       "# TYPE om_events counter\nom_events_total 2 1700000000.25\n# EOF\n",
+		"openmetrics.prom": "# windows_exporter: format=openmetrics\n# TYPE om_build info\nom_build_info{version=\"1\"} 1\n# EOF\n",
+	})
+
+	out, err := collectTextFiles(t, &textFileCollector{directories: []string{dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"optin_metric 1 1700000000000\n",
+		"om_build_info{version=\"1\"} 1\n",
+		fmt.Sprintf("windows_textfile_file_error{directory=%q,file=\"text.prom\",reason=\"timestamps\"} 1\n", dir),
+		fmt.Sprintf("windows_textfile_file_error{directory=%q,file=\"metrics.om\",reason=\"timestamps\"} 1\n", dir),
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+
+	out, err = collectTextFiles(t, &textFileCollector{directories: []string{dir}, timestamps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"text_metric 1 1700000000000\n",
+		"# TYPE om_events_total counter\nom_events_total 2 1700000000250\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "windows_textfile_file_error") {
+		t.Errorf("expected no file errors, got:\n%s", out)
+	}
+}
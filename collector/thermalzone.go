@@ -5,11 +5,14 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("thermalzone", NewThermalZoneCollector)
+	registerCollectorInfo("thermalzone", CollectorInfo{
+		Description: "Thermal information",
+		Sources:     []DataSource{SourceWMI},
+	})
 }
 
 // A thermalZoneCollector is a Prometheus collector for WMI Win32_PerfRawData_Counters_ThermalZoneInformation metrics
@@ -23,7 +26,7 @@ type thermalZoneCollector struct {
 func NewThermalZoneCollector() (Collector, error) {
 	const subsystem = "thermalzone"
 	return &thermalZoneCollector{
-		Temperature: prometheus.NewDesc(
+		Temperature: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "temperature_celsius"),
 			"(Temperature)",
 			[]string{
@@ -31,7 +34,7 @@ func NewThermalZoneCollector() (Collector, error) {
 			},
 			nil,
 		),
-		PercentPassiveLimit: prometheus.NewDesc(
+		PercentPassiveLimit: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "percent_passive_limit"),
 			"(PercentPassiveLimit)",
 			[]string{
@@ -39,7 +42,7 @@ func NewThermalZoneCollector() (Collector, error) {
 			},
 			nil,
 		),
-		ThrottleReasons: prometheus.NewDesc(
+		ThrottleReasons: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "throttle_reasons"),
 			"(ThrottleReasons)",
 			[]string{
@@ -50,10 +53,17 @@ func NewThermalZoneCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *thermalZoneCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.PercentPassiveLimit
+	ch <- c.Temperature
+	ch <- c.ThrottleReasons
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *thermalZoneCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collect(ch); err != nil {
+	if desc, err := c.collect(ctx, ch); err != nil {
 		log.Error("failed collecting thermalzone metrics:", desc, err)
 		return err
 	}
@@ -70,10 +80,10 @@ type Win32_PerfRawData_Counters_ThermalZoneInformation struct {
 	ThrottleReasons          uint32
 }
 
-func (c *thermalZoneCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *thermalZoneCollector) collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_Counters_ThermalZoneInformation
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
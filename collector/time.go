@@ -12,6 +12,11 @@ import (
 
 func init() {
 	registerCollector("time", newTimeCollector, "Windows Time Service")
+	registerCollectorInfo("time", CollectorInfo{
+		Description: "Windows Time Service",
+		Sources:     []DataSource{SourcePerflib},
+		Requires:    "Windows Server 2016 or newer",
+	})
 }
 
 // TimeCollector is a Prometheus collector for Perflib counter metrics
@@ -32,37 +37,37 @@ func newTimeCollector() (Collector, error) {
 	const subsystem = "time"
 
 	return &TimeCollector{
-		ClockFrequencyAdjustmentPPBTotal: prometheus.NewDesc(
+		ClockFrequencyAdjustmentPPBTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clock_frequency_adjustment_ppb_total"),
 			"Total adjustment made to the local system clock frequency by W32Time in Parts Per Billion (PPB) units.",
 			nil,
 			nil,
 		),
-		ComputedTimeOffset: prometheus.NewDesc(
+		ComputedTimeOffset: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "computed_time_offset_seconds"),
 			"Absolute time offset between the system clock and the chosen time source, in seconds",
 			nil,
 			nil,
 		),
-		NTPClientTimeSourceCount: prometheus.NewDesc(
+		NTPClientTimeSourceCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ntp_client_time_sources"),
 			"Active number of NTP Time sources being used by the client",
 			nil,
 			nil,
 		),
-		NTPRoundtripDelay: prometheus.NewDesc(
+		NTPRoundtripDelay: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ntp_round_trip_delay_seconds"),
 			"Roundtrip delay experienced by the NTP client in receiving a response from the server for the most recent request, in seconds",
 			nil,
 			nil,
 		),
-		NTPServerOutgoingResponsesTotal: prometheus.NewDesc(
+		NTPServerOutgoingResponsesTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ntp_server_outgoing_responses_total"),
 			"Total number of requests responded to by NTP server",
 			nil,
 			nil,
 		),
-		NTPServerIncomingRequestsTotal: prometheus.NewDesc(
+		NTPServerIncomingRequestsTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "ntp_server_incoming_requests_total"),
 			"Total number of requests received by NTP server",
 			nil,
@@ -71,6 +76,16 @@ func newTimeCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *TimeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ClockFrequencyAdjustmentPPBTotal
+	ch <- c.ComputedTimeOffset
+	ch <- c.NTPClientTimeSourceCount
+	ch <- c.NTPRoundtripDelay
+	ch <- c.NTPServerIncomingRequestsTotal
+	ch <- c.NTPServerOutgoingResponsesTotal
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *TimeCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
@@ -8,11 +8,16 @@ import (
 
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("vmware", NewVmwareCollector)
+	registerCollectorInfo("vmware", CollectorInfo{
+		Description: "Performance counters installed by the Vmware Guest agent",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "VMware Tools",
+	})
+	registerApplicabilityCheck("vmware", serviceInstalled("VMTools"))
 }
 
 // A VmwareCollector is a Prometheus collector for WMI Win32_PerfRawData_vmGuestLib_VMem/Win32_PerfRawData_vmGuestLib_VCPU metrics
@@ -43,116 +48,116 @@ type VmwareCollector struct {
 func NewVmwareCollector() (Collector, error) {
 	const subsystem = "vmware"
 	return &VmwareCollector{
-		MemActive: prometheus.NewDesc(
+		MemActive: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_active_bytes"),
 			"(MemActiveMB)",
 			nil,
 			nil,
 		),
-		MemBallooned: prometheus.NewDesc(
+		MemBallooned: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_ballooned_bytes"),
 			"(MemBalloonedMB)",
 			nil,
 			nil,
 		),
-		MemLimit: prometheus.NewDesc(
+		MemLimit: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_limit_bytes"),
 			"(MemLimitMB)",
 			nil,
 			nil,
 		),
-		MemMapped: prometheus.NewDesc(
+		MemMapped: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_mapped_bytes"),
 			"(MemMappedMB)",
 			nil,
 			nil,
 		),
-		MemOverhead: prometheus.NewDesc(
+		MemOverhead: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_overhead_bytes"),
 			"(MemOverheadMB)",
 			nil,
 			nil,
 		),
-		MemReservation: prometheus.NewDesc(
+		MemReservation: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_reservation_bytes"),
 			"(MemReservationMB)",
 			nil,
 			nil,
 		),
-		MemShared: prometheus.NewDesc(
+		MemShared: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_shared_bytes"),
 			"(MemSharedMB)",
 			nil,
 			nil,
 		),
-		MemSharedSaved: prometheus.NewDesc(
+		MemSharedSaved: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_shared_saved_bytes"),
 			"(MemSharedSavedMB)",
 			nil,
 			nil,
 		),
-		MemShares: prometheus.NewDesc(
+		MemShares: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_shares"),
 			"(MemShares)",
 			nil,
 			nil,
 		),
-		MemSwapped: prometheus.NewDesc(
+		MemSwapped: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_swapped_bytes"),
 			"(MemSwappedMB)",
 			nil,
 			nil,
 		),
-		MemTargetSize: prometheus.NewDesc(
+		MemTargetSize: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_target_size_bytes"),
 			"(MemTargetSizeMB)",
 			nil,
 			nil,
 		),
-		MemUsed: prometheus.NewDesc(
+		MemUsed: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "mem_used_bytes"),
 			"(MemUsedMB)",
 			nil,
 			nil,
 		),
 
-		CpuLimitMHz: prometheus.NewDesc(
+		CpuLimitMHz: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cpu_limit_mhz"),
 			"(CpuLimitMHz)",
 			nil,
 			nil,
 		),
-		CpuReservationMHz: prometheus.NewDesc(
+		CpuReservationMHz: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cpu_reservation_mhz"),
 			"(CpuReservationMHz)",
 			nil,
 			nil,
 		),
-		CpuShares: prometheus.NewDesc(
+		CpuShares: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cpu_shares"),
 			"(CpuShares)",
 			nil,
 			nil,
 		),
-		CpuStolenTotal: prometheus.NewDesc(
+		CpuStolenTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cpu_stolen_seconds_total"),
 			"(CpuStolenMs)",
 			nil,
 			nil,
 		),
-		CpuTimeTotal: prometheus.NewDesc(
+		CpuTimeTotal: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cpu_time_seconds_total"),
 			"(CpuTimePercents)",
 			nil,
 			nil,
 		),
-		EffectiveVMSpeedMHz: prometheus.NewDesc(
+		EffectiveVMSpeedMHz: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "effective_vm_speed_mhz"),
 			"(EffectiveVMSpeedMHz)",
 			nil,
 			nil,
 		),
-		HostProcessorSpeedMHz: prometheus.NewDesc(
+		HostProcessorSpeedMHz: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "host_processor_speed_mhz"),
 			"(HostProcessorSpeedMHz)",
 			nil,
@@ -161,14 +166,37 @@ func NewVmwareCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *VmwareCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.MemActive
+	ch <- c.MemBallooned
+	ch <- c.MemLimit
+	ch <- c.MemMapped
+	ch <- c.MemOverhead
+	ch <- c.MemReservation
+	ch <- c.MemShared
+	ch <- c.MemSharedSaved
+	ch <- c.MemShares
+	ch <- c.MemSwapped
+	ch <- c.MemTargetSize
+	ch <- c.MemUsed
+	ch <- c.CpuLimitMHz
+	ch <- c.CpuReservationMHz
+	ch <- c.CpuShares
+	ch <- c.CpuStolenTotal
+	ch <- c.CpuTimeTotal
+	ch <- c.EffectiveVMSpeedMHz
+	ch <- c.HostProcessorSpeedMHz
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *VmwareCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collectMem(ch); err != nil {
+	if desc, err := c.collectMem(ctx, ch); err != nil {
 		log.Error("failed collecting vmware memory metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectCpu(ch); err != nil {
+	if desc, err := c.collectCpu(ctx, ch); err != nil {
 		log.Error("failed collecting vmware cpu metrics:", desc, err)
 		return err
 	}
@@ -200,10 +228,10 @@ type Win32_PerfRawData_vmGuestLib_VCPU struct {
 	HostProcessorSpeedMHz uint64
 }
 
-func (c *VmwareCollector) collectMem(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *VmwareCollector) collectMem(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_vmGuestLib_VMem
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -289,10 +317,10 @@ func mbToBytes(mb uint64) float64 {
 	return float64(mb * 1024 * 1024)
 }
 
-func (c *VmwareCollector) collectCpu(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *VmwareCollector) collectCpu(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []Win32_PerfRawData_vmGuestLib_VCPU
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 	if len(dst) == 0 {
@@ -6,11 +6,16 @@ package collector
 import (
 	"github.com/prometheus-community/windows_exporter/log"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/yusufpapurcu/wmi"
 )
 
 func init() {
 	registerCollector("vmware_blast", newVmwareBlastCollector)
+	registerCollectorInfo("vmware_blast", CollectorInfo{
+		Description: "VMware Blast session metrics",
+		Sources:     []DataSource{SourceWMI},
+		Requires:    "VMware Horizon agent",
+	})
+	registerApplicabilityCheck("vmware_blast", serviceInstalled("VMBlast"))
 }
 
 // A vmwareBlastCollector is a Prometheus collector for WMI metrics:
@@ -114,426 +119,426 @@ type vmwareBlastCollector struct {
 func newVmwareBlastCollector() (Collector, error) {
 	const subsystem = "vmware_blast"
 	return &vmwareBlastCollector{
-		AudioReceivedBytes: prometheus.NewDesc(
+		AudioReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "audio_received_bytes_total"),
 			"(AudioReceivedBytes)",
 			nil,
 			nil,
 		),
-		AudioReceivedPackets: prometheus.NewDesc(
+		AudioReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "audio_received_packets_total"),
 			"(AudioReceivedPackets)",
 			nil,
 			nil,
 		),
-		AudioTransmittedBytes: prometheus.NewDesc(
+		AudioTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "audio_transmitted_bytes_total"),
 			"(AudioTransmittedBytes)",
 			nil,
 			nil,
 		),
-		AudioTransmittedPackets: prometheus.NewDesc(
+		AudioTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "audio_transmitted_packets_total"),
 			"(AudioTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		CDRReceivedBytes: prometheus.NewDesc(
+		CDRReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cdr_received_bytes_total"),
 			"(CDRReceivedBytes)",
 			nil,
 			nil,
 		),
-		CDRReceivedPackets: prometheus.NewDesc(
+		CDRReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cdr_received_packets_total"),
 			"(CDRReceivedPackets)",
 			nil,
 			nil,
 		),
-		CDRTransmittedBytes: prometheus.NewDesc(
+		CDRTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cdr_transmitted_bytes_total"),
 			"(CDRTransmittedBytes)",
 			nil,
 			nil,
 		),
-		CDRTransmittedPackets: prometheus.NewDesc(
+		CDRTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "cdr_transmitted_packets_total"),
 			"(CDRTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		ClipboardReceivedBytes: prometheus.NewDesc(
+		ClipboardReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clipboard_received_bytes_total"),
 			"(ClipboardReceivedBytes)",
 			nil,
 			nil,
 		),
-		ClipboardReceivedPackets: prometheus.NewDesc(
+		ClipboardReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clipboard_received_packets_total"),
 			"(ClipboardReceivedPackets)",
 			nil,
 			nil,
 		),
-		ClipboardTransmittedBytes: prometheus.NewDesc(
+		ClipboardTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clipboard_transmitted_bytes_total"),
 			"(ClipboardTransmittedBytes)",
 			nil,
 			nil,
 		),
-		ClipboardTransmittedPackets: prometheus.NewDesc(
+		ClipboardTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "clipboard_transmitted_packets_total"),
 			"(ClipboardTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		HTML5MMRReceivedBytes: prometheus.NewDesc(
+		HTML5MMRReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "html5_mmr_received_bytes_total"),
 			"(HTML5MMRReceivedBytes)",
 			nil,
 			nil,
 		),
-		HTML5MMRReceivedPackets: prometheus.NewDesc(
+		HTML5MMRReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "html5_mmr_received_packets_total"),
 			"(HTML5MMRReceivedPackets)",
 			nil,
 			nil,
 		),
-		HTML5MMRTransmittedBytes: prometheus.NewDesc(
+		HTML5MMRTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "html5_mmr_transmitted_bytes_total"),
 			"(HTML5MMRTransmittedBytes)",
 			nil,
 			nil,
 		),
-		HTML5MMRTransmittedPackets: prometheus.NewDesc(
+		HTML5MMRTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "html5_mmr_transmitted_packets_total"),
 			"(HTML5MMRTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		ImagingDirtyFramesPerSecond: prometheus.NewDesc(
+		ImagingDirtyFramesPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_dirty_frames_per_second"),
 			"(ImagingDirtyFramesPerSecond)",
 			nil,
 			nil,
 		),
-		ImagingFBCRate: prometheus.NewDesc(
+		ImagingFBCRate: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_fbc_rate"),
 			"(ImagingFBCRate)",
 			nil,
 			nil,
 		),
-		ImagingFramesPerSecond: prometheus.NewDesc(
+		ImagingFramesPerSecond: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_frames_per_second"),
 			"(ImagingFramesPerSecond)",
 			nil,
 			nil,
 		),
-		ImagingPollRate: prometheus.NewDesc(
+		ImagingPollRate: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_poll_rate"),
 			"(ImagingPollRate)",
 			nil,
 			nil,
 		),
-		ImagingReceivedBytes: prometheus.NewDesc(
+		ImagingReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_received_bytes_total"),
 			"(ImagingReceivedBytes)",
 			nil,
 			nil,
 		),
-		ImagingReceivedPackets: prometheus.NewDesc(
+		ImagingReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_received_packets_total"),
 			"(ImagingReceivedPackets)",
 			nil,
 			nil,
 		),
-		ImagingTotalDirtyFrames: prometheus.NewDesc(
+		ImagingTotalDirtyFrames: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_total_dirty_frames_total"),
 			"(ImagingTotalDirtyFrames)",
 			nil,
 			nil,
 		),
-		ImagingTotalFBC: prometheus.NewDesc(
+		ImagingTotalFBC: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_fbc_total"),
 			"(ImagingTotalFBC)",
 			nil,
 			nil,
 		),
-		ImagingTotalFrames: prometheus.NewDesc(
+		ImagingTotalFrames: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_frames_total"),
 			"(ImagingTotalFrames)",
 			nil,
 			nil,
 		),
-		ImagingTotalPoll: prometheus.NewDesc(
+		ImagingTotalPoll: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_poll_total"),
 			"(ImagingTotalPoll)",
 			nil,
 			nil,
 		),
-		ImagingTransmittedBytes: prometheus.NewDesc(
+		ImagingTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_transmitted_bytes_total"),
 			"(ImagingTransmittedBytes)",
 			nil,
 			nil,
 		),
-		ImagingTransmittedPackets: prometheus.NewDesc(
+		ImagingTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "imaging_transmitted_packets_total"),
 			"(ImagingTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		RTAVReceivedBytes: prometheus.NewDesc(
+		RTAVReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rtav_received_bytes_total"),
 			"(RTAVReceivedBytes)",
 			nil,
 			nil,
 		),
-		RTAVReceivedPackets: prometheus.NewDesc(
+		RTAVReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rtav_received_packets_total"),
 			"(RTAVReceivedPackets)",
 			nil,
 			nil,
 		),
-		RTAVTransmittedBytes: prometheus.NewDesc(
+		RTAVTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rtav_transmitted_bytes_total"),
 			"(RTAVTransmittedBytes)",
 			nil,
 			nil,
 		),
-		RTAVTransmittedPackets: prometheus.NewDesc(
+		RTAVTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "rtav_transmitted_packets_total"),
 			"(RTAVTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		SerialPortandScannerReceivedBytes: prometheus.NewDesc(
+		SerialPortandScannerReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "serial_port_and_scanner_received_bytes_total"),
 			"(SerialPortandScannerReceivedBytes)",
 			nil,
 			nil,
 		),
-		SerialPortandScannerReceivedPackets: prometheus.NewDesc(
+		SerialPortandScannerReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "serial_port_and_scanner_received_packets_total"),
 			"(SerialPortandScannerReceivedPackets)",
 			nil,
 			nil,
 		),
-		SerialPortandScannerTransmittedBytes: prometheus.NewDesc(
+		SerialPortandScannerTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "serial_port_and_scanner_transmitted_bytes_total"),
 			"(SerialPortandScannerTransmittedBytes)",
 			nil,
 			nil,
 		),
-		SerialPortandScannerTransmittedPackets: prometheus.NewDesc(
+		SerialPortandScannerTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "serial_port_and_scanner_transmitted_packets_total"),
 			"(SerialPortandScannerTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		SessionAutomaticReconnectCount: prometheus.NewDesc(
+		SessionAutomaticReconnectCount: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_automatic_reconnect_count_total"),
 			"(SessionAutomaticReconnectCount)",
 			nil,
 			nil,
 		),
-		SessionCumulativeReceivedBytesOverTCP: prometheus.NewDesc(
+		SessionCumulativeReceivedBytesOverTCP: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_cumlative_received_bytes_over_tcp_total"),
 			"(SessionCumulativeReceivedBytesOverTCP)",
 			nil,
 			nil,
 		),
-		SessionCumulativeReceivedBytesOverUDP: prometheus.NewDesc(
+		SessionCumulativeReceivedBytesOverUDP: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_cumlative_received_bytes_over_udp_total"),
 			"(SessionCumulativeReceivedBytesOverUDP)",
 			nil,
 			nil,
 		),
-		SessionCumulativeTransmittedBytesOverTCP: prometheus.NewDesc(
+		SessionCumulativeTransmittedBytesOverTCP: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_cumlative_transmitted_bytes_over_tcp_total"),
 			"(SessionCumulativeTransmittedBytesOverTCP)",
 			nil,
 			nil,
 		),
-		SessionCumulativeTransmittedBytesOverUDP: prometheus.NewDesc(
+		SessionCumulativeTransmittedBytesOverUDP: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_cumlative_transmitted_bytes_over_udp_total"),
 			"(SessionCumulativeTransmittedBytesOverUDP)",
 			nil,
 			nil,
 		),
-		SessionEstimatedBandwidthUplink: prometheus.NewDesc(
+		SessionEstimatedBandwidthUplink: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_estimated_bandwidth_uplink"),
 			"(SessionEstimatedBandwidthUplink)",
 			nil,
 			nil,
 		),
-		SessionInstantaneousReceivedBytesOverTCP: prometheus.NewDesc(
+		SessionInstantaneousReceivedBytesOverTCP: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_instantaneous_received_bytes_over_tcp_total"),
 			"(SessionInstantaneousReceivedBytesOverTCP)",
 			nil,
 			nil,
 		),
-		SessionInstantaneousReceivedBytesOverUDP: prometheus.NewDesc(
+		SessionInstantaneousReceivedBytesOverUDP: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_instantaneous_received_bytes_over_udp_total"),
 			"(SessionInstantaneousReceivedBytesOverUDP)",
 			nil,
 			nil,
 		),
-		SessionInstantaneousTransmittedBytesOverTCP: prometheus.NewDesc(
+		SessionInstantaneousTransmittedBytesOverTCP: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_instantaneous_transmitted_bytes_over_tcp_total"),
 			"(SessionInstantaneousTransmittedBytesOverTCP)",
 			nil,
 			nil,
 		),
-		SessionInstantaneousTransmittedBytesOverUDP: prometheus.NewDesc(
+		SessionInstantaneousTransmittedBytesOverUDP: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_instantaneous_transmitted_bytes_over_udp_total"),
 			"(SessionInstantaneousTransmittedBytesOverUDP)",
 			nil,
 			nil,
 		),
-		SessionJitterUplink: prometheus.NewDesc(
+		SessionJitterUplink: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_jitter_uplink"),
 			"(SessionJitterUplink)",
 			nil,
 			nil,
 		),
-		SessionPacketLossUplink: prometheus.NewDesc(
+		SessionPacketLossUplink: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_packet_loss_uplink"),
 			"(SessionPacketLossUplink)",
 			nil,
 			nil,
 		),
-		SessionReceivedBytes: prometheus.NewDesc(
+		SessionReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_received_bytes_total"),
 			"(SessionReceivedBytes)",
 			nil,
 			nil,
 		),
-		SessionReceivedPackets: prometheus.NewDesc(
+		SessionReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_received_packets_total"),
 			"(SessionReceivedPackets)",
 			nil,
 			nil,
 		),
-		SessionRTT: prometheus.NewDesc(
+		SessionRTT: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_rtt"),
 			"(SessionRTT)",
 			nil,
 			nil,
 		),
-		SessionTransmittedBytes: prometheus.NewDesc(
+		SessionTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_transmitted_bytes_total"),
 			"(SessionTransmittedBytes)",
 			nil,
 			nil,
 		),
-		SessionTransmittedPackets: prometheus.NewDesc(
+		SessionTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "session_transmitted_packets_total"),
 			"(SessionTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		SkypeforBusinessControlReceivedBytes: prometheus.NewDesc(
+		SkypeforBusinessControlReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "skype_for_business_control_received_bytes_total"),
 			"(SkypeforBusinessControlReceivedBytes)",
 			nil,
 			nil,
 		),
-		SkypeforBusinessControlReceivedPackets: prometheus.NewDesc(
+		SkypeforBusinessControlReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "skype_for_business_control_received_packets_total"),
 			"(SkypeforBusinessControlReceivedPackets)",
 			nil,
 			nil,
 		),
-		SkypeforBusinessControlTransmittedBytes: prometheus.NewDesc(
+		SkypeforBusinessControlTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "skype_for_business_control_transmitted_bytes_total"),
 			"(SkypeforBusinessControlTransmittedBytes)",
 			nil,
 			nil,
 		),
-		SkypeforBusinessControlTransmittedPackets: prometheus.NewDesc(
+		SkypeforBusinessControlTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "skype_for_business_control_transmitted_packets_total"),
 			"(SkypeforBusinessControlTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		ThinPrintReceivedBytes: prometheus.NewDesc(
+		ThinPrintReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "thinprint_received_bytes_total"),
 			"(ThinPrintReceivedBytes)",
 			nil,
 			nil,
 		),
-		ThinPrintReceivedPackets: prometheus.NewDesc(
+		ThinPrintReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "thinprint_received_packets_total"),
 			"(ThinPrintReceivedPackets)",
 			nil,
 			nil,
 		),
-		ThinPrintTransmittedBytes: prometheus.NewDesc(
+		ThinPrintTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "thinprint_transmitted_bytes_total"),
 			"(ThinPrintTransmittedBytes)",
 			nil,
 			nil,
 		),
-		ThinPrintTransmittedPackets: prometheus.NewDesc(
+		ThinPrintTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "thinprint_transmitted_packets_total"),
 			"(ThinPrintTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		USBReceivedBytes: prometheus.NewDesc(
+		USBReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "usb_received_bytes_total"),
 			"(USBReceivedBytes)",
 			nil,
 			nil,
 		),
-		USBReceivedPackets: prometheus.NewDesc(
+		USBReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "usb_received_packets_total"),
 			"(USBReceivedPackets)",
 			nil,
 			nil,
 		),
-		USBTransmittedBytes: prometheus.NewDesc(
+		USBTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "usb_transmitted_bytes_total"),
 			"(USBTransmittedBytes)",
 			nil,
 			nil,
 		),
-		USBTransmittedPackets: prometheus.NewDesc(
+		USBTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "usb_transmitted_packets_total"),
 			"(USBTransmittedPackets)",
 			nil,
 			nil,
 		),
 
-		WindowsMediaMMRReceivedBytes: prometheus.NewDesc(
+		WindowsMediaMMRReceivedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "windows_media_mmr_received_bytes_total"),
 			"(WindowsMediaMMRReceivedBytes)",
 			nil,
 			nil,
 		),
-		WindowsMediaMMRReceivedPackets: prometheus.NewDesc(
+		WindowsMediaMMRReceivedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "windows_media_mmr_received_packets_total"),
 			"(WindowsMediaMMRReceivedPackets)",
 			nil,
 			nil,
 		),
-		WindowsMediaMMRTransmittedBytes: prometheus.NewDesc(
+		WindowsMediaMMRTransmittedBytes: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "windows_media_mmr_transmitted_bytes_total"),
 			"(WindowsMediaMMRTransmittedBytes)",
 			nil,
 			nil,
 		),
-		WindowsMediaMMRTransmittedPackets: prometheus.NewDesc(
+		WindowsMediaMMRTransmittedPackets: newDesc(
 			prometheus.BuildFQName(Namespace, subsystem, "windows_media_mmr_transmitted_packets_total"),
 			"(WindowsMediaMMRTransmittedPackets)",
 			nil,
@@ -542,54 +547,127 @@ func newVmwareBlastCollector() (Collector, error) {
 	}, nil
 }
 
+// Describe sends the descriptors of the metrics the collector emits.
+func (c *vmwareBlastCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.AudioReceivedBytes
+	ch <- c.AudioReceivedPackets
+	ch <- c.AudioTransmittedBytes
+	ch <- c.AudioTransmittedPackets
+	ch <- c.CDRReceivedBytes
+	ch <- c.CDRReceivedPackets
+	ch <- c.CDRTransmittedBytes
+	ch <- c.CDRTransmittedPackets
+	ch <- c.ClipboardReceivedBytes
+	ch <- c.ClipboardReceivedPackets
+	ch <- c.ClipboardTransmittedBytes
+	ch <- c.ClipboardTransmittedPackets
+	ch <- c.HTML5MMRReceivedBytes
+	ch <- c.HTML5MMRReceivedPackets
+	ch <- c.HTML5MMRTransmittedBytes
+	ch <- c.HTML5MMRTransmittedPackets
+	ch <- c.ImagingDirtyFramesPerSecond
+	ch <- c.ImagingFBCRate
+	ch <- c.ImagingFramesPerSecond
+	ch <- c.ImagingPollRate
+	ch <- c.ImagingReceivedBytes
+	ch <- c.ImagingReceivedPackets
+	ch <- c.ImagingTotalDirtyFrames
+	ch <- c.ImagingTotalFBC
+	ch <- c.ImagingTotalFrames
+	ch <- c.ImagingTotalPoll
+	ch <- c.ImagingTransmittedBytes
+	ch <- c.ImagingTransmittedPackets
+	ch <- c.RTAVReceivedBytes
+	ch <- c.RTAVReceivedPackets
+	ch <- c.RTAVTransmittedBytes
+	ch <- c.RTAVTransmittedPackets
+	ch <- c.SerialPortandScannerReceivedBytes
+	ch <- c.SerialPortandScannerReceivedPackets
+	ch <- c.SerialPortandScannerTransmittedBytes
+	ch <- c.SerialPortandScannerTransmittedPackets
+	ch <- c.SessionAutomaticReconnectCount
+	ch <- c.SessionCumulativeReceivedBytesOverTCP
+	ch <- c.SessionCumulativeReceivedBytesOverUDP
+	ch <- c.SessionCumulativeTransmittedBytesOverTCP
+	ch <- c.SessionCumulativeTransmittedBytesOverUDP
+	ch <- c.SessionEstimatedBandwidthUplink
+	ch <- c.SessionInstantaneousReceivedBytesOverTCP
+	ch <- c.SessionInstantaneousReceivedBytesOverUDP
+	ch <- c.SessionInstantaneousTransmittedBytesOverTCP
+	ch <- c.SessionInstantaneousTransmittedBytesOverUDP
+	ch <- c.SessionJitterUplink
+	ch <- c.SessionPacketLossUplink
+	ch <- c.SessionReceivedBytes
+	ch <- c.SessionReceivedPackets
+	ch <- c.SessionRTT
+	ch <- c.SessionTransmittedBytes
+	ch <- c.SessionTransmittedPackets
+	ch <- c.SkypeforBusinessControlReceivedBytes
+	ch <- c.SkypeforBusinessControlReceivedPackets
+	ch <- c.SkypeforBusinessControlTransmittedBytes
+	ch <- c.SkypeforBusinessControlTransmittedPackets
+	ch <- c.ThinPrintReceivedBytes
+	ch <- c.ThinPrintReceivedPackets
+	ch <- c.ThinPrintTransmittedBytes
+	ch <- c.ThinPrintTransmittedPackets
+	ch <- c.USBReceivedBytes
+	ch <- c.USBReceivedPackets
+	ch <- c.USBTransmittedBytes
+	ch <- c.USBTransmittedPackets
+	ch <- c.WindowsMediaMMRReceivedBytes
+	ch <- c.WindowsMediaMMRReceivedPackets
+	ch <- c.WindowsMediaMMRTransmittedBytes
+	ch <- c.WindowsMediaMMRTransmittedPackets
+}
+
 // Collect sends the metric values for each metric
 // to the provided prometheus Metric channel.
 func (c *vmwareBlastCollector) Collect(ctx *ScrapeContext, ch chan<- prometheus.Metric) error {
-	if desc, err := c.collectAudio(ch); err != nil {
+	if desc, err := c.collectAudio(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast audio metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectCdr(ch); err != nil {
+	if desc, err := c.collectCdr(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast CDR metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectClipboard(ch); err != nil {
+	if desc, err := c.collectClipboard(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast clipboard metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectHtml5Mmr(ch); err != nil {
+	if desc, err := c.collectHtml5Mmr(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast HTML5 MMR metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectImaging(ch); err != nil {
+	if desc, err := c.collectImaging(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast imaging metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectRtav(ch); err != nil {
+	if desc, err := c.collectRtav(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast RTAV metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectSerialPortandScanner(ch); err != nil {
+	if desc, err := c.collectSerialPortandScanner(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast serial port and scanner metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectSession(ch); err != nil {
+	if desc, err := c.collectSession(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectSkypeforBusinessControl(ch); err != nil {
+	if desc, err := c.collectSkypeforBusinessControl(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast skype for business control metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectThinPrint(ch); err != nil {
+	if desc, err := c.collectThinPrint(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast thin print metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectUsb(ch); err != nil {
+	if desc, err := c.collectUsb(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast USB metrics:", desc, err)
 		return err
 	}
-	if desc, err := c.collectWindowsMediaMmr(ch); err != nil {
+	if desc, err := c.collectWindowsMediaMmr(ctx, ch); err != nil {
 		log.Error("failed collecting vmware blast windows media MMR metrics:", desc, err)
 		return err
 	}
@@ -701,10 +779,10 @@ type win32_PerfRawData_Counters_VMwareBlastWindowsMediaMMRCounters struct {
 	TransmittedPackets uint32
 }
 
-func (c *vmwareBlastCollector) collectAudio(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectAudio(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastAudioCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -740,10 +818,10 @@ func (c *vmwareBlastCollector) collectAudio(ch chan<- prometheus.Metric) (*prome
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectCdr(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectCdr(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastCDRCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -779,10 +857,10 @@ func (c *vmwareBlastCollector) collectCdr(ch chan<- prometheus.Metric) (*prometh
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectClipboard(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectClipboard(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastClipboardCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -818,10 +896,10 @@ func (c *vmwareBlastCollector) collectClipboard(ch chan<- prometheus.Metric) (*p
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectHtml5Mmr(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectHtml5Mmr(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastHTML5MMRcounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -857,10 +935,10 @@ func (c *vmwareBlastCollector) collectHtml5Mmr(ch chan<- prometheus.Metric) (*pr
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectImaging(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectImaging(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastImagingCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -944,10 +1022,10 @@ func (c *vmwareBlastCollector) collectImaging(ch chan<- prometheus.Metric) (*pro
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectRtav(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectRtav(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastRTAVCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -983,10 +1061,10 @@ func (c *vmwareBlastCollector) collectRtav(ch chan<- prometheus.Metric) (*promet
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectSerialPortandScanner(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectSerialPortandScanner(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastSerialPortandScannerCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1022,10 +1100,10 @@ func (c *vmwareBlastCollector) collectSerialPortandScanner(ch chan<- prometheus.
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectSession(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectSession(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastSessionCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1139,10 +1217,10 @@ func (c *vmwareBlastCollector) collectSession(ch chan<- prometheus.Metric) (*pro
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectSkypeforBusinessControl(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectSkypeforBusinessControl(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastSkypeforBusinessControlCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1178,10 +1256,10 @@ func (c *vmwareBlastCollector) collectSkypeforBusinessControl(ch chan<- promethe
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectThinPrint(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectThinPrint(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastThinPrintCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1217,10 +1295,10 @@ func (c *vmwareBlastCollector) collectThinPrint(ch chan<- prometheus.Metric) (*p
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectUsb(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectUsb(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastUSBCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
@@ -1256,10 +1334,10 @@ func (c *vmwareBlastCollector) collectUsb(ch chan<- prometheus.Metric) (*prometh
 	return nil, nil
 }
 
-func (c *vmwareBlastCollector) collectWindowsMediaMmr(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+func (c *vmwareBlastCollector) collectWindowsMediaMmr(ctx *ScrapeContext, ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
 	var dst []win32_PerfRawData_Counters_VMwareBlastWindowsMediaMMRCounters
 	q := queryAll(&dst)
-	if err := wmi.Query(q, &dst); err != nil {
+	if err := wmiQuery(ctx, q, &dst); err != nil {
 		return nil, err
 	}
 
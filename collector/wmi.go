@@ -5,6 +5,7 @@ import (
 	"reflect"
 
 	"github.com/prometheus-community/windows_exporter/log"
+	"github.com/yusufpapurcu/wmi"
 )
 
 func className(src interface{}) string {
@@ -61,3 +62,26 @@ func queryAllForClassWhere(src interface{}, class string, where string) string {
 	log.Debugf("Generated WMI query %s", b.String())
 	return b.String()
 }
+
+// wmiQuery runs query via the default WMI client and stores the result in dst,
+// like wmi.Query. It returns the context error as soon as the scrape context is
+// done. The COM call itself cannot be interrupted, so it is left to finish in the
+// background and its result is discarded, see runQuery.
+func wmiQuery(ctx *ScrapeContext, query string, dst interface{}, connectServerArgs ...interface{}) error {
+	if err := ctx.ctx.Err(); err != nil {
+		return err
+	}
+	ctx.recordQuery(query)
+
+	return runQuery(ctx.ctx, ctx.collector, func() error {
+		if err := wmi.Query(query, dst, connectServerArgs...); err != nil {
+			return classifiedError{ErrorClassWMI, err}
+		}
+		return nil
+	})
+}
+
+// wmiQueryNamespace is the context-aware equivalent of wmi.QueryNamespace.
+func wmiQueryNamespace(ctx *ScrapeContext, query string, dst interface{}, namespace string) error {
+	return wmiQuery(ctx, query, dst, nil, namespace)
+}
@@ -1,6 +1,8 @@
 package collector
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -113,3 +115,14 @@ func TestCreateQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestWMIQueryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var dst []fakeWmiClass
+	err := wmiQuery(&ScrapeContext{ctx: ctx}, queryAll(&dst), &dst)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
@@ -14,71 +14,521 @@
 package config
 
 import (
-	"io/ioutil"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus-community/windows_exporter/log"
 	"gopkg.in/yaml.v3"
 )
 
-type getFlagger interface {
-	GetFlag(name string) *kingpin.FlagClause
-}
-
 // Resolver represents a configuration file resolver for kingpin.
+//
+// The configuration files follow the schema of Config: every setting is a
+// field of Config, and sets the default of the flag the field is tagged with,
+// so that the flags given on the command line override it. Keys which are not
+// fields of Config, and values which are not valid values of their field, are
+// errors.
+//
+// The configuration may be split across several files, which are merged in
+// order: a later file overrides the single values of an earlier one, adds to
+// its lists and relabel_configs, and overrides its mappings and labels key by
+// key.
 type Resolver struct {
-	flags map[string]string
+	files      []configFile
+	relabeling relabeling
+	// sources describes where the value of each flag bound by the resolver
+	// comes from.
+	sources map[string]string
 }
 
-// NewResolver returns a Resolver structure.
-func NewResolver(file string) (*Resolver, error) {
-	flags := map[string]string{}
-	log.Infof("Loading configuration file: %v", file)
-	if _, err := os.Stat(file); err != nil {
+// configFile is a parsed configuration file.
+type configFile struct {
+	path string
+	// root is the top-level mapping of the file.
+	root *yaml.Node
+}
+
+// NewResolver returns a Resolver structure merging the configuration files at
+// paths, in order. Directories stand for the .yml and .yaml files they
+// contain, sorted by name. References to environment variables and files in
+// the values of the files are expanded, see reference.
+func NewResolver(paths ...string) (*Resolver, error) {
+	files, err := Files(paths)
+	if err != nil {
 		return nil, err
 	}
-	b, err := ioutil.ReadFile(file)
+
+	c := &Resolver{relabeling: relabeling{Labels: map[string]string{}}}
+	for _, file := range files {
+		log.Infof("Loading configuration file: %v", file)
+		root, err := readConfigFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if err := expandReferences(root, filepath.Dir(file)); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		r, err := parseRelabeling(root)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for name, value := range r.Labels {
+			c.relabeling.Labels[name] = value
+		}
+		c.relabeling.RelabelConfigs = append(c.relabeling.RelabelConfigs, r.RelabelConfigs...)
+		c.files = append(c.files, configFile{path: file, root: root})
+	}
+	return c, nil
+}
+
+// Files returns the configuration files at paths, in the order in which they
+// are merged.
+func Files(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		// ReadDir sorts the entries by name.
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if entry.Type().IsRegular() && (ext == ".yml" || ext == ".yaml") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+func readConfigFile(file string) (*yaml.Node, error) {
+	b, err := os.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
 
-	var rawValues map[string]interface{}
-	err = yaml.Unmarshal(b, &rawValues)
-	if err != nil {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
 		return nil, err
 	}
-	// Flatten nested YAML values
-	flattenedValues := flatten(rawValues)
-	for k, v := range flattenedValues {
-		if _, ok := flags[k]; !ok {
-			flags[k] = v
-		}
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of settings", root.Line)
 	}
-	return &Resolver{flags: flags}, nil
+	return root, nil
+}
+
+// Labels returns the static labels added to every series, from the labels
+// section of the configuration file.
+func (c *Resolver) Labels() map[string]string {
+	return c.relabeling.Labels
 }
 
-func (c *Resolver) setDefault(v getFlagger) {
-	for name, value := range c.flags {
-		f := v.GetFlag(name)
-		if f != nil {
-			f.Default(value)
+// RelabelConfigs returns the relabeling rules from the relabel_configs section
+// of the configuration file.
+func (c *Resolver) RelabelConfigs() []RelabelConfig {
+	return c.relabeling.RelabelConfigs
+}
+
+// Sources describes where the value of every flag set by the configuration
+// comes from, as of the last Bind or Parse: the files and lines of the
+// settings it was merged from, or the command line if the flag was also given
+// there.
+func (c *Resolver) Sources() map[string]string {
+	return c.sources
+}
+
+// FlagDefaults holds the default values of the flags of an application.
+type FlagDefaults map[string][]string
+
+// SaveDefaults returns the default values of the flags of app, before a
+// configuration file is bound to it.
+func SaveDefaults(app *kingpin.Application) FlagDefaults {
+	defaults := FlagDefaults{}
+	for _, f := range app.Model().Flags {
+		defaults[f.Name] = f.Default
+	}
+	return defaults
+}
+
+// FlagValues returns the values given to the named flag in args, without
+// parsing them. Invalid arguments are left for kingpin to report once parsed.
+func FlagValues(app *kingpin.Application, args []string, name string) []string {
+	pc, err := app.ParseContext(args)
+	if err != nil {
+		return nil
+	}
+	var values []string
+	for _, element := range pc.Elements {
+		if f, ok := element.Clause.(*kingpin.FlagClause); ok && f.Model().Name == name && element.Value != nil {
+			values = append(values, *element.Value)
 		}
 	}
+	return values
 }
 
 // Bind sets active flags with their default values from the configuration file(s).
+// No flag is changed if any setting is invalid.
 func (c *Resolver) Bind(app *kingpin.Application, args []string) error {
+	flags, settings, err := c.resolve(app, args)
+	if err != nil {
+		return err
+	}
+	for name, s := range settings {
+		flags[name].Default(s.values...)
+	}
+	return nil
+}
+
+// resolve returns the active flags of app and the values the configuration
+// files set them to, and records where the values come from.
+func (c *Resolver) resolve(app *kingpin.Application, args []string) (map[string]*kingpin.FlagClause, map[string]setting, error) {
 	// Parse the command line arguments to get the selected command.
 	pc, err := app.ParseContext(args)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	c.setDefault(app)
+	flags := make(map[string]*kingpin.FlagClause)
+	for _, f := range app.Model().Flags {
+		flags[f.Name] = app.GetFlag(f.Name)
+	}
 	if pc.SelectedCommand != nil {
-		c.setDefault(pc.SelectedCommand)
+		for _, f := range pc.SelectedCommand.Model().Flags {
+			flags[f.Name] = pc.SelectedCommand.GetFlag(f.Name)
+		}
 	}
 
-	return nil
+	settings, err := c.settings(flags)
+	if err != nil {
+		return nil, nil, err
+	}
+	given := make(map[string]bool)
+	for _, element := range pc.Elements {
+		if f, ok := element.Clause.(*kingpin.FlagClause); ok {
+			given[f.Model().Name] = true
+		}
+	}
+	c.sources = make(map[string]string, len(settings))
+	for name, s := range settings {
+		c.sources[name] = strings.Join(s.sources, ", ")
+		if given[name] {
+			c.sources[name] = "the command line, overriding " + c.sources[name]
+		}
+	}
+	return flags, settings, nil
+}
+
+// setting is the value of a flag set by the configuration files.
+type setting struct {
+	values []string
+	// sources are the files and lines of the settings the value is merged
+	// from.
+	sources []string
+}
+
+// settings returns the values of the flags set by the configuration files,
+// reporting every invalid or unknown setting.
+func (c *Resolver) settings(flags map[string]*kingpin.FlagClause) (map[string]setting, error) {
+	settings := make(map[string]setting)
+	var errs []error
+	for _, file := range c.files {
+		var walk func(prefix string, section reflect.Type, node *yaml.Node)
+		walk = func(prefix string, section reflect.Type, node *yaml.Node) {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], resolveAlias(node.Content[i+1])
+				name := key.Value
+				if prefix != "" {
+					name = prefix + "." + name
+				}
+				field, ok := fieldByKey(section, key.Value)
+				if !ok {
+					errs = append(errs, fmt.Errorf("%s: line %d: unknown setting %s", file.path, key.Line, name))
+					continue
+				}
+
+				flag := field.Tag.Get("flag")
+				switch {
+				case flag != "":
+					f, ok := flags[flag]
+					if !ok {
+						errs = append(errs, fmt.Errorf("%s: line %d: unknown setting %s", file.path, key.Line, name))
+						continue
+					}
+					values, err := settingValues(field.Type, f.Model(), value)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("%s: line %d: %s: %w", file.path, value.Line, name, err))
+						continue
+					}
+					source := fmt.Sprintf("%s:%d", file.path, key.Line)
+					settings[flag] = mergeSetting(field.Type, f.Model(), settings[flag], setting{values: values, sources: []string{source}})
+				case field.Type.Kind() == reflect.Struct:
+					if value.Kind != yaml.MappingNode {
+						errs = append(errs, fmt.Errorf("%s: line %d: %s: expected a mapping of settings", file.path, value.Line, name))
+						continue
+					}
+					walk(name, field.Type, value)
+				}
+				// The other fields, labels and relabel_configs, are read by
+				// parseRelabeling.
+			}
+		}
+		walk("", reflect.TypeOf(Config{}), file.root)
+	}
+	return settings, errors.Join(errs...)
+}
+
+// fieldByKey returns the field of the section struct holding the setting of
+// the configuration file with the given key.
+func fieldByKey(section reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < section.NumField(); i++ {
+		field := section.Field(i)
+		if field.Tag.Get("yaml") == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// Flags returns the names of the flags set by the fields of Config, in the
+// order of the fields.
+func Flags() []string {
+	var flags []string
+	var walk func(section reflect.Type)
+	walk = func(section reflect.Type) {
+		for i := 0; i < section.NumField(); i++ {
+			field := section.Field(i)
+			if flag := field.Tag.Get("flag"); flag != "" {
+				flags = append(flags, flag)
+			} else if field.Type.Kind() == reflect.Struct {
+				walk(field.Type)
+			}
+		}
+	}
+	walk(reflect.TypeOf(Config{}))
+	return flags
+}
+
+// mergeSetting returns the value of a flag set by next, in a file merged after
+// the one which set previous, if any. t is the type of the field of the
+// setting.
+func mergeSetting(t reflect.Type, f *kingpin.FlagModel, previous, next setting) setting {
+	if len(previous.values) == 0 {
+		return next
+	}
+	merged := setting{sources: append(previous.sources, next.sources...)}
+	switch {
+	case isCumulative(f.Value):
+		merged.values = append(previous.values, next.values...)
+	case t.Kind() == reflect.Slice:
+		merged.values = []string{joinItems(t, nonEmpty(previous.values[0], next.values[0]))}
+	case t.Kind() == reflect.Map:
+		merged.values = []string{mergePairs(previous.values[0], next.values[0], pairSeparator(t))}
+	default:
+		return next
+	}
+	return merged
+}
+
+// nonEmpty returns the items which are not empty.
+func nonEmpty(items ...string) []string {
+	var kept []string
+	for _, item := range items {
+		if item != "" {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// mergePairs merges two lists of key=value pairs joined with sep, the pairs
+// of next replacing those of previous with the same key.
+func mergePairs(previous, next, sep string) string {
+	var keys []string
+	values := make(map[string]string)
+	for _, list := range []string{previous, next} {
+		for _, pair := range strings.Split(list, sep) {
+			if pair == "" {
+				continue
+			}
+			key, _, _ := strings.Cut(pair, "=")
+			if _, ok := values[key]; !ok {
+				keys = append(keys, key)
+			}
+			values[key] = pair
+		}
+	}
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, values[key])
+	}
+	return strings.Join(pairs, sep)
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode {
+		return node.Alias
+	}
+	return node
+}
+
+var (
+	regexpsType      = reflect.TypeOf(Regexps(nil))
+	whereClausesType = reflect.TypeOf(WhereClauses(nil))
+	durationType     = reflect.TypeOf(time.Duration(0))
+)
+
+// joinItems returns the value of a flag which is not repeatable holding the
+// items of a list field of type t.
+func joinItems(t reflect.Type, items []string) string {
+	switch t {
+	case regexpsType:
+		return Regexps(items).String()
+	case whereClausesType:
+		return WhereClauses(items).String()
+	default:
+		return strings.Join(items, ",")
+	}
+}
+
+// pairSeparator returns the separator of the key=value pairs of the flag of a
+// mapping field of type t. Values which are lists are joined with commas, so
+// their pairs are separated by semicolons.
+func pairSeparator(t reflect.Type) string {
+	if t.Elem().Kind() == reflect.Slice {
+		return ";"
+	}
+	return ","
+}
+
+// settingValues returns the values the node sets the flag f of a field of type
+// t to.
+func settingValues(t reflect.Type, f *kingpin.FlagModel, node *yaml.Node) ([]string, error) {
+	var values []string
+	switch t.Kind() {
+	case reflect.Slice:
+		items, err := listItems(node)
+		if err != nil {
+			return nil, err
+		}
+		if t == regexpsType {
+			for _, item := range items {
+				if _, err := regexp.Compile(item); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if isCumulative(f.Value) {
+			values = items
+		} else {
+			values = []string{joinItems(t, items)}
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil, errors.New("expected a mapping")
+		}
+		pairs := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], resolveAlias(node.Content[i+1])
+			var item string
+			if t.Elem().Kind() == reflect.Slice {
+				items, err := listItems(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", key.Value, err)
+				}
+				item = strings.Join(items, ",")
+			} else {
+				if value.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("line %d: expected a single value for %s", value.Line, key.Value)
+				}
+				item = value.Value
+			}
+			if _, err := parseItem(t.Elem(), item); err != nil {
+				return nil, fmt.Errorf("%s: %w", key.Value, err)
+			}
+			pairs = append(pairs, key.Value+"="+item)
+		}
+		values = []string{strings.Join(pairs, pairSeparator(t))}
+	default:
+		switch node.Kind {
+		case yaml.ScalarNode:
+			if t.Kind() == reflect.Bool && node.ShortTag() != "!!bool" {
+				return nil, fmt.Errorf("expected a boolean, got %q", node.Value)
+			}
+			values = []string{node.Value}
+		case yaml.SequenceNode:
+			return nil, errors.New("expected a single value, got a list")
+		case yaml.MappingNode:
+			return nil, errors.New("expected a single value, got a mapping")
+		default:
+			return nil, fmt.Errorf("unexpected value")
+		}
+	}
+
+	for _, v := range values {
+		if err := checkValue(f.Value, v); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// listItems returns the items of a list, or the single value a list may be
+// given as.
+func listItems(node *yaml.Node) ([]string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}, nil
+	case yaml.SequenceNode:
+		return scalars(node)
+	default:
+		return nil, errors.New("expected a value or a list")
+	}
+}
+
+// scalars returns the items of a list of values.
+func scalars(node *yaml.Node) ([]string, error) {
+	items := make([]string, 0, len(node.Content))
+	for _, item := range node.Content {
+		item = resolveAlias(item)
+		if item.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: expected a list of values", item.Line)
+		}
+		items = append(items, item.Value)
+	}
+	return items, nil
+}
+
+func isCumulative(v kingpin.Value) bool {
+	r, ok := v.(interface{ IsCumulative() bool })
+	return ok && r.IsCumulative()
+}
+
+// checkValue reports whether s is a valid value for flags of the type of v,
+// without changing v. Values of flags which are not backed by a single
+// variable, such as repeatable flags, are left for kingpin to check.
+func checkValue(v kingpin.Value, s string) error {
+	t := reflect.TypeOf(v)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() == reflect.Struct {
+		return nil
+	}
+	return reflect.New(t.Elem()).Interface().(kingpin.Value).Set(s)
 }
@@ -0,0 +1,412 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+)
+
+func TestParse(t *testing.T) {
+	app := kingpin.New("test", "")
+	enabled := app.Flag("collectors.enabled", "").Default("cpu").String()
+	app.Flag("collectors.min-interval", "").String()
+	app.Flag("collectors.profiles", "").String()
+	whitelist := app.Flag("collector.process.whitelist", "").Default(".*").String()
+	app.Flag("collector.process.blacklist", "").String()
+	where := app.Flag("collector.service.services-where", "").String()
+	app.Flag("collector.service.use-api", "").Bool()
+	app.Flag("collectors.background-interval", "").Default("0s").Duration()
+	app.Flag("telemetry.drop", "").Strings()
+	args := []string{"--telemetry.drop", `{state="stopped"}`, "--telemetry.drop", `{state="paused"}`}
+	if _, err := app.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	defaults := SaveDefaults(app)
+
+	dir := t.TempDir()
+	parse := func(content string) *Config {
+		t.Helper()
+		file := filepath.Join(dir, "config.yml")
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		resolver, err := NewResolver(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Parse(app, defaults, resolver, args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg
+	}
+
+	cfg := parse(`collectors:
+  enabled: [os, cpu]
+  background-interval: 1d
+  min-interval:
+    os: 1m
+  profiles:
+    fast: [cpu, net]
+collector:
+  process:
+    whitelist: [firefox.*, chrome]
+  service:
+    services-where: Name='kubelet'
+    use-api: true
+labels:
+  datacenter: dc1
+`)
+	if !reflect.DeepEqual(cfg.Collectors.Enabled, []string{"os", "cpu"}) {
+		t.Errorf("expected the enabled collectors of the file, got %v", cfg.Collectors.Enabled)
+	}
+	if !reflect.DeepEqual(cfg.Collectors.MinInterval, map[string]time.Duration{"os": time.Minute}) {
+		t.Errorf("expected the minimum intervals of the file, got %v", cfg.Collectors.MinInterval)
+	}
+	if !reflect.DeepEqual(cfg.Collectors.Profiles, map[string][]string{"fast": {"cpu", "net"}}) {
+		t.Errorf("expected the profiles of the file, got %v", cfg.Collectors.Profiles)
+	}
+	if re := cfg.Collector.Process.Whitelist.Regexp(); !re.MatchString("chrome") || re.MatchString("chromium") {
+		t.Errorf("expected the whitelist to match any of its regexps, got %s", re)
+	}
+	if cfg.Collector.Process.Blacklist != nil {
+		t.Errorf("expected an empty blacklist, got %v", cfg.Collector.Process.Blacklist)
+	}
+	if cfg.Collector.Service.Where.String() != "Name='kubelet'" {
+		t.Errorf("expected the where clause of the file, got %q", cfg.Collector.Service.Where)
+	}
+	if !cfg.Collector.Service.UseAPI || cfg.Collectors.BackgroundInterval != 24*time.Hour {
+		t.Errorf("expected typed settings to be set, got %v and %v", cfg.Collector.Service.UseAPI, cfg.Collectors.BackgroundInterval)
+	}
+	if !reflect.DeepEqual(cfg.Telemetry.Drop, []string{`{state="stopped"}`, `{state="paused"}`}) {
+		t.Errorf("expected repeated flags to be parsed once, got %v", cfg.Telemetry.Drop)
+	}
+	if !reflect.DeepEqual(cfg.Labels, map[string]string{"datacenter": "dc1"}) {
+		t.Errorf("expected the labels of the file, got %v", cfg.Labels)
+	}
+	// Fields whose flag is not defined are left unset.
+	if cfg.Collectors.Required != nil || cfg.Log.Level != "" {
+		t.Errorf("expected settings without a flag to be unset, got %v and %q", cfg.Collectors.Required, cfg.Log.Level)
+	}
+
+	// Settings removed from the file fall back to their defaults.
+	cfg = parse("{}\n")
+	if !reflect.DeepEqual(cfg.Collectors.Enabled, []string{"cpu"}) || cfg.Collector.Service.Where != nil || cfg.Collector.Service.UseAPI {
+		t.Errorf("expected the settings to be reset to their defaults, got %v, %q and %v", cfg.Collectors.Enabled, cfg.Collector.Service.Where, cfg.Collector.Service.UseAPI)
+	}
+
+	// The variables of the flags are left alone.
+	if *enabled != "cpu" || *where != "" || *whitelist != ".*" {
+		t.Errorf("expected the variables of the flags to be left alone, got %q, %q and %q", *enabled, *where, *whitelist)
+	}
+
+	if _, err := Parse(app, defaults, newTestResolver(t, "collectors:\n  background-interval: soon\n"), args); err == nil {
+		t.Error("expected an error for an invalid setting")
+	}
+	for _, invalid := range [][]string{
+		{"--collectors.background-interval", "soon"},
+		{"--collectors.min-interval", "mssql"},
+		{"--collectors.min-interval", "=5m"},
+		{"--collectors.min-interval", "mssql=soon"},
+		{"--collectors.profiles", "fast"},
+		{"--collectors.profiles", "=cpu"},
+		{"--collectors.profiles", "fast=cpu;fast=net"},
+		{"--collector.process.whitelist", "(chrome"},
+	} {
+		if _, err := Parse(app, defaults, nil, invalid); err == nil {
+			t.Errorf("expected an error for the flags %q", invalid)
+		}
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	app := kingpin.New("test", "")
+	app.Flag("collectors.enabled", "").Default("cpu").String()
+	app.Flag("collectors.min-interval", "").String()
+	app.Flag("collectors.profiles", "").String()
+	app.Flag("collector.service.services-where", "").String()
+
+	resolver := newTestResolver(t, "collectors:\n  enabled: [os, net]\n")
+	cfg, err := Parse(app, SaveDefaults(app), resolver, []string{
+		"--collectors.enabled=cpu,,os",
+		"--collectors.min-interval=mssql=5m,hyperv=30s,",
+		"--collectors.profiles=fast=cpu,net;slow=mssql,cpu;",
+		"--collector.service.services-where=Name='kubelet'",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Collectors.Enabled, []string{"cpu", "os"}) {
+		t.Errorf("expected the command line to override the file, got %v", cfg.Collectors.Enabled)
+	}
+	if expected := map[string]time.Duration{"mssql": 5 * time.Minute, "hyperv": 30 * time.Second}; !reflect.DeepEqual(cfg.Collectors.MinInterval, expected) {
+		t.Errorf("expected %v, got %v", expected, cfg.Collectors.MinInterval)
+	}
+	if expected := map[string][]string{"fast": {"cpu", "net"}, "slow": {"mssql", "cpu"}}; !reflect.DeepEqual(cfg.Collectors.Profiles, expected) {
+		t.Errorf("expected %v, got %v", expected, cfg.Collectors.Profiles)
+	}
+	if !reflect.DeepEqual(cfg.Collector.Service.Where, WhereClauses{"Name='kubelet'"}) {
+		t.Errorf("expected a single where clause, got %q", cfg.Collector.Service.Where)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	flags := Flags()
+	seen := make(map[string]bool)
+	for _, flag := range flags {
+		if seen[flag] {
+			t.Errorf("flag %s is set by several settings", flag)
+		}
+		seen[flag] = true
+	}
+	for _, flag := range []string{"collectors.enabled", "collector.iis.site-whitelist", "remote-write.basic-auth.username", "web.config.file"} {
+		if !seen[flag] {
+			t.Errorf("expected flag %s to be set by a setting", flag)
+		}
+	}
+}
+
+func newTestResolver(t *testing.T, content string) *Resolver {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resolver, err := NewResolver(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolver
+}
+
+func TestBind(t *testing.T) {
+	app := kingpin.New("test", "")
+	enabled := app.Flag("collectors.enabled", "").Default("cpu").String()
+	profiles := app.Flag("collectors.profiles", "").String()
+	whitelist := app.Flag("collector.process.whitelist", "").Default(".*").String()
+	where := app.Flag("collector.service.services-where", "").String()
+	useAPI := app.Flag("collector.service.use-api", "").Bool()
+	interval := app.Flag("collectors.background-interval", "").Default("0s").Duration()
+	listen := app.Flag("web.listen-address", "").Default(":9182").Strings()
+
+	resolver := newTestResolver(t, `
+collectors:
+  enabled: [cpu, os]
+  profiles:
+    fast: [cpu, net]
+    slow: mssql
+  background-interval: 1m
+collector:
+  process:
+    whitelist:
+      - firefox.*
+      - chrome
+  service:
+    services-where: ["Name='kubelet'", "Name='containerd'"]
+    use-api: true
+web:
+  listen-address: [":9182", ":9183"]
+labels:
+  datacenter: dc1
+`)
+	if err := resolver.Bind(app, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for actual, expected := range map[string]string{
+		*enabled:   "cpu,os",
+		*profiles:  "fast=cpu,net;slow=mssql",
+		*whitelist: "(?:firefox.*)|(?:chrome)",
+		*where:     "(Name='kubelet') OR (Name='containerd')",
+	} {
+		if actual != expected {
+			t.Errorf("expected %q, got %q", expected, actual)
+		}
+	}
+	if !*useAPI || *interval != time.Minute {
+		t.Errorf("expected typed settings to be set, got %v and %v", *useAPI, *interval)
+	}
+	if !reflect.DeepEqual(*listen, []string{":9182", ":9183"}) {
+		t.Errorf("expected listen addresses from the list, got %v", *listen)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	app := kingpin.New("test", "")
+	enabled := app.Flag("collectors.enabled", "").Default("cpu").String()
+	app.Flag("collector.service.use-api", "").Bool()
+	app.Flag("collectors.background-interval", "").Default("0s").Duration()
+	app.Flag("collectors.min-interval", "").String()
+	app.Flag("log.level", "").Default("info").String()
+
+	resolver := newTestResolver(t, `collectors:
+  enabled: cpu
+  background-interval: soon
+  min-interval: [os]
+collector:
+  servce:
+    use-api: true
+  service:
+    use-api: "yes"
+log:
+  level: [debug]
+scrap:
+  timeout-margin: 0.5
+`)
+	err := resolver.Bind(app, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, expected := range []string{
+		`line 3: collectors.background-interval: time: invalid duration "soon"`,
+		"line 4: collectors.min-interval: expected a mapping",
+		"line 6: unknown setting collector.servce",
+		`line 9: collector.service.use-api: expected a boolean, got "yes"`,
+		"line 11: log.level: expected a single value, got a list",
+		"line 12: unknown setting scrap",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q, got:\n%s", expected, err)
+		}
+	}
+
+	// Invalid configuration files leave the flags unchanged.
+	if _, err := app.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if *enabled != "cpu" {
+		t.Errorf("expected collectors.enabled to keep its default, got %q", *enabled)
+	}
+}
+
+func TestNewResolverMergesFiles(t *testing.T) {
+	app := kingpin.New("test", "")
+	enabled := app.Flag("collectors.enabled", "").Default("cpu").String()
+	minInterval := app.Flag("collectors.min-interval", "").String()
+	whitelist := app.Flag("collector.process.whitelist", "").Default(".*").String()
+	level := app.Flag("log.level", "").Default("info").String()
+	format := app.Flag("log.format", "").Default("logger:stderr").String()
+	listen := app.Flag("web.listen-address", "").Default(":9182").Strings()
+
+	dir := t.TempDir()
+	confd := filepath.Join(dir, "conf.d")
+	if err := os.Mkdir(confd, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"base.yml": `collectors:
+  enabled: [cpu, os]
+  min-interval:
+    os: 1m
+    cpu: 10s
+log:
+  level: warn
+  format: logger:eventlog
+web:
+  listen-address: ":9182"
+labels:
+  datacenter: dc1
+  role: base
+`,
+		filepath.Join("conf.d", "20-mssql.yaml"): `collectors:
+  enabled: mssql
+log:
+  level: debug
+labels:
+  role: mssql
+relabel_configs:
+  - target_label: instance_role
+    replacement: mssql
+`,
+		filepath.Join("conf.d", "10-iis.yml"): `collectors:
+  enabled: [iis]
+  min-interval:
+    os: 5m
+collector:
+  process:
+    whitelist: w3wp
+web:
+  listen-address: [":9183"]
+relabel_configs:
+  - target_label: instance_role
+    replacement: iis
+`,
+		filepath.Join("conf.d", "README.txt"): "not a configuration file",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resolver, err := NewResolver(filepath.Join(dir, "base.yml"), confd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"--log.format=logger:stdout"}
+	if err := resolver.Bind(app, args); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	for actual, expected := range map[string]string{
+		*enabled:     "cpu,os,iis,mssql",
+		*minInterval: "os=5m,cpu=10s",
+		*whitelist:   "w3wp",
+		*level:       "debug",
+		*format:      "logger:stdout",
+	} {
+		if actual != expected {
+			t.Errorf("expected %q, got %q", expected, actual)
+		}
+	}
+	if !reflect.DeepEqual(*listen, []string{":9182", ":9183"}) {
+		t.Errorf("expected the listen addresses of both files, got %v", *listen)
+	}
+	if expected := map[string]string{"datacenter": "dc1", "role": "mssql"}; !reflect.DeepEqual(resolver.Labels(), expected) {
+		t.Errorf("expected labels %v, got %v", expected, resolver.Labels())
+	}
+	var replacements []string
+	for _, rc := range resolver.RelabelConfigs() {
+		replacements = append(replacements, rc.Replacement)
+	}
+	if !reflect.DeepEqual(replacements, []string{"iis", "mssql"}) {
+		t.Errorf("expected relabel_configs in file order, got %v", replacements)
+	}
+
+	sources := resolver.Sources()
+	for name, expected := range map[string]string{
+		"collectors.enabled": filepath.Join(dir, "base.yml") + ":2, " + filepath.Join(confd, "10-iis.yml") + ":2, " + filepath.Join(confd, "20-mssql.yaml") + ":2",
+		"log.level":          filepath.Join(confd, "20-mssql.yaml") + ":4",
+		"log.format":         "the command line, overriding " + filepath.Join(dir, "base.yml") + ":8",
+	} {
+		if sources[name] != expected {
+			t.Errorf("expected %s to come from %q, got %q", name, expected, sources[name])
+		}
+	}
+}
+
+func TestListsKeepItems(t *testing.T) {
+	items := []string{"firefox.*", "chrome"}
+	if got := Regexps(items).String(); got != "(?:firefox.*)|(?:chrome)" {
+		t.Errorf("unexpected regexp %q", got)
+	}
+	if got := WhereClauses(items).String(); got != "(firefox.*) OR (chrome)" {
+		t.Errorf("unexpected where clause %q", got)
+	}
+	if !reflect.DeepEqual(items, []string{"firefox.*", "chrome"}) {
+		t.Errorf("expected the items to be left alone, got %q", items)
+	}
+	if got := (Regexps{"chrome"}).String(); got != "chrome" {
+		t.Errorf("expected a single regexp to be kept as is, got %q", got)
+	}
+}
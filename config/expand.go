@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reference matches the references expanded in the values of the
+// configuration file: ${NAME} is replaced by the value of the environment
+// variable NAME, and ${file:PATH} by the content of the file at PATH, without
+// surrounding whitespace. $${ is replaced by a literal ${. Other uses of $, such
+// as ${1} in relabel replacements, are left as is.
+var reference = regexp.MustCompile(`\$\$\{|\$\{(file:[^}]+|[A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandReferences expands the references in the values of the tree rooted at
+// node, in place. Relative file paths are relative to dir, the directory of
+// the configuration file.
+func expandReferences(node *yaml.Node, dir string) error {
+	var errs []error
+	var walk func(node *yaml.Node, isKey bool)
+	walk = func(node *yaml.Node, isKey bool) {
+		switch node.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, n := range node.Content {
+				walk(n, false)
+			}
+		case yaml.MappingNode:
+			for i, n := range node.Content {
+				walk(n, i%2 == 0)
+			}
+		case yaml.ScalarNode:
+			if isKey || !strings.Contains(node.Value, "${") {
+				return
+			}
+			value, err := expandValue(node.Value, dir)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %w", node.Line, err))
+				return
+			}
+			node.Value = value
+			// Plain values are typed by what they expand to, so that
+			// ${FLAG} may set a boolean flag.
+			if node.Style == 0 {
+				node.Tag = ""
+			}
+		}
+	}
+	walk(node, false)
+	return errors.Join(errs...)
+}
+
+func expandValue(s, dir string) (string, error) {
+	var err error
+	expanded := reference.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$${" {
+			return "${"
+		}
+		name := ref[2 : len(ref)-1]
+		if path, ok := strings.CutPrefix(name, "file:"); ok {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			b, readErr := os.ReadFile(path)
+			if readErr != nil {
+				if err == nil {
+					err = fmt.Errorf("expanding %s: %w", ref, readErr)
+				}
+				return ""
+			}
+			return strings.TrimSpace(string(b))
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("expanding %s: environment variable %s is not defined", ref, name)
+		}
+		return value
+	})
+	return expanded, err
+}
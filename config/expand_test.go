@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kingpin/v2"
+)
+
+func TestExpandReferences(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("s3cr3t\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WINDOWS_EXPORTER_TEST_ROLE", "web")
+	t.Setenv("WINDOWS_EXPORTER_TEST_EMPTY", "")
+
+	file := filepath.Join(dir, "config.yml")
+	content := `labels:
+  role: ${WINDOWS_EXPORTER_TEST_ROLE}
+  token: ${file:token}
+  absolute: "${file:` + filepath.Join(dir, "token") + `}"
+  empty: x${WINDOWS_EXPORTER_TEST_EMPTY}y
+  escaped: $${WINDOWS_EXPORTER_TEST_ROLE}
+  regex: ^a$|b$$
+relabel_configs:
+  - source_labels: [name]
+    regex: (.*)_${WINDOWS_EXPORTER_TEST_ROLE}
+    target_label: name
+    replacement: ${1}
+`
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resolver, err := NewResolver(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, expected := range map[string]string{
+		"role":     "web",
+		"token":    "s3cr3t",
+		"absolute": "s3cr3t",
+		"empty":    "xy",
+		"escaped":  "${WINDOWS_EXPORTER_TEST_ROLE}",
+		"regex":    "^a$|b$$",
+	} {
+		if actual := resolver.Labels()[name]; actual != expected {
+			t.Errorf("expected label %s to be %q, got %q", name, expected, actual)
+		}
+	}
+	rc := resolver.RelabelConfigs()[0]
+	if rc.Regex != "(.*)_web" || rc.Replacement != "${1}" {
+		t.Errorf("expected regex to be expanded and replacement to be kept, got %q and %q", rc.Regex, rc.Replacement)
+	}
+}
+
+func TestExpandReferencesTyped(t *testing.T) {
+	t.Setenv("WINDOWS_EXPORTER_TEST_USE_API", "true")
+	app := kingpin.New("test", "")
+	useAPI := app.Flag("collector.service.use-api", "").Bool()
+
+	resolver := newTestResolver(t, "collector:\n  service:\n    use-api: ${WINDOWS_EXPORTER_TEST_USE_API}\n")
+	if err := resolver.Bind(app, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !*useAPI {
+		t.Error("expected the expanded boolean to set the flag")
+	}
+}
+
+func TestExpandReferencesErrors(t *testing.T) {
+	os.Unsetenv("WINDOWS_EXPORTER_TEST_UNDEFINED")
+	file := filepath.Join(t.TempDir(), "config.yml")
+	content := `labels:
+  role: ${WINDOWS_EXPORTER_TEST_UNDEFINED}
+  token: ${file:missing}
+`
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewResolver(file)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, expected := range []string{
+		"line 2: expanding ${WINDOWS_EXPORTER_TEST_UNDEFINED}: environment variable WINDOWS_EXPORTER_TEST_UNDEFINED is not defined",
+		"line 3: expanding ${file:missing}: open ",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q, got:\n%s", expected, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RelabelAction is the operation performed by a RelabelConfig.
+type RelabelAction string
+
+const (
+	// Add sets TargetLabel to Replacement on series which do not have it yet.
+	Add RelabelAction = "add"
+	// Replace sets TargetLabel to Replacement, expanded with the groups of Regex
+	// matching the concatenated SourceLabels. An empty result removes the label.
+	Replace RelabelAction = "replace"
+	// Drop removes series whose concatenated SourceLabels match Regex.
+	Drop RelabelAction = "drop"
+	// LabelMap copies the labels whose name matches Regex to the label named by
+	// Replacement, expanded with the groups of Regex.
+	LabelMap RelabelAction = "labelmap"
+)
+
+// DefaultRelabelConfig holds the values of unset RelabelConfig fields, which
+// are the same as in Prometheus relabel_configs.
+var DefaultRelabelConfig = RelabelConfig{
+	Separator:   ";",
+	Regex:       "(.*)",
+	Replacement: "$1",
+	Action:      Replace,
+}
+
+var labelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// RelabelConfig is a relabeling rule applied to the series of collectors,
+// modeled on Prometheus relabel_configs.
+type RelabelConfig struct {
+	// Collectors restricts the rule to the series of these collectors. The rule
+	// applies to all collectors if empty.
+	Collectors   []string      `yaml:"collectors"`
+	SourceLabels []string      `yaml:"source_labels"`
+	Separator    string        `yaml:"separator"`
+	Regex        string        `yaml:"regex"`
+	TargetLabel  string        `yaml:"target_label"`
+	Replacement  string        `yaml:"replacement"`
+	Action       RelabelAction `yaml:"action"`
+}
+
+var relabelConfigFields = map[string]bool{
+	"collectors":    true,
+	"source_labels": true,
+	"separator":     true,
+	"regex":         true,
+	"target_label":  true,
+	"replacement":   true,
+	"action":        true,
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, applying DefaultRelabelConfig and
+// validating the rule.
+func (c *RelabelConfig) UnmarshalYAML(value *yaml.Node) error {
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if key := value.Content[i]; !relabelConfigFields[key.Value] {
+			return fmt.Errorf("line %d: unknown relabel config field %s", key.Line, key.Value)
+		}
+	}
+	*c = DefaultRelabelConfig
+	type plain RelabelConfig
+	if err := value.Decode((*plain)(c)); err != nil {
+		return err
+	}
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("line %d: invalid relabel config: %w", value.Line, err)
+	}
+	return nil
+}
+
+// Validate reports whether the rule is well-formed.
+func (c RelabelConfig) Validate() error {
+	if _, err := regexp.Compile("^(?:" + c.Regex + ")$"); err != nil {
+		return fmt.Errorf("invalid regex %q: %w", c.Regex, err)
+	}
+	for _, l := range c.SourceLabels {
+		if l != "__name__" && !labelName.MatchString(l) {
+			return fmt.Errorf("invalid source label %q", l)
+		}
+	}
+
+	switch c.Action {
+	case Add, Replace:
+		if c.TargetLabel == "" {
+			return fmt.Errorf("action %s requires target_label", c.Action)
+		}
+		if !labelName.MatchString(c.TargetLabel) || c.TargetLabel == "__name__" {
+			return fmt.Errorf("invalid target label %q", c.TargetLabel)
+		}
+	case Drop:
+		if len(c.SourceLabels) == 0 {
+			return fmt.Errorf("action %s requires source_labels", c.Action)
+		}
+	case LabelMap:
+		if c.Replacement == "" {
+			return fmt.Errorf("action %s requires replacement", c.Action)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", c.Action)
+	}
+	return nil
+}
+
+// relabeling holds the sections of the configuration file describing how the
+// series of collectors are labeled.
+type relabeling struct {
+	Labels         map[string]string `yaml:"labels"`
+	RelabelConfigs []RelabelConfig   `yaml:"relabel_configs"`
+}
+
+func parseRelabeling(root *yaml.Node) (relabeling, error) {
+	var r relabeling
+	if err := root.Decode(&r); err != nil {
+		return r, err
+	}
+	for name := range r.Labels {
+		if !labelName.MatchString(name) || name == "__name__" {
+			return r, fmt.Errorf("invalid static label name %q", name)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseRelabelingYAML(s string) (relabeling, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(s), &doc); err != nil {
+		return relabeling{}, err
+	}
+	return parseRelabeling(doc.Content[0])
+}
+
+func TestParseRelabeling(t *testing.T) {
+	r, err := parseRelabelingYAML(`---
+labels:
+  datacenter: dc1
+relabel_configs:
+  - collectors: [service]
+    source_labels: [name]
+    regex: spooler
+    action: drop
+  - target_label: role
+    replacement: web`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(r.Labels, map[string]string{"datacenter": "dc1"}) {
+		t.Errorf("unexpected labels %v", r.Labels)
+	}
+	expected := []RelabelConfig{
+		{Collectors: []string{"service"}, SourceLabels: []string{"name"}, Separator: ";", Regex: "spooler", Replacement: "$1", Action: Drop},
+		{Separator: ";", Regex: "(.*)", TargetLabel: "role", Replacement: "web", Action: Replace},
+	}
+	if !reflect.DeepEqual(r.RelabelConfigs, expected) {
+		t.Errorf("unexpected relabel configs\nExpected: %+v\nActual: %+v", expected, r.RelabelConfigs)
+	}
+}
+
+func TestParseRelabelingErrors(t *testing.T) {
+	cases := map[string]string{
+		"labels:\n  0bad: x":                                            `invalid static label name "0bad"`,
+		"relabel_configs:\n  - action: replace":                         "line 2: invalid relabel config: action replace requires target_label",
+		"relabel_configs:\n  - action: drop":                            "action drop requires source_labels",
+		"relabel_configs:\n  - action: keep\n    target_label: x":       `unknown action "keep"`,
+		"relabel_configs:\n  - target_label: x\n    regex: '('":         "invalid regex",
+		"relabel_configs:\n  - target_label: __name__\n    action: add": `invalid target label "__name__"`,
+		"relabel_configs:\n  - target: x":                               "line 2: unknown relabel config field target",
+	}
+	for input, expected := range cases {
+		if _, err := parseRelabelingYAML(input); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error containing %q for %q, got %v", expected, input, err)
+		}
+	}
+}
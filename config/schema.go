@@ -0,0 +1,238 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Config is the schema of the configuration file, and the settings the
+// exporter and its collectors are built from.
+//
+// Every setting is a field tagged with its key in the file and the flag which
+// overrides it on the command line. The settings of the collectors are grouped
+// in a section per collector.
+type Config struct {
+	Collectors  CollectorsConfig  `yaml:"collectors"`
+	Collector   CollectorConfig   `yaml:"collector"`
+	Scrape      ScrapeConfig      `yaml:"scrape"`
+	Health      HealthConfig      `yaml:"health"`
+	Telemetry   TelemetryConfig   `yaml:"telemetry"`
+	Config      ReloadConfig      `yaml:"config"`
+	Web         WebConfig         `yaml:"web"`
+	Log         LogConfig         `yaml:"log"`
+	Push        PushConfig        `yaml:"push"`
+	RemoteWrite RemoteWriteConfig `yaml:"remote-write"`
+
+	// Labels and RelabelConfigs have no flag.
+	Labels         map[string]string `yaml:"labels"`
+	RelabelConfigs []RelabelConfig   `yaml:"relabel_configs"`
+}
+
+// CollectorsConfig selects the collectors and how they are run. The settings
+// of a few collectors are kept here rather than in CollectorConfig, after
+// their flags.
+type CollectorsConfig struct {
+	Enabled            []string                 `yaml:"enabled" flag:"collectors.enabled"`
+	Required           []string                 `yaml:"required" flag:"collectors.required"`
+	MinInterval        map[string]time.Duration `yaml:"min-interval" flag:"collectors.min-interval"`
+	Profiles           map[string][]string      `yaml:"profiles" flag:"collectors.profiles"`
+	BackgroundInterval time.Duration            `yaml:"background-interval" flag:"collectors.background-interval"`
+	CircuitBreaker     CircuitBreakerConfig     `yaml:"circuit-breaker"`
+
+	DFSR     DFSRConfig     `yaml:"dfsr"`
+	Exchange ExchangeConfig `yaml:"exchange"`
+	MSSQL    MSSQLConfig    `yaml:"mssql"`
+}
+
+type CircuitBreakerConfig struct {
+	Failures   int           `yaml:"failures" flag:"collectors.circuit-breaker.failures"`
+	Backoff    time.Duration `yaml:"backoff" flag:"collectors.circuit-breaker.backoff"`
+	MaxBackoff time.Duration `yaml:"max-backoff" flag:"collectors.circuit-breaker.max-backoff"`
+}
+
+type DFSRConfig struct {
+	SourcesEnabled []string `yaml:"sources-enabled" flag:"collectors.dfsr.sources-enabled"`
+}
+
+type ExchangeConfig struct {
+	List    bool     `yaml:"list" flag:"collectors.exchange.list"`
+	Enabled []string `yaml:"enabled" flag:"collectors.exchange.enabled"`
+}
+
+type MSSQLConfig struct {
+	ClassesEnabled []string `yaml:"classes-enabled" flag:"collectors.mssql.classes-enabled"`
+	ClassPrint     bool     `yaml:"class-print" flag:"collectors.mssql.class-print"`
+}
+
+// CollectorConfig holds the settings of the collectors, in a section per
+// collector.
+type CollectorConfig struct {
+	IIS           IISConfig           `yaml:"iis"`
+	LogicalDisk   LogicalDiskConfig   `yaml:"logical_disk"`
+	MSMQ          MSMQConfig          `yaml:"msmq"`
+	Net           NetConfig           `yaml:"net"`
+	Process       ProcessConfig       `yaml:"process"`
+	ScheduledTask ScheduledTaskConfig `yaml:"scheduled_task"`
+	Service       ServiceConfig       `yaml:"service"`
+	SMTP          SMTPConfig          `yaml:"smtp"`
+	Textfile      TextfileConfig      `yaml:"textfile"`
+}
+
+type IISConfig struct {
+	SiteWhitelist Regexps `yaml:"site-whitelist" flag:"collector.iis.site-whitelist"`
+	SiteBlacklist Regexps `yaml:"site-blacklist" flag:"collector.iis.site-blacklist"`
+	AppWhitelist  Regexps `yaml:"app-whitelist" flag:"collector.iis.app-whitelist"`
+	AppBlacklist  Regexps `yaml:"app-blacklist" flag:"collector.iis.app-blacklist"`
+}
+
+type LogicalDiskConfig struct {
+	VolumeWhitelist Regexps `yaml:"volume-whitelist" flag:"collector.logical_disk.volume-whitelist"`
+	VolumeBlacklist Regexps `yaml:"volume-blacklist" flag:"collector.logical_disk.volume-blacklist"`
+}
+
+type MSMQConfig struct {
+	Where WhereClauses `yaml:"msmq-where" flag:"collector.msmq.msmq-where"`
+}
+
+type NetConfig struct {
+	NICWhitelist Regexps `yaml:"nic-whitelist" flag:"collector.net.nic-whitelist"`
+	NICBlacklist Regexps `yaml:"nic-blacklist" flag:"collector.net.nic-blacklist"`
+}
+
+type ProcessConfig struct {
+	Whitelist Regexps `yaml:"whitelist" flag:"collector.process.whitelist"`
+	Blacklist Regexps `yaml:"blacklist" flag:"collector.process.blacklist"`
+}
+
+type ScheduledTaskConfig struct {
+	Whitelist Regexps `yaml:"whitelist" flag:"collector.scheduled_task.whitelist"`
+	Blacklist Regexps `yaml:"blacklist" flag:"collector.scheduled_task.blacklist"`
+}
+
+type ServiceConfig struct {
+	Where  WhereClauses `yaml:"services-where" flag:"collector.service.services-where"`
+	UseAPI bool         `yaml:"use-api" flag:"collector.service.use-api"`
+}
+
+type SMTPConfig struct {
+	ServerWhitelist Regexps `yaml:"server-whitelist" flag:"collector.smtp.server-whitelist"`
+	ServerBlacklist Regexps `yaml:"server-blacklist" flag:"collector.smtp.server-blacklist"`
+}
+
+type TextfileConfig struct {
+	Directories []string      `yaml:"directory" flag:"collector.textfile.directory"`
+	MaxAge      time.Duration `yaml:"max-age" flag:"collector.textfile.max-age"`
+	Timestamps  bool          `yaml:"timestamps" flag:"collector.textfile.timestamps"`
+}
+
+type ScrapeConfig struct {
+	TimeoutMargin float64 `yaml:"timeout-margin" flag:"scrape.timeout-margin"`
+}
+
+type HealthConfig struct {
+	MaxSuccessAge time.Duration `yaml:"max-success-age" flag:"health.max-success-age"`
+}
+
+type TelemetryConfig struct {
+	Path        string   `yaml:"path" flag:"telemetry.path"`
+	MaxRequests int      `yaml:"max-requests" flag:"telemetry.max-requests"`
+	Drop        []string `yaml:"drop" flag:"telemetry.drop"`
+}
+
+type ReloadConfig struct {
+	WatchInterval time.Duration `yaml:"watch-interval" flag:"config.watch-interval"`
+}
+
+type WebConfig struct {
+	ListenAddresses []string      `yaml:"listen-address" flag:"web.listen-address"`
+	Config          WebFileConfig `yaml:"config"`
+}
+
+type WebFileConfig struct {
+	File string `yaml:"file" flag:"web.config.file"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" flag:"log.level"`
+	Format string `yaml:"format" flag:"log.format"`
+}
+
+type PushConfig struct {
+	Pushgateway PushgatewayConfig `yaml:"pushgateway"`
+	OTLP        OTLPConfig        `yaml:"otlp"`
+	Interval    time.Duration     `yaml:"interval" flag:"push.interval"`
+	Timeout     time.Duration     `yaml:"timeout" flag:"push.timeout"`
+	Job         string            `yaml:"job" flag:"push.job"`
+}
+
+type PushgatewayConfig struct {
+	URL string `yaml:"url" flag:"push.pushgateway.url"`
+}
+
+type OTLPConfig struct {
+	URL string `yaml:"url" flag:"push.otlp.url"`
+}
+
+type RemoteWriteConfig struct {
+	URL             string               `yaml:"url" flag:"remote-write.url"`
+	Interval        time.Duration        `yaml:"interval" flag:"remote-write.interval"`
+	Timeout         time.Duration        `yaml:"timeout" flag:"remote-write.timeout"`
+	Job             string               `yaml:"job" flag:"remote-write.job"`
+	MaxRetries      int                  `yaml:"max-retries" flag:"remote-write.max-retries"`
+	RetryBackoff    time.Duration        `yaml:"retry-backoff" flag:"remote-write.retry-backoff"`
+	Queue           RemoteWriteQueue     `yaml:"queue"`
+	BasicAuth       RemoteWriteBasicAuth `yaml:"basic-auth"`
+	BearerTokenFile string               `yaml:"bearer-token-file" flag:"remote-write.bearer-token-file"`
+}
+
+type RemoteWriteQueue struct {
+	Directory string `yaml:"directory" flag:"remote-write.queue.directory"`
+	// MaxSize is in bytes. It is written with a unit, e.g. 64MB.
+	MaxSize int64 `yaml:"max-size" flag:"remote-write.queue.max-size"`
+}
+
+type RemoteWriteBasicAuth struct {
+	Username     string `yaml:"username" flag:"remote-write.basic-auth.username"`
+	PasswordFile string `yaml:"password-file" flag:"remote-write.basic-auth.password-file"`
+}
+
+// Regexps is a list of regular expressions, matching what any of them
+// matches. On the command line, it is a single regular expression.
+type Regexps []string
+
+// Regexp returns a regular expression matching the whole of the strings
+// matched by any of r. The items of r are checked when the configuration is
+// parsed.
+func (r Regexps) Regexp() *regexp.Regexp {
+	return regexp.MustCompile("^(?:" + r.String() + ")$")
+}
+
+// String returns a regular expression matching what any of r matches.
+func (r Regexps) String() string {
+	if len(r) == 1 {
+		return r[0]
+	}
+	regexps := make([]string, len(r))
+	for i, item := range r {
+		regexps[i] = "(?:" + item + ")"
+	}
+	return strings.Join(regexps, "|")
+}
+
+// WhereClauses is a list of WQL WHERE clauses, matching what any of them
+// matches. On the command line, it is a single clause.
+type WhereClauses []string
+
+// String returns a WQL WHERE clause matching what any of w matches, or "" if
+// w is empty.
+func (w WhereClauses) String() string {
+	if len(w) == 1 {
+		return w[0]
+	}
+	clauses := make([]string, len(w))
+	for i, item := range w {
+		clauses[i] = "(" + item + ")"
+	}
+	return strings.Join(clauses, " OR ")
+}
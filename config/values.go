@@ -0,0 +1,221 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+)
+
+// Parse parses args into a copy of the flags of app, whose defaults are the
+// saved ones overridden by the settings of resolver, if not nil, and returns
+// the resulting settings. Neither the flags of app nor their variables are
+// changed, so that settings which are reloaded can be parsed again while
+// others are in use.
+//
+// Fields of Config whose flag is not defined by app are left unset.
+func Parse(app *kingpin.Application, defaults FlagDefaults, resolver *Resolver, args []string) (*Config, error) {
+	var settings map[string]setting
+	if resolver != nil {
+		var err error
+		if _, settings, err = resolver.resolve(app, args); err != nil {
+			return nil, err
+		}
+	}
+
+	parsed := kingpin.New(app.Name, app.Help).Terminate(nil)
+	values := make(map[string]*flagValue)
+	for _, f := range app.Model().Flags {
+		// The help flags are defined by every application.
+		if parsed.GetFlag(f.Name) != nil {
+			continue
+		}
+		value := &flagValue{original: f.Value}
+		clause := parsed.Flag(f.Name, f.Help).Default(defaults[f.Name]...)
+		if s, ok := settings[f.Name]; ok {
+			clause.Default(s.values...)
+		}
+		if f.Short != 0 {
+			clause.Short(f.Short)
+		}
+		if f.Envar != "" {
+			clause.Envar(f.Envar)
+		}
+		clause.SetValue(value)
+		values[f.Name] = value
+	}
+	if _, err := parsed.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if resolver != nil {
+		cfg.Labels = resolver.Labels()
+		cfg.RelabelConfigs = resolver.RelabelConfigs()
+	}
+	if err := setFields(reflect.ValueOf(cfg).Elem(), values); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// setFields sets the fields of the section struct s from the values of their
+// flags.
+func setFields(s reflect.Value, values map[string]*flagValue) error {
+	for i := 0; i < s.NumField(); i++ {
+		field := s.Type().Field(i)
+		flag := field.Tag.Get("flag")
+		if flag == "" {
+			if field.Type.Kind() == reflect.Struct {
+				if err := setFields(s.Field(i), values); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		value, ok := values[flag]
+		if !ok {
+			continue
+		}
+		v, err := value.field(field.Type)
+		if err != nil {
+			return fmt.Errorf("%s: %w", flag, err)
+		}
+		s.Field(i).Set(v)
+	}
+	return nil
+}
+
+// flagValue records the values given to a copy of a flag, once checked
+// against the type of the original flag.
+type flagValue struct {
+	original kingpin.Value
+	values   []string
+}
+
+// last returns the value of the flag, or its last value if it may be repeated.
+func (v *flagValue) last() string {
+	if len(v.values) == 0 {
+		return ""
+	}
+	return v.values[len(v.values)-1]
+}
+
+// field returns the value of the flag as a field of type t.
+func (v *flagValue) field(t reflect.Type) (reflect.Value, error) {
+	s := v.last()
+	switch {
+	case v.IsCumulative():
+		return reflect.ValueOf(append([]string(nil), v.values...)).Convert(t), nil
+	case t == regexpsType:
+		if s == "" {
+			return reflect.Zero(t), nil
+		}
+		if _, err := regexp.Compile(s); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(Regexps{s}), nil
+	case t == whereClausesType:
+		if s == "" {
+			return reflect.Zero(t), nil
+		}
+		return reflect.ValueOf(WhereClauses{s}), nil
+	case t.Kind() == reflect.Slice:
+		return reflect.ValueOf(nonEmpty(strings.Split(s, ",")...)).Convert(t), nil
+	case t.Kind() == reflect.Map:
+		return parsePairs(t, s)
+	case s == "":
+		return reflect.Zero(t), nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(s).Convert(t), nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		return reflect.ValueOf(b), err
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		return reflect.ValueOf(n), err
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		return reflect.ValueOf(f), err
+	}
+	// Other values are parsed like kingpin does, e.g. durations may be given
+	// in days and sizes with a unit.
+	parsed := reflect.New(reflect.TypeOf(v.original).Elem()).Interface().(kingpin.Value)
+	if err := parsed.Set(s); err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(parsed.(kingpin.Getter).Get()).Convert(t), nil
+}
+
+// parsePairs parses the key=value pairs of the flag of a mapping field of type
+// t.
+func parsePairs(t reflect.Type, s string) (reflect.Value, error) {
+	m := reflect.MakeMap(t)
+	for _, pair := range strings.Split(s, pairSeparator(t)) {
+		if pair == "" {
+			continue
+		}
+		key, item, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return reflect.Value{}, fmt.Errorf("invalid pair %q, expected key=value", pair)
+		}
+		if m.MapIndex(reflect.ValueOf(key)).IsValid() {
+			return reflect.Value{}, fmt.Errorf("duplicate key %s", key)
+		}
+		value, err := parseItem(t.Elem(), item)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", key, err)
+		}
+		m.SetMapIndex(reflect.ValueOf(key), value)
+	}
+	return m, nil
+}
+
+// parseItem parses a value of a mapping whose values are of type t.
+func parseItem(t reflect.Type, s string) (reflect.Value, error) {
+	switch {
+	case t == durationType:
+		d, err := time.ParseDuration(s)
+		return reflect.ValueOf(d), err
+	case t.Kind() == reflect.Slice:
+		return reflect.ValueOf(nonEmpty(strings.Split(s, ",")...)).Convert(t), nil
+	default:
+		return reflect.ValueOf(s).Convert(t), nil
+	}
+}
+
+func (v *flagValue) Set(s string) error {
+	if err := checkValue(v.original, s); err != nil {
+		return err
+	}
+	if v.IsBoolFlag() {
+		if _, err := strconv.ParseBool(s); err != nil {
+			return err
+		}
+	}
+	if v.IsCumulative() {
+		v.values = append(v.values, s)
+	} else {
+		v.values = []string{s}
+	}
+	return nil
+}
+
+func (v *flagValue) String() string {
+	return strings.Join(v.values, ",")
+}
+
+func (v *flagValue) IsCumulative() bool {
+	return isCumulative(v.original)
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	b, ok := v.original.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
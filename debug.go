@@ -0,0 +1,125 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+)
+
+// collectorRun describes a single run of a collector.
+type collectorRun struct {
+	Time            time.Time `json:"time"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	// Series is the number of series emitted, before relabeling.
+	Series     int      `json:"series"`
+	WMIQueries []string `json:"wmi_queries,omitempty"`
+	Err        error    `json:"-"`
+}
+
+// collectorDebugInfo describes the last runs of a collector.
+type collectorDebugInfo struct {
+	Name            string        `json:"name"`
+	LastRun         *collectorRun `json:"last_run,omitempty"`
+	Success         bool          `json:"success"`
+	LastError       string        `json:"last_error,omitempty"`
+	LastErrorClass  string        `json:"last_error_class,omitempty"`
+	LastErrorTime   *time.Time    `json:"last_error_time,omitempty"`
+	LastSuccessTime *time.Time    `json:"last_success_time,omitempty"`
+	PerflibObjects  []string      `json:"perflib_objects,omitempty"`
+}
+
+// collectorRunStore keeps the last run, the last failed run and the time of the
+// last successful run of each collector.
+type collectorRunStore struct {
+	sync.Mutex
+	runs map[string]collectorDebugInfo
+}
+
+var collectorRuns = &collectorRunStore{runs: make(map[string]collectorDebugInfo)}
+
+func (s *collectorRunStore) record(name string, run collectorRun) {
+	s.Lock()
+	defer s.Unlock()
+	info := s.runs[name]
+	info.LastRun = &run
+	info.Success = run.Err == nil
+	if run.Err == nil {
+		info.LastSuccessTime = &run.Time
+	} else {
+		info.LastError = run.Err.Error()
+		info.LastErrorClass = string(collector.ClassifyError(run.Err))
+		info.LastErrorTime = &run.Time
+	}
+	s.runs[name] = info
+}
+
+// get describes the named collectors, sorted by name. Collectors which have
+// not run yet only have their name and perflib objects set.
+func (s *collectorRunStore) get(names []string) []collectorDebugInfo {
+	s.Lock()
+	defer s.Unlock()
+	infos := make([]collectorDebugInfo, 0, len(names))
+	for _, name := range names {
+		info := s.runs[name]
+		info.Name = name
+		info.PerflibObjects = collector.PerflibObjects(name)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+var collectorRunsTemplate = template.Must(template.New("collectors").Parse(`<html>
+<head><title>windows_exporter collectors</title></head>
+<body>
+<h1>Collectors</h1>
+<table border="1">
+<tr><th>Collector</th><th>Last run</th><th>Duration</th><th>Series</th><th>Success</th><th>Last error</th><th>WMI queries</th><th>Perflib objects</th></tr>
+{{- range . }}
+<tr>
+<td>{{ .Name }}</td>
+{{- with .LastRun }}
+<td>{{ .Time.Format "2006-01-02T15:04:05Z07:00" }}</td>
+<td>{{ printf "%.3fs" .DurationSeconds }}</td>
+<td>{{ .Series }}</td>
+{{- else }}
+<td>never</td><td></td><td></td>
+{{- end }}
+<td>{{ .Success }}</td>
+<td>{{ with .LastErrorTime }}{{ .Format "2006-01-02T15:04:05Z07:00" }}: {{ end }}{{ .LastError }}</td>
+<td>{{ with .LastRun }}{{ range .WMIQueries }}{{ . }}<br>{{ end }}{{ end }}</td>
+<td>{{ range .PerflibObjects }}{{ . }}<br>{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+// serveCollectorRuns describes the last runs of the named collectors, as JSON
+// if asked for with the format=json parameter or the Accept header, and as HTML
+// otherwise.
+func serveCollectorRuns(w http.ResponseWriter, r *http.Request, names []string) {
+	infos := collectorRuns.get(names)
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(infos); err != nil {
+			http.Error(w, fmt.Sprintf("error encoding JSON: %s", err), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := collectorRunsTemplate.Execute(w, infos); err != nil {
+		http.Error(w, fmt.Sprintf("error rendering page: %s", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,46 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestServeCollectorRuns(t *testing.T) {
+	// The telemetry is global, so the collector is named after the test to
+	// leave the counters of other tests alone.
+	execute("debug_failing", failingCollector{}, nil, make(chan prometheus.Metric, 1))
+
+	rec := httptest.NewRecorder()
+	serveCollectorRuns(rec, httptest.NewRequest("GET", "/debug/collectors?format=json", nil), []string{"debug_failing", "debug_idle"})
+	var infos []collectorDebugInfo
+	if err := json.NewDecoder(rec.Body).Decode(&infos); err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 collectors, got %d", len(infos))
+	}
+	failing, idle := infos[0], infos[1]
+	if failing.LastRun == nil || failing.LastRun.Series != 1 || failing.Success {
+		t.Errorf("unexpected last run %+v", failing.LastRun)
+	}
+	if failing.LastError == "" || failing.LastErrorTime == nil || failing.LastErrorClass != string(collector.ErrorClassParse) {
+		t.Errorf("expected the last error to be reported, got %+v", failing)
+	}
+	if idle.LastRun != nil {
+		t.Errorf("expected collector idle not to have run, got %+v", idle.LastRun)
+	}
+
+	rec = httptest.NewRecorder()
+	serveCollectorRuns(rec, httptest.NewRequest("GET", "/debug/collectors", nil), []string{"debug_failing"})
+	if body := rec.Body.String(); !strings.Contains(body, "<td>debug_failing</td>") || !strings.Contains(body, "invalid syntax") {
+		t.Errorf("expected the HTML page to describe collector failing, got %s", body)
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"github.com/prometheus-community/windows_exporter/initiate"
 	"github.com/prometheus-community/windows_exporter/log"
 
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/user"
+	"runtime/debug"
 	"sort"
 	"strconv"
 	"strings"
@@ -26,16 +28,27 @@ import (
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/version"
 	"github.com/prometheus/exporter-toolkit/web"
 	webflag "github.com/prometheus/exporter-toolkit/web/kingpinflag"
 )
 
+// prepareScrapeContext is collector.PrepareScrapeContext, replaced in tests.
+var prepareScrapeContext = collector.PrepareScrapeContext
+
 type windowsCollector struct {
 	maxScrapeDuration time.Duration
 	collectors        map[string]collector.Collector
+	// minIntervals holds the minimum refresh interval of collectors whose
+	// results are cached and replayed on scrapes within that interval.
+	minIntervals map[string]time.Duration
+	cache        *snapshotStore
+	// breaker skips collectors which keep failing. Nil if disabled.
+	breaker *circuitBreaker
+	// snapshots is set when collectors run in the background, in which case
+	// their last results are served instead of running them.
+	snapshots *snapshotStore
 }
 
 // Same struct prometheus uses for their /version endpoint.
@@ -52,8 +65,13 @@ type prometheusVersion struct {
 const (
 	defaultCollectors            = "cpu,cs,logical_disk,net,os,service,system,textfile"
 	defaultCollectorsPlaceholder = "[defaults]"
+	autoCollectorsPlaceholder    = "[auto]"
 )
 
+// detectCollectors returns the role-specific collectors applying to this host.
+// Replaced in tests.
+var detectCollectors = collector.Detect
+
 var (
 	scrapeDurationDesc = prometheus.NewDesc(
 		prometheus.BuildFQName(collector.Namespace, "exporter", "collector_duration_seconds"),
@@ -73,12 +91,44 @@ var (
 		[]string{"collector"},
 		nil,
 	)
+	scrapeOrphanedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(collector.Namespace, "exporter", "collector_orphaned_goroutines"),
+		"windows_exporter: Number of WMI and perflib queries of the collector still running after the scrape which started them gave up on them.",
+		[]string{"collector"},
+		nil,
+	)
 	snapshotDuration = prometheus.NewDesc(
 		prometheus.BuildFQName(collector.Namespace, "exporter", "perflib_snapshot_duration_seconds"),
 		"Duration of perflib snapshot capture",
 		nil,
 		nil,
 	)
+	snapshotOrphanedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(collector.Namespace, "exporter", "perflib_snapshot_orphaned_goroutines"),
+		"windows_exporter: Number of perflib snapshot queries still running after the scrape which started them gave up on them.",
+		nil,
+		nil,
+	)
+)
+
+var collectorPanics = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: collector.Namespace,
+		Subsystem: "exporter",
+		Name:      "collector_panics_total",
+		Help:      "windows_exporter: Number of times the collector panicked.",
+	},
+	[]string{"collector"},
+)
+
+var enabledCollectorsInfo = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: collector.Namespace,
+		Subsystem: "exporter",
+		Name:      "collector_enabled_info",
+		Help:      "windows_exporter: A metric with a constant '1' value for each enabled collector, after expanding placeholders.",
+	},
+	[]string{"collector"},
 )
 
 // Describe sends all the descriptors of the collectors included to
@@ -99,27 +149,63 @@ const (
 // Collect sends the collected metrics from each of the collectors to
 // prometheus.
 func (coll windowsCollector) Collect(ch chan<- prometheus.Metric) {
+	if coll.snapshots != nil {
+		coll.collectSnapshots(ch)
+		return
+	}
+
+	// Cancelled once the scrape times out, so that collectors stop waiting on
+	// WMI and perflib queries whose results would be discarded anyway.
+	ctx, cancel := context.WithTimeout(context.Background(), coll.maxScrapeDuration)
+	defer cancel()
+
+	// Collectors with fresh cached results are replayed and need no perflib data.
+	fresh := coll.freshSnapshots()
+
+	// Collectors whose circuit is open are skipped altogether.
+	skipped := make(map[string]bool)
+	now := time.Now()
+	for name := range coll.collectors {
+		if _, ok := fresh[name]; !ok && !coll.breaker.allow(name, now) {
+			skipped[name] = true
+		}
+	}
+
 	t := time.Now()
 	cs := make([]string, 0, len(coll.collectors))
 	for name := range coll.collectors {
-		cs = append(cs, name)
+		if _, ok := fresh[name]; !ok && !skipped[name] {
+			cs = append(cs, name)
+		}
 	}
-	scrapeContext, err := collector.PrepareScrapeContext(cs)
+	scrapeContext, err := prepareScrapeContext(ctx, cs)
 	ch <- prometheus.MustNewConstMetric(
 		snapshotDuration,
 		prometheus.GaugeValue,
 		time.Since(t).Seconds(),
 	)
+	ch <- prometheus.MustNewConstMetric(
+		snapshotOrphanedDesc,
+		prometheus.GaugeValue,
+		float64(collector.OrphanedQueries(collector.PerflibSnapshotQueries)),
+	)
 	if err != nil {
+		// The collectors allowed to run count as failed, or the trial run of
+		// half-open circuits would never end.
+		for _, name := range cs {
+			coll.breaker.record(name, failed, time.Now())
+		}
 		ch <- prometheus.NewInvalidMetric(scrapeSuccessDesc, fmt.Errorf("failed to prepare scrape: %v", err))
 		return
 	}
 
 	wg := sync.WaitGroup{}
-	wg.Add(len(coll.collectors))
 	collectorOutcomes := make(map[string]collectorOutcome)
 	for name := range coll.collectors {
 		collectorOutcomes[name] = pending
+		if skipped[name] {
+			collectorOutcomes[name] = failed
+		}
 	}
 
 	metricsBuffer := make(chan prometheus.Metric)
@@ -136,9 +222,19 @@ func (coll windowsCollector) Collect(ch chan<- prometheus.Metric) {
 	}()
 
 	for name, c := range coll.collectors {
+		if skipped[name] {
+			continue
+		}
+		wg.Add(1)
 		go func(name string, c collector.Collector) {
 			defer wg.Done()
-			outcome := execute(name, c, scrapeContext, metricsBuffer)
+			var outcome collectorOutcome
+			if snap, ok := fresh[name]; ok {
+				outcome = replaySnapshot(name, snap, metricsBuffer)
+			} else {
+				outcome = coll.executeCached(name, c, scrapeContext, metricsBuffer)
+				coll.breaker.record(name, outcome, time.Now())
+			}
 			l.Lock()
 			if !finished {
 				collectorOutcomes[name] = outcome
@@ -157,18 +253,21 @@ func (coll windowsCollector) Collect(ch chan<- prometheus.Metric) {
 	// Wait until either all collectors finish, or timeout expires
 	select {
 	case <-allDone:
-	case <-time.After(coll.maxScrapeDuration):
+	case <-ctx.Done():
 	}
 
 	l.Lock()
 	finished = true
 
 	remainingCollectorNames := make([]string, 0)
+	names := make([]string, 0, len(collectorOutcomes))
 	for name, outcome := range collectorOutcomes {
+		names = append(names, name)
 		var successValue, timeoutValue float64
 		if outcome == pending {
 			timeoutValue = 1.0
 			remainingCollectorNames = append(remainingCollectorNames, name)
+			collectorErrors.WithLabelValues(name, string(collector.ErrorClassTimeout)).Inc()
 		}
 		if outcome == success {
 			successValue = 1.0
@@ -186,7 +285,14 @@ func (coll windowsCollector) Collect(ch chan<- prometheus.Metric) {
 			timeoutValue,
 			name,
 		)
+		ch <- prometheus.MustNewConstMetric(
+			scrapeOrphanedDesc,
+			prometheus.GaugeValue,
+			float64(collector.OrphanedQueries(name)),
+			name,
+		)
 	}
+	coll.breaker.collectStates(names, ch)
 
 	if len(remainingCollectorNames) > 0 {
 		log.Warn("Collection timed out, still waiting for ", remainingCollectorNames)
@@ -197,8 +303,21 @@ func (coll windowsCollector) Collect(ch chan<- prometheus.Metric) {
 
 func execute(name string, c collector.Collector, ctx *collector.ScrapeContext, ch chan<- prometheus.Metric) collectorOutcome {
 	t := time.Now()
-	err := c.Collect(ctx, ch)
+	ctx, queries := ctx.ForCollector(name).WithQueryLog()
+	relabeled, done := relabelChannel(rulesFor(currentConfig().relabelRules, name), ch)
+	counted, countDone := countingChannel(name, relabeled)
+	err := collectSafely(name, c, ctx, counted)
+	series := countDone()
+	done()
 	duration := time.Since(t).Seconds()
+	collectorScrapeDurations.WithLabelValues(name).Observe(duration)
+	collectorRuns.record(name, collectorRun{
+		Time:            t,
+		DurationSeconds: duration,
+		Series:          series,
+		WMIQueries:      queries(),
+		Err:             err,
+	})
 	ch <- prometheus.MustNewConstMetric(
 		scrapeDurationDesc,
 		prometheus.GaugeValue,
@@ -207,6 +326,7 @@ func execute(name string, c collector.Collector, ctx *collector.ScrapeContext, c
 	)
 
 	if err != nil {
+		countError(name, err)
 		log.Errorf("collector %s failed after %fs: %s", name, duration, err)
 		return failed
 	}
@@ -214,8 +334,26 @@ func execute(name string, c collector.Collector, ctx *collector.ScrapeContext, c
 	return success
 }
 
+// collectSafely calls c.Collect, turning a panic in the collector into an error
+// so that a single broken collector cannot take down the exporter.
+func collectSafely(name string, c collector.Collector, ctx *collector.ScrapeContext, ch chan<- prometheus.Metric) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			collectorPanics.WithLabelValues(name).Inc()
+			log.Errorf("collector %s panicked: %v\n%s", name, r, debug.Stack())
+			err = fmt.Errorf("collector panicked: %v", r)
+		}
+	}()
+	return c.Collect(ctx, ch)
+}
+
 func expandEnabledCollectors(enabled string) []string {
 	expanded := strings.Replace(enabled, defaultCollectorsPlaceholder, defaultCollectors, -1)
+	if strings.Contains(expanded, autoCollectorsPlaceholder) {
+		detected := detectCollectors()
+		log.Infof("Auto-detected collectors: %s", strings.Join(detected, ", "))
+		expanded = strings.Replace(expanded, autoCollectorsPlaceholder, strings.Join(detected, ","), -1)
+	}
 	separated := strings.Split(expanded, ",")
 	unique := map[string]bool{}
 	for _, s := range separated {
@@ -230,12 +368,12 @@ func expandEnabledCollectors(enabled string) []string {
 	return result
 }
 
-func loadCollectors(list string) (map[string]collector.Collector, error) {
+func loadCollectors(list []string, settings *config.Config) (map[string]collector.Collector, error) {
 	collectors := map[string]collector.Collector{}
-	enabled := expandEnabledCollectors(list)
+	enabled := expandEnabledCollectors(strings.Join(list, ","))
 
 	for _, name := range enabled {
-		c, err := collector.Build(name)
+		c, err := collector.Build(name, settings)
 		if err != nil {
 			return nil, err
 		}
@@ -245,25 +383,87 @@ func loadCollectors(list string) (map[string]collector.Collector, error) {
 	return collectors, nil
 }
 
-func initWbem() {
+// newRuntimeConfig builds the settings which can be reloaded from settings,
+// logging where those of the configuration files of resolver, if not nil, come
+// from.
+func newRuntimeConfig(resolver *config.Resolver, settings *config.Config) (*runtimeConfig, error) {
+	cfg := &runtimeConfig{
+		cache:        newSnapshotStore(),
+		labels:       settings.Labels,
+		relabelRules: compileRelabelRules(settings.RelabelConfigs),
+	}
+	if resolver != nil {
+		sources := resolver.Sources()
+		names := make([]string, 0, len(sources))
+		for name := range sources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			log.Debugf("Setting %s is set by %s", name, sources[name])
+		}
+	}
+
+	var err error
+	cfg.collectors, err = loadCollectors(settings.Collectors.Enabled, settings)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load collectors: %w", err)
+	}
+
+	cfg.minIntervals = settings.Collectors.MinInterval
+	for name := range cfg.minIntervals {
+		if _, ok := cfg.collectors[name]; !ok {
+			log.Warnf("Minimum interval set for collector %s, which is not enabled", name)
+		}
+	}
+
+	for _, name := range settings.Collectors.Required {
+		if _, ok := cfg.collectors[name]; !ok {
+			return nil, fmt.Errorf("required collector %s is not enabled", name)
+		}
+		cfg.required = append(cfg.required, name)
+	}
+
+	cfg.profiles = expandCollectorProfiles(settings.Collectors.Profiles)
+	for profile, names := range cfg.profiles {
+		if _, err := filterCollectors(cfg.collectors, names, nil); err != nil {
+			return nil, fmt.Errorf("invalid collector profile %s: %w", profile, err)
+		}
+	}
+
+	cfg.drop, err = parseSeriesSelectors(settings.Telemetry.Drop)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse dropped series: %w", err)
+	}
+	return cfg, nil
+}
+
+// initWbem sets up the default WMI client. Should it fail, WMI queries are still
+// attempted without SWbemServices.
+func initWbem() error {
 	// This initialization prevents a memory leak on WMF 5+. See
 	// https://github.com/prometheus-community/windows_exporter/issues/77 and
 	// linked issues for details.
 	log.Debugf("Initializing SWbemServices")
+	wmi.DefaultClient.AllowMissingFields = true
 	s, err := wmi.InitializeSWbemServices(wmi.DefaultClient)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	wmi.DefaultClient.AllowMissingFields = true
 	wmi.DefaultClient.SWbemServicesClient = s
+	return nil
 }
 
 func main() {
 	var (
-		configFile = kingpin.Flag(
+		configFiles = kingpin.Flag(
 			"config.file",
-			"YAML configuration file to use. Values set in this file will be overridden by CLI flags.",
-		).String()
+			"YAML configuration file, or directory of .yml and .yaml files, to use. May be repeated; later files override earlier ones. Values set in these files will be overridden by CLI flags.",
+		).Strings()
+		checkConfig = kingpin.Flag(
+			"config.check",
+			"If true, validate the configuration file and exit.",
+		).Bool()
 		webConfig   = webflag.AddFlags(kingpin.CommandLine, ":9182")
 		metricsPath = kingpin.Flag(
 			"telemetry.path",
@@ -273,10 +473,6 @@ func main() {
 			"telemetry.max-requests",
 			"Maximum number of concurrent requests. 0 to disable.",
 		).Default("5").Int()
-		enabledCollectors = kingpin.Flag(
-			"collectors.enabled",
-			"Comma-separated list of collectors to use. Use '[defaults]' as a placeholder for all the collectors enabled by default.").
-			Default(defaultCollectors).String()
 		printCollectors = kingpin.Flag(
 			"collectors.print",
 			"If true, print available collectors and exit.",
@@ -285,32 +481,160 @@ func main() {
 			"scrape.timeout-margin",
 			"Seconds to subtract from the timeout allowed by the client. Tune to allow for overhead or high loads.",
 		).Default("0.5").Float64()
+		backgroundInterval = kingpin.Flag(
+			"collectors.background-interval",
+			"If set, run each collector in the background at this interval and serve the last results on scrape, instead of running collectors on every scrape. 0 to disable.",
+		).Default("0s").Duration()
+		circuitFailures = kingpin.Flag(
+			"collectors.circuit-breaker.failures",
+			"Number of consecutive failures after which a collector is skipped until its backoff has elapsed. 0 to disable.",
+		).Default("0").Int()
+		circuitBackoff = kingpin.Flag(
+			"collectors.circuit-breaker.backoff",
+			"Time to skip a collector for after its circuit opens. Doubles on every failed retry.",
+		).Default("1m").Duration()
+		circuitMaxBackoff = kingpin.Flag(
+			"collectors.circuit-breaker.max-backoff",
+			"Maximum time to skip a failing collector for.",
+		).Default("1h").Duration()
+		configWatchInterval = kingpin.Flag(
+			"config.watch-interval",
+			"Interval at which to check the configuration file for changes, and reload it when it changed. 0 to disable.",
+		).Default("0s").Duration()
+		readinessMaxAge = kingpin.Flag(
+			"health.max-success-age",
+			"Maximum time since the last success of a required collector for the exporter to be ready.",
+		).Default("5m").Duration()
+		remoteWriteURL = kingpin.Flag(
+			"remote-write.url",
+			"URL of a Prometheus remote write endpoint to push metrics to. Empty to disable.",
+		).Default("").String()
+		remoteWriteInterval = kingpin.Flag(
+			"remote-write.interval",
+			"Interval at which to collect and push metrics to the remote write endpoint.",
+		).Default("1m").Duration()
+		remoteWriteTimeout = kingpin.Flag(
+			"remote-write.timeout",
+			"Timeout of requests to the remote write endpoint.",
+		).Default("30s").Duration()
+		remoteWriteJob = kingpin.Flag(
+			"remote-write.job",
+			"Value of the job label added to pushed series. The instance label is set to the hostname.",
+		).Default("windows_exporter").String()
+		remoteWriteMaxRetries = kingpin.Flag(
+			"remote-write.max-retries",
+			"Number of times to retry pushing a batch after a server error or a network error.",
+		).Default("3").Int()
+		remoteWriteRetryBackoff = kingpin.Flag(
+			"remote-write.retry-backoff",
+			"Time to wait before the first retry. Doubles on every retry.",
+		).Default("1s").Duration()
+		remoteWriteQueueDir = kingpin.Flag(
+			"remote-write.queue.directory",
+			"Directory in which to keep the batches waiting to be pushed, so that they survive restarts. Batches are kept in memory if empty.",
+		).Default("").String()
+		remoteWriteQueueMaxSize = kingpin.Flag(
+			"remote-write.queue.max-size",
+			"Maximum size of the batches waiting to be pushed. The oldest batches are dropped beyond it.",
+		).Default("64MB").Bytes()
+		remoteWriteUsername = kingpin.Flag(
+			"remote-write.basic-auth.username",
+			"Username for basic authentication to the remote write endpoint.",
+		).Default("").String()
+		remoteWritePasswordFile = kingpin.Flag(
+			"remote-write.basic-auth.password-file",
+			"File containing the password for basic authentication to the remote write endpoint.",
+		).Default("").String()
+		remoteWriteBearerTokenFile = kingpin.Flag(
+			"remote-write.bearer-token-file",
+			"File containing the bearer token for the remote write endpoint.",
+		).Default("").String()
+		pushgatewayURL = kingpin.Flag(
+			"push.pushgateway.url",
+			"URL of a Pushgateway to push metrics to. Empty to disable.",
+		).Default("").String()
+		otlpURL = kingpin.Flag(
+			"push.otlp.url",
+			"URL of an OTLP/HTTP metrics endpoint to push metrics to, e.g. 'http://localhost:4318/v1/metrics'. Empty to disable.",
+		).Default("").String()
+		pushInterval = kingpin.Flag(
+			"push.interval",
+			"Interval at which to collect and push metrics to the Pushgateway and OTLP endpoint.",
+		).Default("1m").Duration()
+		pushTimeout = kingpin.Flag(
+			"push.timeout",
+			"Timeout of requests to the Pushgateway and OTLP endpoint.",
+		).Default("30s").Duration()
+		pushJob = kingpin.Flag(
+			"push.job",
+			"Job name of the metrics pushed to the Pushgateway, grouped by hostname, and service name of those pushed to the OTLP endpoint.",
+		).Default("windows_exporter").String()
 	)
+	kingpin.Flag(
+		"collectors.enabled",
+		"Comma-separated list of collectors to use. Use '[defaults]' as a placeholder for all the collectors enabled by default, and '[auto]' for all the role-specific collectors applying to this host.").
+		Default(defaultCollectors).String()
+	kingpin.Flag(
+		"collectors.min-interval",
+		"Comma-separated list of collector=duration pairs, e.g. 'mssql=5m,hyperv=1m'. Results of these collectors are cached and reused on scrapes within that interval.",
+	).Default("").String()
+	kingpin.Flag(
+		"collectors.profiles",
+		"Semicolon-separated list of named collector profiles, which can be selected with the 'profile' parameter of a scrape, e.g. 'fast=cpu,net;slow=[defaults],mssql'.",
+	).Default("").String()
+	kingpin.Flag(
+		"telemetry.drop",
+		"Series selector, e.g. 'windows_service_state{state!=\"running\"}', of series to drop from every scrape. May be repeated.",
+	).Strings()
+	kingpin.Flag(
+		"collectors.required",
+		"Comma-separated list of collectors which must have succeeded within --health.max-success-age for /-/ready to report the exporter as ready.",
+	).Default("").String()
 	log.AddFlags(kingpin.CommandLine)
 	kingpin.Version(version.Print("windows_exporter"))
 	kingpin.HelpFlag.Short('h')
 
-	// Load values from configuration file(s). Executable flags must first be parsed, in order
-	// to load the specified file(s).
+	// Reloads parse the flags again over their original defaults.
+	defaults := config.SaveDefaults(kingpin.CommandLine)
+
+	// The configuration file sets the defaults of the flags, so it is loaded
+	// before they are parsed.
+	var (
+		resolver  *config.Resolver
+		configErr error
+	)
+	if files := config.FlagValues(kingpin.CommandLine, os.Args[1:], "config.file"); len(files) > 0 {
+		resolver, configErr = config.NewResolver(files...)
+		if configErr == nil {
+			configErr = resolver.Bind(kingpin.CommandLine, os.Args[1:])
+		}
+	}
 	kingpin.Parse()
 	log.Debug("Logging has Started")
-	if *configFile != "" {
-		resolver, err := config.NewResolver(*configFile)
-		if err != nil {
-			log.Fatalf("could not load config file: %v\n", err)
+
+	if *checkConfig {
+		if len(*configFiles) == 0 {
+			fmt.Fprintln(os.Stderr, "--config.check requires --config.file")
+			os.Exit(1)
 		}
-		err = resolver.Bind(kingpin.CommandLine, os.Args[1:])
-		if err != nil {
-			log.Fatalf("%v\n", err)
+		// The settings which are reloaded are checked as on every reload,
+		// which builds the enabled collectors.
+		if configErr == nil {
+			var settings *config.Config
+			settings, configErr = config.Parse(kingpin.CommandLine, defaults, resolver, os.Args[1:])
+			if configErr == nil {
+				_, configErr = newRuntimeConfig(resolver, settings)
+			}
 		}
-
-		// NOTE: This is temporary fix for issue #1092, calling kingpin.Parse
-		// twice makes slices flags duplicate its value, this clean up
-		// the first parse before the second call.
-		*webConfig.WebListenAddresses = (*webConfig.WebListenAddresses)[1:]
-
-		// Parse flags once more to include those discovered in configuration file(s).
-		kingpin.Parse()
+		if configErr != nil {
+			fmt.Fprintf(os.Stderr, "%s is invalid:\n%s\n", strings.Join(*configFiles, ", "), configErr)
+			os.Exit(1)
+		}
+		fmt.Printf("%s is valid\n", strings.Join(*configFiles, ", "))
+		return
+	}
+	if configErr != nil {
+		log.Fatalf("could not load config file: %v\n", configErr)
 	}
 
 	if *printCollectors {
@@ -323,15 +647,16 @@ func main() {
 		fmt.Printf("Available collectors:\n")
 		for _, n := range collectorNames {
 			fmt.Printf(" - %s\n", n)
+			if details := describeCollector(collectorStatusOf(n, nil)); details != "" {
+				fmt.Printf("   %s\n", details)
+			}
 		}
 		return
 	}
 
-	initWbem()
-
-	collectors, err := loadCollectors(*enabledCollectors)
-	if err != nil {
-		log.Fatalf("Couldn't load collectors: %s", err)
+	wbemErr := initWbem()
+	if wbemErr != nil {
+		log.Errorf("Couldn't initialize SWbemServices: %s", wbemErr)
 	}
 
 	u, err := user.Current()
@@ -344,32 +669,170 @@ func main() {
 		log.Warnf("Running as a preconfigured Windows Container user. This may mean you do not have Windows HostProcess containers configured correctly and some functionality will not work as expected.")
 	}
 
-	log.Infof("Enabled collectors: %v", strings.Join(keys(collectors), ", "))
+	breaker := newCircuitBreaker(*circuitFailures, *circuitBackoff, *circuitMaxBackoff)
+
+	var snapshots *snapshotStore
+	stopBackgroundCollection := func() {}
+	if *backgroundInterval > 0 {
+		log.Infof("Running collectors in the background every %s", *backgroundInterval)
+		snapshots = newSnapshotStore()
+	}
+
+	rl := &reloader{
+		load: func() (*runtimeConfig, error) {
+			// The flags are parsed apart from their variables, which are in
+			// use while the configuration is reloaded.
+			var resolver *config.Resolver
+			if len(*configFiles) > 0 {
+				var err error
+				resolver, err = config.NewResolver(*configFiles...)
+				if err != nil {
+					return nil, err
+				}
+			}
+			settings, err := config.Parse(kingpin.CommandLine, defaults, resolver, os.Args[1:])
+			if err != nil {
+				return nil, err
+			}
+			return newRuntimeConfig(resolver, settings)
+		},
+		apply: func(cfg, previous *runtimeConfig) {
+			// The telemetry of the collectors is only set up once their
+			// configuration is accepted.
+			if previous == nil || !sameCollectors(cfg.collectors, previous.collectors) {
+				setEnabledCollectors(keys(cfg.collectors))
+			}
+			if snapshots != nil {
+				stopBackgroundCollection()
+				stopBackgroundCollection = startBackgroundCollection(snapshots, cfg.collectors, *backgroundInterval, cfg.minIntervals, breaker)
+			}
+		},
+	}
+
+	settings, err := config.Parse(kingpin.CommandLine, defaults, resolver, os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid configuration: %s", err)
+	}
+	cfg, err := newRuntimeConfig(resolver, settings)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %s", err)
+	}
+	rl.use(cfg)
+	log.Infof("Enabled collectors: %v", strings.Join(keys(cfg.collectors), ", "))
 
 	h := &metricsHandler{
 		timeoutMargin: *timeoutMargin,
-		collectorFactory: func(timeout time.Duration, requestedCollectors []string) (error, *windowsCollector) {
-			filteredCollectors := make(map[string]collector.Collector)
-			// scrape all enabled collectors if no collector is requested
-			if len(requestedCollectors) == 0 {
-				filteredCollectors = collectors
-			}
-			for _, name := range requestedCollectors {
-				col, exists := collectors[name]
-				if !exists {
-					return fmt.Errorf("unavailable collector: %s", name), nil
-				}
-				filteredCollectors[name] = col
+		collectorFactory: func(cfg *runtimeConfig, timeout time.Duration, requestedCollectors, excludedCollectors []string) (error, *windowsCollector) {
+			filteredCollectors, err := filterCollectors(cfg.collectors, requestedCollectors, excludedCollectors)
+			if err != nil {
+				return err, nil
 			}
 			return nil, &windowsCollector{
 				collectors:        filteredCollectors,
 				maxScrapeDuration: timeout,
+				minIntervals:      cfg.minIntervals,
+				cache:             cfg.cache,
+				breaker:           breaker,
+				snapshots:         snapshots,
 			}
 		},
 	}
 
-	http.HandleFunc(*metricsPath, withConcurrencyLimit(*maxRequests, h.ServeHTTP))
+	// The required collectors are run once, rather than on the first scrape,
+	// for the exporter to become ready. Background collection runs them on
+	// its own.
+	if len(cfg.required) > 0 && snapshots == nil {
+		go func() {
+			g, err := h.gatherer(time.Duration(defaultTimeout*float64(time.Second)), cfg.required, nil, nil)
+			if err == nil {
+				_, err = g.Gather()
+			}
+			if err != nil {
+				log.Warnf("Running the required collectors failed: %s", err)
+			}
+		}()
+	}
+
+	if len(*configFiles) > 0 && *configWatchInterval > 0 {
+		go rl.watch(*configFiles, *configWatchInterval)
+	}
+	go rl.listen(initiate.ReloadCh, "a service parameter change")
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Fatalf("Couldn't get hostname: %s", err)
+	}
+
+	if *remoteWriteURL != "" {
+		password, err := readSecretFile(*remoteWritePasswordFile)
+		if err != nil {
+			log.Fatalf("Couldn't read remote write password: %s", err)
+		}
+		bearerToken, err := readSecretFile(*remoteWriteBearerTokenFile)
+		if err != nil {
+			log.Fatalf("Couldn't read remote write bearer token: %s", err)
+		}
+
+		writer, err := newRemoteWriter(remoteWriteConfig{
+			url:          *remoteWriteURL,
+			interval:     *remoteWriteInterval,
+			timeout:      *remoteWriteTimeout,
+			labels:       map[string]string{"job": *remoteWriteJob, "instance": hostname},
+			maxRetries:   *remoteWriteMaxRetries,
+			retryBackoff: *remoteWriteRetryBackoff,
+			queueDir:     *remoteWriteQueueDir,
+			queueMaxSize: int64(*remoteWriteQueueMaxSize),
+			username:     *remoteWriteUsername,
+			password:     password,
+			bearerToken:  bearerToken,
+		}, func() (prometheus.Gatherer, error) {
+			return h.gatherer(*remoteWriteInterval, nil, nil, nil)
+		})
+		if err != nil {
+			log.Fatalf("Couldn't start remote write: %s", err)
+		}
+		log.Infof("Pushing metrics to %s every %s", *remoteWriteURL, *remoteWriteInterval)
+		go writer.run()
+	}
+
+	var pushers []metricsPusher
+	pushClient := &http.Client{Timeout: *pushTimeout}
+	if *pushgatewayURL != "" {
+		pushers = append(pushers, &pushgatewayPusher{url: *pushgatewayURL, job: *pushJob, hostname: hostname, client: pushClient})
+	}
+	if *otlpURL != "" {
+		pushers = append(pushers, &otlpPusher{url: *otlpURL, serviceName: *pushJob, hostname: hostname, client: pushClient})
+	}
+	if len(pushers) > 0 {
+		for _, p := range pushers {
+			log.Infof("Pushing metrics to %s every %s", p.name(), *pushInterval)
+		}
+		go pushLoop(*pushInterval, func() (prometheus.Gatherer, error) {
+			return h.gatherer(*pushInterval, nil, nil, nil)
+		}, pushers)
+	}
+
+	http.HandleFunc(*metricsPath, withConcurrencyLimit(*maxRequests, *metricsPath, h.ServeHTTP))
 	http.HandleFunc("/health", healthCheck)
+	http.HandleFunc("/-/healthy", healthy)
+	http.Handle("/-/ready", readiness{wbemErr: wbemErr, maxAge: *readinessMaxAge})
+	http.Handle("/-/reload", rl)
+	http.HandleFunc("/collectors", func(w http.ResponseWriter, r *http.Request) {
+		statuses := make([]collectorStatus, 0)
+		collectors := currentConfig().collectors
+		for _, name := range collector.Available() {
+			statuses = append(statuses, collectorStatusOf(name, collectors[name]))
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(statuses); err != nil {
+			http.Error(w, fmt.Sprintf("error encoding JSON: %s", err), http.StatusInternalServerError)
+		}
+	})
+	http.HandleFunc("/debug/collectors", func(w http.ResponseWriter, r *http.Request) {
+		serveCollectorRuns(w, r, keys(currentConfig().collectors))
+	})
 	http.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
 		// we can't use "version" directly as it is a package, and not an object that
 		// can be serialized.
@@ -391,6 +854,8 @@ func main() {
 <body>
 <h1>windows_exporter</h1>
 <p><a href="` + *metricsPath + `">Metrics</a></p>
+<p><a href="/collectors">Collectors</a></p>
+<p><a href="/debug/collectors">Last collector runs</a></p>
 <p><i>` + version.Info() + `</i></p>
 </body>
 </html>`))
@@ -414,6 +879,64 @@ func main() {
 	}
 }
 
+// collectorStatus describes a collector and whether it can be used on this host.
+type collectorStatus struct {
+	collector.CollectorInfo
+	Enabled bool `json:"enabled"`
+	// Applicable is only set for role-specific collectors.
+	Applicable *bool `json:"applicable,omitempty"`
+}
+
+// collectorStatusOf describes the named collector. c is nil for collectors
+// which are not enabled.
+func collectorStatusOf(name string, c collector.Collector) collectorStatus {
+	info, ok := collector.Info(name)
+	if !ok {
+		info.Name = name
+	}
+	status := collectorStatus{CollectorInfo: info, Enabled: c != nil}
+	if c != nil {
+		status.Metrics = collector.MetricFamilies(c)
+	}
+
+	applies, ok, err := collector.Applicable(name)
+	if err != nil {
+		log.Warnf("Couldn't detect whether collector %s applies: %v", name, err)
+	} else if ok {
+		status.Applicable = &applies
+	}
+	return status
+}
+
+// describeCollector formats a collectorStatus for --collectors.print.
+func describeCollector(status collectorStatus) string {
+	var details []string
+	if status.Description != "" {
+		details = append(details, status.Description)
+	}
+	sources := make([]string, 0, len(status.Sources))
+	for _, source := range status.Sources {
+		sources = append(sources, string(source))
+	}
+	if len(sources) > 0 {
+		details = append(details, "source: "+strings.Join(sources, ", "))
+	}
+	if status.Requires != "" {
+		details = append(details, "requires: "+status.Requires)
+	}
+	if status.Applicable != nil {
+		if *status.Applicable {
+			details = append(details, "detected on this host")
+		} else {
+			details = append(details, "not detected on this host")
+		}
+	}
+	if status.Expensive {
+		details = append(details, "expensive")
+	}
+	return strings.Join(details, "; ")
+}
+
 func healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_, err := fmt.Fprintln(w, `{"status":"ok"}`)
@@ -422,6 +945,19 @@ func healthCheck(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// readSecretFile returns the trimmed content of file, or an empty string if
+// file is empty.
+func readSecretFile(file string) (string, error) {
+	if file == "" {
+		return "", nil
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
 func keys(m map[string]collector.Collector) []string {
 	ret := make([]string, 0, len(m))
 	for key := range m {
@@ -430,10 +966,24 @@ func keys(m map[string]collector.Collector) []string {
 	return ret
 }
 
-func withConcurrencyLimit(n int, next http.HandlerFunc) http.HandlerFunc {
+// sameCollectors returns whether a and b hold collectors of the same names.
+func sameCollectors(a, b map[string]collector.Collector) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func withConcurrencyLimit(n int, handler string, next http.HandlerFunc) http.HandlerFunc {
 	if n <= 0 {
 		return next
 	}
+	concurrencyLimitRejections.WithLabelValues(handler)
 
 	sem := make(chan struct{}, n)
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -441,6 +991,7 @@ func withConcurrencyLimit(n int, next http.HandlerFunc) http.HandlerFunc {
 		case sem <- struct{}{}:
 			defer func() { <-sem }()
 		default:
+			concurrencyLimitRejections.WithLabelValues(handler).Inc()
 			w.WriteHeader(http.StatusServiceUnavailable)
 			_, _ = w.Write([]byte("Too many concurrent requests"))
 			return
@@ -449,14 +1000,54 @@ func withConcurrencyLimit(n int, next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// expandCollectorProfiles returns the collectors of each of the named
+// profiles, with the placeholders in their lists expanded, e.g. those of
+// "slow: [[defaults], mssql]".
+func expandCollectorProfiles(profiles map[string][]string) map[string][]string {
+	expanded := make(map[string][]string, len(profiles))
+	for name, list := range profiles {
+		expanded[name] = expandEnabledCollectors(strings.Join(list, ","))
+	}
+	return expanded
+}
+
+// filterCollectors returns the requested collectors, or all of them if none is
+// requested, minus the excluded ones. Requesting or excluding a collector
+// which is not enabled is an error.
+func filterCollectors(collectors map[string]collector.Collector, requested, excluded []string) (map[string]collector.Collector, error) {
+	filteredCollectors := make(map[string]collector.Collector)
+	// scrape all enabled collectors if no collector is requested
+	if len(requested) == 0 {
+		for name, col := range collectors {
+			filteredCollectors[name] = col
+		}
+	}
+	for _, name := range requested {
+		col, exists := collectors[name]
+		if !exists {
+			return nil, fmt.Errorf("unavailable collector: %s", name)
+		}
+		filteredCollectors[name] = col
+	}
+	for _, name := range excluded {
+		if _, exists := collectors[name]; !exists {
+			return nil, fmt.Errorf("unavailable collector: %s", name)
+		}
+		delete(filteredCollectors, name)
+	}
+	return filteredCollectors, nil
+}
+
 type metricsHandler struct {
 	timeoutMargin    float64
-	collectorFactory func(timeout time.Duration, requestedCollectors []string) (error, *windowsCollector)
+	collectorFactory func(cfg *runtimeConfig, timeout time.Duration, requestedCollectors, excludedCollectors []string) (error, *windowsCollector)
 }
 
-func (mh *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	const defaultTimeout = 10.0
+// defaultTimeout is the timeout of scrapes, in seconds, if the client gives
+// none.
+const defaultTimeout = 10.0
 
+func (mh *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var timeoutSeconds float64
 	if v := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); v != "" {
 		var err error
@@ -470,21 +1061,62 @@ func (mh *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	timeoutSeconds = timeoutSeconds - mh.timeoutMargin
 
-	reg := prometheus.NewRegistry()
-	err, wc := mh.collectorFactory(time.Duration(timeoutSeconds*float64(time.Second)), r.URL.Query()["collect[]"])
+	query := r.URL.Query()
+	requestedCollectors := query["collect[]"]
+	for _, profile := range query["profile"] {
+		names, ok := currentConfig().profiles[profile]
+		if !ok {
+			log.Warnln("Unknown collector profile requested: ", profile)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("Unknown collector profile: %s", profile))) //nolint:errcheck
+			return
+		}
+		requestedCollectors = append(requestedCollectors, names...)
+	}
+
+	match, err := parseSeriesSelectors(query["match[]"])
+	if err != nil {
+		log.Warnln("Couldn't parse match[] parameter: ", err)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("Couldn't parse match[] parameter: %s", err))) //nolint:errcheck
+		return
+	}
+	g, err := mh.gatherer(time.Duration(timeoutSeconds*float64(time.Second)), requestedCollectors, query["exclude[]"], match)
 	if err != nil {
 		log.Warnln("Couldn't create filtered metrics handler: ", err)
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte(fmt.Sprintf("Couldn't create filtered metrics handler: %s", err))) //nolint:errcheck
 		return
 	}
-	reg.MustRegister(wc)
-	reg.MustRegister(
-		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
-		collectors.NewGoCollector(),
-		version.NewCollector("windows_exporter"),
-	)
 
-	h := promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+	h := metricsExposition(g)
 	h.ServeHTTP(w, r)
 }
+
+// gatherer returns a gatherer running the requested collectors, minus the
+// excluded ones, within timeout. It keeps the series matching any of the match
+// selectors, or all of them if there are none.
+func (mh *metricsHandler) gatherer(timeout time.Duration, requestedCollectors, excludedCollectors []string, match []seriesSelector) (prometheus.Gatherer, error) {
+	cfg := currentConfig()
+	err, wc := mh.collectorFactory(cfg, timeout, requestedCollectors, excludedCollectors)
+	if err != nil {
+		return nil, err
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(wc)
+
+	filter := &seriesFilter{match: match, drop: cfg.drop}
+	return filter.gatherer(staticLabelsGatherer(cfg.labels, prometheus.Gatherers{exporterRegistry, reg})), nil
+}
+
+// metricsExposition serves the series of g in the format negotiated with the
+// client, OpenMetrics included.
+func metricsExposition(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+		// Counters report when they started from zero, such as when the system
+		// or the process booted, so that resets can be told apart.
+		EnableOpenMetricsTextCreatedSamples: true,
+	})
+}
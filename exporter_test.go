@@ -4,9 +4,21 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus-community/windows_exporter/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 )
 
 type expansionTestCase struct {
@@ -27,8 +39,15 @@ func TestExpandEnabled(t *testing.T) {
 		{defaultCollectorsPlaceholder + "," + defaultCollectorsPlaceholder, strings.Split(defaultCollectors, ",")},
 		// Composite case
 		{"foo," + defaultCollectorsPlaceholder + ",bar", append(strings.Split(defaultCollectors, ","), "foo", "bar")},
+		// Auto-detection placeholder
+		{autoCollectorsPlaceholder + ",cs", []string{"cs", "iis", "mssql"}},
+		// De-duplicate auto-detected collectors
+		{autoCollectorsPlaceholder + ",iis", []string{"iis", "mssql"}},
 	}
 
+	detectCollectors = func() []string { return []string{"iis", "mssql"} }
+	defer func() { detectCollectors = collector.Detect }()
+
 	for _, testCase := range expansionTests {
 		output := expandEnabledCollectors(testCase.input)
 		sort.Strings(output)
@@ -50,3 +69,386 @@ func TestExpandEnabled(t *testing.T) {
 		}
 	}
 }
+
+func TestSnapshotStoreKeepsLastSuccess(t *testing.T) {
+	store := newSnapshotStore()
+	first := time.Unix(100, 0)
+	store.set("cpu", collectorSnapshot{outcome: success, collectedAt: first})
+	store.set("cpu", collectorSnapshot{outcome: failed, collectedAt: time.Unix(200, 0)})
+
+	snap, ok := store.get("cpu")
+	if !ok {
+		t.Fatal("snapshot not found")
+	}
+	if snap.outcome != failed {
+		t.Errorf("expected latest outcome to be failed, got %v", snap.outcome)
+	}
+	if !snap.lastSuccess.Equal(first) {
+		t.Errorf("expected last success %v, got %v", first, snap.lastSuccess)
+	}
+}
+
+type panickingCollector struct{}
+
+func (panickingCollector) Collect(_ *collector.ScrapeContext, _ chan<- prometheus.Metric) error {
+	var m map[string]int
+	m["boom"]++
+	return nil
+}
+
+func TestExecuteRecoversPanic(t *testing.T) {
+	ch := make(chan prometheus.Metric, 1)
+	if outcome := execute("panicking", panickingCollector{}, nil, ch); outcome != failed {
+		t.Errorf("expected outcome %v, got %v", failed, outcome)
+	}
+
+	var m dto.Metric
+	if err := collectorPanics.WithLabelValues("panicking").Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	if m.GetCounter().GetValue() != 1 {
+		t.Errorf("expected 1 panic to be counted, got %v", m.GetCounter().GetValue())
+	}
+}
+
+type failingCollector struct{}
+
+func (failingCollector) Collect(_ *collector.ScrapeContext, ch chan<- prometheus.Metric) error {
+	ch <- prometheus.MustNewConstMetric(prometheus.NewDesc("test_metric", "Test.", nil, nil), prometheus.GaugeValue, 1)
+	_, err := strconv.Atoi("x")
+	return err
+}
+
+func TestExecuteCountsMetricsAndErrors(t *testing.T) {
+	initCollectorTelemetry("failing")
+	counters := []struct {
+		counter prometheus.Counter
+		delta   float64
+	}{
+		{collectorMetricsEmitted.WithLabelValues("failing"), 1},
+		{collectorErrors.WithLabelValues("failing", string(collector.ErrorClassParse)), 1},
+		{collectorErrors.WithLabelValues("failing", string(collector.ErrorClassWMI)), 0},
+	}
+	// Other tests may run collectors under the same name, so only the
+	// changes of the counters are checked.
+	before := make([]float64, len(counters))
+	for i, c := range counters {
+		before[i] = testutil.ToFloat64(c.counter)
+	}
+
+	ch := make(chan prometheus.Metric, 2)
+	if outcome := execute("failing", failingCollector{}, nil, ch); outcome != failed {
+		t.Errorf("expected outcome %v, got %v", failed, outcome)
+	}
+
+	for i, c := range counters {
+		if delta := testutil.ToFloat64(c.counter) - before[i]; delta != c.delta {
+			t.Errorf("expected %s to increase by %v, got %v", c.counter.Desc(), c.delta, delta)
+		}
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	b := newCircuitBreaker(2, time.Minute, 3*time.Minute)
+	now := time.Unix(0, 0)
+
+	b.record("iis", failed, now)
+	if !b.allow("iis", now) {
+		t.Fatal("circuit opened before reaching the failure threshold")
+	}
+	b.record("iis", failed, now)
+	if b.allow("iis", now.Add(30*time.Second)) {
+		t.Fatal("expected open circuit to skip the collector")
+	}
+
+	// The backoff has elapsed, so a single trial run is allowed.
+	now = now.Add(time.Minute)
+	if !b.allow("iis", now) || b.state("iis") != circuitHalfOpen {
+		t.Fatal("expected a trial run once the backoff elapsed")
+	}
+	if b.allow("iis", now) {
+		t.Fatal("expected only a single trial run")
+	}
+
+	// A failed trial doubles the backoff, up to the maximum.
+	b.record("iis", failed, now)
+	if b.allow("iis", now.Add(90*time.Second)) {
+		t.Fatal("expected backoff to double after a failed trial")
+	}
+	now = now.Add(2 * time.Minute)
+	b.allow("iis", now)
+	b.record("iis", failed, now)
+	if !b.allow("iis", now.Add(3*time.Minute)) {
+		t.Fatal("expected backoff to be capped at the maximum")
+	}
+
+	b.record("iis", success, now)
+	if b.state("iis") != circuitClosed {
+		t.Errorf("expected circuit to close after a success, got %v", b.state("iis"))
+	}
+}
+
+func TestCollectRecordsFailedPrepare(t *testing.T) {
+	defer func(f func(context.Context, []string) (*collector.ScrapeContext, error)) { prepareScrapeContext = f }(prepareScrapeContext)
+	prepareScrapeContext = func(context.Context, []string) (*collector.ScrapeContext, error) {
+		return nil, errors.New("perflib unavailable")
+	}
+
+	b := newCircuitBreaker(1, time.Minute, time.Hour)
+	b.record("iis", failed, time.Now().Add(-time.Hour))
+	coll := windowsCollector{
+		maxScrapeDuration: time.Second,
+		collectors:        map[string]collector.Collector{"iis": failingCollector{}},
+		breaker:           b,
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		coll.Collect(ch)
+		close(ch)
+	}()
+	for range ch {
+	}
+
+	// The trial run failed before the collector ran, so the circuit opens
+	// again instead of staying half-open for good.
+	if b.state("iis") != circuitOpen {
+		t.Fatalf("expected the circuit to open again, got %v", b.state("iis"))
+	}
+	if !b.allow("iis", time.Now().Add(3*time.Minute)) {
+		t.Error("expected another trial run once the backoff elapsed")
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	b := newCircuitBreaker(0, time.Minute, time.Hour)
+	b.record("iis", failed, time.Now())
+	if !b.allow("iis", time.Now()) {
+		t.Error("disabled circuit breaker skipped a collector")
+	}
+}
+
+func TestFilterCollectors(t *testing.T) {
+	collectors := map[string]collector.Collector{
+		"cpu":     panickingCollector{},
+		"net":     panickingCollector{},
+		"process": panickingCollector{},
+	}
+	cases := []struct {
+		name      string
+		requested []string
+		excluded  []string
+		expected  []string
+		fail      bool
+	}{
+		{name: "all", expected: []string{"cpu", "net", "process"}},
+		{name: "requested", requested: []string{"cpu"}, expected: []string{"cpu"}},
+		{name: "excluded", excluded: []string{"process"}, expected: []string{"cpu", "net"}},
+		{name: "requested and excluded", requested: []string{"cpu", "net"}, excluded: []string{"net"}, expected: []string{"cpu"}},
+		{name: "unknown requested", requested: []string{"iis"}, fail: true},
+		{name: "unknown excluded", excluded: []string{"iis"}, fail: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			filtered, err := filterCollectors(collectors, c.requested, c.excluded)
+			if c.fail {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := keys(filtered)
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, names)
+			}
+			// The enabled collectors must not be modified by filtering.
+			if len(collectors) != 3 {
+				t.Errorf("filtering modified the enabled collectors: %v", keys(collectors))
+			}
+		})
+	}
+}
+
+func TestExpandCollectorProfiles(t *testing.T) {
+	profiles := expandCollectorProfiles(map[string][]string{"fast": {"cpu", "net"}, "slow": {"mssql", "cpu", "mssql"}})
+	for _, names := range profiles {
+		sort.Strings(names)
+	}
+	expected := map[string][]string{"fast": {"cpu", "net"}, "slow": {"cpu", "mssql"}}
+	if !reflect.DeepEqual(profiles, expected) {
+		t.Errorf("expected %v, got %v", expected, profiles)
+	}
+}
+
+func labelPairs(kv ...string) []*dto.LabelPair {
+	pairs := make([]*dto.LabelPair, 0, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		pairs = append(pairs, &dto.LabelPair{Name: &kv[i], Value: &kv[i+1]})
+	}
+	return pairs
+}
+
+func TestSeriesSelector(t *testing.T) {
+	cases := []struct {
+		selector string
+		name     string
+		labels   []*dto.LabelPair
+		matches  bool
+	}{
+		{`windows_cpu_time_total`, "windows_cpu_time_total", nil, true},
+		{`windows_cpu_time_total`, "windows_cpu_interrupts_total", nil, false},
+		{`{__name__=~"windows_cpu_.*"}`, "windows_cpu_time_total", nil, true},
+		{`{__name__=~"windows_cpu"}`, "windows_cpu_time_total", nil, false},
+		{`windows_service_state{state="running"}`, "windows_service_state", labelPairs("name", "w32time", "state", "running"), true},
+		{`windows_service_state{state!="running"}`, "windows_service_state", labelPairs("name", "w32time", "state", "running"), false},
+		{`windows_service_state{ name =~ "w.*" , state="running", }`, "windows_service_state", labelPairs("name", "w32time", "state", "running"), true},
+		{`windows_service_state{name!~"w.*"}`, "windows_service_state", labelPairs("name", "w32time"), false},
+		{`windows_service_state{missing=""}`, "windows_service_state", labelPairs("name", "w32time"), true},
+		{`{name=~".*",state="running"}`, "windows_service_state", labelPairs("state", "running"), true},
+		{`{name="quoted \"value\""}`, "windows_service_state", labelPairs("name", `quoted "value"`), true},
+		{"{name=`C:\\path`}", "windows_service_state", labelPairs("name", `C:\path`), true},
+		{`{name='w32time'}`, "windows_service_state", labelPairs("name", "w32time"), true},
+		{`{name='it\'s "quoted"\n'}`, "windows_service_state", labelPairs("name", "it's \"quoted\"\n"), true},
+		{`{name=~'w.*'}`, "windows_service_state", labelPairs("name", "spooler"), false},
+	}
+	for _, c := range cases {
+		selector, err := parseSeriesSelector(c.selector)
+		if err != nil {
+			t.Errorf("%s: %v", c.selector, err)
+			continue
+		}
+		if matches := selector.matches(c.name, c.labels); matches != c.matches {
+			t.Errorf("%s: expected match %v for %s%v, got %v", c.selector, c.matches, c.name, c.labels, matches)
+		}
+	}
+
+	for _, invalid := range []string{``, `{}`, `{name}`, `{name="unterminated}`, `{name=~"("}`, `{name="a" state="b"}`, `foo bar`, `{name='unterminated}`, `{name=~".*"}`, `{name=""}`, `{name!="a"}`, `{name!~"a.*",state=~".*"}`} {
+		if _, err := parseSeriesSelector(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestSeriesFilterGatherer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	state := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "windows_service_state", Help: "State."}, []string{"name", "state"})
+	state.WithLabelValues("w32time", "running").Set(1)
+	state.WithLabelValues("spooler", "stopped").Set(1)
+	cpu := prometheus.NewCounter(prometheus.CounterOpts{Name: "windows_cpu_time_total", Help: "CPU."})
+	reg.MustRegister(state, cpu)
+
+	match, _ := parseSeriesSelectors([]string{`windows_service_state`})
+	drop, _ := parseSeriesSelectors([]string{`{state="stopped"}`})
+	mfs, err := (&seriesFilter{match: match, drop: drop}).gatherer(reg).Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mfs) != 1 || mfs[0].GetName() != "windows_service_state" || len(mfs[0].Metric) != 1 {
+		t.Fatalf("expected a single windows_service_state series, got %v", mfs)
+	}
+	if labels := mfs[0].Metric[0].GetLabel(); labels[0].GetValue() != "w32time" {
+		t.Errorf("expected the w32time series to be kept, got %v", labels)
+	}
+}
+
+func TestRelabel(t *testing.T) {
+	rules := compileRelabelRules([]config.RelabelConfig{
+		{Collectors: []string{"service"}, SourceLabels: []string{"name"}, Separator: ";", Regex: "spooler", Action: config.Drop},
+		{SourceLabels: []string{"__name__", "name"}, Separator: ";", Regex: "windows_(\\w+)_state;(.*)", TargetLabel: "subject", Replacement: "$1/$2", Action: config.Replace},
+		{Regex: "name", Replacement: "service_name", Action: config.LabelMap},
+		{TargetLabel: "site", Replacement: "dc1", Action: config.Add},
+		{TargetLabel: "state", Replacement: "unknown", Action: config.Add},
+	})
+
+	labels, keep := relabel(rulesFor(rules, "service"), "windows_service_state", labelPairs("name", "w32time", "state", "running"))
+	if !keep {
+		t.Fatal("expected the w32time series to be kept")
+	}
+	expected := labelPairs("name", "w32time", "service_name", "w32time", "site", "dc1", "state", "running", "subject", "service/w32time")
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("unexpected labels %v, expected %v", labels, expected)
+	}
+
+	if _, keep := relabel(rulesFor(rules, "service"), "windows_service_state", labelPairs("name", "spooler")); keep {
+		t.Error("expected the spooler series to be dropped")
+	}
+	if _, keep := relabel(rulesFor(rules, "process"), "windows_process_state", labelPairs("name", "spooler")); !keep {
+		t.Error("expected the drop rule to only apply to the service collector")
+	}
+}
+
+func TestStaticLabelsGatherer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	state := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "windows_service_state", Help: "State."}, []string{"name", "site"})
+	state.WithLabelValues("w32time", "branch").Set(1)
+	state.WithLabelValues("spooler", "").Set(1)
+	reg.MustRegister(state)
+
+	mfs, err := staticLabelsGatherer(map[string]string{"site": "dc1", "env": "prod"}, reg).Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]*dto.LabelPair{
+		labelPairs("env", "prod", "name", "spooler", "site", "dc1"),
+		labelPairs("env", "prod", "name", "w32time", "site", "branch"),
+	}
+	for i, m := range mfs[0].Metric {
+		if !reflect.DeepEqual(m.GetLabel(), expected[i]) {
+			t.Errorf("unexpected labels %v, expected %v", m.GetLabel(), expected[i])
+		}
+	}
+}
+
+// constCollector sends a fixed set of metrics.
+type constCollector []prometheus.Metric
+
+func (c constCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c constCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range c {
+		ch <- m
+	}
+}
+
+func TestMetricsExpositionOpenMetrics(t *testing.T) {
+	desc := prometheus.NewDesc("windows_cpu_time_total", "Time.", []string{"core", "mode"}, nil)
+	created := time.Unix(1700000000, 0)
+	counter := prometheus.MustNewConstMetricWithCreatedTimestamp(desc, prometheus.CounterValue, 42, created, "0,0", "idle")
+	relabeled, _ := relabel(compileRelabelRules([]config.RelabelConfig{
+		{TargetLabel: "site", Replacement: "dc1", Action: config.Add},
+	}), "windows_cpu_time_total", labelPairs("core", "0,1", "mode", "idle"))
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(constCollector{
+		counter,
+		relabeledMetric{
+			Metric: prometheus.MustNewConstMetricWithCreatedTimestamp(desc, prometheus.CounterValue, 7, created, "0,1", "idle"),
+			labels: relabeled,
+		},
+	})
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rec := httptest.NewRecorder()
+	metricsExposition(reg).ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Fatalf("expected OpenMetrics content type, got %q", ct)
+	}
+	body := rec.Body.String()
+	for _, line := range []string{
+		`windows_cpu_time_total{core="0,0",mode="idle"} 42.0`,
+		`windows_cpu_time_created{core="0,0",mode="idle"} 1.7e+09`,
+		`windows_cpu_time_created{core="0,1",mode="idle",site="dc1"} 1.7e+09`,
+		"# EOF",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("expected line %q in output:\n%s", line, body)
+		}
+	}
+}
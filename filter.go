@@ -0,0 +1,314 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type matchType int
+
+const (
+	matchEqual matchType = iota
+	matchNotEqual
+	matchRegexp
+	matchNotRegexp
+)
+
+// labelMatcher matches the value of a single label, like a PromQL label matcher.
+type labelMatcher struct {
+	name      string
+	matchType matchType
+	value     string
+	re        *regexp.Regexp
+}
+
+func (m labelMatcher) matches(v string) bool {
+	switch m.matchType {
+	case matchNotEqual:
+		return v != m.value
+	case matchRegexp:
+		return m.re.MatchString(v)
+	case matchNotRegexp:
+		return !m.re.MatchString(v)
+	default:
+		return v == m.value
+	}
+}
+
+// seriesSelector is a PromQL instant vector selector such as
+// windows_service_state{state="running",name=~"w.*"}, as used by the match[]
+// parameter of Prometheus federation.
+type seriesSelector []labelMatcher
+
+// matches reports whether a series with the given metric name and labels is
+// selected. Missing labels match as empty values.
+func (s seriesSelector) matches(name string, labels []*dto.LabelPair) bool {
+	for _, m := range s {
+		value := ""
+		if m.name == "__name__" {
+			value = name
+		} else {
+			for _, l := range labels {
+				if l.GetName() == m.name {
+					value = l.GetValue()
+					break
+				}
+			}
+		}
+		if !m.matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSeriesSelector parses a PromQL instant vector selector made of an
+// optional metric name followed by optional label matchers in braces.
+func parseSeriesSelector(input string) (seriesSelector, error) {
+	p := selectorParser{input: input}
+	selector, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid series selector %q: %w", input, err)
+	}
+	return selector, nil
+}
+
+type selectorParser struct {
+	input string
+	pos   int
+}
+
+func (p *selectorParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *selectorParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func isNameChar(c byte, first bool) bool {
+	return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+func (p *selectorParser) name() string {
+	start := p.pos
+	for p.pos < len(p.input) && isNameChar(p.input[p.pos], p.pos == start) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *selectorParser) parse() (seriesSelector, error) {
+	var selector seriesSelector
+
+	p.skipSpace()
+	if name := p.name(); name != "" {
+		selector = append(selector, labelMatcher{name: "__name__", value: name})
+	}
+	p.skipSpace()
+
+	if p.peek() == '{' {
+		p.pos++
+		for {
+			p.skipSpace()
+			if p.peek() == '}' {
+				p.pos++
+				break
+			}
+			m, err := p.matcher()
+			if err != nil {
+				return nil, err
+			}
+			selector = append(selector, m)
+
+			p.skipSpace()
+			switch p.peek() {
+			case ',':
+				p.pos++
+			case '}':
+			default:
+				return nil, fmt.Errorf("expected ',' or '}' at position %d", p.pos)
+			}
+		}
+	}
+
+	p.skipSpace()
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos:], p.pos)
+	}
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("selector is empty")
+	}
+	// Like PromQL, a selector must not select every series, including those
+	// without any of its labels.
+	for _, m := range selector {
+		if !m.matches("") {
+			return selector, nil
+		}
+	}
+	return nil, fmt.Errorf("selector must contain at least one matcher not matching the empty string")
+}
+
+func (p *selectorParser) matcher() (labelMatcher, error) {
+	m := labelMatcher{name: p.name()}
+	if m.name == "" {
+		return m, fmt.Errorf("expected label name at position %d", p.pos)
+	}
+
+	p.skipSpace()
+	rest := p.input[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, "=~"):
+		m.matchType = matchRegexp
+		p.pos += 2
+	case strings.HasPrefix(rest, "!~"):
+		m.matchType = matchNotRegexp
+		p.pos += 2
+	case strings.HasPrefix(rest, "!="):
+		m.matchType = matchNotEqual
+		p.pos += 2
+	case strings.HasPrefix(rest, "="):
+		m.matchType = matchEqual
+		p.pos++
+	default:
+		return m, fmt.Errorf("expected label matcher operator at position %d", p.pos)
+	}
+
+	p.skipSpace()
+	value, err := p.quoted()
+	if err != nil {
+		return m, err
+	}
+	m.value = value
+
+	if m.matchType == matchRegexp || m.matchType == matchNotRegexp {
+		// Like PromQL, regular expressions are fully anchored.
+		m.re, err = regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return m, err
+		}
+	}
+	return m, nil
+}
+
+// quoted reads a double-quoted, single-quoted or backtick-quoted string.
+// Double- and single-quoted strings use the escapes of Go strings.
+func (p *selectorParser) quoted() (string, error) {
+	quote := p.peek()
+	if quote != '"' && quote != '\'' && quote != '`' {
+		return "", fmt.Errorf("expected quoted label value at position %d", p.pos)
+	}
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c == '\\' && quote != '`' {
+			p.pos += 2
+			continue
+		}
+		p.pos++
+		if c == quote {
+			if quote == '\'' {
+				return unquoteSingle(p.input[start+1 : p.pos-1])
+			}
+			return strconv.Unquote(p.input[start:p.pos])
+		}
+	}
+	return "", fmt.Errorf("unterminated quoted string at position %d", start)
+}
+
+// unquoteSingle unescapes the content of a single-quoted string, which
+// strconv.Unquote only accepts for single characters.
+func unquoteSingle(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case c == '\\' && i+1 < len(s):
+			b.WriteString(s[i : i+2])
+			i++
+		case c == '"':
+			b.WriteString(`\"`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strconv.Unquote(`"` + b.String() + `"`)
+}
+
+// seriesFilter keeps the series matching any of its match selectors, or all
+// series if there are none, unless they match any of its drop selectors.
+type seriesFilter struct {
+	match []seriesSelector
+	drop  []seriesSelector
+}
+
+func parseSeriesSelectors(inputs []string) ([]seriesSelector, error) {
+	selectors := make([]seriesSelector, 0, len(inputs))
+	for _, input := range inputs {
+		selector, err := parseSeriesSelector(input)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+func (f *seriesFilter) keep(name string, labels []*dto.LabelPair) bool {
+	for _, s := range f.drop {
+		if s.matches(name, labels) {
+			return false
+		}
+	}
+	if len(f.match) == 0 {
+		return true
+	}
+	for _, s := range f.match {
+		if s.matches(name, labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// gatherer wraps g, removing the series which are not kept by the filter
+// before they are encoded.
+func (f *seriesFilter) gatherer(g prometheus.Gatherer) prometheus.Gatherer {
+	if len(f.match) == 0 && len(f.drop) == 0 {
+		return g
+	}
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		mfs, err := g.Gather()
+		filtered := mfs[:0]
+		for _, mf := range mfs {
+			metrics := mf.Metric[:0]
+			for _, m := range mf.Metric {
+				if f.keep(mf.GetName(), m.GetLabel()) {
+					metrics = append(metrics, m)
+				}
+			}
+			if len(metrics) > 0 {
+				mf.Metric = metrics
+				filtered = append(filtered, mf)
+			}
+		}
+		return filtered, err
+	})
+}
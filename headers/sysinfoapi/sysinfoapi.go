@@ -103,6 +103,7 @@ var (
 	procGetSystemInfo        = kernel32.NewProc("GetSystemInfo")
 	procGlobalMemoryStatusEx = kernel32.NewProc("GlobalMemoryStatusEx")
 	procGetComputerNameExW   = kernel32.NewProc("GetComputerNameExW")
+	procGetTickCount64       = kernel32.NewProc("GetTickCount64")
 )
 
 // GlobalMemoryStatusEx retrieves information about the system's current usage of both physical and virtual memory.
@@ -162,3 +163,14 @@ func GetComputerName(f WinComputerNameFormat) (string, error) {
 	out := utf16.Decode(bytes)
 	return string(out), nil
 }
+
+// GetTickCount64 retrieves the number of milliseconds that have elapsed since the system was started.
+// https://docs.microsoft.com/en-us/windows/win32/api/sysinfoapi/nf-sysinfoapi-gettickcount64
+func GetTickCount64() uint64 {
+	r1, r2, _ := procGetTickCount64.Call()
+	if unsafe.Sizeof(r1) == 4 {
+		// On 32-bit platforms, the result is returned in EDX:EAX.
+		return uint64(r2)<<32 | uint64(r1)
+	}
+	return uint64(r1)
+}
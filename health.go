@@ -0,0 +1,114 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus-community/windows_exporter/log"
+	"github.com/prometheus/common/version"
+)
+
+// checkResult is the outcome of a single readiness check.
+type checkResult struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+}
+
+// healthStatus is the JSON body of /-/healthy and /-/ready.
+type healthStatus struct {
+	Status        string        `json:"status"`
+	Version       string        `json:"version,omitempty"`
+	UptimeSeconds float64       `json:"uptime_seconds,omitempty"`
+	Checks        []checkResult `json:"checks,omitempty"`
+}
+
+var startTime = time.Now()
+
+// readiness decides whether the exporter can serve meaningful metrics. Unlike a
+// scrape, checking it runs no collector.
+type readiness struct {
+	// wbemErr is the error initWbem failed with, if any.
+	wbemErr error
+	// maxAge is the maximum time since the last success of the required
+	// collectors.
+	maxAge time.Duration
+}
+
+func (rd readiness) check(now time.Time) healthStatus {
+	checks := []checkResult{{Name: "wmi", Ready: rd.wbemErr == nil}}
+	if rd.wbemErr != nil {
+		checks[0].Message = fmt.Sprintf("initializing SWbemServices failed: %s", rd.wbemErr)
+	}
+
+	perflib := checkResult{Name: "perflib", Ready: collector.PerflibNameTableLoaded()}
+	if !perflib.Ready {
+		perflib.Message = "perflib counter name table is empty"
+	}
+	checks = append(checks, perflib)
+
+	cfg := currentConfig()
+	for _, info := range collectorRuns.get(cfg.required) {
+		// Replays of cached results within the minimum interval of the
+		// collector are not runs, so its last success may be older by as much.
+		checks = append(checks, checkCollector(info, rd.maxAge+cfg.minIntervals[info.Name], now))
+	}
+
+	status := healthStatus{Status: "ready", Checks: checks}
+	for _, c := range checks {
+		if !c.Ready {
+			status.Status = "not ready"
+		}
+	}
+	return status
+}
+
+// checkCollector reports a collector as ready as long as it succeeded within
+// maxAge. It is not ready until it has run.
+func checkCollector(info collectorDebugInfo, maxAge time.Duration, now time.Time) checkResult {
+	result := checkResult{Name: "collector:" + info.Name, Ready: true}
+	switch {
+	case info.LastRun == nil:
+		result.Ready = false
+		result.Message = "collector has not run yet"
+	case info.LastSuccessTime == nil:
+		result.Ready = false
+		result.Message = fmt.Sprintf("collector never succeeded, last error: %s", info.LastError)
+	case now.Sub(*info.LastSuccessTime) > maxAge:
+		result.Ready = false
+		result.Message = fmt.Sprintf("collector last succeeded at %s, last error: %s", info.LastSuccessTime.Format(time.RFC3339), info.LastError)
+	}
+	return result
+}
+
+func (rd readiness) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := rd.check(time.Now())
+	code := http.StatusOK
+	if status.Status != "ready" {
+		code = http.StatusServiceUnavailable
+	}
+	writeHealthStatus(w, code, status)
+}
+
+// healthy reports that the exporter is up and serving requests.
+func healthy(w http.ResponseWriter, r *http.Request) {
+	writeHealthStatus(w, http.StatusOK, healthStatus{
+		Status:        "healthy",
+		Version:       version.Version,
+		UptimeSeconds: time.Since(startTime).Seconds(),
+	})
+}
+
+func writeHealthStatus(w http.ResponseWriter, code int, status healthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Debugf("Failed to write to stream: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckCollector(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	recent, old := now.Add(-time.Minute), now.Add(-time.Hour)
+	run := &collectorRun{Time: now}
+	cases := []struct {
+		name     string
+		info     collectorDebugInfo
+		expected bool
+	}{
+		{"not run yet", collectorDebugInfo{}, false},
+		{"never succeeded", collectorDebugInfo{LastRun: run, LastError: "access denied"}, false},
+		{"succeeded recently", collectorDebugInfo{LastRun: run, LastSuccessTime: &recent}, true},
+		{"succeeded long ago", collectorDebugInfo{LastRun: run, LastSuccessTime: &old}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if result := checkCollector(c.info, 5*time.Minute, now); result.Ready != c.expected {
+				t.Errorf("expected ready to be %v, got %+v", c.expected, result)
+			}
+		})
+	}
+}
@@ -13,11 +13,12 @@ const (
 )
 
 type windowsExporterService struct {
-	stopCh chan<- bool
+	stopCh   chan<- bool
+	reloadCh chan<- bool
 }
 
 func (s *windowsExporterService) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (ssec bool, errno uint32) {
-	const cmdsAccepted = svc.AcceptStop | svc.AcceptShutdown
+	const cmdsAccepted = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptParamChange
 	changes <- svc.Status{State: svc.StartPending}
 	changes <- svc.Status{State: svc.Running, Accepts: cmdsAccepted}
 loop:
@@ -31,6 +32,13 @@ loop:
 				log.Debug("Service Stop Received")
 				s.stopCh <- true
 				break loop
+			case svc.ParamChange:
+				log.Debug("Service Parameter Change Received")
+				// A reload is already pending otherwise.
+				select {
+				case s.reloadCh <- true:
+				default:
+				}
 			default:
 				log.Error(fmt.Sprintf("unexpected control request #%d", c))
 			}
@@ -42,6 +50,9 @@ loop:
 
 var StopCh = make(chan bool)
 
+// ReloadCh receives a value when the service is asked to reload its parameters.
+var ReloadCh = make(chan bool, 1)
+
 func init() {
 	log.Debug("Checking if We are a service")
 	isService, err := svc.IsWindowsService()
@@ -51,7 +62,7 @@ func init() {
 	log.Debug("Attempting to start exporter service")
 	if isService {
 		go func() {
-			err = svc.Run(serviceName, &windowsExporterService{stopCh: StopCh})
+			err = svc.Run(serviceName, &windowsExporterService{stopCh: StopCh, reloadCh: ReloadCh})
 			if err != nil {
 				log.Errorf("Failed to start service: %v", err)
 			}
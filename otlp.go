@@ -0,0 +1,317 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/version"
+)
+
+// otlpPusher sends metrics to an OTLP/HTTP metrics endpoint, using the JSON
+// encoding of the OTLP protocol.
+type otlpPusher struct {
+	url string
+	// serviceName is the service.name resource attribute.
+	serviceName string
+	// hostname is the host.name resource attribute, unless the cs collector
+	// reports one.
+	hostname string
+	client   *http.Client
+}
+
+func (p *otlpPusher) name() string {
+	return "OTLP endpoint " + p.url
+}
+
+func (p *otlpPusher) push(mfs []*dto.MetricFamily) error {
+	body, err := json.Marshal(encodeOTLP(mfs, p.resourceAttributes(mfs), time.Now()))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "windows_exporter/"+version.Version)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("server returned HTTP status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// resourceAttributes describes the host, following the OpenTelemetry semantic
+// conventions, from the series of the cs and os collectors when they are enabled.
+func (p *otlpPusher) resourceAttributes(mfs []*dto.MetricFamily) map[string]string {
+	attributes := map[string]string{
+		"service.name":    p.serviceName,
+		"service.version": version.Version,
+		"host.name":       p.hostname,
+		"os.type":         "windows",
+	}
+	for _, mf := range mfs {
+		if len(mf.Metric) == 0 {
+			continue
+		}
+		labels := make(map[string]string)
+		for _, l := range mf.Metric[0].Label {
+			labels[l.GetName()] = l.GetValue()
+		}
+		switch mf.GetName() {
+		case "windows_cs_hostname":
+			if labels["fqdn"] != "" {
+				attributes["host.name"] = labels["fqdn"]
+			}
+		case "windows_os_info":
+			attributes["os.description"] = labels["product"]
+			attributes["os.version"] = labels["version"]
+			attributes["os.build_id"] = labels["build_number"]
+		}
+	}
+	return attributes
+}
+
+// The types below follow the JSON encoding of the OTLP metrics protocol, in
+// which 64-bit integers are strings.
+
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Gauge       *otlpGauge     `json:"gauge,omitempty"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+	Summary     *otlpSummary   `json:"summary,omitempty"`
+}
+
+// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE, the temporality of all
+// Prometheus counters and histograms.
+const otlpCumulative = 2
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                      `json:"aggregationTemporality"`
+}
+
+type otlpSummary struct {
+	DataPoints []otlpSummaryDataPoint `json:"dataPoints"`
+}
+
+type otlpDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+}
+
+type otlpNumberDataPoint struct {
+	otlpDataPoint
+	AsDouble otlpDouble `json:"asDouble"`
+}
+
+type otlpHistogramDataPoint struct {
+	otlpDataPoint
+	Count          string       `json:"count"`
+	Sum            otlpDouble   `json:"sum"`
+	BucketCounts   []string     `json:"bucketCounts"`
+	ExplicitBounds []otlpDouble `json:"explicitBounds"`
+}
+
+type otlpSummaryDataPoint struct {
+	otlpDataPoint
+	Count          string              `json:"count"`
+	Sum            otlpDouble          `json:"sum"`
+	QuantileValues []otlpQuantileValue `json:"quantileValues"`
+}
+
+type otlpQuantileValue struct {
+	Quantile otlpDouble `json:"quantile"`
+	Value    otlpDouble `json:"value"`
+}
+
+// otlpDouble is a float64 encoded like in the proto3 JSON mapping, which
+// represents special values as strings.
+type otlpDouble float64
+
+func (d otlpDouble) MarshalJSON() ([]byte, error) {
+	f := float64(d)
+	switch {
+	case math.IsNaN(f):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(f, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(f, -1):
+		return []byte(`"-Infinity"`), nil
+	default:
+		return json.Marshal(f)
+	}
+}
+
+func otlpAttributes(attributes map[string]string) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attributes))
+	for k, v := range attributes {
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: v}})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// encodeOTLP maps Prometheus metric families to OTLP metrics. Counters become
+// monotonic cumulative sums, starting at their created timestamp if known.
+// Gauges and untyped metrics become gauges.
+func encodeOTLP(mfs []*dto.MetricFamily, resource map[string]string, now time.Time) otlpMetricsRequest {
+	metrics := make([]otlpMetric, 0, len(mfs))
+	for _, mf := range mfs {
+		// OTLP receivers reject metrics without data points.
+		if len(mf.Metric) == 0 {
+			continue
+		}
+		metric := otlpMetric{Name: mf.GetName(), Description: mf.GetHelp()}
+		for _, m := range mf.Metric {
+			point := otlpDataPoint{TimeUnixNano: unixNano(now)}
+			if m.TimestampMs != nil {
+				point.TimeUnixNano = unixNano(time.UnixMilli(m.GetTimestampMs()))
+			}
+			attributes := make([]otlpKeyValue, 0, len(m.Label))
+			for _, l := range m.Label {
+				attributes = append(attributes, otlpKeyValue{Key: l.GetName(), Value: otlpAnyValue{StringValue: l.GetValue()}})
+			}
+			point.Attributes = attributes
+
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				if ct := m.GetCounter().GetCreatedTimestamp(); ct != nil {
+					point.StartTimeUnixNano = unixNano(ct.AsTime())
+				}
+				if metric.Sum == nil {
+					metric.Sum = &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+				}
+				metric.Sum.DataPoints = append(metric.Sum.DataPoints, otlpNumberDataPoint{point, otlpDouble(m.GetCounter().GetValue())})
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				if ct := h.GetCreatedTimestamp(); ct != nil {
+					point.StartTimeUnixNano = unixNano(ct.AsTime())
+				}
+				dp := otlpHistogramDataPoint{
+					otlpDataPoint: point,
+					Count:         strconv.FormatUint(h.GetSampleCount(), 10),
+					Sum:           otlpDouble(h.GetSampleSum()),
+				}
+				// OTLP bucket counts are not cumulative, and the last bucket has
+				// no explicit bound.
+				var previous uint64
+				for _, b := range h.Bucket {
+					if math.IsInf(b.GetUpperBound(), 1) {
+						continue
+					}
+					dp.ExplicitBounds = append(dp.ExplicitBounds, otlpDouble(b.GetUpperBound()))
+					dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(b.GetCumulativeCount()-previous, 10))
+					previous = b.GetCumulativeCount()
+				}
+				dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(h.GetSampleCount()-previous, 10))
+				if metric.Histogram == nil {
+					metric.Histogram = &otlpHistogram{AggregationTemporality: otlpCumulative}
+				}
+				metric.Histogram.DataPoints = append(metric.Histogram.DataPoints, dp)
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				if ct := s.GetCreatedTimestamp(); ct != nil {
+					point.StartTimeUnixNano = unixNano(ct.AsTime())
+				}
+				dp := otlpSummaryDataPoint{
+					otlpDataPoint: point,
+					Count:         strconv.FormatUint(s.GetSampleCount(), 10),
+					Sum:           otlpDouble(s.GetSampleSum()),
+				}
+				for _, q := range s.Quantile {
+					dp.QuantileValues = append(dp.QuantileValues, otlpQuantileValue{otlpDouble(q.GetQuantile()), otlpDouble(q.GetValue())})
+				}
+				if metric.Summary == nil {
+					metric.Summary = &otlpSummary{}
+				}
+				metric.Summary.DataPoints = append(metric.Summary.DataPoints, dp)
+			default:
+				value := m.GetUntyped().GetValue()
+				if mf.GetType() == dto.MetricType_GAUGE {
+					value = m.GetGauge().GetValue()
+				}
+				if metric.Gauge == nil {
+					metric.Gauge = &otlpGauge{}
+				}
+				metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, otlpNumberDataPoint{point, otlpDouble(value)})
+			}
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return otlpMetricsRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: otlpAttributes(resource)},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: "windows_exporter", Version: version.Version},
+			Metrics: metrics,
+		}},
+	}}}
+}
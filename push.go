@@ -0,0 +1,159 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus-community/windows_exporter/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+var pushgatewaySeriesChanges = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: collector.Namespace,
+		Subsystem: "exporter",
+		Name:      "pushgateway_series_changes_total",
+		Help:      "windows_exporter: Number of timestamps and labels dropped or renamed for the Pushgateway to accept the pushed series, by change.",
+	},
+	[]string{"change"},
+)
+
+// The changes counted by pushgatewaySeriesChanges.
+const (
+	changeTimestampDropped = "timestamp_dropped"
+	changeLabelRenamed     = "label_renamed"
+	changeLabelDropped     = "label_dropped"
+)
+
+func init() {
+	exporterRegistry.MustRegister(pushgatewaySeriesChanges)
+}
+
+// metricsPusher sends gathered metrics to a push-based system.
+type metricsPusher interface {
+	// name identifies the pusher in logs.
+	name() string
+	push(mfs []*dto.MetricFamily) error
+}
+
+// pushLoop gathers the series of the exporter every interval, and sends them
+// to every pusher. It never returns.
+func pushLoop(interval time.Duration, gatherer func() (prometheus.Gatherer, error), pushers []metricsPusher) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		pushOnce(gatherer, pushers)
+		<-ticker.C
+	}
+}
+
+func pushOnce(gatherer func() (prometheus.Gatherer, error), pushers []metricsPusher) {
+	g, err := gatherer()
+	if err != nil {
+		log.Errorf("push: couldn't create gatherer: %v", err)
+		return
+	}
+	// Like a scrape, failing collectors do not prevent the others from being pushed.
+	mfs, err := g.Gather()
+	if err != nil {
+		log.Warnf("push: error gathering metrics: %v", err)
+	}
+	for _, p := range pushers {
+		if err := p.push(mfs); err != nil {
+			log.Errorf("push: couldn't push metrics to %s: %v", p.name(), err)
+		}
+	}
+}
+
+// pushgatewayPusher replaces the metrics of the group identified by its job and
+// the hostname of the host on a Pushgateway.
+type pushgatewayPusher struct {
+	url      string
+	job      string
+	hostname string
+	client   *http.Client
+}
+
+func (p *pushgatewayPusher) name() string {
+	return "Pushgateway " + p.url
+}
+
+func (p *pushgatewayPusher) push(mfs []*dto.MetricFamily) error {
+	mfs = pushgatewayFamilies(mfs)
+	g := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) { return mfs, nil })
+	return push.New(p.url, p.job).
+		Grouping("instance", p.hostname).
+		Client(p.client).
+		Gatherer(g).
+		Push()
+}
+
+// pushgatewayFamilies returns mfs as the Pushgateway accepts them. It rejects
+// whole pushes holding timestamps, or job and instance labels, which identify
+// the group instead. Timestamps are dropped, and job and instance labels are
+// renamed to exported_job and exported_instance like Prometheus does, or
+// dropped if those are taken. Families needing changes are copied, as mfs is
+// pushed to other systems too.
+func pushgatewayFamilies(mfs []*dto.MetricFamily) []*dto.MetricFamily {
+	out := make([]*dto.MetricFamily, 0, len(mfs))
+	for _, mf := range mfs {
+		if !needsPushgatewayChanges(mf) {
+			out = append(out, mf)
+			continue
+		}
+		mf = proto.Clone(mf).(*dto.MetricFamily)
+		for _, m := range mf.Metric {
+			if m.TimestampMs != nil {
+				m.TimestampMs = nil
+				pushgatewaySeriesChanges.WithLabelValues(changeTimestampDropped).Inc()
+			}
+			m.Label = pushgatewayLabels(m.Label)
+		}
+		out = append(out, mf)
+	}
+	return out
+}
+
+func needsPushgatewayChanges(mf *dto.MetricFamily) bool {
+	for _, m := range mf.Metric {
+		if m.TimestampMs != nil {
+			return true
+		}
+		for _, l := range m.Label {
+			if l.GetName() == "job" || l.GetName() == "instance" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func pushgatewayLabels(labels []*dto.LabelPair) []*dto.LabelPair {
+	names := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		names[l.GetName()] = true
+	}
+	kept := labels[:0]
+	for _, l := range labels {
+		if name := l.GetName(); name == "job" || name == "instance" {
+			exported := "exported_" + name
+			if names[exported] {
+				pushgatewaySeriesChanges.WithLabelValues(changeLabelDropped).Inc()
+				continue
+			}
+			l.Name = &exported
+			pushgatewaySeriesChanges.WithLabelValues(changeLabelRenamed).Inc()
+		}
+		kept = append(kept, l)
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].GetName() < kept[j].GetName() })
+	return kept
+}
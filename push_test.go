@@ -0,0 +1,179 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func gatherTestMetrics(t *testing.T) []*dto.MetricFamily {
+	t.Helper()
+	reg := prometheus.NewRegistry()
+	cpu := prometheus.NewDesc("windows_cpu_time_total", "Time.", []string{"mode"}, nil)
+	hostname := prometheus.NewDesc("windows_cs_hostname", "Hostname.", []string{"hostname", "domain", "fqdn"}, nil)
+	osInfo := prometheus.NewDesc("windows_os_info", "OS.", []string{"product", "version", "build_number"}, nil)
+	reg.MustRegister(constCollector{
+		prometheus.MustNewConstMetricWithCreatedTimestamp(cpu, prometheus.CounterValue, 42, time.Unix(1700000000, 0), "idle"),
+		prometheus.MustNewConstMetric(hostname, prometheus.GaugeValue, 1, "host1", "example.com", "host1.example.com"),
+		prometheus.MustNewConstMetric(osInfo, prometheus.GaugeValue, 1, "Microsoft Windows Server 2022", "10.0.20348", "20348"),
+		prometheus.MustNewConstHistogram(prometheus.NewDesc("windows_duration_seconds", "Duration.", nil, nil), 3, 2.5, map[float64]uint64{0.5: 1, 1: 2}),
+	})
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mfs
+}
+
+func TestPushgatewayPusher(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(b)
+	}))
+	defer server.Close()
+
+	p := &pushgatewayPusher{url: server.URL, job: "windows", hostname: "host1", client: server.Client()}
+	if err := p.push(gatherTestMetrics(t)); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/windows/instance/host1" {
+		t.Errorf("unexpected request %s %s", method, path)
+	}
+	if !strings.Contains(body, "windows_cpu_time_total") {
+		t.Errorf("expected pushed metrics to include windows_cpu_time_total")
+	}
+}
+
+func TestOTLPPusher(t *testing.T) {
+	var request otlpMetricsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "unexpected content type", http.StatusUnsupportedMediaType)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	p := &otlpPusher{url: server.URL, serviceName: "windows_exporter", hostname: "fallback", client: server.Client()}
+	if err := p.push(gatherTestMetrics(t)); err != nil {
+		t.Fatal(err)
+	}
+	if len(request.ResourceMetrics) != 1 {
+		t.Fatalf("expected a single resource, got %d", len(request.ResourceMetrics))
+	}
+
+	attributes := make(map[string]string)
+	for _, kv := range request.ResourceMetrics[0].Resource.Attributes {
+		attributes[kv.Key] = kv.Value.StringValue
+	}
+	for k, v := range map[string]string{
+		"service.name":   "windows_exporter",
+		"host.name":      "host1.example.com",
+		"os.type":        "windows",
+		"os.description": "Microsoft Windows Server 2022",
+		"os.version":     "10.0.20348",
+	} {
+		if attributes[k] != v {
+			t.Errorf("expected resource attribute %s=%q, got %q", k, v, attributes[k])
+		}
+	}
+
+	metrics := make(map[string]otlpMetric)
+	for _, m := range request.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	sum := metrics["windows_cpu_time_total"].Sum
+	if sum == nil || !sum.IsMonotonic || sum.AggregationTemporality != otlpCumulative {
+		t.Fatalf("expected windows_cpu_time_total to be a monotonic cumulative sum, got %+v", metrics["windows_cpu_time_total"])
+	}
+	if dp := sum.DataPoints[0]; dp.AsDouble != 42 || dp.StartTimeUnixNano != "1700000000000000000" {
+		t.Errorf("unexpected data point %+v", dp)
+	}
+	if metrics["windows_os_info"].Gauge == nil {
+		t.Errorf("expected windows_os_info to be a gauge")
+	}
+
+	histogram := metrics["windows_duration_seconds"].Histogram
+	if histogram == nil {
+		t.Fatalf("expected windows_duration_seconds to be a histogram")
+	}
+	dp := histogram.DataPoints[0]
+	if !reflect.DeepEqual(dp.BucketCounts, []string{"1", "1", "1"}) || !reflect.DeepEqual(dp.ExplicitBounds, []otlpDouble{0.5, 1}) || dp.Count != "3" {
+		t.Errorf("unexpected histogram data point %+v", dp)
+	}
+}
+
+func TestPushgatewayFamilies(t *testing.T) {
+	ts := int64(1700000000000)
+	name, value := "windows_textfile_backup", 1.0
+	metric := &dto.Metric{
+		Label:       labelPairs("instance", "host2", "job", "backup", "exported_instance", "host3"),
+		Gauge:       &dto.Gauge{Value: &value},
+		TimestampMs: &ts,
+	}
+	typ := dto.MetricType_GAUGE
+	mfs := []*dto.MetricFamily{{Name: &name, Type: &typ, Metric: []*dto.Metric{metric}}}
+
+	changes := func(change string) float64 {
+		return testutil.ToFloat64(pushgatewaySeriesChanges.WithLabelValues(change))
+	}
+	timestamps, renamed, dropped := changes(changeTimestampDropped), changes(changeLabelRenamed), changes(changeLabelDropped)
+
+	pushed := pushgatewayFamilies(mfs)
+	m := pushed[0].Metric[0]
+	if m.TimestampMs != nil {
+		t.Errorf("expected the timestamp to be dropped")
+	}
+	expected := labelPairs("exported_instance", "host3", "exported_job", "backup")
+	if !reflect.DeepEqual(m.Label, expected) {
+		t.Errorf("expected labels %v, got %v", expected, m.Label)
+	}
+	if metric.TimestampMs == nil || len(metric.Label) != 3 || metric.Label[1].GetName() != "job" {
+		t.Errorf("expected the gathered metric to be left as is, got %v", metric)
+	}
+	for change, delta := range map[string]float64{
+		changeTimestampDropped: changes(changeTimestampDropped) - timestamps,
+		changeLabelRenamed:     changes(changeLabelRenamed) - renamed,
+		changeLabelDropped:     changes(changeLabelDropped) - dropped,
+	} {
+		if delta != 1 {
+			t.Errorf("expected one %s change, got %v", change, delta)
+		}
+	}
+
+	// The Pushgateway accepts the result.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	p := &pushgatewayPusher{url: server.URL, job: "windows", hostname: "host1", client: server.Client()}
+	if err := p.push(mfs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOTLPSkipsEmptyFamilies(t *testing.T) {
+	name := "windows_empty"
+	typ := dto.MetricType_GAUGE
+	mfs := append(gatherTestMetrics(t), &dto.MetricFamily{Name: &name, Type: &typ})
+	request := encodeOTLP(mfs, nil, time.Now())
+	for _, m := range request.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		if m.Name == name {
+			t.Errorf("expected %s without data points to be skipped", name)
+		}
+	}
+}
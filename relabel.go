@@ -0,0 +1,194 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus-community/windows_exporter/config"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+)
+
+// relabelRule is a compiled config.RelabelConfig.
+type relabelRule struct {
+	config.RelabelConfig
+	regex      *regexp.Regexp
+	collectors map[string]bool
+}
+
+// compileRelabelRules compiles rules which have already been validated by the
+// config package.
+func compileRelabelRules(configs []config.RelabelConfig) []relabelRule {
+	rules := make([]relabelRule, 0, len(configs))
+	for _, c := range configs {
+		rule := relabelRule{
+			RelabelConfig: c,
+			// Like Prometheus, regular expressions are fully anchored.
+			regex: regexp.MustCompile("^(?:" + c.Regex + ")$"),
+		}
+		if len(c.Collectors) > 0 {
+			rule.collectors = make(map[string]bool, len(c.Collectors))
+			for _, name := range c.Collectors {
+				rule.collectors[name] = true
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// rulesFor returns the rules applying to the series of the named collector.
+func rulesFor(rules []relabelRule, name string) []relabelRule {
+	var applying []relabelRule
+	for _, r := range rules {
+		if r.collectors == nil || r.collectors[name] {
+			applying = append(applying, r)
+		}
+	}
+	return applying
+}
+
+// relabel applies rules in order to the labels of a series, returning its new
+// labels sorted by name, or false if the series is dropped. Like in Prometheus,
+// labels with an empty value are removed.
+func relabel(rules []relabelRule, name string, labels []*dto.LabelPair) ([]*dto.LabelPair, bool) {
+	set := make(map[string]string, len(labels)+1)
+	for _, l := range labels {
+		set[l.GetName()] = l.GetValue()
+	}
+
+	for _, r := range rules {
+		values := make([]string, 0, len(r.SourceLabels))
+		for _, l := range r.SourceLabels {
+			if l == "__name__" {
+				values = append(values, name)
+			} else {
+				values = append(values, set[l])
+			}
+		}
+		value := strings.Join(values, r.Separator)
+
+		switch r.Action {
+		case config.Add:
+			if set[r.TargetLabel] == "" {
+				set[r.TargetLabel] = r.Replacement
+			}
+		case config.Replace:
+			match := r.regex.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+			set[r.TargetLabel] = string(r.regex.ExpandString(nil, r.Replacement, value, match))
+		case config.Drop:
+			if r.regex.MatchString(value) {
+				return nil, false
+			}
+		case config.LabelMap:
+			mapped := make(map[string]string)
+			for l, v := range set {
+				if !r.regex.MatchString(l) {
+					continue
+				}
+				if target := r.regex.ReplaceAllString(l, r.Replacement); model.LabelName(target).IsValidLegacy() {
+					mapped[target] = v
+				}
+			}
+			for l, v := range mapped {
+				set[l] = v
+			}
+		}
+	}
+
+	relabeled := make([]*dto.LabelPair, 0, len(set))
+	for l, v := range set {
+		if v != "" {
+			relabeled = append(relabeled, labelPair(l, v))
+		}
+	}
+	sort.Slice(relabeled, func(i, j int) bool { return relabeled[i].GetName() < relabeled[j].GetName() })
+	return relabeled, true
+}
+
+func labelPair(name, value string) *dto.LabelPair {
+	return &dto.LabelPair{Name: &name, Value: &value}
+}
+
+// relabeledMetric is a metric whose labels were rewritten by relabel.
+type relabeledMetric struct {
+	prometheus.Metric
+	labels []*dto.LabelPair
+}
+
+func (m relabeledMetric) Write(out *dto.Metric) error {
+	if err := m.Metric.Write(out); err != nil {
+		return err
+	}
+	out.Label = m.labels
+	return nil
+}
+
+// relabelChannel returns a channel relabeling the metrics sent to it before
+// passing them on to ch, and a function to call once all metrics are sent.
+func relabelChannel(rules []relabelRule, ch chan<- prometheus.Metric) (chan<- prometheus.Metric, func()) {
+	if len(rules) == 0 {
+		return ch, func() {}
+	}
+
+	in := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for m := range in {
+			var pb dto.Metric
+			if err := m.Write(&pb); err != nil {
+				// Left for the registry to report.
+				ch <- m
+				continue
+			}
+			labels, keep := relabel(rules, collector.MetricName(m), pb.GetLabel())
+			if keep {
+				ch <- relabeledMetric{Metric: m, labels: labels}
+			}
+		}
+	}()
+	return in, func() {
+		close(in)
+		<-done
+	}
+}
+
+// staticLabelsGatherer wraps g, adding labels to every series which does not
+// already have them, or has them with an empty value.
+func staticLabelsGatherer(labels map[string]string, g prometheus.Gatherer) prometheus.Gatherer {
+	if len(labels) == 0 {
+		return g
+	}
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		mfs, err := g.Gather()
+		for _, mf := range mfs {
+			for _, m := range mf.Metric {
+				present := make(map[string]bool, len(m.Label))
+				for _, l := range m.Label {
+					// Empty labels are the same as missing ones.
+					if value, ok := labels[l.GetName()]; ok && l.GetValue() == "" {
+						l.Value = &value
+					}
+					present[l.GetName()] = true
+				}
+				for name, value := range labels {
+					if !present[name] {
+						m.Label = append(m.Label, labelPair(name, value))
+					}
+				}
+				sort.Slice(m.Label, func(i, j int) bool { return m.Label[i].GetName() < m.Label[j].GetName() })
+			}
+		}
+		return mfs, err
+	})
+}
@@ -0,0 +1,172 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus-community/windows_exporter/config"
+	"github.com/prometheus-community/windows_exporter/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	configReloadSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: collector.Namespace,
+		Subsystem: "exporter",
+		Name:      "config_last_reload_successful",
+		Help:      "windows_exporter: Whether the last configuration reload attempt was successful.",
+	})
+	configReloadSuccessTime = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: collector.Namespace,
+		Subsystem: "exporter",
+		Name:      "config_last_reload_success_timestamp_seconds",
+		Help:      "windows_exporter: Timestamp of the last successful configuration reload.",
+	})
+)
+
+func init() {
+	exporterRegistry.MustRegister(configReloadSuccess, configReloadSuccessTime)
+}
+
+// runtimeConfig holds the settings which take effect without restarting the
+// exporter. It is replaced as a whole on every reload.
+type runtimeConfig struct {
+	collectors map[string]collector.Collector
+	// minIntervals holds the minimum refresh interval of collectors whose
+	// results are cached and replayed on scrapes within that interval.
+	minIntervals map[string]time.Duration
+	// cache holds the results of the collectors with a minimum refresh interval.
+	cache *snapshotStore
+	// profiles maps profile names to the collectors they select.
+	profiles map[string][]string
+	// drop holds the selectors of series dropped from every scrape.
+	drop []seriesSelector
+	// labels are added to every series which does not already have them.
+	labels       map[string]string
+	relabelRules []relabelRule
+	// required are the collectors which must have succeeded recently for the
+	// exporter to be ready.
+	required []string
+}
+
+var activeConfig atomic.Pointer[runtimeConfig]
+
+// currentConfig returns the settings in use. Before the configuration is
+// loaded, no collector is enabled.
+func currentConfig() *runtimeConfig {
+	if cfg := activeConfig.Load(); cfg != nil {
+		return cfg
+	}
+	return &runtimeConfig{}
+}
+
+// reloader reloads the configuration, one reload at a time.
+type reloader struct {
+	mu sync.Mutex
+	// load re-reads the configuration and builds the settings it describes.
+	load func() (*runtimeConfig, error)
+	// apply is called with the new settings before they are put in use, and
+	// with the settings they replace, if any.
+	apply func(cfg, previous *runtimeConfig)
+}
+
+// use puts cfg in use.
+func (rl *reloader) use(cfg *runtimeConfig) {
+	previous := activeConfig.Load()
+	if rl.apply != nil {
+		rl.apply(cfg, previous)
+	}
+	activeConfig.Store(cfg)
+	configReloadSuccess.Set(1)
+	configReloadSuccessTime.SetToCurrentTime()
+}
+
+// reload re-reads the configuration and swaps the settings in use for the new
+// ones. The settings in use are kept if the configuration is invalid.
+func (rl *reloader) reload(trigger string) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	log.Infof("Reloading configuration, triggered by %s", trigger)
+	cfg, err := rl.load()
+	if err != nil {
+		configReloadSuccess.Set(0)
+		log.Errorf("Couldn't reload configuration: %s", err)
+		return err
+	}
+	rl.use(cfg)
+	log.Infof("Configuration reloaded, enabled collectors: %v", keys(cfg.collectors))
+	return nil
+}
+
+// ServeHTTP reloads the configuration on POST requests.
+func (rl *reloader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Only POST requests allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := rl.reload("a request to /-/reload"); err != nil {
+		http.Error(w, fmt.Sprintf("failed to reload config: %s", err), http.StatusInternalServerError)
+	}
+}
+
+// listen reloads the configuration whenever a value is received on ch.
+func (rl *reloader) listen(ch <-chan bool, trigger string) {
+	for range ch {
+		_ = rl.reload(trigger)
+	}
+}
+
+// watch reloads the configuration whenever the configuration files change,
+// checking them every interval. Files added to or removed from configuration
+// directories count as changes. It never returns.
+func (rl *reloader) watch(paths []string, interval time.Duration) {
+	sum, err := configChecksum(paths)
+	if err != nil {
+		log.Warnf("Couldn't read configuration files: %s", err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		current, err := configChecksum(paths)
+		if err != nil {
+			log.Warnf("Couldn't read configuration files: %s", err)
+			continue
+		}
+		if bytes.Equal(current, sum) {
+			continue
+		}
+		sum = current
+		_ = rl.reload("a change of the configuration files")
+	}
+}
+
+// configChecksum returns a checksum of the names and contents of the
+// configuration files at paths.
+func configChecksum(paths []string) ([]byte, error) {
+	files, err := config.Files(paths)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", file, len(b))
+		h.Write(b)
+	}
+	return h.Sum(nil), nil
+}
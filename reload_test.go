@@ -0,0 +1,65 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestReloader(t *testing.T) {
+	var loadErr error
+	loaded := &runtimeConfig{collectors: map[string]collector.Collector{"panicking": panickingCollector{}}}
+	rl := &reloader{load: func() (*runtimeConfig, error) { return loaded, loadErr }}
+	lastReloadSuccessful := func() float64 {
+		var m dto.Metric
+		if err := configReloadSuccess.Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetGauge().GetValue()
+	}
+
+	rec := httptest.NewRecorder()
+	rl.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/-/reload", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected GET requests to be rejected, got status %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	rl.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/-/reload", nil))
+	if rec.Code != http.StatusOK || currentConfig() != loaded || lastReloadSuccessful() != 1 {
+		t.Errorf("expected the configuration to be reloaded, got status %d", rec.Code)
+	}
+
+	// An invalid configuration leaves the one in use in place.
+	loadErr = errors.New("invalid configuration")
+	previous := loaded
+	loaded = &runtimeConfig{}
+	rec = httptest.NewRecorder()
+	rl.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/-/reload", nil))
+	if rec.Code != http.StatusInternalServerError || currentConfig() != previous || lastReloadSuccessful() != 0 {
+		t.Errorf("expected the reload to fail, got status %d", rec.Code)
+	}
+	activeConfig.Store(nil)
+}
+
+func TestSetEnabledCollectors(t *testing.T) {
+	defer enabledCollectorsInfo.Reset()
+	setEnabledCollectors([]string{"cpu", "net"})
+	setEnabledCollectors([]string{"net", "os"})
+	if n := testutil.CollectAndCount(enabledCollectorsInfo); n != 2 {
+		t.Errorf("expected 2 enabled collectors, got %d", n)
+	}
+	for name, expected := range map[string]float64{"net": 1, "os": 1} {
+		if value := testutil.ToFloat64(enabledCollectorsInfo.WithLabelValues(name)); value != expected {
+			t.Errorf("expected collector %s to be enabled, got %v", name, value)
+		}
+	}
+}
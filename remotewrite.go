@@ -0,0 +1,438 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus-community/windows_exporter/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/version"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// remoteWriteConfig configures a remoteWriter.
+type remoteWriteConfig struct {
+	url      string
+	interval time.Duration
+	timeout  time.Duration
+	// labels are added to every series which does not already have them.
+	labels map[string]string
+
+	maxRetries   int
+	retryBackoff time.Duration
+
+	// queueDir holds the batches waiting to be sent. They are kept in memory if
+	// empty.
+	queueDir     string
+	queueMaxSize int64
+
+	username    string
+	password    string
+	bearerToken string
+}
+
+// remoteWriter periodically gathers the series of the exporter and sends them
+// to a Prometheus remote write endpoint.
+type remoteWriter struct {
+	remoteWriteConfig
+	client *http.Client
+	queue  *batchQueue
+	// gatherer returns the gatherer of a single collection.
+	gatherer func() (prometheus.Gatherer, error)
+	// retries is the number of times the oldest batch was retried.
+	retries int
+}
+
+func newRemoteWriter(cfg remoteWriteConfig, gatherer func() (prometheus.Gatherer, error)) (*remoteWriter, error) {
+	queue, err := newBatchQueue(cfg.queueDir, cfg.queueMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteWriter{
+		remoteWriteConfig: cfg,
+		client:            &http.Client{Timeout: cfg.timeout},
+		queue:             queue,
+		gatherer:          gatherer,
+	}, nil
+}
+
+// run collects and sends the series of the exporter every interval. It never
+// returns. Batches which could not be sent are retried from this loop too, so
+// that waiting for a retry never delays a collection.
+func (w *remoteWriter) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	w.collect()
+	for {
+		var retry <-chan time.Time
+		if backoff := w.flush(); backoff > 0 {
+			retry = time.After(backoff)
+		}
+		select {
+		case <-ticker.C:
+			w.collect()
+		case <-retry:
+		}
+	}
+}
+
+// collect gathers the series of the exporter and queues them for sending.
+func (w *remoteWriter) collect() {
+	g, err := w.gatherer()
+	if err != nil {
+		log.Errorf("remote write: couldn't create gatherer: %v", err)
+		return
+	}
+	// Like a scrape, failing collectors do not prevent the others from being sent.
+	mfs, err := g.Gather()
+	if err != nil {
+		log.Warnf("remote write: error gathering metrics: %v", err)
+	}
+	batch := snappy.Encode(nil, encodeWriteRequest(mfs, w.labels, time.Now()))
+	if err := w.queue.push(batch); err != nil {
+		log.Errorf("remote write: couldn't queue batch: %v", err)
+	}
+}
+
+// flush sends the queued batches, oldest first. It stops at the first batch
+// which could not be sent but may be retried, keeping it queued, and returns
+// how long to wait before retrying it. The wait doubles on every retry, and is
+// 0 once maxRetries retries failed, leaving the batch for the next collection.
+func (w *remoteWriter) flush() time.Duration {
+	for {
+		batch, ok, err := w.queue.peek()
+		if !ok {
+			return 0
+		}
+		if err == nil {
+			err = w.send(batch)
+			var recoverable recoverableError
+			if errors.As(err, &recoverable) {
+				if w.retries < w.maxRetries {
+					backoff := w.retryBackoff << w.retries
+					w.retries++
+					log.Warnf("remote write: %v, retrying in %s", err, backoff)
+					return backoff
+				}
+				w.retries = 0
+				log.Warnf("remote write: %v, keeping %d queued batches for later", err, w.queue.len())
+				return 0
+			}
+		}
+		w.retries = 0
+		if err != nil {
+			log.Errorf("remote write: dropping batch: %v", err)
+		}
+		if err := w.queue.pop(); err != nil {
+			log.Errorf("remote write: couldn't remove batch from queue: %v", err)
+			return 0
+		}
+	}
+}
+
+// recoverableError is an error sending a batch which may succeed if retried.
+type recoverableError struct {
+	error
+}
+
+// send sends a batch once.
+func (w *remoteWriter) send(batch []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(batch))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "windows_exporter/"+version.Version)
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.username != "" {
+		req.SetBasicAuth(w.username, w.password)
+	} else if w.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+w.bearerToken)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return recoverableError{err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("server returned HTTP status %s: %s", resp.Status, bytes.TrimSpace(body))
+	// Like Prometheus, retry server errors and rate limiting only.
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return recoverableError{err}
+	}
+	return err
+}
+
+// encodeWriteRequest encodes the series of mfs as a remote write WriteRequest
+// protobuf message, adding labels to the series which do not have them.
+// Summaries and histograms are split into one series per quantile or bucket,
+// plus their sum and count, as in the text format.
+func encodeWriteRequest(mfs []*dto.MetricFamily, labels map[string]string, now time.Time) []byte {
+	var b []byte
+	for _, mf := range mfs {
+		name := mf.GetName()
+		for _, m := range mf.Metric {
+			ts := now.UnixMilli()
+			if m.TimestampMs != nil {
+				ts = m.GetTimestampMs()
+			}
+			series := func(suffix string, value float64, extra ...string) {
+				b = appendTimeSeries(b, seriesLabels(name+suffix, m.Label, labels, extra...), value, ts)
+			}
+
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				series("", m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				series("", m.GetGauge().GetValue())
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.Quantile {
+					series("", q.GetValue(), "quantile", formatFloat(q.GetQuantile()))
+				}
+				series("_sum", s.GetSampleSum())
+				series("_count", float64(s.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				inf := false
+				for _, bucket := range h.Bucket {
+					series("_bucket", float64(bucket.GetCumulativeCount()), "le", formatFloat(bucket.GetUpperBound()))
+					inf = math.IsInf(bucket.GetUpperBound(), 1)
+				}
+				if !inf {
+					series("_bucket", float64(h.GetSampleCount()), "le", "+Inf")
+				}
+				series("_sum", h.GetSampleSum())
+				series("_count", float64(h.GetSampleCount()))
+			default:
+				series("", m.GetUntyped().GetValue())
+			}
+		}
+	}
+	return b
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+// seriesLabels returns the labels of a series sorted by name, as remote write
+// requires.
+func seriesLabels(name string, pairs []*dto.LabelPair, labels map[string]string, extra ...string) [][2]string {
+	set := map[string]string{"__name__": name}
+	for k, v := range labels {
+		set[k] = v
+	}
+	for _, l := range pairs {
+		if l.GetValue() != "" {
+			set[l.GetName()] = l.GetValue()
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		set[extra[i]] = extra[i+1]
+	}
+
+	sorted := make([][2]string, 0, len(set))
+	for k, v := range set {
+		sorted = append(sorted, [2]string{k, v})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+	return sorted
+}
+
+// appendTimeSeries appends a WriteRequest.timeseries field holding a single
+// sample.
+//
+//	message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label      { string name = 1; string value = 2; }
+//	message Sample     { double value = 1; int64 timestamp = 2; }
+func appendTimeSeries(b []byte, labels [][2]string, value float64, ts int64) []byte {
+	var series []byte
+	for _, l := range labels {
+		var label []byte
+		label = protowire.AppendTag(label, 1, protowire.BytesType)
+		label = protowire.AppendString(label, l[0])
+		label = protowire.AppendTag(label, 2, protowire.BytesType)
+		label = protowire.AppendString(label, l[1])
+		series = protowire.AppendTag(series, 1, protowire.BytesType)
+		series = protowire.AppendBytes(series, label)
+	}
+
+	var sample []byte
+	sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, math.Float64bits(value))
+	sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+	sample = protowire.AppendVarint(sample, uint64(ts))
+	series = protowire.AppendTag(series, 2, protowire.BytesType)
+	series = protowire.AppendBytes(series, sample)
+
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, series)
+}
+
+// batchQueue holds the batches waiting to be sent, oldest first. If dir is set,
+// batches are written to files in that directory, so that they survive restarts.
+// The oldest batches are discarded when the queue grows beyond maxSize bytes.
+// It is not safe for concurrent use.
+type batchQueue struct {
+	dir     string
+	maxSize int64
+
+	batches []queuedBatch
+	size    int64
+	next    uint64
+}
+
+type queuedBatch struct {
+	// data holds the batch if the queue is kept in memory.
+	data []byte
+	// file holds the batch otherwise.
+	file string
+	size int64
+}
+
+const (
+	batchFileSuffix = ".batch"
+	// tmpFileSuffix is appended to the name of a batch file while it is written.
+	tmpFileSuffix = ".tmp"
+)
+
+func newBatchQueue(dir string, maxSize int64) (*batchQueue, error) {
+	q := &batchQueue{dir: dir, maxSize: maxSize}
+	if dir == "" {
+		return q, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	// Entries are sorted by file name, which is the sequence number of the batch.
+	for _, entry := range entries {
+		// Left behind by a crash while writing a batch.
+		if strings.HasSuffix(entry.Name(), batchFileSuffix+tmpFileSuffix) {
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+				log.Warnf("remote write: couldn't remove incomplete batch: %v", err)
+			}
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(entry.Name(), batchFileSuffix), 10, 64)
+		if err != nil || !strings.HasSuffix(entry.Name(), batchFileSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		q.batches = append(q.batches, queuedBatch{file: filepath.Join(dir, entry.Name()), size: info.Size()})
+		q.size += info.Size()
+		q.next = seq + 1
+	}
+	if len(q.batches) > 0 {
+		log.Infof("remote write: resuming with %d queued batches from %s", len(q.batches), dir)
+	}
+	q.trim()
+	return q, nil
+}
+
+func (q *batchQueue) len() int {
+	return len(q.batches)
+}
+
+// push adds a batch to the end of the queue.
+func (q *batchQueue) push(data []byte) error {
+	batch := queuedBatch{size: int64(len(data))}
+	if q.dir == "" {
+		batch.data = data
+	} else {
+		batch.file = filepath.Join(q.dir, fmt.Sprintf("%020d%s", q.next, batchFileSuffix))
+		// Written under a temporary name first, so that a crash never leaves a
+		// truncated batch behind.
+		tmp := batch.file + tmpFileSuffix
+		if err := os.WriteFile(tmp, data, 0o600); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, batch.file); err != nil {
+			return err
+		}
+	}
+	q.next++
+	q.batches = append(q.batches, batch)
+	q.size += batch.size
+	q.trim()
+	return nil
+}
+
+// trim discards the oldest batches until the queue fits in maxSize, always
+// keeping the newest batch.
+func (q *batchQueue) trim() {
+	dropped := 0
+	for q.maxSize > 0 && q.size > q.maxSize && len(q.batches) > 1 {
+		if err := q.pop(); err != nil {
+			log.Errorf("remote write: couldn't remove batch from queue: %v", err)
+			break
+		}
+		dropped++
+	}
+	if dropped > 0 {
+		log.Warnf("remote write: queue is full, dropped the %d oldest batches", dropped)
+	}
+}
+
+// peek returns the oldest batch, and false if the queue is empty.
+func (q *batchQueue) peek() ([]byte, bool, error) {
+	if len(q.batches) == 0 {
+		return nil, false, nil
+	}
+	batch := q.batches[0]
+	if q.dir == "" {
+		return batch.data, true, nil
+	}
+	data, err := os.ReadFile(batch.file)
+	return data, true, err
+}
+
+// pop removes the oldest batch.
+func (q *batchQueue) pop() error {
+	batch := q.batches[0]
+	if batch.file != "" {
+		if err := os.Remove(batch.file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	q.batches = q.batches[1:]
+	q.size -= batch.size
+	return nil
+}
@@ -0,0 +1,238 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodeWriteRequest decodes a WriteRequest into one line per sample, in the
+// form name{label="value",...} value timestamp.
+func decodeWriteRequest(t *testing.T, b []byte) []string {
+	t.Helper()
+	var samples []string
+	forEachField(t, b, func(num protowire.Number, v []byte, _ uint64) {
+		if num != 1 {
+			return
+		}
+		var name, value string
+		var labels []string
+		forEachField(t, v, func(num protowire.Number, v []byte, _ uint64) {
+			switch num {
+			case 1:
+				var l [2]string
+				forEachField(t, v, func(num protowire.Number, v []byte, _ uint64) {
+					l[num-1] = string(v)
+				})
+				if l[0] == "__name__" {
+					name = l[1]
+				} else {
+					labels = append(labels, l[0]+"=\""+l[1]+"\"")
+				}
+			case 2:
+				forEachField(t, v, func(num protowire.Number, _ []byte, n uint64) {
+					if num == 1 {
+						value = formatFloat(math.Float64frombits(n))
+					} else {
+						value += " " + formatFloat(float64(n))
+					}
+				})
+			}
+		})
+		samples = append(samples, name+"{"+strings.Join(labels, ",")+"} "+value)
+	})
+	sort.Strings(samples)
+	return samples
+}
+
+func forEachField(t *testing.T, b []byte, f func(num protowire.Number, v []byte, n uint64)) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			f(num, v, 0)
+			b = b[n:]
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			f(num, nil, v)
+			b = b[n:]
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			f(num, nil, v)
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %v", typ)
+		}
+	}
+}
+
+func TestRemoteWriter(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received [][]string
+		failures = 1
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if user, pass, _ := r.BasicAuth(); user != "user" || pass != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Content-Encoding") != "snappy" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			http.Error(w, "unexpected encoding", http.StatusBadRequest)
+			return
+		}
+		compressed, _ := io.ReadAll(r.Body)
+		b, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received = append(received, decodeWriteRequest(t, b))
+	}))
+	defer server.Close()
+
+	reg := prometheus.NewRegistry()
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests_total", Help: "Requests."}, []string{"job"})
+	requests.WithLabelValues("iis").Add(3)
+	duration := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "duration_seconds", Help: "Duration.", Buckets: []float64{1}})
+	duration.Observe(0.5)
+	reg.MustRegister(requests, duration)
+
+	w, err := newRemoteWriter(remoteWriteConfig{
+		url:          server.URL,
+		timeout:      time.Second,
+		labels:       map[string]string{"job": "windows_exporter"},
+		maxRetries:   1,
+		retryBackoff: time.Millisecond,
+		queueDir:     t.TempDir(),
+		username:     "user",
+		password:     "secret",
+	}, func() (prometheus.Gatherer, error) { return reg, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.collect()
+	if backoff := w.flush(); backoff != time.Millisecond {
+		t.Fatalf("expected the batch to be retried after 1ms, got %s", backoff)
+	}
+	if backoff := w.flush(); backoff != 0 {
+		t.Fatalf("expected no further retry, got %s", backoff)
+	}
+
+	if w.queue.len() != 0 {
+		t.Errorf("expected the queue to be empty after a retried push, got %d batches", w.queue.len())
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected a single batch to be received, got %d", len(received))
+	}
+	for i := range received[0] {
+		// Strip the timestamp.
+		received[0][i] = received[0][i][:strings.LastIndex(received[0][i], " ")]
+	}
+	expected := []string{
+		`duration_seconds_bucket{job="windows_exporter",le="+Inf"} 1`,
+		`duration_seconds_bucket{job="windows_exporter",le="1"} 1`,
+		`duration_seconds_count{job="windows_exporter"} 1`,
+		`duration_seconds_sum{job="windows_exporter"} 0.5`,
+		`requests_total{job="iis"} 3`,
+	}
+	if !reflect.DeepEqual(received[0], expected) {
+		t.Errorf("unexpected samples\nExpected: %v\nActual: %v", expected, received[0])
+	}
+}
+
+func TestRemoteWriterKeepsBatchesOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := remoteWriteConfig{url: server.URL, timeout: time.Second, queueDir: dir}
+	gatherer := func() (prometheus.Gatherer, error) { return prometheus.NewRegistry(), nil }
+	w, err := newRemoteWriter(cfg, gatherer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.collect()
+	w.collect()
+	w.flush()
+	if w.queue.len() != 2 {
+		t.Fatalf("expected 2 queued batches, got %d", w.queue.len())
+	}
+
+	// The queue is replayed after a restart.
+	w, err = newRemoteWriter(cfg, gatherer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.queue.len() != 2 {
+		t.Errorf("expected 2 batches to be replayed, got %d", w.queue.len())
+	}
+}
+
+func TestBatchQueueRemovesIncompleteBatches(t *testing.T) {
+	dir := t.TempDir()
+	tmp := filepath.Join(dir, "00000000000000000000.batch.tmp")
+	if err := os.WriteFile(tmp, []byte("trunc"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	q, err := newBatchQueue(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.len() != 0 {
+		t.Errorf("expected no queued batches, got %d", q.len())
+	}
+	if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", tmp, err)
+	}
+}
+
+func TestBatchQueueMaxSize(t *testing.T) {
+	for _, dir := range []string{"", t.TempDir()} {
+		q, err := newBatchQueue(dir, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, batch := range []string{"first", "second", "third"} {
+			if err := q.push([]byte(batch)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if q.len() != 1 {
+			t.Fatalf("expected the queue to be trimmed to 1 batch, got %d", q.len())
+		}
+		if batch, _, _ := q.peek(); string(batch) != "third" {
+			t.Errorf("expected the newest batch to be kept, got %q", batch)
+		}
+	}
+}
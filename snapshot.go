@@ -0,0 +1,258 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus-community/windows_exporter/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	lastSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(collector.Namespace, "exporter", "collector_last_success_timestamp_seconds"),
+		"windows_exporter: Unix timestamp of the last successful run of the collector.",
+		[]string{"collector"},
+		nil,
+	)
+	stalenessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(collector.Namespace, "exporter", "collector_staleness_seconds"),
+		"windows_exporter: Age of the collector results served in this scrape.",
+		[]string{"collector"},
+		nil,
+	)
+)
+
+// collectorSnapshot holds the result of a single run of a collector.
+type collectorSnapshot struct {
+	// metrics holds everything the run emitted, including its duration.
+	metrics     []prometheus.Metric
+	outcome     collectorOutcome
+	timedOut    bool
+	collectedAt time.Time
+	lastSuccess time.Time
+}
+
+// snapshotStore keeps the last snapshot of each collector.
+type snapshotStore struct {
+	mu        sync.RWMutex
+	snapshots map[string]collectorSnapshot
+}
+
+func newSnapshotStore() *snapshotStore {
+	return &snapshotStore{snapshots: make(map[string]collectorSnapshot)}
+}
+
+func (s *snapshotStore) get(name string) (collectorSnapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snap, ok := s.snapshots[name]
+	return snap, ok
+}
+
+// set stores snap as the latest snapshot of the named collector. A failed run
+// keeps the last success time of the previous snapshot.
+func (s *snapshotStore) set(name string, snap collectorSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if snap.outcome == success {
+		snap.lastSuccess = snap.collectedAt
+	} else if prev, ok := s.snapshots[name]; ok {
+		snap.lastSuccess = prev.lastSuccess
+	}
+	s.snapshots[name] = snap
+}
+
+// runSnapshot runs a single collector to completion, bounded by timeout, and
+// returns everything it emitted.
+func runSnapshot(name string, c collector.Collector, timeout time.Duration) collectorSnapshot {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	snap := collectorSnapshot{outcome: failed}
+	scrapeContext, err := prepareScrapeContext(ctx, []string{name})
+	if err != nil {
+		log.Errorf("collector %s failed to prepare scrape: %s", name, err)
+		snap.timedOut = errors.Is(err, context.DeadlineExceeded)
+		snap.collectedAt = time.Now()
+		return snap
+	}
+
+	metricsBuffer := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	go func() {
+		for m := range metricsBuffer {
+			snap.metrics = append(snap.metrics, m)
+		}
+		close(done)
+	}()
+
+	snap.outcome = execute(name, c, scrapeContext, metricsBuffer)
+	close(metricsBuffer)
+	<-done
+
+	snap.timedOut = errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if snap.timedOut {
+		collectorErrors.WithLabelValues(name, string(collector.ErrorClassTimeout)).Inc()
+	}
+	snap.collectedAt = time.Now()
+	return snap
+}
+
+// freshSnapshots returns the cached snapshots of the given collectors which
+// succeeded within their minimum refresh interval.
+func (coll windowsCollector) freshSnapshots() map[string]collectorSnapshot {
+	fresh := make(map[string]collectorSnapshot)
+	if coll.cache == nil {
+		return fresh
+	}
+	for name := range coll.collectors {
+		interval := coll.minIntervals[name]
+		if interval <= 0 {
+			continue
+		}
+		if snap, ok := coll.cache.get(name); ok && snap.outcome == success && time.Since(snap.collectedAt) < interval {
+			fresh[name] = snap
+		}
+	}
+	return fresh
+}
+
+// replaySnapshot sends the metrics of a cached run of the collector.
+func replaySnapshot(name string, snap collectorSnapshot, ch chan<- prometheus.Metric) collectorOutcome {
+	for _, m := range snap.metrics {
+		ch <- m
+	}
+	ch <- prometheus.MustNewConstMetric(
+		stalenessDesc,
+		prometheus.GaugeValue,
+		time.Since(snap.collectedAt).Seconds(),
+		name,
+	)
+	return snap.outcome
+}
+
+// executeCached runs the collector like execute, additionally storing the
+// results in the cache if the collector has a minimum refresh interval.
+func (coll windowsCollector) executeCached(name string, c collector.Collector, ctx *collector.ScrapeContext, ch chan<- prometheus.Metric) collectorOutcome {
+	if coll.cache == nil || coll.minIntervals[name] <= 0 {
+		return execute(name, c, ctx, ch)
+	}
+
+	tee := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	var metrics []prometheus.Metric
+	go func() {
+		for m := range tee {
+			metrics = append(metrics, m)
+			ch <- m
+		}
+		close(done)
+	}()
+
+	outcome := execute(name, c, ctx, tee)
+	close(tee)
+	<-done
+
+	coll.cache.set(name, collectorSnapshot{metrics: metrics, outcome: outcome, collectedAt: time.Now()})
+	ch <- prometheus.MustNewConstMetric(stalenessDesc, prometheus.GaugeValue, 0, name)
+	return outcome
+}
+
+// startBackgroundCollection runs every collector on its own schedule, storing
+// the results of each run in store. Collectors run at the given interval, or at
+// their minimum refresh interval if that is longer. Each run may take at most
+// one interval. Runs are skipped while the collector's circuit is open. The
+// returned function stops scheduling runs.
+func startBackgroundCollection(store *snapshotStore, collectors map[string]collector.Collector, interval time.Duration, minIntervals map[string]time.Duration, breaker *circuitBreaker) func() {
+	stop := make(chan struct{})
+	for name, c := range collectors {
+		collectorInterval := interval
+		if d := minIntervals[name]; d > collectorInterval {
+			collectorInterval = d
+		}
+		go func(name string, c collector.Collector, interval time.Duration) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				if breaker.allow(name, time.Now()) {
+					snap := runSnapshot(name, c, interval)
+					breaker.record(name, snap.outcome, snap.collectedAt)
+					store.set(name, snap)
+				}
+				select {
+				case <-ticker.C:
+				case <-stop:
+					return
+				}
+			}
+		}(name, c, collectorInterval)
+	}
+	return func() { close(stop) }
+}
+
+// collectSnapshots sends the last stored results of the collectors to
+// prometheus, instead of running them.
+func (coll windowsCollector) collectSnapshots(ch chan<- prometheus.Metric) {
+	names := make([]string, 0, len(coll.collectors))
+	for name := range coll.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	coll.breaker.collectStates(names, ch)
+
+	now := time.Now()
+	for _, name := range names {
+		snap, ok := coll.snapshots.get(name)
+		if !ok {
+			// The first run has not completed yet.
+			ch <- prometheus.MustNewConstMetric(scrapeSuccessDesc, prometheus.GaugeValue, 0, name)
+			continue
+		}
+
+		for _, m := range snap.metrics {
+			ch <- m
+		}
+		ch <- prometheus.MustNewConstMetric(
+			scrapeSuccessDesc,
+			prometheus.GaugeValue,
+			boolToFloat(snap.outcome == success),
+			name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			scrapeTimeoutDesc,
+			prometheus.GaugeValue,
+			boolToFloat(snap.timedOut),
+			name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			stalenessDesc,
+			prometheus.GaugeValue,
+			now.Sub(snap.collectedAt).Seconds(),
+			name,
+		)
+		if !snap.lastSuccess.IsZero() {
+			ch <- prometheus.MustNewConstMetric(
+				lastSuccessDesc,
+				prometheus.GaugeValue,
+				float64(snap.lastSuccess.UnixNano())/1e9,
+				name,
+			)
+		}
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1.0
+	}
+	return 0.0
+}
@@ -0,0 +1,120 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"github.com/prometheus-community/windows_exporter/collector"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	versioncollector "github.com/prometheus/client_golang/prometheus/collectors/version"
+)
+
+var (
+	collectorScrapeDurations = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: collector.Namespace,
+			Subsystem: "exporter",
+			Name:      "collector_scrape_duration_seconds",
+			Help:      "windows_exporter: Histogram of the duration of collections.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		},
+		[]string{"collector"},
+	)
+	collectorMetricsEmitted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: collector.Namespace,
+			Subsystem: "exporter",
+			Name:      "collector_metrics_emitted_total",
+			Help:      "windows_exporter: Number of metrics emitted by the collector.",
+		},
+		[]string{"collector"},
+	)
+	collectorErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: collector.Namespace,
+			Subsystem: "exporter",
+			Name:      "collector_errors_total",
+			Help:      "windows_exporter: Number of failed collections, by class of error.",
+		},
+		[]string{"collector", "class"},
+	)
+	concurrencyLimitRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: collector.Namespace,
+			Subsystem: "exporter",
+			Name:      "concurrency_limit_rejections_total",
+			Help:      "windows_exporter: Number of requests rejected because too many were being served concurrently.",
+		},
+		[]string{"handler"},
+	)
+)
+
+// exporterRegistry holds the metrics describing the exporter itself. Unlike
+// the collectors, which run anew for every request, they are registered once.
+var exporterRegistry = prometheus.NewRegistry()
+
+func init() {
+	exporterRegistry.MustRegister(
+		collectorPanics,
+		enabledCollectorsInfo,
+		collectorScrapeDurations,
+		collectorMetricsEmitted,
+		collectorErrors,
+		concurrencyLimitRejections,
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewGoCollector(),
+		versioncollector.NewCollector("windows_exporter"),
+	)
+}
+
+// initCollectorTelemetry exposes the series of an enabled collector from the
+// start, rather than on its first error.
+func initCollectorTelemetry(name string) {
+	collectorPanics.WithLabelValues(name)
+	collectorMetricsEmitted.WithLabelValues(name)
+	for _, class := range collector.ErrorClasses {
+		collectorErrors.WithLabelValues(name, string(class))
+	}
+	enabledCollectorsInfo.WithLabelValues(name).Set(1)
+}
+
+// setEnabledCollectors replaces the enabled collectors exposed by
+// enabledCollectorsInfo with names, and sets up their telemetry.
+func setEnabledCollectors(names []string) {
+	enabledCollectorsInfo.Reset()
+	for _, name := range names {
+		initCollectorTelemetry(name)
+	}
+}
+
+// countError counts a failed collection by the class of its error. Timeouts
+// are counted by the scrape which gave up on the collector.
+func countError(name string, err error) {
+	if class := collector.ClassifyError(err); class != collector.ErrorClassTimeout {
+		collectorErrors.WithLabelValues(name, string(class)).Inc()
+	}
+}
+
+// countingChannel forwards the metrics sent to the returned channel to ch,
+// counting them as emitted by the named collector. The returned function must
+// be called once the collector is done with the channel, and returns the
+// number of metrics emitted.
+func countingChannel(name string, ch chan<- prometheus.Metric) (chan<- prometheus.Metric, func() int) {
+	in := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	var n int
+	go func() {
+		defer close(done)
+		for m := range in {
+			n++
+			ch <- m
+		}
+		collectorMetricsEmitted.WithLabelValues(name).Add(float64(n))
+	}()
+	return in, func() int {
+		close(in)
+		<-done
+		return n
+	}
+}